@@ -0,0 +1,127 @@
+// Package authz defines the roles of users, what each role is permitted to
+// do and the identity the gateway forwards to the services.
+package authz
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Header carries the identity token the gateway signs for the caller, see
+// Signer and Middleware.
+const Header = "X-Authentication-Info"
+
+var (
+	ErrUnknownRole       = errors.New("authz: unknown role")
+	ErrUnknownPermission = errors.New("authz: unknown permission")
+)
+
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleSeller   Role = "seller"
+	RoleAdmin    Role = "admin"
+	RoleSupport  Role = "support"
+)
+
+// DefaultRoles are given to every new user.
+var DefaultRoles = []Role{RoleCustomer}
+
+type Permission string
+
+const (
+	PermissionPlaceOrders Permission = "orders:place"
+	// sellers change only their own products
+	PermissionSellProducts     Permission = "products:sell"
+	PermissionManageAnyProduct Permission = "products:manage_any"
+	// categories and attributes
+	PermissionManageCatalog       Permission = "catalog:manage"
+	PermissionManageExchangeRates Permission = "exchange_rates:manage"
+	PermissionReadAnyOrder        Permission = "orders:read_any"
+	PermissionManageRoles         Permission = "users:manage_roles"
+)
+
+var permissions = []Permission{
+	PermissionPlaceOrders,
+	PermissionSellProducts,
+	PermissionManageAnyProduct,
+	PermissionManageCatalog,
+	PermissionManageExchangeRates,
+	PermissionReadAnyOrder,
+	PermissionManageRoles,
+}
+
+var rolePermissions = map[Role][]Permission{
+	RoleCustomer: {PermissionPlaceOrders},
+	RoleSeller:   {PermissionSellProducts},
+	RoleSupport:  {PermissionReadAnyOrder},
+	RoleAdmin:    permissions,
+}
+
+// ParseRoles validates role names, duplicates are dropped.
+func ParseRoles(names []string) ([]Role, error) {
+	roles := make([]Role, 0, len(names))
+	seen := make(map[Role]bool, len(names))
+	for _, name := range names {
+		role := Role(name)
+		if _, ok := rolePermissions[role]; !ok {
+			return nil, ErrUnknownRole
+		}
+		if seen[role] {
+			continue
+		}
+		seen[role] = true
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func ParsePermission(name string) (Permission, error) {
+	for _, permission := range permissions {
+		if string(permission) == name {
+			return permission, nil
+		}
+	}
+	return "", ErrUnknownPermission
+}
+
+// Can reports whether any of the roles grants the permission.
+func Can(roles []Role, permission Permission) bool {
+	for _, role := range roles {
+		for _, granted := range rolePermissions[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Identity is the authenticated caller. A zero UserID means an anonymous
+// caller.
+type Identity struct {
+	UserID    string `json:"user_id"`
+	Roles     []Role `json:"roles"`
+	SessionID string `json:"session_id,omitempty"`
+}
+
+func (i Identity) Can(permission Permission) bool {
+	return i.UserID != "" && Can(i.Roles, permission)
+}
+
+func (i Identity) HasRole(role Role) bool {
+	for _, r := range i.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// FromRequest returns the identity Middleware verified for the request, an
+// anonymous identity when there is none.
+func FromRequest(r *http.Request) Identity {
+	identity, _ := FromContext(r.Context())
+	return identity
+}
@@ -0,0 +1,116 @@
+package authz_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"shared/authz"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCan(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		roles      []authz.Role
+		permission authz.Permission
+		want       bool
+	}{
+		{
+			name:       "CustomerPlacesOrders",
+			roles:      []authz.Role{authz.RoleCustomer},
+			permission: authz.PermissionPlaceOrders,
+			want:       true,
+		},
+		{
+			name:       "CustomerCannotSell",
+			roles:      []authz.Role{authz.RoleCustomer},
+			permission: authz.PermissionSellProducts,
+			want:       false,
+		},
+		{
+			name:       "RolesAreCombined",
+			roles:      []authz.Role{authz.RoleCustomer, authz.RoleSeller},
+			permission: authz.PermissionSellProducts,
+			want:       true,
+		},
+		{
+			name:       "SupportReadsAnyOrder",
+			roles:      []authz.Role{authz.RoleSupport},
+			permission: authz.PermissionReadAnyOrder,
+			want:       true,
+		},
+		{
+			name:       "AdminManagesRoles",
+			roles:      []authz.Role{authz.RoleAdmin},
+			permission: authz.PermissionManageRoles,
+			want:       true,
+		},
+		{
+			name:       "UnknownRole_GrantsNothing",
+			roles:      []authz.Role{"root"},
+			permission: authz.PermissionManageRoles,
+			want:       false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, authz.Can(tc.roles, tc.permission))
+		})
+	}
+}
+
+func TestParseRoles(t *testing.T) {
+	t.Parallel()
+	roles, err := authz.ParseRoles([]string{"seller", "customer", "seller"})
+	require.NoError(t, err)
+	assert.Equal(t, []authz.Role{authz.RoleSeller, authz.RoleCustomer}, roles)
+
+	_, err = authz.ParseRoles([]string{"root"})
+	assert.ErrorIs(t, err, authz.ErrUnknownRole)
+}
+
+func TestParsePermission(t *testing.T) {
+	t.Parallel()
+	permission, err := authz.ParsePermission("products:sell")
+	require.NoError(t, err)
+	assert.Equal(t, authz.PermissionSellProducts, permission)
+
+	_, err = authz.ParsePermission("products:steal")
+	assert.ErrorIs(t, err, authz.ErrUnknownPermission)
+}
+
+func TestFromRequest(t *testing.T) {
+	t.Parallel()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(authz.NewContext(r.Context(), authz.Identity{
+		UserID: "user-1",
+		Roles:  []authz.Role{authz.RoleSupport},
+	}))
+
+	identity := authz.FromRequest(r)
+
+	assert.Equal(t, "user-1", identity.UserID)
+	assert.True(t, identity.HasRole(authz.RoleSupport))
+	assert.True(t, identity.Can(authz.PermissionReadAnyOrder))
+	assert.False(t, identity.Can(authz.PermissionManageCatalog))
+}
+
+func TestFromRequest_Anonymous(t *testing.T) {
+	t.Parallel()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(authz.NewContext(r.Context(), authz.Identity{Roles: []authz.Role{authz.RoleAdmin}}))
+
+	identity := authz.FromRequest(r)
+
+	assert.False(t, identity.Can(authz.PermissionManageRoles))
+	// the unverified header is ignored
+	unverified := httptest.NewRequest("GET", "/", nil)
+	unverified.Header.Set(authz.Header, `{"user_id":"user-1","roles":["admin"]}`)
+	assert.Equal(t, authz.Identity{}, authz.FromRequest(unverified))
+}
@@ -0,0 +1,49 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySet_Rotation(t *testing.T) {
+	t.Parallel()
+	keys, err := NewKeySet([]byte("a secret of at least thirty-two bytes"), time.Hour)
+	require.NoError(t, err)
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	keys.now = func() time.Time { return now }
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(keys.JWKS())
+	}))
+	t.Cleanup(server.Close)
+	verifier := NewJWKSVerifier(server.URL, "gateway", server.Client())
+	verifier.now = keys.now
+	signer := NewSigner(keys, "gateway", time.Minute)
+	signer.now = keys.now
+	identity := Identity{UserID: "user-1", Roles: []Role{RoleCustomer}}
+
+	before, err := signer.Sign(identity)
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), before)
+	require.NoError(t, err)
+
+	// the next key was published with the first fetch
+	now = now.Add(time.Hour)
+	after, err := signer.Sign(identity)
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), after)
+	assert.NoError(t, err)
+
+	// two rotations later the first key is gone
+	now = now.Add(2 * time.Hour)
+	fresh := NewJWKSVerifier(server.URL, "gateway", server.Client())
+	fresh.now = keys.now
+	_, err = fresh.Verify(context.Background(), before)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
@@ -0,0 +1,45 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	httpErrors "shared/errors/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext returns the identity Middleware verified, false for requests
+// that did not pass through it.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(Identity)
+	return identity, ok
+}
+
+// Middleware verifies the identity token the gateway forwards in Header.
+// Requests without a token or with a forged or expired one are rejected.
+func Middleware(verifier Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(Header)
+		if token == "" {
+			httpErrors.Unauthorized(c, "identity token is missing")
+			return
+		}
+		identity, err := verifier.Verify(c.Request.Context(), token)
+		if errors.Is(err, ErrTokenExpired) {
+			httpErrors.Unauthorized(c, "identity token expired")
+			return
+		}
+		if err != nil {
+			httpErrors.Unauthorized(c, "identity token is invalid")
+			return
+		}
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), identity))
+		c.Next()
+	}
+}
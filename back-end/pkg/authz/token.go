@@ -0,0 +1,255 @@
+package authz
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Identity tokens are JWTs signed with Ed25519 (RFC 8037).
+const _algorithm = "EdDSA"
+
+// tolerated difference between the clocks of the gateway and a service
+const _clockSkew = 5 * time.Second
+
+var (
+	ErrInvalidToken = errors.New("authz: invalid identity token")
+	ErrTokenExpired = errors.New("authz: identity token expired")
+	ErrUnknownKey   = errors.New("authz: unknown signing key")
+)
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+type claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Roles     []Role `json:"roles,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type signingKey struct {
+	id         string
+	privateKey ed25519.PrivateKey
+}
+
+// the shortest secret keys are derived from
+const _minSecretSize = 32
+
+var ErrWeakSecret = errors.New("authz: the signing secret must be at least 32 bytes")
+
+// KeySet derives the keys the gateway signs with from a secret every gateway
+// shares, so they all publish and sign with the same keys. Time is split into
+// periods of the rotation interval with a key each. The key of the next
+// period is published before it is used so services already know it after a
+// rotation, the key of the previous period stays published until the tokens
+// it signed expire.
+type KeySet struct {
+	secret   []byte
+	interval time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	keys map[int64]signingKey
+}
+
+// NewKeySet -. The rotation interval must be longer than the lifetime of the
+// tokens.
+func NewKeySet(secret []byte, rotationInterval time.Duration) (*KeySet, error) {
+	if len(secret) < _minSecretSize {
+		return nil, ErrWeakSecret
+	}
+	if rotationInterval <= 0 {
+		return nil, errors.New("authz: the rotation interval must be positive")
+	}
+	return &KeySet{
+		secret:   secret,
+		interval: rotationInterval,
+		now:      time.Now,
+		keys:     map[int64]signingKey{},
+	}, nil
+}
+
+// JWKS returns the public keys tokens may be verified with.
+func (s *KeySet) JWKS() JWKS {
+	period := s.period()
+	return JWKS{Keys: []JWK{
+		newJWK(s.key(period)),
+		newJWK(s.key(period + 1)),
+		newJWK(s.key(period - 1)),
+	}}
+}
+
+func (s *KeySet) signingKey() signingKey {
+	return s.key(s.period())
+}
+
+func (s *KeySet) period() int64 {
+	return s.now().UnixNano() / int64(s.interval)
+}
+
+// key derives the key of period, the keys of older periods are forgotten.
+func (s *KeySet) key(period int64) signingKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[period]; ok {
+		return key
+	}
+	for p := range s.keys {
+		if p < period-2 {
+			delete(s.keys, p)
+		}
+	}
+	seed := s.derive("signing key", period)
+	id := s.derive("key id", period)
+	key := signingKey{
+		id:         hex.EncodeToString(id[:8]),
+		privateKey: ed25519.NewKeyFromSeed(seed[:ed25519.SeedSize]),
+	}
+	s.keys[period] = key
+	return key
+}
+
+func (s *KeySet) derive(purpose string, period int64) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s %d", purpose, period)
+	return mac.Sum(nil)
+}
+
+// JWK is an Ed25519 public key (RFC 8037).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func newJWK(key signingKey) JWK {
+	publicKey := key.privateKey.Public().(ed25519.PublicKey)
+	return JWK{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		X:         base64.RawURLEncoding.EncodeToString(publicKey),
+		KeyID:     key.id,
+		Algorithm: _algorithm,
+		Use:       "sig",
+	}
+}
+
+func (k JWK) publicKey() (ed25519.PublicKey, error) {
+	if k.KeyType != "OKP" || k.Curve != "Ed25519" {
+		return nil, fmt.Errorf("authz: unsupported key %s/%s", k.KeyType, k.Curve)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("authz: malformed key %s", k.KeyID)
+	}
+	return ed25519.PublicKey(x), nil
+}
+
+// Signer mints the short-lived identity tokens the gateway forwards to the
+// services.
+type Signer struct {
+	keys   *KeySet
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewSigner(keys *KeySet, issuer string, ttl time.Duration) *Signer {
+	return &Signer{keys: keys, issuer: issuer, ttl: ttl, now: time.Now}
+}
+
+func (s *Signer) Sign(identity Identity) (string, error) {
+	key := s.keys.signingKey()
+	now := s.now()
+	header, err := json.Marshal(tokenHeader{Algorithm: _algorithm, Type: "JWT", KeyID: key.id})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims{
+		Issuer:    s.issuer,
+		Subject:   identity.UserID,
+		Roles:     identity.Roles,
+		SessionID: identity.SessionID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	signature := ed25519.Sign(key.privateKey, []byte(signingInput))
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// parseToken checks the signature, the issuer and the expiry of the token.
+// lookup returns the public key of a key id.
+func parseToken(
+	token string,
+	issuer string,
+	now time.Time,
+	lookup func(keyID string) (ed25519.PublicKey, error),
+) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, ErrInvalidToken
+	}
+	var header tokenHeader
+	err := decodeSegment(parts[0], &header)
+	if err != nil || header.Algorithm != _algorithm {
+		return Identity{}, ErrInvalidToken
+	}
+	publicKey, err := lookup(header.KeyID)
+	if err != nil {
+		return Identity{}, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	if !ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return Identity{}, ErrInvalidToken
+	}
+
+	var c claims
+	err = decodeSegment(parts[1], &c)
+	if err != nil || c.Subject == "" || c.Issuer != issuer {
+		return Identity{}, ErrInvalidToken
+	}
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(_clockSkew)) {
+		return Identity{}, ErrTokenExpired
+	}
+	return Identity{UserID: c.Subject, Roles: c.Roles, SessionID: c.SessionID}, nil
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
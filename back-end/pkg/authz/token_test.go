@@ -0,0 +1,202 @@
+package authz_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"shared/authz"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	issuer  = "gateway"
+	_secret = "a secret of at least thirty-two bytes"
+)
+
+func newJWKSServer(t *testing.T, keys *authz.KeySet) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(keys.JWKS())
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newKeySet(t *testing.T, secret string) *authz.KeySet {
+	t.Helper()
+	keys, err := authz.NewKeySet([]byte(secret), time.Hour)
+	require.NoError(t, err)
+	return keys
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	keys := newKeySet(t, _secret)
+	server := newJWKSServer(t, keys)
+	identity := authz.Identity{
+		UserID:    "user-1",
+		Roles:     []authz.Role{authz.RoleSeller},
+		SessionID: "session-1",
+	}
+	otherKeys := newKeySet(t, strings.Repeat("o", 32))
+
+	testCases := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr error
+	}{
+		{
+			name: "Valid",
+			token: func(t *testing.T) string {
+				token, err := authz.NewSigner(keys, issuer, time.Minute).Sign(identity)
+				require.NoError(t, err)
+				return token
+			},
+		},
+		{
+			name: "Expired",
+			token: func(t *testing.T) string {
+				token, err := authz.NewSigner(keys, issuer, -time.Minute).Sign(identity)
+				require.NoError(t, err)
+				return token
+			},
+			wantErr: authz.ErrTokenExpired,
+		},
+		{
+			name: "OtherIssuer",
+			token: func(t *testing.T) string {
+				token, err := authz.NewSigner(keys, "someone", time.Minute).Sign(identity)
+				require.NoError(t, err)
+				return token
+			},
+			wantErr: authz.ErrInvalidToken,
+		},
+		{
+			name: "ForeignKey",
+			token: func(t *testing.T) string {
+				token, err := authz.NewSigner(otherKeys, issuer, time.Minute).Sign(identity)
+				require.NoError(t, err)
+				return token
+			},
+			wantErr: authz.ErrUnknownKey,
+		},
+		{
+			name: "TamperedClaims",
+			token: func(t *testing.T) string {
+				token, err := authz.NewSigner(keys, issuer, time.Minute).Sign(identity)
+				require.NoError(t, err)
+				forged, err := authz.NewSigner(keys, issuer, time.Minute).Sign(authz.Identity{
+					UserID: "user-1",
+					Roles:  []authz.Role{authz.RoleAdmin},
+				})
+				require.NoError(t, err)
+				parts := strings.Split(token, ".")
+				parts[1] = strings.Split(forged, ".")[1]
+				return strings.Join(parts, ".")
+			},
+			wantErr: authz.ErrInvalidToken,
+		},
+		{
+			name: "Unsigned",
+			token: func(t *testing.T) string {
+				return `{"user_id":"user-1","roles":["admin"]}`
+			},
+			wantErr: authz.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			verifier := authz.NewJWKSVerifier(server.URL, issuer, server.Client())
+
+			got, err := verifier.Verify(context.Background(), tc.token(t))
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, identity, got)
+		})
+	}
+}
+
+func TestNewKeySet_WeakSecret(t *testing.T) {
+	t.Parallel()
+	_, err := authz.NewKeySet([]byte("short"), time.Hour)
+	assert.ErrorIs(t, err, authz.ErrWeakSecret)
+}
+
+// gateways sharing the secret sign with the same keys, a service may fetch
+// the keys from any of them
+func TestKeySet_SharedSecret(t *testing.T) {
+	t.Parallel()
+	keys := newKeySet(t, _secret)
+	otherGateway := newKeySet(t, _secret)
+	require.Equal(t, keys.JWKS(), otherGateway.JWKS())
+
+	token, err := authz.NewSigner(keys, issuer, time.Minute).Sign(authz.Identity{UserID: "user-1"})
+	require.NoError(t, err)
+	server := newJWKSServer(t, otherGateway)
+	got, err := authz.NewJWKSVerifier(server.URL, issuer, server.Client()).Verify(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", got.UserID)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	keys := newKeySet(t, _secret)
+	server := newJWKSServer(t, keys)
+	token, err := authz.NewSigner(keys, issuer, time.Minute).Sign(authz.Identity{
+		UserID: "user-1",
+		Roles:  []authz.Role{authz.RoleCustomer},
+	})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(authz.Middleware(authz.NewJWKSVerifier(server.URL, issuer, server.Client())))
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, authz.FromRequest(c.Request).UserID)
+	})
+
+	testCases := []struct {
+		name     string
+		header   string
+		wantCode int
+		wantBody string
+	}{
+		{name: "Verified", header: token, wantCode: http.StatusOK, wantBody: "user-1"},
+		{name: "Missing", wantCode: http.StatusUnauthorized},
+		{name: "PlainJSON", header: `{"user_id":"user-1"}`, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				r.Header.Set(authz.Header, tc.header)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package authz
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// the gateway rotates its keys rarely, an unknown key id refetches the keys
+// at most this often so forged tokens cannot flood the gateway
+const _minRefreshInterval = 10 * time.Second
+
+type Verifier interface {
+	Verify(ctx context.Context, token string) (Identity, error)
+}
+
+// JWKSVerifier verifies identity tokens with the keys the gateway publishes
+// at its JWKS endpoint.
+type JWKSVerifier struct {
+	url    string
+	issuer string
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]ed25519.PublicKey
+	fetchedAt time.Time
+}
+
+func NewJWKSVerifier(url, issuer string, client *http.Client) *JWKSVerifier {
+	return &JWKSVerifier{
+		url:    url,
+		issuer: issuer,
+		client: client,
+		now:    time.Now,
+		keys:   map[string]ed25519.PublicKey{},
+	}
+}
+
+func (v *JWKSVerifier) Verify(ctx context.Context, token string) (Identity, error) {
+	return parseToken(token, v.issuer, v.now(), func(keyID string) (ed25519.PublicKey, error) {
+		return v.publicKey(ctx, keyID)
+	})
+}
+
+func (v *JWKSVerifier) publicKey(ctx context.Context, keyID string) (ed25519.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if key, ok := v.keys[keyID]; ok {
+		return key, nil
+	}
+	if !v.fetchedAt.IsZero() && v.now().Sub(v.fetchedAt) < _minRefreshInterval {
+		return nil, ErrUnknownKey
+	}
+	err := v.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := v.keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+func (v *JWKSVerifier) fetch(ctx context.Context) error {
+	v.fetchedAt = v.now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return fmt.Errorf("authz: fetch keys: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("authz: fetch keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("authz: fetch keys: unexpected status %d", resp.StatusCode)
+	}
+	var jwks JWKS
+	err = json.NewDecoder(resp.Body).Decode(&jwks)
+	if err != nil {
+		return fmt.Errorf("authz: decode keys: %w", err)
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	v.keys = keys
+	return nil
+}
@@ -0,0 +1,78 @@
+// Inspects and replays the dead-letter stream of a durable consumer.
+//
+//	NATS_URI=nats://localhost:4222 go run ./cmd/dlq -consumer cart-product-created
+//	NATS_URI=nats://localhost:4222 go run ./cmd/dlq -consumer cart-product-created -replay -seq 3,4
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	nats "shared/messaging/nats"
+)
+
+func main() {
+	consumer := flag.String("consumer", "", "durable consumer name")
+	replay := flag.Bool("replay", false, "replay dead letters to their original subject")
+	seq := flag.String("seq", "", "comma separated dead-letter sequences, all when empty")
+	flag.Parse()
+
+	if *consumer == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	sequences, err := parseSequences(*seq)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	client := nats.NewNatsClient()
+	if *replay {
+		replayed, err := client.ReplayDeadLetters(*consumer, sequences...)
+		fmt.Printf("replayed %d message(s)\n", replayed)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	deadLetters, err := client.ListDeadLetters(*consumer)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, deadLetter := range deadLetters {
+		fmt.Printf(
+			"seq=%d subject=%s deliveries=%d failed_at=%s error=%q\n%s\n",
+			deadLetter.Sequence,
+			deadLetter.OriginalSubject,
+			deadLetter.Deliveries,
+			deadLetter.FailedAt.Format(time.RFC3339),
+			deadLetter.Error,
+			deadLetter.Data,
+		)
+	}
+}
+
+func parseSequences(value string) ([]uint64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.Split(value, ",")
+	sequences := make([]uint64, 0, len(parts))
+	for _, part := range parts {
+		seq, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sequence %q: %w", part, err)
+		}
+		sequences = append(sequences, seq)
+	}
+	return sequences, nil
+}
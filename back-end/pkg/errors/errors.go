@@ -7,6 +7,7 @@ type ErrorType struct {
 var (
 	ErrorTypeUnknown        = ErrorType{"unknown"}
 	ErrorTypeAuthorization  = ErrorType{"authorization"}
+	ErrorTypeForbidden      = ErrorType{"forbidden"}
 	ErrorTypeIncorrectInput = ErrorType{"incorrect-input"}
 	ErrorTypeNotFound       = ErrorType{"not-found"}
 )
@@ -45,6 +46,14 @@ func NewAuthorizationError(error string, message string) CustomError {
 	}
 }
 
+func NewForbiddenError(error string, message string) CustomError {
+	return CustomError{
+		error:     error,
+		message:   message,
+		errorType: ErrorTypeForbidden,
+	}
+}
+
 func NewNotFoundError(error string, message string) CustomError {
 	return CustomError{
 		error:     error,
@@ -1,6 +1,7 @@
 package httperrors
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	customErrors "shared/errors"
@@ -17,6 +18,10 @@ func Unauthorized(c *gin.Context, message string) {
 	httpRespondWithError(c, message, http.StatusUnauthorized)
 }
 
+func Forbidden(c *gin.Context, message string) {
+	httpRespondWithError(c, message, http.StatusForbidden)
+}
+
 func BadRequest(c *gin.Context, message string) {
 	httpRespondWithError(c, message, http.StatusBadRequest)
 }
@@ -25,10 +30,22 @@ func NotFoundRequest(c *gin.Context, message string) {
 	httpRespondWithError(c, message, http.StatusNotFound)
 }
 
+func ServiceUnavailable(c *gin.Context, message string) {
+	httpRespondWithError(c, message, http.StatusServiceUnavailable)
+}
+
 func TooManyRequests(c *gin.Context) {
 	httpRespondWithError(c, "Too Many requests", http.StatusTooManyRequests)
 }
 
+// WriteError writes the error body for handlers outside of gin, like the
+// error handler of a reverse proxy.
+func WriteError(w http.ResponseWriter, message string, status int) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Message: message, Success: false})
+}
+
 func RespondWithError(c *gin.Context, err error) {
 	log.Debug().Err(err).Msg("RespondWithError")
 	var customErrorStruct customErrors.CustomError
@@ -40,6 +57,8 @@ func RespondWithError(c *gin.Context, err error) {
 	switch customErrorStruct.ErrorType() {
 	case customErrors.ErrorTypeAuthorization:
 		Unauthorized(c, customErrorStruct.Message())
+	case customErrors.ErrorTypeForbidden:
+		Forbidden(c, customErrorStruct.Message())
 	case customErrors.ErrorTypeIncorrectInput:
 		BadRequest(c, customErrorStruct.Message())
 	case customErrors.ErrorTypeNotFound:
@@ -0,0 +1,37 @@
+package events
+
+import "shared/money"
+
+// Cart lines are identified by product and variant, VariantID is empty for
+// products without variants. AddedPrice is the price of the line when it was
+// added to the cart, it is missing from lines added before it was tracked.
+type CartProductAdded struct {
+	CustomerID string       `json:"customer_id"`
+	ProductID  string       `json:"product_id"`
+	VariantID  string       `json:"variant_id,omitempty"`
+	Quantity   int          `json:"quantity"`
+	AddedPrice *money.Money `json:"added_price,omitempty"`
+}
+
+func (CartProductAdded) EventType() string { return "carts.product_added" }
+func (CartProductAdded) EventVersion() int { return 1 }
+
+type CartProductQuantityChanged struct {
+	CustomerID string       `json:"customer_id"`
+	ProductID  string       `json:"product_id"`
+	VariantID  string       `json:"variant_id,omitempty"`
+	Quantity   int          `json:"quantity"`
+	AddedPrice *money.Money `json:"added_price,omitempty"`
+}
+
+func (CartProductQuantityChanged) EventType() string { return "carts.product_quantity_changed" }
+func (CartProductQuantityChanged) EventVersion() int { return 1 }
+
+type CartProductRemoved struct {
+	CustomerID string `json:"customer_id"`
+	ProductID  string `json:"product_id"`
+	VariantID  string `json:"variant_id,omitempty"`
+}
+
+func (CartProductRemoved) EventType() string { return "carts.product_removed" }
+func (CartProductRemoved) EventVersion() int { return 1 }
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a payload published on a NATS subject. EventType is the subject
+// and EventVersion is bumped on every change that can break a consumer.
+type Event interface {
+	EventType() string
+	EventVersion() int
+}
+
+// Envelope wraps every event published by the services.
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Producer   string          `json:"producer"`
+	Trace      TraceContext    `json:"trace,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// TraceContext carries W3C trace context headers across services.
+type TraceContext struct {
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+}
+
+type traceKey struct{}
+
+func ContextWithTrace(ctx context.Context, trace TraceContext) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+func TraceFromContext(ctx context.Context) TraceContext {
+	trace, _ := ctx.Value(traceKey{}).(TraceContext)
+	return trace
+}
+
+func NewEnvelope(ctx context.Context, producer string, event Event) (Envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("events -> NewEnvelope - json.Marshal: %w", err)
+	}
+	return Envelope{
+		ID:         uuid.New().String(),
+		Type:       event.EventType(),
+		Version:    event.EventVersion(),
+		OccurredAt: time.Now().UTC(),
+		Producer:   producer,
+		Trace:      TraceFromContext(ctx),
+		Payload:    payload,
+	}, nil
+}
+
+// DecodeEnvelope parses a message body. Bodies published before the envelope
+// was introduced are bare payloads and are treated as version 1.
+func DecodeEnvelope(subject string, data []byte) (Envelope, error) {
+	var envelope Envelope
+	err := json.Unmarshal(data, &envelope)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("events -> DecodeEnvelope - json.Unmarshal: %w", err)
+	}
+	if envelope.Type == "" || envelope.Payload == nil {
+		return Envelope{Type: subject, Version: 1, Payload: data}, nil
+	}
+	return envelope, nil
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"time"
+
+	"shared/money"
+)
+
+// ExchangeRateUpdated is published when the value of one unit of
+// BaseCurrency in Currency changes.
+type ExchangeRateUpdated struct {
+	BaseCurrency money.Currency `json:"base_currency"`
+	Currency     money.Currency `json:"currency"`
+	Rate         money.Rate     `json:"rate"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+func (ExchangeRateUpdated) EventType() string { return "exchange_rates.updated" }
+func (ExchangeRateUpdated) EventVersion() int { return 1 }
+
+type ExchangeRateDeleted struct {
+	Currency  money.Currency `json:"currency"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (ExchangeRateDeleted) EventType() string { return "exchange_rates.deleted" }
+func (ExchangeRateDeleted) EventVersion() int { return 1 }
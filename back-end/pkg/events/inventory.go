@@ -0,0 +1,37 @@
+package events
+
+import "time"
+
+// Reasons carried by StockReservationFailed and StockReleased.
+const (
+	ReasonInsufficientStock  = "insufficient_stock"
+	ReasonProductNotFound    = "product_not_found"
+	ReasonReservationExpired = "reservation_expired"
+	ReasonOrderCancelled     = "order_cancelled"
+)
+
+type StockReserved struct {
+	OrderID   string    `json:"order_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (StockReserved) EventType() string { return "inventory.stock_reserved" }
+func (StockReserved) EventVersion() int { return 1 }
+
+type StockReservationFailed struct {
+	OrderID   string `json:"order_id"`
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+func (StockReservationFailed) EventType() string { return "inventory.stock_reservation_failed" }
+func (StockReservationFailed) EventVersion() int { return 1 }
+
+type StockReleased struct {
+	OrderID string `json:"order_id"`
+	Reason  string `json:"reason"`
+}
+
+func (StockReleased) EventType() string { return "inventory.stock_released" }
+func (StockReleased) EventVersion() int { return 1 }
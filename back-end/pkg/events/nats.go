@@ -0,0 +1,141 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	natsClient "shared/messaging/nats"
+	"shared/outbox"
+
+	"github.com/nats-io/nats.go"
+)
+
+var ErrUnsupportedVersion = errors.New("unsupported event version")
+
+// NewOutboxMessage wraps event in an envelope. The envelope ID doubles as
+// the outbox message ID and therefore as the NATS message ID.
+func NewOutboxMessage(ctx context.Context, producer string, event Event) (outbox.Message, error) {
+	envelope, err := NewEnvelope(ctx, producer, event)
+	if err != nil {
+		return outbox.Message{}, err
+	}
+	message, err := outbox.NewMessage(envelope.Type, envelope)
+	if err != nil {
+		return outbox.Message{}, fmt.Errorf("events -> NewOutboxMessage - outbox.NewMessage: %w", err)
+	}
+	message.ID = envelope.ID
+	return message, nil
+}
+
+// Publish sends event straight to JetStream, bypassing the outbox.
+func Publish(ctx context.Context, publisher outbox.Publisher, producer string, event Event) error {
+	envelope, err := NewEnvelope(ctx, producer, event)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("events -> Publish - json.Marshal: %w", err)
+	}
+	return publisher.PublishMessageWithID(envelope.Type, envelope.ID, bytes)
+}
+
+// Subscribe decodes messages of T's subject and passes them to handler. The
+// handler context carries the producer's trace context. Legacy messages
+// without an envelope are identified by their NATS message id, or by their
+// stream sequence when they were published without one.
+func Subscribe[T Event](
+	client natsClient.NatsClient,
+	streamName string,
+	consumerName string,
+	handler func(ctx context.Context, event T, envelope Envelope) error,
+	opts ...natsClient.SubscribeOption,
+) {
+	typed := Handle(handler)
+	client.SubscribeDurable(typed.eventType, streamName, consumerName, func(m *nats.Msg) error {
+		envelope, err := decodeMessage(m)
+		if err != nil {
+			return err
+		}
+		return typed.handle(envelope)
+	}, opts...)
+}
+
+// Handler handles the events of one type, see SubscribeAll.
+type Handler struct {
+	eventType string
+	handle    func(envelope Envelope) error
+}
+
+// Handle decodes the envelopes of T for handler.
+func Handle[T Event](handler func(ctx context.Context, event T, envelope Envelope) error) Handler {
+	var zero T
+	return Handler{
+		eventType: zero.EventType(),
+		handle: func(envelope Envelope) error {
+			if envelope.Version > zero.EventVersion() {
+				return fmt.Errorf("events -> Subscribe - %s v%d: %w", envelope.Type, envelope.Version, ErrUnsupportedVersion)
+			}
+			var event T
+			err := json.Unmarshal(envelope.Payload, &event)
+			if err != nil {
+				return fmt.Errorf("events -> Subscribe - json.Unmarshal(%s): %w", envelope.Type, err)
+			}
+			ctx := ContextWithTrace(context.Background(), envelope.Trace)
+			return handler(ctx, event, envelope)
+		},
+	}
+}
+
+// SubscribeAll passes the events of subject, a wildcard, to the handler of
+// their type through a single consumer. With natsClient.MaxAckPending(1) the
+// events are handled in the order they were published. Events without a
+// handler are acked and skipped.
+func SubscribeAll(
+	client natsClient.NatsClient,
+	streamName string,
+	consumerName string,
+	subject string,
+	handlers []Handler,
+	opts ...natsClient.SubscribeOption,
+) {
+	byType := make(map[string]Handler, len(handlers))
+	for _, handler := range handlers {
+		byType[handler.eventType] = handler
+	}
+	client.SubscribeDurable(subject, streamName, consumerName, func(m *nats.Msg) error {
+		envelope, err := decodeMessage(m)
+		if err != nil {
+			return err
+		}
+		handler, ok := byType[envelope.Type]
+		if !ok {
+			return nil
+		}
+		return handler.handle(envelope)
+	}, opts...)
+}
+
+func decodeMessage(m *nats.Msg) (Envelope, error) {
+	envelope, err := DecodeEnvelope(m.Subject, m.Data)
+	if err != nil {
+		return Envelope{}, err
+	}
+	if envelope.ID == "" {
+		envelope.ID = legacyMessageID(m)
+	}
+	return envelope, nil
+}
+
+func legacyMessageID(m *nats.Msg) string {
+	if id := m.Header.Get(nats.MsgIdHdr); id != "" {
+		return id
+	}
+	meta, err := m.Metadata()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", meta.Stream, meta.Sequence.Stream)
+}
@@ -0,0 +1,75 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"shared/events"
+	natsClient "shared/messaging/nats"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subscriber keeps the handler of the durable subscription.
+type subscriber struct {
+	natsClient.NatsClient
+	subject string
+	handler func(m *nats.Msg) error
+}
+
+func (s *subscriber) SubscribeDurable(subject string, _ string, _ string, handler func(m *nats.Msg) error, _ ...natsClient.SubscribeOption) {
+	s.subject, s.handler = subject, handler
+}
+
+func (s *subscriber) deliver(t *testing.T, event events.Event) error {
+	envelope, err := events.NewEnvelope(context.Background(), "cart", event)
+	require.NoError(t, err)
+	data, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	return s.handler(&nats.Msg{Subject: event.EventType(), Data: data})
+}
+
+func TestSubscribeAll(t *testing.T) {
+	t.Parallel()
+	client := &subscriber{}
+	var handled []string
+	events.SubscribeAll(client, "carts", "orders-carts", "carts.*", []events.Handler{
+		events.Handle(func(ctx context.Context, event events.CartProductAdded, envelope events.Envelope) error {
+			handled = append(handled, "added "+event.ProductID)
+			return nil
+		}),
+		events.Handle(func(ctx context.Context, event events.CartProductRemoved, envelope events.Envelope) error {
+			handled = append(handled, "removed "+event.ProductID)
+			return nil
+		}),
+	})
+
+	assert.Equal(t, "carts.*", client.subject)
+	require.NoError(t, client.deliver(t, events.CartProductAdded{ProductID: "p1", Quantity: 1}))
+	// events without a handler are skipped
+	require.NoError(t, client.deliver(t, events.CartProductQuantityChanged{ProductID: "p1", Quantity: 2}))
+	require.NoError(t, client.deliver(t, events.CartProductRemoved{ProductID: "p1"}))
+	assert.Equal(t, []string{"added p1", "removed p1"}, handled)
+}
+
+type futureCartProductAdded struct {
+	events.CartProductAdded
+}
+
+func (futureCartProductAdded) EventVersion() int { return 2 }
+
+func TestSubscribeAll_UnsupportedVersion(t *testing.T) {
+	t.Parallel()
+	client := &subscriber{}
+	events.SubscribeAll(client, "carts", "orders-carts", "carts.*", []events.Handler{
+		events.Handle(func(ctx context.Context, event events.CartProductAdded, envelope events.Envelope) error {
+			return nil
+		}),
+	})
+
+	err := client.deliver(t, futureCartProductAdded{events.CartProductAdded{ProductID: "p1"}})
+	assert.ErrorIs(t, err, events.ErrUnsupportedVersion)
+}
@@ -0,0 +1,10 @@
+package events
+
+type NotificationCreated struct {
+	NotificationTypeID string      `json:"notificationTypeID"`
+	UserID             string      `json:"userID"`
+	Data               interface{} `json:"data,omitempty"`
+}
+
+func (NotificationCreated) EventType() string { return "notifications.created" }
+func (NotificationCreated) EventVersion() int { return 1 }
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"time"
+
+	"shared/money"
+)
+
+type OrderItem struct {
+	ProductID string      `json:"product_id"`
+	VariantID string      `json:"variant_id,omitempty"`
+	SKU       string      `json:"sku,omitempty"`
+	Name      string      `json:"name"`
+	Price     money.Money `json:"price"`
+	Quantity  int         `json:"quantity"`
+}
+
+type OrderCreated struct {
+	ID         string      `json:"id"`
+	CustomerID string      `json:"customer_id"`
+	Items      []OrderItem `json:"items"`
+	TotalPrice money.Money `json:"total_price"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+func (OrderCreated) EventType() string { return "orders.created" }
+func (OrderCreated) EventVersion() int { return 2 }
+
+type OrderConfirmed struct {
+	ID         string `json:"id"`
+	CustomerID string `json:"customer_id"`
+}
+
+func (OrderConfirmed) EventType() string { return "orders.confirmed" }
+func (OrderConfirmed) EventVersion() int { return 1 }
+
+type OrderCancelled struct {
+	ID         string `json:"id"`
+	CustomerID string `json:"customer_id"`
+	Reason     string `json:"reason"`
+}
+
+func (OrderCancelled) EventType() string { return "orders.cancelled" }
+func (OrderCancelled) EventVersion() int { return 1 }
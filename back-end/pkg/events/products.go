@@ -0,0 +1,87 @@
+package events
+
+import (
+	"time"
+
+	"shared/money"
+)
+
+type ProductCreated struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	SellerID  string         `json:"seller_id,omitempty"`
+	Price     money.Money    `json:"price"`
+	Quantity  int            `json:"quantity"`
+	Images    []ProductImage `json:"images,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (ProductCreated) EventType() string { return "products.created" }
+func (ProductCreated) EventVersion() int { return 2 }
+
+type ProductUpdated struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	SellerID  string         `json:"seller_id,omitempty"`
+	Price     money.Money    `json:"price"`
+	Quantity  int            `json:"quantity"`
+	Images    []ProductImage `json:"images,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (ProductUpdated) EventType() string { return "products.updated" }
+func (ProductUpdated) EventVersion() int { return 2 }
+
+// ProductImage links an uploaded image of a product and its thumbnail.
+type ProductImage struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+type ProductDeleted struct {
+	ID string `json:"id"`
+}
+
+func (ProductDeleted) EventType() string { return "products.deleted" }
+func (ProductDeleted) EventVersion() int { return 1 }
+
+// ProductVariantCreated and ProductVariantUpdated carry the whole variant.
+// Attributes are keyed by attribute code, e.g. {"size": "M"}.
+type ProductVariantCreated struct {
+	ID         string            `json:"id"`
+	ProductID  string            `json:"product_id"`
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      money.Money       `json:"price"`
+	Quantity   int               `json:"quantity"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+func (ProductVariantCreated) EventType() string { return "products.variant_created" }
+func (ProductVariantCreated) EventVersion() int { return 1 }
+
+type ProductVariantUpdated struct {
+	ID         string            `json:"id"`
+	ProductID  string            `json:"product_id"`
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      money.Money       `json:"price"`
+	Quantity   int               `json:"quantity"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+func (ProductVariantUpdated) EventType() string { return "products.variant_updated" }
+func (ProductVariantUpdated) EventVersion() int { return 1 }
+
+type ProductVariantDeleted struct {
+	ID        string `json:"id"`
+	ProductID string `json:"product_id"`
+}
+
+func (ProductVariantDeleted) EventType() string { return "products.variant_deleted" }
+func (ProductVariantDeleted) EventVersion() int { return 1 }
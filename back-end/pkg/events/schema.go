@@ -0,0 +1,207 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"shared/money"
+)
+
+// Registry lists every event published by the services. Its schemas are
+// pinned in testdata/schemas and checked for compatibility on every test run.
+func Registry() []Event {
+	return []Event{
+		ProductCreated{},
+		ProductUpdated{},
+		ProductDeleted{},
+		ProductVariantCreated{},
+		ProductVariantUpdated{},
+		ProductVariantDeleted{},
+		UserCreated{},
+		UserUpdated{},
+		UserDeleted{},
+		UserPasswordChanged{},
+		UserLoggedOut{},
+		NotificationCreated{},
+		CartProductAdded{},
+		CartProductQuantityChanged{},
+		CartProductRemoved{},
+		OrderCreated{},
+		OrderConfirmed{},
+		OrderCancelled{},
+		StockReserved{},
+		StockReservationFailed{},
+		StockReleased{},
+		ExchangeRateUpdated{},
+		ExchangeRateDeleted{},
+	}
+}
+
+const (
+	KindString  = "string"
+	KindInteger = "integer"
+	KindNumber  = "number"
+	KindBoolean = "boolean"
+	KindTime    = "time"
+	KindObject  = "object"
+	KindArray   = "array"
+	KindAny     = "any"
+)
+
+// Schema describes the JSON shape of an event payload.
+type Schema struct {
+	Type    string           `json:"type"`
+	Version int              `json:"version"`
+	Fields  map[string]Field `json:"fields"`
+}
+
+type Field struct {
+	Kind     string           `json:"kind"`
+	Required bool             `json:"required"`
+	Fields   map[string]Field `json:"fields,omitempty"`
+	Items    *Field           `json:"items,omitempty"`
+}
+
+// SchemaOf derives the schema of a payload from its JSON struct tags.
+// Fields tagged omitempty are optional.
+func SchemaOf(event Event) Schema {
+	return Schema{
+		Type:    event.EventType(),
+		Version: event.EventVersion(),
+		Fields:  fieldsOf(reflect.TypeOf(event)),
+	}
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	moneyType = reflect.TypeOf(money.Money{})
+	rateType  = reflect.TypeOf(money.Rate{})
+)
+
+func fieldsOf(t reflect.Type) map[string]Field {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields := map[string]Field{}
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = structField.Name
+		}
+		field := fieldOf(structField.Type)
+		field.Required = !strings.Contains(options, "omitempty") && structField.Type.Kind() != reflect.Pointer
+		fields[name] = field
+	}
+	return fields
+}
+
+func fieldOf(t reflect.Type) Field {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return Field{Kind: KindTime}
+	}
+	if t == rateType {
+		return Field{Kind: KindString}
+	}
+	if t == moneyType {
+		return Field{Kind: KindObject, Fields: map[string]Field{
+			"amount":   {Kind: KindString, Required: true},
+			"currency": {Kind: KindString, Required: true},
+		}}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return Field{Kind: KindString}
+	case reflect.Bool:
+		return Field{Kind: KindBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Field{Kind: KindInteger}
+	case reflect.Float32, reflect.Float64:
+		return Field{Kind: KindNumber}
+	case reflect.Struct:
+		return Field{Kind: KindObject, Fields: fieldsOf(t)}
+	case reflect.Map:
+		return Field{Kind: KindObject}
+	case reflect.Slice, reflect.Array:
+		items := fieldOf(t.Elem())
+		return Field{Kind: KindArray, Items: &items}
+	default:
+		return Field{Kind: KindAny}
+	}
+}
+
+// CheckCompatibility reports every way in which messages written with the
+// producer schema cannot be read by a consumer built against the consumer schema.
+func CheckCompatibility(producer Schema, consumer Schema) error {
+	if producer.Type != consumer.Type {
+		return fmt.Errorf("event type %q does not match %q", producer.Type, consumer.Type)
+	}
+	if producer.Version != consumer.Version {
+		return fmt.Errorf("%s: version %d does not match %d", producer.Type, producer.Version, consumer.Version)
+	}
+	violations := checkFields(producer.Type, producer.Fields, consumer.Fields)
+	return errors.Join(violations...)
+}
+
+func checkFields(path string, producer map[string]Field, consumer map[string]Field) []error {
+	names := make([]string, 0, len(consumer))
+	for name := range consumer {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []error
+	for _, name := range names {
+		consumerField := consumer[name]
+		fieldPath := path + "." + name
+		producerField, ok := producer[name]
+		if !ok {
+			if consumerField.Required {
+				violations = append(violations, fmt.Errorf("%s: required field was removed", fieldPath))
+			}
+			continue
+		}
+		if consumerField.Required && !producerField.Required {
+			violations = append(violations, fmt.Errorf("%s: required field became optional", fieldPath))
+		}
+		violations = append(violations, checkField(fieldPath, producerField, consumerField)...)
+	}
+	return violations
+}
+
+func checkField(path string, producer Field, consumer Field) []error {
+	if !kindCompatible(producer.Kind, consumer.Kind) {
+		return []error{fmt.Errorf("%s: kind changed from %s to %s", path, consumer.Kind, producer.Kind)}
+	}
+	switch consumer.Kind {
+	case KindObject:
+		return checkFields(path, producer.Fields, consumer.Fields)
+	case KindArray:
+		if producer.Items != nil && consumer.Items != nil {
+			return checkField(path+"[]", *producer.Items, *consumer.Items)
+		}
+	}
+	return nil
+}
+
+func kindCompatible(producer string, consumer string) bool {
+	if producer == consumer || consumer == KindAny {
+		return true
+	}
+	// every JSON integer is a valid number
+	return producer == KindInteger && consumer == KindNumber
+}
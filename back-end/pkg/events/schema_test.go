@@ -0,0 +1,207 @@
+package events_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"shared/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "write schemas of new event versions to testdata")
+
+func schemaPath(schema events.Schema) string {
+	return filepath.Join("testdata", "schemas", fmt.Sprintf("%s.v%d.json", schema.Type, schema.Version))
+}
+
+// Every published event version is pinned in testdata. A producer change that
+// breaks consumers of a pinned version must bump the event version instead.
+func TestRegistry_ProducersAreCompatibleWithPinnedSchemas(t *testing.T) {
+	t.Parallel()
+	for _, event := range events.Registry() {
+		current := events.SchemaOf(event)
+		t.Run(fmt.Sprintf("%s.v%d", current.Type, current.Version), func(t *testing.T) {
+			t.Parallel()
+			path := schemaPath(current)
+			bytes, err := os.ReadFile(path)
+			if errors.Is(err, os.ErrNotExist) && *update {
+				bytes, err = json.MarshalIndent(current, "", "  ")
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, append(bytes, '\n'), 0o644))
+				return
+			}
+			require.NoError(t, err, "schema is not pinned, run `go test ./events -update`")
+
+			var pinned events.Schema
+			require.NoError(t, json.Unmarshal(bytes, &pinned))
+			require.NoError(t, events.CheckCompatibility(current, pinned))
+		})
+	}
+}
+
+type consumerProduct struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name,omitempty"`
+	Price float64 `json:"price"`
+}
+
+func (consumerProduct) EventType() string { return "products.created" }
+func (consumerProduct) EventVersion() int { return 1 }
+
+type producerProduct struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Quantity int     `json:"quantity"`
+}
+
+func (producerProduct) EventType() string { return "products.created" }
+func (producerProduct) EventVersion() int { return 1 }
+
+type producerWithoutPrice struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (producerWithoutPrice) EventType() string { return "products.created" }
+func (producerWithoutPrice) EventVersion() int { return 1 }
+
+type producerWithStringPrice struct {
+	ID    string `json:"id"`
+	Price string `json:"price"`
+}
+
+func (producerWithStringPrice) EventType() string { return "products.created" }
+func (producerWithStringPrice) EventVersion() int { return 1 }
+
+type producerWithOptionalPrice struct {
+	ID    string `json:"id"`
+	Price int    `json:"price,omitempty"`
+}
+
+func (producerWithOptionalPrice) EventType() string { return "products.created" }
+func (producerWithOptionalPrice) EventVersion() int { return 1 }
+
+type producerWithIntegerPrice struct {
+	ID       string `json:"id"`
+	Price    int    `json:"price"`
+	Quantity int    `json:"quantity"`
+}
+
+func (producerWithIntegerPrice) EventType() string { return "products.created" }
+func (producerWithIntegerPrice) EventVersion() int { return 1 }
+
+type producerV2 struct {
+	ID    string  `json:"id"`
+	Price float64 `json:"price"`
+}
+
+func (producerV2) EventType() string { return "products.created" }
+func (producerV2) EventVersion() int { return 2 }
+
+func TestCheckCompatibility(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		producer events.Event
+		consumer events.Event
+		wantErr  bool
+	}{
+		{
+			name:     "same_schema",
+			producer: events.ProductCreated{},
+			consumer: events.ProductCreated{},
+		},
+		{
+			name:     "consumer_reads_subset",
+			producer: producerProduct{},
+			consumer: consumerProduct{},
+		},
+		{
+			name:     "added_field_and_integer_read_as_number",
+			producer: producerWithIntegerPrice{},
+			consumer: consumerProduct{},
+		},
+		{
+			name:     "removed_required_field",
+			producer: producerWithoutPrice{},
+			consumer: consumerProduct{},
+			wantErr:  true,
+		},
+		{
+			name:     "changed_kind",
+			producer: producerWithStringPrice{},
+			consumer: consumerProduct{},
+			wantErr:  true,
+		},
+		{
+			name:     "required_field_became_optional",
+			producer: producerWithOptionalPrice{},
+			consumer: consumerProduct{},
+			wantErr:  true,
+		},
+		{
+			name:     "different_version",
+			producer: producerV2{},
+			consumer: consumerProduct{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := events.CheckCompatibility(events.SchemaOf(tc.producer), events.SchemaOf(tc.consumer))
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		data        string
+		wantType    string
+		wantVersion int
+		wantPayload string
+	}{
+		{
+			name:        "envelope",
+			data:        `{"id":"1","type":"users.deleted","version":1,"producer":"authentication","payload":{"id":"2"}}`,
+			wantType:    "users.deleted",
+			wantVersion: 1,
+			wantPayload: `{"id":"2"}`,
+		},
+		{
+			name:        "legacy_bare_payload",
+			data:        `{"id":"2"}`,
+			wantType:    "users.deleted",
+			wantVersion: 1,
+			wantPayload: `{"id":"2"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			envelope, err := events.DecodeEnvelope("users.deleted", []byte(tc.data))
+			require.NoError(t, err)
+			require.Equal(t, tc.wantType, envelope.Type)
+			require.Equal(t, tc.wantVersion, envelope.Version)
+			require.JSONEq(t, tc.wantPayload, string(envelope.Payload))
+		})
+	}
+}
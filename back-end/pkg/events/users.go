@@ -0,0 +1,42 @@
+package events
+
+type UserCreated struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	ID    string `json:"id"`
+}
+
+func (UserCreated) EventType() string { return "users.created" }
+func (UserCreated) EventVersion() int { return 1 }
+
+type UserUpdated struct {
+	Name  string   `json:"name"`
+	ID    string   `json:"id"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+func (UserUpdated) EventType() string { return "users.updated" }
+func (UserUpdated) EventVersion() int { return 1 }
+
+type UserDeleted struct {
+	ID string `json:"id"`
+}
+
+func (UserDeleted) EventType() string { return "users.deleted" }
+func (UserDeleted) EventVersion() int { return 1 }
+
+type UserPasswordChanged struct {
+	ID string `json:"id"`
+}
+
+func (UserPasswordChanged) EventType() string { return "users.password_changed" }
+func (UserPasswordChanged) EventVersion() int { return 1 }
+
+// UserLoggedOut is published when a session of the user ends.
+type UserLoggedOut struct {
+	ID        string `json:"id"`
+	SessionID string `json:"session_id"`
+}
+
+func (UserLoggedOut) EventType() string { return "users.logged_out" }
+func (UserLoggedOut) EventVersion() int { return 1 }
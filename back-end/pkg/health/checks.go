@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping checks a *bun.DB or *sql.DB.
+func Ping(db Pinger) Checker {
+	return CheckerFunc(db.PingContext)
+}
+
+type ConnectionStatus interface {
+	IsConnected() bool
+}
+
+var ErrDisconnected = errors.New("disconnected")
+
+// Connection checks that a client, such as the NATS client, is connected.
+func Connection(client ConnectionStatus) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		if !client.IsConnected() {
+			return ErrDisconnected
+		}
+		return nil
+	})
+}
+
+type LagReporter interface {
+	ConsumersPending() (map[string]uint64, error)
+}
+
+// ConsumerLag fails when any durable consumer has more than maxPending
+// messages waiting to be delivered. The consumers of a stream are shared by
+// the instances of a service, so it is meant for AddDegradedCheck.
+func ConsumerLag(reporter LagReporter, maxPending uint64) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		pending, err := reporter.ConsumersPending()
+		if err != nil {
+			return err
+		}
+		var lagging []string
+		for consumer, n := range pending {
+			if n > maxPending {
+				lagging = append(lagging, fmt.Sprintf("%s has %d pending messages", consumer, n))
+			}
+		}
+		if len(lagging) > 0 {
+			sort.Strings(lagging)
+			return errors.New(strings.Join(lagging, ", "))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,180 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const _defaultTimeout = 2 * time.Second
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	// the instance serves requests but a check reports a problem
+	StatusDegraded = "degraded"
+)
+
+var ErrNotRunning = errors.New("service is not running")
+
+// Checker reports whether a dependency is usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Probe exposes the lifecycle state of the service, see lifecycle.Runner.
+type Probe interface {
+	Ready() bool
+	Live() bool
+}
+
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+	// a failure degrades the report instead of failing it
+	degrades bool
+}
+
+type Health struct {
+	probe     Probe
+	timeout   time.Duration
+	mu        sync.RWMutex
+	liveness  []namedChecker
+	readiness []namedChecker
+}
+
+func NewHealth(probe Probe, opts ...Option) *Health {
+	h := &Health{probe: probe, timeout: _defaultTimeout}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// AddLivenessCheck registers a check that, when failing, means the process
+// must be restarted. Dependencies that can recover on their own belong in
+// readiness checks instead.
+func (h *Health) AddLivenessCheck(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.liveness = append(h.liveness, namedChecker{name: name, checker: checker})
+}
+
+// AddReadinessCheck registers a check that, when failing, takes the instance
+// out of rotation.
+func (h *Health) AddReadinessCheck(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readiness = append(h.readiness, namedChecker{name: name, checker: checker})
+}
+
+// AddDegradedCheck registers a check that, when failing, reports the
+// instance as degraded on /readyz while it stays in rotation. It suits
+// problems every instance shares, taking them all out of rotation would
+// not help.
+func (h *Health) AddDegradedCheck(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readiness = append(h.readiness, namedChecker{name: name, checker: checker, degrades: true})
+}
+
+// Register exposes GET /livez and GET /readyz. GET /health is kept as an
+// alias of /livez for existing probes.
+func (h *Health) Register(handler gin.IRoutes) {
+	handler.GET("/livez", h.Livez)
+	handler.GET("/readyz", h.Readyz)
+	handler.GET("/health", h.Livez)
+}
+
+func (h *Health) Livez(c *gin.Context) {
+	h.mu.RLock()
+	checkers := h.liveness
+	h.mu.RUnlock()
+	respond(c, h.run(c.Request.Context(), h.probe.Live, checkers))
+}
+
+func (h *Health) Readyz(c *gin.Context) {
+	h.mu.RLock()
+	checkers := h.readiness
+	h.mu.RUnlock()
+	respond(c, h.run(c.Request.Context(), h.probe.Ready, checkers))
+}
+
+func respond(c *gin.Context, report Report) {
+	status := http.StatusOK
+	if report.Status == StatusUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+func (h *Health) run(ctx context.Context, state func() bool, checkers []namedChecker) Report {
+	lifecycle := func(ctx context.Context) error {
+		if !state() {
+			return ErrNotRunning
+		}
+		return nil
+	}
+	checkers = append([]namedChecker{{name: "lifecycle", checker: CheckerFunc(lifecycle)}}, checkers...)
+
+	results := make([]CheckResult, len(checkers))
+	var wg sync.WaitGroup
+	for i, nc := range checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			results[i] = h.check(ctx, checker)
+		}(i, nc.checker)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checkers))}
+	for i, nc := range checkers {
+		if nc.degrades && results[i].Status != StatusOK {
+			results[i].Status = StatusDegraded
+		}
+		report.Checks[nc.name] = results[i]
+		switch {
+		case results[i].Status == StatusUnavailable:
+			report.Status = StatusUnavailable
+		case results[i].Status == StatusDegraded && report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (h *Health) check(ctx context.Context, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := CheckResult{Status: StatusOK, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"shared/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type probe struct {
+	ready bool
+	live  bool
+}
+
+func (p probe) Ready() bool { return p.ready }
+func (p probe) Live() bool  { return p.live }
+
+type lag map[string]uint64
+
+func (l lag) ConsumersPending() (map[string]uint64, error) { return l, nil }
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	failing := health.CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+	passing := health.CheckerFunc(func(ctx context.Context) error { return nil })
+
+	testCases := []struct {
+		name       string
+		probe      probe
+		readiness  map[string]health.Checker
+		degraded   map[string]health.Checker
+		path       string
+		wantStatus int
+		// the status of the report, derived from wantStatus when empty
+		wantReport string
+		wantChecks map[string]string
+	}{
+		{
+			name:       "ready",
+			probe:      probe{ready: true, live: true},
+			readiness:  map[string]health.Checker{"postgres": passing},
+			path:       "/readyz",
+			wantStatus: http.StatusOK,
+			wantChecks: map[string]string{"lifecycle": health.StatusOK, "postgres": health.StatusOK},
+		},
+		{
+			name:       "dependency_down",
+			probe:      probe{ready: true, live: true},
+			readiness:  map[string]health.Checker{"postgres": failing, "nats": passing},
+			path:       "/readyz",
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"lifecycle": health.StatusOK, "postgres": health.StatusUnavailable, "nats": health.StatusOK},
+		},
+		{
+			name:       "shutting_down",
+			probe:      probe{ready: false, live: true},
+			path:       "/readyz",
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"lifecycle": health.StatusUnavailable},
+		},
+		{
+			name:       "live_while_dependency_down",
+			probe:      probe{ready: true, live: true},
+			readiness:  map[string]health.Checker{"postgres": failing},
+			path:       "/livez",
+			wantStatus: http.StatusOK,
+			wantChecks: map[string]string{"lifecycle": health.StatusOK},
+		},
+		{
+			name:       "consumer_lagging",
+			probe:      probe{ready: true, live: true},
+			readiness:  map[string]health.Checker{"postgres": passing},
+			degraded:   map[string]health.Checker{"consumer lag": health.ConsumerLag(lag{"cart-product-created": 11, "cart-user-create": 0}, 10)},
+			path:       "/readyz",
+			wantStatus: http.StatusOK,
+			wantReport: health.StatusDegraded,
+			wantChecks: map[string]string{"lifecycle": health.StatusOK, "postgres": health.StatusOK, "consumer lag": health.StatusDegraded},
+		},
+		{
+			name:       "consumer_caught_up",
+			probe:      probe{ready: true, live: true},
+			degraded:   map[string]health.Checker{"consumer lag": health.ConsumerLag(lag{"cart-product-created": 10}, 10)},
+			path:       "/readyz",
+			wantStatus: http.StatusOK,
+			wantChecks: map[string]string{"lifecycle": health.StatusOK, "consumer lag": health.StatusOK},
+		},
+		{
+			name:       "degraded_and_dependency_down",
+			probe:      probe{ready: true, live: true},
+			readiness:  map[string]health.Checker{"postgres": failing},
+			degraded:   map[string]health.Checker{"consumer lag": failing},
+			path:       "/readyz",
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"lifecycle": health.StatusOK, "postgres": health.StatusUnavailable, "consumer lag": health.StatusDegraded},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			checks := health.NewHealth(tc.probe)
+			for name, checker := range tc.readiness {
+				checks.AddReadinessCheck(name, checker)
+			}
+			for name, checker := range tc.degraded {
+				checks.AddDegradedCheck(name, checker)
+			}
+			handler := gin.New()
+			checks.Register(handler)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			require.Equal(t, tc.wantStatus, w.Code)
+
+			var report health.Report
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			wantReport := tc.wantReport
+			if wantReport == "" {
+				wantReport = health.StatusOK
+				if tc.wantStatus != http.StatusOK {
+					wantReport = health.StatusUnavailable
+				}
+			}
+			require.Equal(t, wantReport, report.Status)
+			got := map[string]string{}
+			for name, result := range report.Checks {
+				got[name] = result.Status
+			}
+			require.Equal(t, tc.wantChecks, got)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"shared/events"
+)
+
+var ErrMissingMessageID = errors.New("message has no id")
+
+// Inbox records which messages a consumer has already applied so that
+// redelivered messages are acknowledged without being applied twice.
+type Inbox interface {
+	// Process runs fn unless the consumer already processed messageID. The
+	// message is marked processed in the same transaction as fn, so a failing
+	// fn leaves it unmarked and the redelivery is applied again.
+	Process(ctx context.Context, consumer string, messageID string, fn func(ctx context.Context) error) error
+}
+
+// Idempotent wraps an events.Subscribe handler so that each envelope is
+// applied at most once per consumer.
+func Idempotent[T events.Event](
+	in Inbox,
+	consumer string,
+	handler func(ctx context.Context, event T, envelope events.Envelope) error,
+) func(ctx context.Context, event T, envelope events.Envelope) error {
+	return func(ctx context.Context, event T, envelope events.Envelope) error {
+		if envelope.ID == "" {
+			return fmt.Errorf("inbox -> Idempotent - %s: %w", envelope.Type, ErrMissingMessageID)
+		}
+		return in.Process(ctx, consumer, envelope.ID, func(ctx context.Context) error {
+			return handler(ctx, event, envelope)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package inbox_test
+
+import (
+	"context"
+	"testing"
+
+	"shared/events"
+	"shared/inbox"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryInbox map[string]bool
+
+func (m memoryInbox) Process(ctx context.Context, consumer string, messageID string, fn func(ctx context.Context) error) error {
+	key := consumer + "/" + messageID
+	if m[key] {
+		return nil
+	}
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	m[key] = true
+	return nil
+}
+
+func TestIdempotent(t *testing.T) {
+	t.Parallel()
+	applied := 0
+	handler := inbox.Idempotent(memoryInbox{}, "test-consumer", func(ctx context.Context, event events.UserCreated, envelope events.Envelope) error {
+		applied++
+		return nil
+	})
+
+	ctx := context.Background()
+	require.NoError(t, handler(ctx, events.UserCreated{}, events.Envelope{ID: "1"}))
+	require.NoError(t, handler(ctx, events.UserCreated{}, events.Envelope{ID: "1"}))
+	require.NoError(t, handler(ctx, events.UserCreated{}, events.Envelope{ID: "2"}))
+	require.Equal(t, 2, applied)
+
+	err := handler(ctx, events.UserCreated{}, events.Envelope{})
+	require.ErrorIs(t, err, inbox.ErrMissingMessageID)
+	require.Equal(t, 2, applied)
+}
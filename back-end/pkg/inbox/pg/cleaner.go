@@ -0,0 +1,96 @@
+package inboxpg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+)
+
+const (
+	defaultCleanupInterval = 1 * time.Hour
+	// well past the redeliveries and dead-letter replays of a message
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+// Cleaner deletes the processed messages older than the retention until its
+// context is cancelled. A message redelivered after it was deleted is applied
+// again.
+type Cleaner struct {
+	db        *bun.DB
+	logger    zerolog.Logger
+	interval  time.Duration
+	retention time.Duration
+}
+
+// Option -.
+type Option func(*Cleaner)
+
+// CleanupInterval -.
+func CleanupInterval(interval time.Duration) Option {
+	return func(c *Cleaner) {
+		c.interval = interval
+	}
+}
+
+// Retention is how long a processed message is kept.
+func Retention(retention time.Duration) Option {
+	return func(c *Cleaner) {
+		c.retention = retention
+	}
+}
+
+func NewCleaner(db *bun.DB, logger zerolog.Logger, opts ...Option) *Cleaner {
+	c := &Cleaner{
+		db:        db,
+		logger:    logger,
+		interval:  defaultCleanupInterval,
+		retention: defaultRetention,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Cleaner) Run(ctx context.Context) {
+	c.logger.Info().Msg("inbox cleaner started")
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := c.Clean(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Error().Err(err).Msg("Cleaner -> Run - c.Clean")
+			}
+		} else if deleted > 0 {
+			c.logger.Info().Int64("deleted", deleted).Msg("Deleted processed messages")
+		}
+		select {
+		case <-ctx.Done():
+			c.logger.Info().Msg("inbox cleaner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Clean deletes the messages processed before the retention and returns how
+// many it deleted.
+func (c *Cleaner) Clean(ctx context.Context) (int64, error) {
+	res, err := c.db.NewDelete().
+		Model((*ProcessedMessageModel)(nil)).
+		Where("processed_at < ?", time.Now().UTC().Add(-c.retention)).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("Cleaner -> Clean - c.db.NewDelete: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("Cleaner -> Clean - res.RowsAffected: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,54 @@
+package inboxpg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"shared/inbox"
+	pgStorage "shared/storage/pg"
+
+	"github.com/uptrace/bun"
+)
+
+var _ inbox.Inbox = (*pgInbox)(nil)
+
+type ProcessedMessageModel struct {
+	bun.BaseModel `bun:"table:processed_messages,alias:pm"`
+
+	Consumer    string    `bun:"consumer,pk"`
+	MessageID   string    `bun:"message_id,pk"`
+	ProcessedAt time.Time `bun:"processed_at"`
+}
+
+type pgInbox struct {
+	db         *bun.DB
+	transactor pgStorage.Transactor
+}
+
+func NewInbox(db *bun.DB, transactor pgStorage.Transactor) *pgInbox {
+	return &pgInbox{db: db, transactor: transactor}
+}
+
+func (i *pgInbox) Process(ctx context.Context, consumer string, messageID string, fn func(ctx context.Context) error) error {
+	return i.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		model := ProcessedMessageModel{
+			Consumer:    consumer,
+			MessageID:   messageID,
+			ProcessedAt: time.Now().UTC(),
+		}
+		res, err := pgStorage.Conn(ctx, i.db).NewInsert().Model(&model).On("CONFLICT DO NOTHING").Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("pgInbox -> Process - i.db.NewInsert: %w", err)
+		}
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("pgInbox -> Process - res.RowsAffected: %w", err)
+		}
+		if inserted == 0 {
+			// already applied by an earlier delivery
+			return nil
+		}
+		return fn(ctx)
+	})
+}
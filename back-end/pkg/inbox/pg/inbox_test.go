@@ -0,0 +1,193 @@
+package inboxpg_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	inboxpg "shared/inbox/pg"
+	pgStorage "shared/storage/pg"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// how pgdialect writes timestamps into queries
+const _timestampFormat = "2006-01-02 15:04:05.999999-07:00"
+
+// database records the statements bun sends, transactions included, and
+// answers the queries with rowsAffected or err.
+type database struct {
+	mu           sync.Mutex
+	statements   []string
+	rowsAffected int64
+	err          error
+}
+
+func (d *database) Connect(context.Context) (driver.Conn, error) { return conn{d}, nil }
+func (d *database) Driver() driver.Driver                        { return nil }
+
+func (d *database) record(statement string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, statement)
+}
+
+type conn struct {
+	d *database
+}
+
+func (c conn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c conn) Close() error                        { return nil }
+
+func (c conn) Begin() (driver.Tx, error) {
+	c.d.record("BEGIN")
+	return tx{c.d}, nil
+}
+
+func (c conn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query)
+	if c.d.err != nil {
+		return nil, c.d.err
+	}
+	return driver.RowsAffected(c.d.rowsAffected), nil
+}
+
+type tx struct {
+	d *database
+}
+
+func (t tx) Commit() error {
+	t.d.record("COMMIT")
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.d.record("ROLLBACK")
+	return nil
+}
+
+func newDB(d *database) *bun.DB {
+	return bun.NewDB(sql.OpenDB(d), pgdialect.New())
+}
+
+var insert = regexp.MustCompile(`^INSERT INTO "processed_messages" AS "pm" \("consumer", "message_id", "processed_at"\) ` +
+	`VALUES \('cart', 'message-1', '[^']+'\) ON CONFLICT DO NOTHING$`)
+
+func TestInbox_Process(t *testing.T) {
+	t.Parallel()
+	failure := errors.New("the product is missing")
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		fnErr        error
+		applied      bool
+		err          error
+		statements   int
+		end          string
+	}{
+		{
+			name:         "applies a new message",
+			rowsAffected: 1,
+			applied:      true,
+			statements:   4,
+			end:          "COMMIT",
+		},
+		{
+			name:         "skips a processed message",
+			rowsAffected: 0,
+			statements:   3,
+			end:          "COMMIT",
+		},
+		{
+			name:         "leaves a failed message unmarked",
+			rowsAffected: 1,
+			fnErr:        failure,
+			applied:      true,
+			err:          failure,
+			statements:   4,
+			end:          "ROLLBACK",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d := &database{rowsAffected: tc.rowsAffected}
+			db := newDB(d)
+			in := inboxpg.NewInbox(db, pgStorage.NewTransactor(db))
+
+			applied := false
+			err := in.Process(context.Background(), "cart", "message-1", func(ctx context.Context) error {
+				applied = true
+				// the handler joins the transaction of the inbox
+				_, err := pgStorage.Conn(ctx, db).ExecContext(ctx, "UPDATE carts SET total = 1")
+				require.NoError(t, err)
+				return tc.fnErr
+			})
+
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.applied, applied)
+			require.Len(t, d.statements, tc.statements)
+			assert.Equal(t, "BEGIN", d.statements[0])
+			assert.Regexp(t, insert, d.statements[1])
+			if tc.applied {
+				assert.Equal(t, "UPDATE carts SET total = 1", d.statements[2])
+			}
+			assert.Equal(t, tc.end, d.statements[len(d.statements)-1])
+		})
+	}
+}
+
+func TestInbox_Process_InsertError(t *testing.T) {
+	t.Parallel()
+	d := &database{err: errors.New("connection refused")}
+	db := newDB(d)
+	in := inboxpg.NewInbox(db, pgStorage.NewTransactor(db))
+
+	err := in.Process(context.Background(), "cart", "message-1", func(ctx context.Context) error {
+		t.Fatal("the message must not be applied")
+		return nil
+	})
+
+	assert.ErrorIs(t, err, d.err)
+	assert.ErrorContains(t, err, "pgInbox -> Process - i.db.NewInsert")
+	require.Len(t, d.statements, 3)
+	assert.Equal(t, "ROLLBACK", d.statements[2])
+}
+
+func TestCleaner_Clean(t *testing.T) {
+	t.Parallel()
+	d := &database{rowsAffected: 3}
+
+	before := time.Now().UTC()
+	deleted, err := inboxpg.NewCleaner(newDB(d), zerolog.Nop(), inboxpg.Retention(24*time.Hour)).Clean(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	require.Len(t, d.statements, 1)
+	match := regexp.MustCompile(`^DELETE FROM "processed_messages" AS "pm" WHERE \(processed_at < '([^']+)'\)$`).
+		FindStringSubmatch(d.statements[0])
+	require.NotNil(t, match, d.statements[0])
+	processedBefore, err := time.Parse(_timestampFormat, match[1])
+	require.NoError(t, err)
+	assert.WithinDuration(t, before.Add(-24*time.Hour), processedBefore, time.Second)
+}
+
+func TestCleaner_Clean_Error(t *testing.T) {
+	t.Parallel()
+	d := &database{err: errors.New("connection refused")}
+
+	_, err := inboxpg.NewCleaner(newDB(d), zerolog.Nop()).Clean(context.Background())
+
+	assert.ErrorIs(t, err, d.err)
+	assert.ErrorContains(t, err, "Cleaner -> Clean - c.db.NewDelete")
+}
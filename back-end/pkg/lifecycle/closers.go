@@ -0,0 +1,13 @@
+package lifecycle
+
+import (
+	"context"
+	"io"
+)
+
+// Closer adapts an io.Closer, such as a *bun.DB, to a shutdown step.
+func Closer(c io.Closer) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return c.Close()
+	}
+}
@@ -0,0 +1,13 @@
+package lifecycle
+
+import "time"
+
+// Option -.
+type Option func(*Runner)
+
+// ShutdownTimeout bounds the time all shutdown steps together may take.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(r *Runner) {
+		r.shutdownTimeout = timeout
+	}
+}
@@ -0,0 +1,207 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const _defaultShutdownTimeout = 30 * time.Second
+
+type State int32
+
+const (
+	StateStarting State = iota
+	StateRunning
+	StateStopping
+	StateStopped
+)
+
+func (s State) String() string {
+	switch s {
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	default:
+		return fmt.Sprintf("State(%d)", int32(s))
+	}
+}
+
+// Server is implemented by the httpserver packages of every service.
+type Server interface {
+	Notify() <-chan error
+	Shutdown() error
+}
+
+type step struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Runner owns the lifetime of a service's components. Components register a
+// shutdown step as soon as they are built; on SIGINT/SIGTERM or when a worker
+// or server fails, the steps run in reverse registration order, so that a
+// component is stopped before anything it depends on is closed.
+type Runner struct {
+	logger          zerolog.Logger
+	shutdownTimeout time.Duration
+
+	state    atomic.Int32
+	mu       sync.Mutex
+	steps    []step
+	stop     context.CancelFunc
+	ctx      context.Context
+	failures chan error
+}
+
+func NewRunner(logger zerolog.Logger, opts ...Option) *Runner {
+	ctx, stop := context.WithCancel(context.Background())
+	r := &Runner{
+		logger:          logger,
+		shutdownTimeout: _defaultShutdownTimeout,
+		ctx:             ctx,
+		stop:            stop,
+		failures:        make(chan error, 1),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// OnShutdown registers fn to run during shutdown.
+func (r *Runner) OnShutdown(name string, fn func(ctx context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step{name: name, fn: fn})
+}
+
+// Go starts fn in the background. Its context is cancelled at its shutdown
+// step, which waits for fn to return. An error returned before shutdown
+// stops the service.
+func (r *Runner) Go(name string, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := fn(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+	r.OnShutdown(name, func(shutdownCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-shutdownCtx.Done():
+			return shutdownCtx.Err()
+		}
+	})
+}
+
+// AddServer watches server for failures and shuts it down at its step.
+func (r *Runner) AddServer(name string, server Server) {
+	go func() {
+		err, ok := <-server.Notify()
+		if ok && err != nil && !errors.Is(err, http.ErrServerClosed) {
+			r.fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+	r.OnShutdown(name, func(ctx context.Context) error {
+		return server.Shutdown()
+	})
+}
+
+func (r *Runner) fail(err error) {
+	select {
+	case r.failures <- err:
+	default:
+	}
+	r.stop()
+}
+
+// Run blocks until the process is signalled or a component fails, then
+// shuts everything down. It returns the failure, if any, joined with the
+// errors of the shutdown steps.
+func (r *Runner) Run() error {
+	signalCtx, stopSignals := signal.NotifyContext(r.ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	r.setState(StateRunning)
+	<-signalCtx.Done()
+
+	var failure error
+	select {
+	case failure = <-r.failures:
+		r.logger.Error().Err(failure).Msg("lifecycle -> Run - component failed")
+	default:
+		r.logger.Info().Msg("lifecycle -> Run - received shutdown signal")
+	}
+
+	return errors.Join(failure, r.shutdown())
+}
+
+// Shutdown stops the service without waiting for a signal.
+func (r *Runner) Shutdown() {
+	r.stop()
+}
+
+func (r *Runner) shutdown() error {
+	r.setState(StateStopping)
+	defer r.setState(StateStopped)
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.shutdownTimeout)
+	defer cancel()
+
+	r.mu.Lock()
+	steps := r.steps
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		s := steps[i]
+		r.logger.Info().Str("component", s.name).Msg("lifecycle -> shutdown - stopping")
+		err := s.fn(ctx)
+		if err != nil {
+			r.logger.Error().Err(err).Str("component", s.name).Msg("lifecycle -> shutdown - failed")
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Runner) setState(state State) {
+	r.state.Store(int32(state))
+	r.logger.Info().Str("state", state.String()).Msg("lifecycle -> state changed")
+}
+
+func (r *Runner) State() State {
+	return State(r.state.Load())
+}
+
+// Ready reports whether the service should receive traffic. It turns false
+// as soon as shutdown begins so load balancers stop routing to it.
+func (r *Runner) Ready() bool {
+	return r.State() == StateRunning
+}
+
+// Live reports whether the process is still functional. It stays true while
+// shutting down so that the orchestrator does not kill a draining instance.
+func (r *Runner) Live() bool {
+	return r.State() != StateStopped
+}
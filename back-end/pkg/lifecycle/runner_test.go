@@ -0,0 +1,71 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"shared/lifecycle"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeServer struct {
+	notify   chan error
+	shutdown func()
+}
+
+func (s fakeServer) Notify() <-chan error { return s.notify }
+func (s fakeServer) Shutdown() error {
+	s.shutdown()
+	return nil
+}
+
+func TestRunner_ShutsDownInReverseOrderOnFailure(t *testing.T) {
+	t.Parallel()
+	runner := lifecycle.NewRunner(zerolog.Nop())
+	var stopped []string
+	record := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			stopped = append(stopped, name)
+			return nil
+		}
+	}
+
+	runner.OnShutdown("postgres", record("postgres"))
+	runner.OnShutdown("nats", record("nats"))
+	runner.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped = append(stopped, "worker")
+		return nil
+	})
+	server := fakeServer{notify: make(chan error, 1), shutdown: func() { stopped = append(stopped, "http server") }}
+	runner.AddServer("http server", server)
+	require.Equal(t, lifecycle.StateStarting, runner.State())
+
+	listenErr := errors.New("address already in use")
+	server.notify <- listenErr
+
+	err := runner.Run()
+	require.ErrorIs(t, err, listenErr)
+	require.Equal(t, []string{"http server", "worker", "nats", "postgres"}, stopped)
+	require.Equal(t, lifecycle.StateStopped, runner.State())
+	require.False(t, runner.Ready())
+	require.False(t, runner.Live())
+}
+
+func TestRunner_ReportsShutdownErrors(t *testing.T) {
+	t.Parallel()
+	runner := lifecycle.NewRunner(zerolog.Nop())
+	closeErr := errors.New("close failed")
+	runner.OnShutdown("postgres", func(ctx context.Context) error { return closeErr })
+	runner.Go("worker", func(ctx context.Context) error {
+		require.True(t, runner.Live())
+		runner.Shutdown()
+		return nil
+	})
+
+	err := runner.Run()
+	require.ErrorIs(t, err, closeErr)
+}
@@ -1,8 +1,11 @@
 package app
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -13,26 +16,37 @@ import (
 type NatsClient interface {
 	PublishMessage(subject string, message string)
 	PublishMessageEphemeral(subject string, message string)
+	PublishMessageWithID(subject string, messageID string, data []byte) error
 	CreateStream(streamName string, streamSubjects string) error
-	SubscribeDurable(subject string, streamName string, consumerName string, handler func(m *nats.Msg) error)
+	SubscribeDurable(subject string, streamName string, consumerName string, handler func(m *nats.Msg) error, opts ...SubscribeOption)
 	AddConsumer(streamName string, consumerName string, subject string) error
+	DeleteConsumer(streamName string, consumerName string) error
 	SubscribeEphemeral(subject string, handler func(m *nats.Msg) error)
+	ListDeadLetters(consumerName string) ([]DeadLetter, error)
+	ReplayDeadLetters(consumerName string, sequences ...uint64) (int, error)
+	Drain(ctx context.Context) error
+	IsConnected() bool
+	ConsumersPending() (map[string]uint64, error)
 }
 
 type natsClient struct {
 	nc     *nats.Conn
 	js     nats.JetStreamContext
 	logger zerolog.Logger
+	closed chan struct{}
+	// durable consumers subscribed through this client, by consumer name
+	consumers *sync.Map
 }
 
 func NewNatsClient() *natsClient {
 	uri := os.Getenv("NATS_URI")
-	nc := newNatsConnection(uri)
+	closed := make(chan struct{})
+	nc := newNatsConnection(uri, nats.ClosedHandler(func(*nats.Conn) { close(closed) }))
 	js, err := nc.JetStream(nats.PublishAsyncMaxPending(256))
 	if err != nil {
 		panic(err)
 	}
-	serverNats := natsClient{nc: nc, js: js}
+	serverNats := natsClient{nc: nc, js: js, logger: log.Logger, closed: closed, consumers: &sync.Map{}}
 	return &serverNats
 }
 
@@ -54,12 +68,23 @@ func (n natsClient) PublishMessage(subject, message string) {
 	}
 }
 
-func newNatsConnection(uri string) *nats.Conn {
+// Publishes to JetStream and waits for the ack. The stream drops messages
+// whose ID it has already seen within its duplicates window.
+func (n natsClient) PublishMessageWithID(subject string, messageID string, data []byte) error {
+	n.logger.Debug().Msgf("Publishing message %s to %s", messageID, subject)
+	_, err := n.js.Publish(subject, data, nats.MsgId(messageID))
+	if err != nil {
+		return fmt.Errorf("natsClient -> PublishMessageWithID - js.Publish: %w", err)
+	}
+	return nil
+}
+
+func newNatsConnection(uri string, opts ...nats.Option) *nats.Conn {
 	var nc *nats.Conn
 	var err error
 
 	for i := 0; i < 5; i++ {
-		nc, err = nats.Connect(uri)
+		nc, err = nats.Connect(uri, opts...)
 		if err == nil {
 			break
 		}
@@ -75,6 +100,48 @@ func newNatsConnection(uri string) *nats.Conn {
 	return nc
 }
 
+// Drain stops all subscriptions from receiving new messages, waits for the
+// handlers of messages already delivered, flushes pending publishes and
+// closes the connection.
+func (n natsClient) Drain(ctx context.Context) error {
+	err := n.nc.Drain()
+	if err != nil {
+		return fmt.Errorf("natsClient -> Drain - nc.Drain: %w", err)
+	}
+	select {
+	case <-n.closed:
+		return nil
+	case <-ctx.Done():
+		n.nc.Close()
+		return fmt.Errorf("natsClient -> Drain: %w", ctx.Err())
+	}
+}
+
+func (n natsClient) IsConnected() bool {
+	return n.nc.IsConnected()
+}
+
+// ConsumersPending returns, for every durable consumer subscribed through
+// this client, the number of messages not yet delivered to it.
+func (n natsClient) ConsumersPending() (map[string]uint64, error) {
+	pending := map[string]uint64{}
+	var err error
+	n.consumers.Range(func(key, value any) bool {
+		consumerName, streamName := key.(string), value.(string)
+		info, infoErr := n.js.ConsumerInfo(streamName, consumerName)
+		if infoErr != nil {
+			err = fmt.Errorf("natsClient -> ConsumersPending - js.ConsumerInfo(%s): %w", consumerName, infoErr)
+			return false
+		}
+		pending[consumerName] = info.NumPending
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
 func (n natsClient) CreateStream(streamName string, streamSubjects string) error {
 	stream, err := n.js.StreamInfo(streamName)
 	if err != nil {
@@ -108,26 +175,160 @@ func (n natsClient) AddConsumer(streamName string, consumerName string, subject
 	return err
 }
 
-func (n natsClient) SubscribeDurable(subject string, streamName string, consumerName string, handler func(m *nats.Msg) error) {
+// DeleteConsumer removes a durable consumer that is no longer subscribed,
+// streams with interest retention keep every message it has not acked.
+func (n natsClient) DeleteConsumer(streamName string, consumerName string) error {
+	err := n.js.DeleteConsumer(streamName, consumerName)
+	if err != nil && !errors.Is(err, nats.ErrConsumerNotFound) {
+		return fmt.Errorf("natsClient -> DeleteConsumer - js.DeleteConsumer: %w", err)
+	}
+	return nil
+}
+
+// Acks a message once handler succeeds. A failed message is redelivered with
+// an exponential backoff until MaxDeliver is reached, then it is moved to the
+// consumer's dead-letter stream. So is a message whose last delivery is not
+// acked within AckWait, through the max deliveries advisory of the server.
+func (n natsClient) SubscribeDurable(
+	subject string,
+	streamName string,
+	consumerName string,
+	handler func(m *nats.Msg) error,
+	opts ...SubscribeOption,
+) {
+	options := newSubscribeOptions(opts)
 	log.Logger.Info().
 		Str("subject", subject).
 		Str("streamName", streamName).
 		Str("consumerName", consumerName).
+		Int("maxDeliver", options.maxDeliver).
 		Msg("Subscribing to subject")
-	_, err := n.js.Subscribe(subject, func(m *nats.Msg) {
-		err := m.Ack()
 
+	err := n.createDeadLetterStream(consumerName)
+	if err != nil {
+		n.logger.Err(err).Msg("natsClient -> SubscribeDurable - n.createDeadLetterStream")
+		return
+	}
+	err = n.updateConsumer(streamName, consumerName, options)
+	if err != nil {
+		n.logger.Err(err).Msg("natsClient -> SubscribeDurable - n.updateConsumer")
+		return
+	}
+	// a queue group, so only one instance of the service moves the message
+	_, err = n.nc.QueueSubscribe(maxDeliveriesAdvisorySubject(streamName, consumerName), consumerName, n.handleMaxDeliveries)
+	if err != nil {
+		n.logger.Err(err).Msg("natsClient -> SubscribeDurable - nc.QueueSubscribe")
+		return
+	}
+
+	subOpts := []nats.SubOpt{
+		nats.BindStream(streamName),
+		nats.Durable(consumerName),
+		nats.AckExplicit(),
+		nats.ManualAck(),
+		nats.MaxDeliver(options.maxDeliver),
+	}
+	if options.ackWait > 0 {
+		subOpts = append(subOpts, nats.AckWait(options.ackWait))
+	}
+	if options.maxAckPending > 0 {
+		subOpts = append(subOpts, nats.MaxAckPending(options.maxAckPending))
+	}
+	_, err = n.js.Subscribe(subject, func(m *nats.Msg) {
+		if replayedForOther(m, consumerName) {
+			err := m.Ack()
+			if err != nil {
+				n.logger.Error().Err(err).Msg("Error acking message")
+			}
+			return
+		}
+
+		handlerErr := handler(m)
+		if handlerErr == nil {
+			err := m.Ack()
+			if err != nil {
+				n.logger.Error().Err(err).Msg("Error acking message")
+			}
+			return
+		}
+
+		metadata, err := m.Metadata()
 		if err != nil {
-			n.logger.Error().Err(err).Msg("Error acking message")
+			n.logger.Error().Err(err).Msg("Error reading message metadata")
+			err = m.Nak()
+			if err != nil {
+				n.logger.Error().Err(err).Msg("Error naking message")
+			}
+			return
 		}
+		n.logger.Warn().Err(handlerErr).
+			Str("subject", m.Subject).
+			Str("consumerName", consumerName).
+			Uint64("numDelivered", metadata.NumDelivered).
+			Msg("Message handler failed")
 
-		handler(m)
-	}, nats.BindStream(streamName), nats.Durable(consumerName), nats.AckExplicit())
+		if metadata.NumDelivered < uint64(options.maxDeliver) {
+			err = m.NakWithDelay(options.backoff(metadata.NumDelivered))
+			if err != nil {
+				n.logger.Error().Err(err).Msg("Error naking message")
+			}
+			return
+		}
+
+		err = n.publishDeadLetter(failedMessage{
+			consumer:   consumerName,
+			stream:     metadata.Stream,
+			sequence:   metadata.Sequence.Stream,
+			subject:    m.Subject,
+			deliveries: metadata.NumDelivered,
+			err:        handlerErr.Error(),
+			data:       m.Data,
+		})
+		if err != nil {
+			// the server does not redeliver after the last delivery, left
+			// unacked the message comes back in a max deliveries advisory
+			// once AckWait expires
+			n.logger.Error().Err(err).Msg("Error moving message to the dead-letter stream")
+			return
+		}
+		err = m.Term()
+		if err != nil {
+			n.logger.Error().Err(err).Msg("Error terminating message")
+		}
+	}, subOpts...)
 
 	if err != nil {
 		n.logger.Err(err).Msg("natsClient -> Subscribe")
 		return
 	}
+	n.consumers.Store(consumerName, streamName)
+}
+
+// Existing consumers keep their MaxDeliver, AckWait and MaxAckPending, which
+// would make js.Subscribe fail with a configuration mismatch when the options
+// change.
+func (n natsClient) updateConsumer(streamName string, consumerName string, options subscribeOptions) error {
+	info, err := n.js.ConsumerInfo(streamName, consumerName)
+	if err != nil {
+		if errors.Is(err, nats.ErrConsumerNotFound) {
+			return nil
+		}
+		return err
+	}
+	config := info.Config
+	config.MaxDeliver = options.maxDeliver
+	if options.ackWait > 0 {
+		config.AckWait = options.ackWait
+	}
+	if options.maxAckPending > 0 {
+		config.MaxAckPending = options.maxAckPending
+	}
+	if config.MaxDeliver == info.Config.MaxDeliver && config.AckWait == info.Config.AckWait &&
+		config.MaxAckPending == info.Config.MaxAckPending {
+		return nil
+	}
+	_, err = n.js.UpdateConsumer(streamName, &config)
+	return err
 }
 
 func (n natsClient) SubscribeEphemeral(subject string, handler func(m *nats.Msg) error) {
@@ -141,6 +342,10 @@ func (n natsClient) SubscribeEphemeral(subject string, handler func(m *nats.Msg)
 			n.logger.Error().Err(err).Msg("Error acking message")
 		}
 
+		// replayed dead letters are for one durable consumer only
+		if m.Header.Get(headerReplayConsumer) != "" {
+			return
+		}
 		handler(m)
 	})
 
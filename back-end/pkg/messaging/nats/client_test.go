@@ -0,0 +1,213 @@
+package app
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testStream   = "ORDERS"
+	testSubject  = "orders.created"
+	testConsumer = "notifier"
+)
+
+// newTestClient connects to an embedded JetStream server with the stream of
+// the tests.
+func newTestClient(t *testing.T) natsClient {
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	server := natsserver.RunServer(&opts)
+	t.Cleanup(server.Shutdown)
+
+	nc, err := nats.Connect(server.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	js, err := nc.JetStream()
+	require.NoError(t, err)
+	client := natsClient{nc: nc, js: js, logger: zerolog.Nop(), closed: make(chan struct{}), consumers: &sync.Map{}}
+	require.NoError(t, client.CreateStream(testStream, "orders.*"))
+	return client
+}
+
+func waitForDeadLetter(t *testing.T, client natsClient) DeadLetter {
+	var deadLetters []DeadLetter
+	require.Eventually(t, func() bool {
+		var err error
+		deadLetters, err = client.ListDeadLetters(testConsumer)
+		require.NoError(t, err)
+		return len(deadLetters) > 0
+	}, 5*time.Second, 20*time.Millisecond)
+	require.Len(t, deadLetters, 1)
+	return deadLetters[0]
+}
+
+func TestSubscribeDurable_DeadLettersAfterMaxDeliver(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	var deliveries atomic.Int64
+	client.SubscribeDurable(testSubject, testStream, testConsumer, func(m *nats.Msg) error {
+		deliveries.Add(1)
+		return errors.New("the template is missing")
+	}, MaxDeliver(3), NakBackoff(10*time.Millisecond, 10*time.Millisecond))
+
+	require.NoError(t, client.PublishMessageWithID(testSubject, "order-1", []byte(`{"id":1}`)))
+
+	deadLetter := waitForDeadLetter(t, client)
+	assert.Equal(t, testConsumer, deadLetter.Consumer)
+	assert.Equal(t, testSubject, deadLetter.OriginalSubject)
+	assert.Equal(t, testStream, deadLetter.OriginalStream)
+	assert.Equal(t, uint64(1), deadLetter.OriginalSequence)
+	assert.Equal(t, uint64(3), deadLetter.Deliveries)
+	assert.Equal(t, "the template is missing", deadLetter.Error)
+	assert.Equal(t, []byte(`{"id":1}`), deadLetter.Data)
+	assert.Equal(t, int64(3), deliveries.Load())
+}
+
+func TestReplayDeadLetters_OnlyToTheConsumer(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	var fixed atomic.Bool
+	var deliveries, otherDeliveries atomic.Int64
+	client.SubscribeDurable(testSubject, testStream, testConsumer, func(m *nats.Msg) error {
+		deliveries.Add(1)
+		if !fixed.Load() {
+			return errors.New("the template is missing")
+		}
+		return nil
+	}, MaxDeliver(1))
+	client.SubscribeDurable(testSubject, testStream, "analytics", func(m *nats.Msg) error {
+		otherDeliveries.Add(1)
+		return nil
+	})
+
+	require.NoError(t, client.PublishMessageWithID(testSubject, "order-1", []byte(`{"id":1}`)))
+	waitForDeadLetter(t, client)
+	fixed.Store(true)
+
+	replayed, err := client.ReplayDeadLetters(testConsumer)
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+	require.Eventually(t, func() bool { return deliveries.Load() == 2 }, 5*time.Second, 20*time.Millisecond)
+	deadLetters, err := client.ListDeadLetters(testConsumer)
+	require.NoError(t, err)
+	assert.Empty(t, deadLetters)
+
+	// the other consumer handles the later message but never the replay
+	require.NoError(t, client.PublishMessageWithID(testSubject, "order-2", []byte(`{"id":2}`)))
+	require.Eventually(t, func() bool { return deliveries.Load() == 3 }, 5*time.Second, 20*time.Millisecond)
+	require.Eventually(t, func() bool { return otherDeliveries.Load() == 2 }, 5*time.Second, 20*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int64(2), otherDeliveries.Load())
+}
+
+func TestSubscribeDurable_DeadLettersUnackedLastDelivery(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	client.SubscribeDurable(testSubject, testStream, testConsumer, func(m *nats.Msg) error {
+		metadata, err := m.Metadata()
+		require.NoError(t, err)
+		if metadata.NumDelivered == 1 {
+			return errors.New("the template is missing")
+		}
+		// the last delivery outlives AckWait
+		<-release
+		return nil
+	}, MaxDeliver(2), AckWait(200*time.Millisecond), NakBackoff(10*time.Millisecond, 10*time.Millisecond))
+
+	require.NoError(t, client.PublishMessageWithID(testSubject, "order-1", []byte(`{"id":1}`)))
+
+	deadLetter := waitForDeadLetter(t, client)
+	assert.Equal(t, testSubject, deadLetter.OriginalSubject)
+	assert.Equal(t, uint64(1), deadLetter.OriginalSequence)
+	assert.Equal(t, uint64(2), deadLetter.Deliveries)
+	assert.Equal(t, "not acknowledged after the last delivery", deadLetter.Error)
+	assert.Equal(t, []byte(`{"id":1}`), deadLetter.Data)
+}
+
+func TestSubscribeDurable_RetriesDeadLetterPublish(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	client.SubscribeDurable(testSubject, testStream, testConsumer, func(m *nats.Msg) error {
+		// the dead-letter stream is unavailable for a moment
+		require.NoError(t, client.js.DeleteStream(deadLetterStreamName(testConsumer)))
+		time.AfterFunc(deadLetterRetryDelay/2, func() {
+			assert.NoError(t, client.createDeadLetterStream(testConsumer))
+		})
+		return errors.New("the template is missing")
+	}, MaxDeliver(1))
+
+	require.NoError(t, client.PublishMessageWithID(testSubject, "order-1", []byte(`{"id":1}`)))
+
+	deadLetter := waitForDeadLetter(t, client)
+	assert.Equal(t, "the template is missing", deadLetter.Error)
+	// only moved once, even when the advisory comes in too
+	time.Sleep(100 * time.Millisecond)
+	deadLetters, err := client.ListDeadLetters(testConsumer)
+	require.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+}
+
+func TestSubscribeDurable_AcksHandledMessages(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	handled := make(chan struct{}, 1)
+	client.SubscribeDurable(testSubject, testStream, testConsumer, func(m *nats.Msg) error {
+		handled <- struct{}{}
+		return nil
+	})
+
+	require.NoError(t, client.PublishMessageWithID(testSubject, "order-1", []byte(`{"id":1}`)))
+
+	<-handled
+	assert.Eventually(t, func() bool {
+		info, err := client.js.ConsumerInfo(testStream, testConsumer)
+		require.NoError(t, err)
+		return info.NumAckPending == 0 && info.NumPending == 0
+	}, time.Second, 10*time.Millisecond)
+	deadLetters, err := client.ListDeadLetters(testConsumer)
+	require.NoError(t, err)
+	assert.Empty(t, deadLetters)
+}
+
+func TestSubscribeDurable_MaxAckPendingKeepsOrder(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	var mu sync.Mutex
+	var handled []string
+	client.SubscribeDurable(testSubject, testStream, testConsumer, func(m *nats.Msg) error {
+		metadata, err := m.Metadata()
+		require.NoError(t, err)
+		id := m.Header.Get(nats.MsgIdHdr)
+		// the first message fails once, the second must wait for it
+		if id == "order-1" && metadata.NumDelivered == 1 {
+			return errors.New("the database is down")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, id)
+		return nil
+	}, MaxAckPending(1), NakBackoff(50*time.Millisecond, 50*time.Millisecond))
+
+	require.NoError(t, client.PublishMessageWithID(testSubject, "order-1", []byte(`{"id":1}`)))
+	require.NoError(t, client.PublishMessageWithID(testSubject, "order-2", []byte(`{"id":2}`)))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"order-1", "order-2"}, handled)
+}
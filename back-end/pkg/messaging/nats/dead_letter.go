@@ -0,0 +1,256 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	deadLetterStreamPrefix  = "DLQ-"
+	deadLetterSubjectPrefix = "dlq."
+
+	headerOriginalSubject  = "Dlq-Original-Subject"
+	headerOriginalStream   = "Dlq-Original-Stream"
+	headerOriginalSequence = "Dlq-Original-Sequence"
+	headerConsumer         = "Dlq-Consumer"
+	headerDeliveries       = "Dlq-Deliveries"
+	headerError            = "Dlq-Error"
+	headerFailedAt         = "Dlq-Failed-At"
+	// set on replayed dead letters, the other consumers of the subject skip them
+	headerReplayConsumer = "Dlq-Replay-Consumer"
+
+	// the server reports messages whose last delivery was naked or not
+	// acked in time on this subject, followed by the stream and consumer
+	maxDeliveriesAdvisoryPrefix = "$JS.EVENT.ADVISORY.CONSUMER.MAX_DELIVERIES."
+
+	deadLetterAttempts   = 3
+	deadLetterRetryDelay = 500 * time.Millisecond
+)
+
+// DeadLetter is a message that exhausted its deliveries for a consumer.
+type DeadLetter struct {
+	Sequence         uint64
+	Consumer         string
+	OriginalSubject  string
+	OriginalStream   string
+	OriginalSequence uint64
+	Deliveries       uint64
+	Error            string
+	FailedAt         time.Time
+	Data             []byte
+}
+
+func deadLetterStreamName(consumerName string) string {
+	return deadLetterStreamPrefix + consumerName
+}
+
+func deadLetterSubject(consumerName string) string {
+	return deadLetterSubjectPrefix + consumerName
+}
+
+// Dead letters are kept until they are replayed, so the stream uses the
+// limits policy instead of the interest policy of the regular streams.
+func (n natsClient) createDeadLetterStream(consumerName string) error {
+	streamName := deadLetterStreamName(consumerName)
+	_, err := n.js.StreamInfo(streamName)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrStreamNotFound) {
+		return fmt.Errorf("natsClient -> createDeadLetterStream - js.StreamInfo: %w", err)
+	}
+	_, err = n.js.AddStream(&nats.StreamConfig{
+		Name:      streamName,
+		Subjects:  []string{deadLetterSubject(consumerName)},
+		Retention: nats.LimitsPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("natsClient -> createDeadLetterStream - js.AddStream: %w", err)
+	}
+	return nil
+}
+
+func maxDeliveriesAdvisorySubject(streamName string, consumerName string) string {
+	return maxDeliveriesAdvisoryPrefix + streamName + "." + consumerName
+}
+
+// failedMessage is a message of a stream that exhausted its deliveries for a
+// consumer.
+type failedMessage struct {
+	consumer   string
+	stream     string
+	sequence   uint64
+	subject    string
+	deliveries uint64
+	err        string
+	data       []byte
+}
+
+func newDeadLetterMsg(failed failedMessage, failedAt time.Time) *nats.Msg {
+	deadLetter := nats.NewMsg(deadLetterSubject(failed.consumer))
+	deadLetter.Data = failed.data
+	deadLetter.Header.Set(headerOriginalSubject, failed.subject)
+	deadLetter.Header.Set(headerOriginalStream, failed.stream)
+	deadLetter.Header.Set(headerOriginalSequence, strconv.FormatUint(failed.sequence, 10))
+	deadLetter.Header.Set(headerConsumer, failed.consumer)
+	deadLetter.Header.Set(headerDeliveries, strconv.FormatUint(failed.deliveries, 10))
+	deadLetter.Header.Set(headerError, failed.err)
+	deadLetter.Header.Set(headerFailedAt, failedAt.UTC().Format(time.RFC3339))
+	return deadLetter
+}
+
+// Both the subscription and the max deliveries advisory may move the same
+// message, the ID makes the dead-letter stream drop the second copy.
+func (n natsClient) publishDeadLetter(failed failedMessage) error {
+	deadLetter := newDeadLetterMsg(failed, time.Now())
+	msgID := nats.MsgId(fmt.Sprintf("%s-%d", failed.stream, failed.sequence))
+
+	var err error
+	for attempt := 1; attempt <= deadLetterAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(deadLetterRetryDelay)
+		}
+		_, err = n.js.PublishMsg(deadLetter, msgID)
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("natsClient -> publishDeadLetter - js.PublishMsg: %w", err)
+}
+
+type maxDeliveriesAdvisory struct {
+	Stream     string `json:"stream"`
+	Consumer   string `json:"consumer"`
+	StreamSeq  uint64 `json:"stream_seq"`
+	Deliveries uint64 `json:"deliveries"`
+}
+
+// Moves the message of a max deliveries advisory to the dead-letter stream.
+// The subscription handles failed deliveries itself, the advisory covers the
+// last deliveries that timed out and those it could not move.
+func (n natsClient) handleMaxDeliveries(advisoryMsg *nats.Msg) {
+	var advisory maxDeliveriesAdvisory
+	err := json.Unmarshal(advisoryMsg.Data, &advisory)
+	if err != nil {
+		n.logger.Error().Err(err).Msg("natsClient -> handleMaxDeliveries - json.Unmarshal")
+		return
+	}
+	m, err := n.js.GetMsg(advisory.Stream, advisory.StreamSeq)
+	if err != nil {
+		n.logger.Error().Err(err).
+			Str("stream", advisory.Stream).
+			Uint64("sequence", advisory.StreamSeq).
+			Msg("natsClient -> handleMaxDeliveries - js.GetMsg")
+		return
+	}
+	err = n.publishDeadLetter(failedMessage{
+		consumer:   advisory.Consumer,
+		stream:     advisory.Stream,
+		sequence:   advisory.StreamSeq,
+		subject:    m.Subject,
+		deliveries: advisory.Deliveries,
+		err:        "not acknowledged after the last delivery",
+		data:       m.Data,
+	})
+	if err != nil {
+		n.logger.Error().Err(err).
+			Str("stream", advisory.Stream).
+			Uint64("sequence", advisory.StreamSeq).
+			Msg("natsClient -> handleMaxDeliveries - n.publishDeadLetter")
+	}
+}
+
+func toDeadLetter(m *nats.RawStreamMsg) DeadLetter {
+	originalSequence, _ := strconv.ParseUint(m.Header.Get(headerOriginalSequence), 10, 64)
+	deliveries, _ := strconv.ParseUint(m.Header.Get(headerDeliveries), 10, 64)
+	failedAt, _ := time.Parse(time.RFC3339, m.Header.Get(headerFailedAt))
+	return DeadLetter{
+		Sequence:         m.Sequence,
+		Consumer:         m.Header.Get(headerConsumer),
+		OriginalSubject:  m.Header.Get(headerOriginalSubject),
+		OriginalStream:   m.Header.Get(headerOriginalStream),
+		OriginalSequence: originalSequence,
+		Deliveries:       deliveries,
+		Error:            m.Header.Get(headerError),
+		FailedAt:         failedAt,
+		Data:             m.Data,
+	}
+}
+
+// Lists the messages in the consumer's dead-letter stream, oldest first
+func (n natsClient) ListDeadLetters(consumerName string) ([]DeadLetter, error) {
+	streamName := deadLetterStreamName(consumerName)
+	info, err := n.js.StreamInfo(streamName)
+	if err != nil {
+		if errors.Is(err, nats.ErrStreamNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("natsClient -> ListDeadLetters - js.StreamInfo: %w", err)
+	}
+
+	deadLetters := make([]DeadLetter, 0, info.State.Msgs)
+	if info.State.Msgs == 0 {
+		return deadLetters, nil
+	}
+	for seq := info.State.FirstSeq; seq <= info.State.LastSeq; seq++ {
+		m, err := n.js.GetMsg(streamName, seq)
+		if err != nil {
+			if errors.Is(err, nats.ErrMsgNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("natsClient -> ListDeadLetters - js.GetMsg: %w", err)
+		}
+		deadLetters = append(deadLetters, toDeadLetter(m))
+	}
+	return deadLetters, nil
+}
+
+// replayedForOther reports whether m is a dead letter replayed for another
+// consumer than consumerName.
+func replayedForOther(m *nats.Msg, consumerName string) bool {
+	replayedFor := m.Header.Get(headerReplayConsumer)
+	return replayedFor != "" && replayedFor != consumerName
+}
+
+// Republishes dead letters to their original subject and removes them from
+// the dead-letter stream. Every dead letter is replayed when no sequence is
+// given. Only the consumer receives the replayed message, the other consumers
+// of the subject ack it without handling it.
+func (n natsClient) ReplayDeadLetters(consumerName string, sequences ...uint64) (int, error) {
+	streamName := deadLetterStreamName(consumerName)
+	if len(sequences) == 0 {
+		deadLetters, err := n.ListDeadLetters(consumerName)
+		if err != nil {
+			return 0, err
+		}
+		for _, deadLetter := range deadLetters {
+			sequences = append(sequences, deadLetter.Sequence)
+		}
+	}
+
+	replayed := 0
+	for _, seq := range sequences {
+		m, err := n.js.GetMsg(streamName, seq)
+		if err != nil {
+			return replayed, fmt.Errorf("natsClient -> ReplayDeadLetters - js.GetMsg(%d): %w", seq, err)
+		}
+		replay := nats.NewMsg(m.Header.Get(headerOriginalSubject))
+		replay.Data = m.Data
+		replay.Header.Set(headerReplayConsumer, consumerName)
+		_, err = n.js.PublishMsg(replay)
+		if err != nil {
+			return replayed, fmt.Errorf("natsClient -> ReplayDeadLetters - js.Publish(%d): %w", seq, err)
+		}
+		err = n.js.DeleteMsg(streamName, seq)
+		if err != nil {
+			return replayed, fmt.Errorf("natsClient -> ReplayDeadLetters - js.DeleteMsg(%d): %w", seq, err)
+		}
+		replayed++
+	}
+	return replayed, nil
+}
@@ -0,0 +1,78 @@
+package app
+
+import "time"
+
+const (
+	defaultMaxDeliver = 5
+	defaultMinBackoff = 1 * time.Second
+	defaultMaxBackoff = 1 * time.Minute
+)
+
+type subscribeOptions struct {
+	maxDeliver int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	// zero keeps the server's default
+	ackWait       time.Duration
+	maxAckPending int
+}
+
+func newSubscribeOptions(opts []SubscribeOption) subscribeOptions {
+	o := subscribeOptions{
+		maxDeliver: defaultMaxDeliver,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Delay before the next redelivery, doubled on every failed attempt.
+func (o subscribeOptions) backoff(numDelivered uint64) time.Duration {
+	backoff := o.minBackoff
+	for i := uint64(1); i < numDelivered; i++ {
+		backoff *= 2
+		if backoff >= o.maxBackoff {
+			return o.maxBackoff
+		}
+	}
+	return backoff
+}
+
+// SubscribeOption -.
+type SubscribeOption func(*subscribeOptions)
+
+// MaxDeliver sets how many times a message is delivered before it is moved
+// to the consumer's dead-letter stream.
+func MaxDeliver(maxDeliver int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.maxDeliver = maxDeliver
+	}
+}
+
+// NakBackoff sets the redelivery delay after the first failure and its upper bound.
+func NakBackoff(min time.Duration, max time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.minBackoff = min
+		o.maxBackoff = max
+	}
+}
+
+// MaxAckPending limits the messages delivered but not yet acked. With 1 the
+// messages are handled one at a time in stream order, a failed message holds
+// back the ones after it until it is handled or dead-lettered.
+func MaxAckPending(maxAckPending int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.maxAckPending = maxAckPending
+	}
+}
+
+// AckWait sets how long a delivery may take before the server counts it as
+// failed and redelivers the message.
+func AckWait(ackWait time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.ackWait = ackWait
+	}
+}
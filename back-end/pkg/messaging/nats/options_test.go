@@ -0,0 +1,49 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeOptions_Backoff(t *testing.T) {
+	t.Parallel()
+	defaults := newSubscribeOptions(nil)
+	custom := newSubscribeOptions([]SubscribeOption{NakBackoff(100*time.Millisecond, time.Second)})
+
+	testCases := []struct {
+		name         string
+		options      subscribeOptions
+		numDelivered uint64
+		want         time.Duration
+	}{
+		{name: "FirstDelivery", options: defaults, numDelivered: 1, want: time.Second},
+		{name: "Doubles", options: defaults, numDelivered: 2, want: 2 * time.Second},
+		{name: "DoublesEveryDelivery", options: defaults, numDelivered: 5, want: 16 * time.Second},
+		{name: "CappedAtMax", options: defaults, numDelivered: 7, want: time.Minute},
+		{name: "ManyDeliveries", options: defaults, numDelivered: 1000, want: time.Minute},
+		{name: "NoDelivery", options: defaults, numDelivered: 0, want: time.Second},
+		{name: "Custom", options: custom, numDelivered: 3, want: 400 * time.Millisecond},
+		{name: "CustomCapped", options: custom, numDelivered: 5, want: time.Second},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, tc.options.backoff(tc.numDelivered))
+		})
+	}
+}
+
+func TestNewSubscribeOptions(t *testing.T) {
+	t.Parallel()
+	options := newSubscribeOptions([]SubscribeOption{MaxDeliver(3), AckWait(time.Second)})
+
+	assert.Equal(t, subscribeOptions{
+		maxDeliver: 3,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		ackWait:    time.Second,
+	}, options)
+}
@@ -0,0 +1,55 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	GBP Currency = "GBP"
+	CHF Currency = "CHF"
+	PLN Currency = "PLN"
+	UAH Currency = "UAH"
+	JPY Currency = "JPY"
+)
+
+// DefaultCurrency is assumed for amounts stored or published before prices
+// carried a currency.
+const DefaultCurrency = USD
+
+// number of digits after the decimal separator
+var exponents = map[Currency]int{
+	USD: 2,
+	EUR: 2,
+	GBP: 2,
+	CHF: 2,
+	PLN: 2,
+	UAH: 2,
+	JPY: 0,
+}
+
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := exponents[currency]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return currency, nil
+}
+
+func (c Currency) Exponent() int {
+	return exponents[c]
+}
+
+func (c Currency) IsValid() bool {
+	_, ok := exponents[c]
+	return ok
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
@@ -0,0 +1,264 @@
+// Package money implements an exact monetary amount in a single currency.
+// Amounts are stored as an integer number of minor units (cents for USD), so
+// addition and multiplication by a quantity never lose precision.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("money: unknown currency")
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrInvalidAmount    = errors.New("money: invalid amount")
+	ErrOverflow         = errors.New("money: amount out of range")
+)
+
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// New returns minorUnits of currency, e.g. New(1234, USD) is 12.34 USD.
+func New(minorUnits int64, currency Currency) Money {
+	return Money{amount: minorUnits, currency: currency}
+}
+
+// Zero returns a zero amount of currency.
+func Zero(currency Currency) Money {
+	return Money{currency: currency}
+}
+
+// Parse reads a decimal amount such as "12.34" or "-0.5". Digits beyond the
+// currency's minor unit are rounded half away from zero.
+func Parse(amount string, currency Currency) (Money, error) {
+	if !currency.IsValid() {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	exponent := currency.Exponent()
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	roundUp := false
+	if len(fraction) > exponent {
+		roundUp = fraction[exponent] >= '5'
+		fraction = fraction[:exponent]
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	digits := strings.TrimLeft(whole+fraction, "0")
+	if digits == "" {
+		digits = "0"
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrOverflow, amount)
+	}
+	if roundUp {
+		if minor == math.MaxInt64 {
+			return Money{}, fmt.Errorf("%w: %q", ErrOverflow, amount)
+		}
+		minor++
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{amount: minor, currency: currency}, nil
+}
+
+// MustParse is Parse for constants and tests. It panics on invalid input.
+func MustParse(amount string, currency Currency) Money {
+	m, err := Parse(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// FromFloat converts a float that was used to carry a decimal amount, e.g. a
+// JSON number. The shortest decimal representation of f is parsed, so 0.1
+// becomes exactly 0.10.
+func FromFloat(f float64, currency Currency) (Money, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidAmount, f)
+	}
+	return Parse(strconv.FormatFloat(f, 'f', -1, 64), currency)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MinorUnits returns the amount in the currency's minor unit.
+func (m Money) MinorUnits() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// Amount formats the amount with exactly as many decimals as the currency has.
+func (m Money) Amount() string {
+	exponent := m.currency.Exponent()
+	sign := ""
+	amount := m.amount
+	if amount < 0 {
+		sign = "-"
+	}
+	digits := strconv.FormatUint(absUint(amount), 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	split := len(digits) - exponent
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+func absUint(amount int64) uint64 {
+	if amount < 0 {
+		return uint64(-(amount + 1)) + 1
+	}
+	return uint64(amount)
+}
+
+func (m Money) String() string {
+	return m.Amount() + " " + string(m.currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Equal reports whether both amount and currency are the same.
+func (m Money) Equal(other Money) bool {
+	return m == other
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+	sum := m.amount + other.amount
+	if (sum > m.amount) != (other.amount > 0) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: sum, currency: m.currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(Money{amount: -other.amount, currency: other.currency})
+}
+
+// Multiply returns the price of quantity items.
+func (m Money) Multiply(quantity int64) (Money, error) {
+	if m.amount == 0 || quantity == 0 {
+		return Money{currency: m.currency}, nil
+	}
+	product := m.amount * quantity
+	if product/quantity != m.amount || (m.amount == -1 && quantity == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: product, currency: m.currency}, nil
+}
+
+// Sum adds amounts that must all be in currency.
+func Sum(currency Currency, amounts ...Money) (Money, error) {
+	total := Zero(currency)
+	for _, amount := range amounts {
+		var err error
+		total, err = total.Add(amount)
+		if err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+type jsonMoney struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON writes {"amount":"12.34","currency":"USD"}. The amount is a
+// string so that no JSON decoder turns it into a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{
+		Amount:   m.Amount(),
+		Currency: string(m.currency),
+	})
+}
+
+// UnmarshalJSON accepts the object written by MarshalJSON with the amount as
+// a string or a number, and bare amounts in DefaultCurrency written before
+// prices carried a currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "null" {
+		*m = Money{}
+		return nil
+	}
+	if !strings.HasPrefix(trimmed, "{") {
+		var number json.Number
+		err := json.Unmarshal(data, &number)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, trimmed)
+		}
+		parsed, err := Parse(number.String(), DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	var raw jsonMoney
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	err := decoder.Decode(&raw)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, trimmed)
+	}
+	currency, err := ParseCurrency(raw.Currency)
+	if err != nil {
+		return err
+	}
+	parsed, err := Parse(raw.Amount.String(), currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
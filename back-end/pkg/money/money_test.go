@@ -0,0 +1,145 @@
+package money_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"shared/money"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		amount   string
+		currency money.Currency
+		expected int64
+		err      error
+	}{
+		{name: "whole", amount: "12", currency: money.USD, expected: 1200},
+		{name: "cents", amount: "12.34", currency: money.USD, expected: 1234},
+		{name: "one decimal", amount: "0.5", currency: money.EUR, expected: 50},
+		{name: "leading dot", amount: ".07", currency: money.USD, expected: 7},
+		{name: "negative", amount: "-3.10", currency: money.USD, expected: -310},
+		{name: "rounds half up", amount: "1.005", currency: money.USD, expected: 101},
+		{name: "rounds down", amount: "1.0049", currency: money.USD, expected: 100},
+		{name: "rounds negative away from zero", amount: "-1.005", currency: money.USD, expected: -101},
+		{name: "no minor unit", amount: "150.5", currency: money.JPY, expected: 151},
+		{name: "empty", amount: "", currency: money.USD, err: money.ErrInvalidAmount},
+		{name: "letters", amount: "1.2a", currency: money.USD, err: money.ErrInvalidAmount},
+		{name: "exponent", amount: "1e3", currency: money.USD, err: money.ErrInvalidAmount},
+		{name: "too large", amount: "92233720368547758.08", currency: money.USD, err: money.ErrOverflow},
+		{name: "unknown currency", amount: "1", currency: "XYZ", err: money.ErrUnknownCurrency},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := money.Parse(tc.amount, tc.currency)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, money.New(tc.expected, tc.currency), m)
+		})
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	t.Parallel()
+	m, err := money.FromFloat(0.1+0.2, money.USD)
+	require.NoError(t, err)
+	require.Equal(t, money.New(30, money.USD), m)
+
+	_, err = money.FromFloat(math.NaN(), money.USD)
+	require.ErrorIs(t, err, money.ErrInvalidAmount)
+}
+
+func TestMoney_Amount(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "12.34", money.New(1234, money.USD).Amount())
+	require.Equal(t, "0.05", money.New(5, money.USD).Amount())
+	require.Equal(t, "-0.05", money.New(-5, money.USD).Amount())
+	require.Equal(t, "0.00", money.Zero(money.EUR).Amount())
+	require.Equal(t, "1500", money.New(1500, money.JPY).Amount())
+	require.Equal(t, "-92233720368547758.08", money.New(math.MinInt64, money.USD).Amount())
+	require.Equal(t, "12.34 USD", money.New(1234, money.USD).String())
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	t.Parallel()
+	price := money.MustParse("0.10", money.USD)
+
+	total, err := money.Sum(money.USD, price, price, price)
+	require.NoError(t, err)
+	require.Equal(t, money.MustParse("0.30", money.USD), total)
+
+	line, err := price.Multiply(3)
+	require.NoError(t, err)
+	require.True(t, line.Equal(total))
+
+	diff, err := price.Sub(line)
+	require.NoError(t, err)
+	require.True(t, diff.IsNegative())
+	require.Equal(t, "-0.20", diff.Amount())
+
+	_, err = price.Add(money.MustParse("0.10", money.EUR))
+	require.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	_, err = money.New(math.MaxInt64, money.USD).Add(money.New(1, money.USD))
+	require.ErrorIs(t, err, money.ErrOverflow)
+
+	_, err = money.New(math.MaxInt64/2+1, money.USD).Multiply(2)
+	require.ErrorIs(t, err, money.ErrOverflow)
+}
+
+func TestMoney_JSON(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(money.MustParse("9.99", money.EUR))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"9.99","currency":"EUR"}`, string(data))
+
+	testCases := []struct {
+		name     string
+		data     string
+		expected money.Money
+		err      error
+	}{
+		{name: "object", data: `{"amount":"9.99","currency":"EUR"}`, expected: money.New(999, money.EUR)},
+		{name: "numeric amount", data: `{"amount":9.99,"currency":"eur"}`, expected: money.New(999, money.EUR)},
+		{name: "legacy number", data: `19.9`, expected: money.New(1990, money.DefaultCurrency)},
+		{name: "null", data: `null`, expected: money.Money{}},
+		{name: "missing currency", data: `{"amount":"1"}`, err: money.ErrUnknownCurrency},
+		{name: "invalid amount", data: `{"amount":"abc","currency":"USD"}`, err: money.ErrInvalidAmount},
+		{name: "legacy string", data: `"9.99"`, expected: money.New(999, money.DefaultCurrency)},
+		{name: "bool", data: `true`, err: money.ErrInvalidAmount},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var m money.Money
+			err := json.Unmarshal([]byte(tc.data), &m)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	t.Parallel()
+	currency, err := money.ParseCurrency(" pln ")
+	require.NoError(t, err)
+	require.Equal(t, money.PLN, currency)
+
+	_, err = money.ParseCurrency("ABC")
+	require.ErrorIs(t, err, money.ErrUnknownCurrency)
+}
@@ -0,0 +1,155 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidRate  = errors.New("money: invalid exchange rate")
+	ErrRateNotFound = errors.New("money: exchange rate not found")
+)
+
+// Rate is an exact positive decimal exchange rate such as "0.9215".
+type Rate struct {
+	decimal string
+}
+
+func ParseRate(rate string) (Rate, error) {
+	s := strings.TrimSpace(rate)
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+	}
+	whole = strings.TrimLeft(whole, "0")
+	fraction = strings.TrimRight(fraction, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	decimal := whole
+	if fraction != "" {
+		decimal += "." + fraction
+	}
+	if strings.Trim(decimal, "0.") == "" {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+	}
+	return Rate{decimal: decimal}, nil
+}
+
+func MustParseRate(rate string) Rate {
+	r, err := ParseRate(rate)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r Rate) String() string {
+	return r.decimal
+}
+
+func (r Rate) IsZero() bool {
+	return r.decimal == ""
+}
+
+func (r Rate) rat() *big.Rat {
+	value, _ := new(big.Rat).SetString(r.decimal)
+	return value
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.decimal)
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	var decimal json.Number
+	err := json.Unmarshal(data, &decimal)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRate, data)
+	}
+	parsed, err := ParseRate(decimal.String())
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// RateTable holds the value of one unit of the base currency in other
+// currencies. Conversions between two non-base currencies go through the base.
+type RateTable struct {
+	base  Currency
+	rates map[Currency]Rate
+}
+
+func NewRateTable(base Currency, rates map[Currency]Rate) RateTable {
+	table := RateTable{base: base, rates: make(map[Currency]Rate, len(rates))}
+	for currency, rate := range rates {
+		table.rates[currency] = rate
+	}
+	return table
+}
+
+func (t RateTable) Base() Currency {
+	return t.base
+}
+
+// Rate returns how many units of currency one unit of the base currency buys.
+func (t RateTable) Rate(currency Currency) (Rate, bool) {
+	if currency == t.base {
+		return Rate{decimal: "1"}, true
+	}
+	rate, ok := t.rates[currency]
+	return rate, ok
+}
+
+// Convert exchanges m into currency. The result is rounded half away from
+// zero to the minor unit of currency.
+func (t RateTable) Convert(m Money, currency Currency) (Money, error) {
+	if m.currency == currency {
+		return m, nil
+	}
+	if !currency.IsValid() {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	from, ok := t.Rate(m.currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %s", ErrRateNotFound, m.currency)
+	}
+	to, ok := t.Rate(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %s", ErrRateNotFound, currency)
+	}
+
+	value := new(big.Rat).SetInt64(m.amount)
+	value.Mul(value, to.rat())
+	value.Quo(value, from.rat())
+	scale := new(big.Rat).SetInt(pow10(currency.Exponent()))
+	value.Mul(value, scale)
+	value.Quo(value, new(big.Rat).SetInt(pow10(m.currency.Exponent())))
+
+	amount, err := roundHalfAwayFromZero(value)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: amount, currency: currency}, nil
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+func roundHalfAwayFromZero(value *big.Rat) (int64, error) {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	remainder.Abs(remainder).Lsh(remainder, 1)
+	if remainder.Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+	if !quotient.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return quotient.Int64(), nil
+}
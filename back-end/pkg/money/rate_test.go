@@ -0,0 +1,93 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"shared/money"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		rate     string
+		expected string
+		err      error
+	}{
+		{name: "decimal", rate: "0.9215", expected: "0.9215"},
+		{name: "normalized", rate: "001.2500", expected: "1.25"},
+		{name: "whole", rate: "150", expected: "150"},
+		{name: "zero", rate: "0.000", err: money.ErrInvalidRate},
+		{name: "negative", rate: "-1", err: money.ErrInvalidRate},
+		{name: "fraction", rate: "1/3", err: money.ErrInvalidRate},
+		{name: "empty", rate: "", err: money.ErrInvalidRate},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rate, err := money.ParseRate(tc.rate)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, rate.String())
+		})
+	}
+}
+
+func TestRateTable_Convert(t *testing.T) {
+	t.Parallel()
+	rates := money.NewRateTable(money.USD, map[money.Currency]money.Rate{
+		money.EUR: money.MustParseRate("0.9"),
+		money.PLN: money.MustParseRate("4.05"),
+		money.JPY: money.MustParseRate("149.5"),
+	})
+	testCases := []struct {
+		name     string
+		amount   money.Money
+		currency money.Currency
+		expected money.Money
+		err      error
+	}{
+		{name: "same currency", amount: money.MustParse("1.99", money.EUR), currency: money.EUR, expected: money.MustParse("1.99", money.EUR)},
+		{name: "from base", amount: money.MustParse("10", money.USD), currency: money.EUR, expected: money.MustParse("9", money.EUR)},
+		{name: "to base rounds half up", amount: money.MustParse("0.01", money.EUR), currency: money.USD, expected: money.MustParse("0.01", money.USD)},
+		{name: "to base", amount: money.MustParse("9.99", money.EUR), currency: money.USD, expected: money.MustParse("11.10", money.USD)},
+		{name: "cross rate", amount: money.MustParse("9", money.EUR), currency: money.PLN, expected: money.MustParse("40.50", money.PLN)},
+		{name: "no minor unit", amount: money.MustParse("2.55", money.USD), currency: money.JPY, expected: money.MustParse("381", money.JPY)},
+		{name: "from no minor unit", amount: money.MustParse("381", money.JPY), currency: money.USD, expected: money.MustParse("2.55", money.USD)},
+		{name: "negative", amount: money.MustParse("-10", money.USD), currency: money.EUR, expected: money.MustParse("-9", money.EUR)},
+		{name: "missing rate", amount: money.MustParse("1", money.GBP), currency: money.USD, err: money.ErrRateNotFound},
+		{name: "unknown currency", amount: money.MustParse("1", money.USD), currency: "XYZ", err: money.ErrUnknownCurrency},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			converted, err := rates.Convert(tc.amount, tc.currency)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, converted)
+		})
+	}
+}
+
+func TestRate_JSON(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(money.MustParseRate("0.92"))
+	require.NoError(t, err)
+	require.Equal(t, `"0.92"`, string(data))
+
+	var rate money.Rate
+	require.NoError(t, json.Unmarshal([]byte(`1.5`), &rate))
+	require.Equal(t, "1.5", rate.String())
+	require.ErrorIs(t, json.Unmarshal([]byte(`"0"`), &rate), money.ErrInvalidRate)
+}
@@ -0,0 +1,28 @@
+package outbox
+
+import "time"
+
+// Option -.
+type Option func(*Relay)
+
+// PollInterval -.
+func PollInterval(interval time.Duration) Option {
+	return func(r *Relay) {
+		r.pollInterval = interval
+	}
+}
+
+// BatchSize -.
+func BatchSize(size int) Option {
+	return func(r *Relay) {
+		r.batchSize = size
+	}
+}
+
+// Backoff sets the delay before the first retry and the upper bound of the exponential backoff.
+func Backoff(min time.Duration, max time.Duration) Option {
+	return func(r *Relay) {
+		r.minBackoff = min
+		r.maxBackoff = max
+	}
+}
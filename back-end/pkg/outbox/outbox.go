@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is a domain event waiting to be published. It is written in the
+// same transaction as the aggregate that produced it.
+type Message struct {
+	ID            string
+	Subject       string
+	Payload       []byte
+	CreatedAt     time.Time
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	PublishedAt   *time.Time
+}
+
+func NewMessage(subject string, payload interface{}) (Message, error) {
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return Message{}, fmt.Errorf("outbox -> NewMessage - json.Marshal: %w", err)
+	}
+	now := time.Now().UTC()
+	return Message{
+		ID:            uuid.New().String(),
+		Subject:       subject,
+		Payload:       bytes,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// Writer stores messages. Implementations join the transaction carried by ctx.
+type Writer interface {
+	Save(ctx context.Context, messages ...Message) error
+}
+
+type Store interface {
+	Writer
+	// FetchPending claims up to limit unpublished messages that are due.
+	FetchPending(ctx context.Context, limit int) ([]Message, error)
+	MarkPublished(ctx context.Context, messageID string) error
+	MarkFailed(ctx context.Context, messageID string, attempts int, nextAttemptAt time.Time, lastError string) error
+}
+
+// Publisher delivers a message. The message ID is used for broker-side deduplication.
+type Publisher interface {
+	PublishMessageWithID(subject string, messageID string, data []byte) error
+}
@@ -0,0 +1,145 @@
+package outboxpg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"shared/outbox"
+	pgStorage "shared/storage/pg"
+
+	"github.com/uptrace/bun"
+)
+
+// Messages fetched by a relay are leased for this long so that other
+// replicas skip them while they are being published.
+const leaseDuration = 30 * time.Second
+
+var _ outbox.Store = (*store)(nil)
+
+type MessageModel struct {
+	bun.BaseModel `bun:"table:outbox_messages,alias:om"`
+
+	ID            string     `bun:"id,pk"`
+	Subject       string     `bun:"subject"`
+	Payload       string     `bun:"payload"`
+	CreatedAt     time.Time  `bun:"created_at"`
+	Attempts      int        `bun:"attempts"`
+	LastError     string     `bun:"last_error,nullzero"`
+	NextAttemptAt time.Time  `bun:"next_attempt_at"`
+	PublishedAt   *time.Time `bun:"published_at"`
+}
+
+func (m MessageModel) toEntity() outbox.Message {
+	return outbox.Message{
+		ID:            m.ID,
+		Subject:       m.Subject,
+		Payload:       []byte(m.Payload),
+		CreatedAt:     m.CreatedAt,
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		NextAttemptAt: m.NextAttemptAt,
+		PublishedAt:   m.PublishedAt,
+	}
+}
+
+func toDB(m outbox.Message) MessageModel {
+	return MessageModel{
+		ID:            m.ID,
+		Subject:       m.Subject,
+		Payload:       string(m.Payload),
+		CreatedAt:     m.CreatedAt,
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		NextAttemptAt: m.NextAttemptAt,
+		PublishedAt:   m.PublishedAt,
+	}
+}
+
+type store struct {
+	db *bun.DB
+}
+
+func NewStore(db *bun.DB) *store {
+	return &store{db: db}
+}
+
+func (s *store) Save(ctx context.Context, messages ...outbox.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	models := make([]MessageModel, 0, len(messages))
+	for _, message := range messages {
+		models = append(models, toDB(message))
+	}
+	_, err := pgStorage.Conn(ctx, s.db).NewInsert().Model(&models).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("outboxStore -> Save - NewInsert: %w", err)
+	}
+	return nil
+}
+
+func (s *store) FetchPending(ctx context.Context, limit int) ([]outbox.Message, error) {
+	now := time.Now().UTC()
+	due := s.db.NewSelect().
+		Model((*MessageModel)(nil)).
+		Column("id").
+		Where("published_at IS NULL").
+		Where("next_attempt_at <= ?", now).
+		Order("created_at ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	models := make([]MessageModel, 0, limit)
+	_, err := s.db.NewUpdate().
+		Model((*MessageModel)(nil)).
+		Set("next_attempt_at = ?", now.Add(leaseDuration)).
+		Where("id IN (?)", due).
+		Returning("*").
+		Exec(ctx, &models)
+	if err != nil {
+		return nil, fmt.Errorf("outboxStore -> FetchPending - NewUpdate: %w", err)
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].CreatedAt.Before(models[j].CreatedAt)
+	})
+	messages := make([]outbox.Message, 0, len(models))
+	for _, model := range models {
+		messages = append(messages, model.toEntity())
+	}
+	return messages, nil
+}
+
+func (s *store) MarkPublished(ctx context.Context, messageID string) error {
+	_, err := s.db.NewUpdate().
+		Model((*MessageModel)(nil)).
+		Set("published_at = ?", time.Now().UTC()).
+		Where("id = ?", messageID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("outboxStore -> MarkPublished - NewUpdate: %w", err)
+	}
+	return nil
+}
+
+func (s *store) MarkFailed(
+	ctx context.Context,
+	messageID string,
+	attempts int,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	_, err := s.db.NewUpdate().
+		Model((*MessageModel)(nil)).
+		Set("attempts = ?", attempts).
+		Set("next_attempt_at = ?", nextAttemptAt).
+		Set("last_error = ?", lastError).
+		Where("id = ?", messageID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("outboxStore -> MarkFailed - NewUpdate: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package outboxpg_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"shared/outbox"
+	outboxpg "shared/outbox/pg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// how pgdialect writes timestamps into queries
+const _timestampFormat = "2006-01-02 15:04:05.999999-07:00"
+
+// database records the queries bun sends and answers them with rows.
+type database struct {
+	mu      sync.Mutex
+	queries []string
+	columns []string
+	rows    [][]driver.Value
+	err     error
+}
+
+func (d *database) Connect(context.Context) (driver.Conn, error) { return conn{d}, nil }
+func (d *database) Driver() driver.Driver                        { return nil }
+
+func (d *database) record(query string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, query)
+	return d.err
+}
+
+type conn struct {
+	d *database
+}
+
+func (c conn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c conn) Close() error                        { return nil }
+func (c conn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c conn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := c.d.record(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c conn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := c.d.record(query); err != nil {
+		return nil, err
+	}
+	return &rows{columns: c.d.columns, values: c.d.rows}, nil
+}
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newStore(d *database) outbox.Store {
+	return outboxpg.NewStore(bun.NewDB(sql.OpenDB(d), pgdialect.New()))
+}
+
+func TestStore_FetchPending(t *testing.T) {
+	t.Parallel()
+	createdAt := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	d := &database{
+		columns: []string{"id", "subject", "payload", "created_at", "attempts", "last_error", "next_attempt_at", "published_at"},
+		rows: [][]driver.Value{
+			{"2", "orders.created", `{"id":2}`, createdAt.Add(time.Second), int64(1), "timeout", createdAt.Add(time.Minute), nil},
+			{"1", "orders.created", `{"id":1}`, createdAt, int64(0), nil, createdAt, nil},
+		},
+	}
+
+	messages, err := newStore(d).FetchPending(context.Background(), 10)
+
+	require.NoError(t, err)
+	require.Len(t, d.queries, 1)
+	// due messages are leased in one statement, replicas skip the locked rows
+	lease := regexp.MustCompile(`^UPDATE "outbox_messages" AS "om" SET next_attempt_at = '([^']+)' ` +
+		`WHERE \(id IN \(SELECT "om"."id" FROM "outbox_messages" AS "om" ` +
+		`WHERE \(published_at IS NULL\) AND \(next_attempt_at <= '([^']+)'\) ` +
+		`ORDER BY "created_at" ASC LIMIT 10 FOR UPDATE SKIP LOCKED\)\) RETURNING \*$`)
+	match := lease.FindStringSubmatch(d.queries[0])
+	require.NotNil(t, match, d.queries[0])
+	leasedUntil, err := time.Parse(_timestampFormat, match[1])
+	require.NoError(t, err)
+	now, err := time.Parse(_timestampFormat, match[2])
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, leasedUntil.Sub(now))
+	// the oldest first, whatever order the rows come in
+	assert.Equal(t, []outbox.Message{
+		{
+			ID:            "1",
+			Subject:       "orders.created",
+			Payload:       []byte(`{"id":1}`),
+			CreatedAt:     createdAt,
+			NextAttemptAt: createdAt,
+		},
+		{
+			ID:            "2",
+			Subject:       "orders.created",
+			Payload:       []byte(`{"id":2}`),
+			CreatedAt:     createdAt.Add(time.Second),
+			Attempts:      1,
+			LastError:     "timeout",
+			NextAttemptAt: createdAt.Add(time.Minute),
+		},
+	}, messages)
+}
+
+func TestStore_FetchPending_Error(t *testing.T) {
+	t.Parallel()
+	d := &database{err: errors.New("connection refused")}
+
+	_, err := newStore(d).FetchPending(context.Background(), 10)
+
+	assert.ErrorIs(t, err, d.err)
+	assert.ErrorContains(t, err, "outboxStore -> FetchPending - NewUpdate")
+}
+
+func TestStore_Save(t *testing.T) {
+	t.Parallel()
+	d := &database{}
+	store := newStore(d)
+	createdAt := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.Save(context.Background()))
+	assert.Empty(t, d.queries, "nothing to insert")
+
+	err := store.Save(context.Background(), outbox.Message{
+		ID:            "1",
+		Subject:       "orders.created",
+		Payload:       []byte(`{"id":1}`),
+		CreatedAt:     createdAt,
+		NextAttemptAt: createdAt,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, d.queries, 1)
+	assert.Equal(t, `INSERT INTO "outbox_messages" `+
+		`("id", "subject", "payload", "created_at", "attempts", "last_error", "next_attempt_at", "published_at") `+
+		`VALUES ('1', 'orders.created', '{"id":1}', '2023-10-01 12:00:00+00:00', 0, DEFAULT, '2023-10-01 12:00:00+00:00', DEFAULT) `+
+		`RETURNING "last_error", "published_at"`, d.queries[0])
+}
+
+func TestStore_MarkFailed(t *testing.T) {
+	t.Parallel()
+	d := &database{}
+	nextAttemptAt := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	err := newStore(d).MarkFailed(context.Background(), "1", 3, nextAttemptAt, "it's down")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{`UPDATE "outbox_messages" AS "om" ` +
+		`SET attempts = 3, next_attempt_at = '2023-10-01 12:00:00+00:00', last_error = 'it''s down' ` +
+		`WHERE (id = '1')`}, d.queries)
+}
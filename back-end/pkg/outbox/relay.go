@@ -0,0 +1,106 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultPollInterval = 1 * time.Second
+	defaultBatchSize    = 100
+	defaultMinBackoff   = 1 * time.Second
+	defaultMaxBackoff   = 5 * time.Minute
+)
+
+// Relay polls the store and publishes pending messages until its context is cancelled.
+type Relay struct {
+	store        Store
+	publisher    Publisher
+	logger       zerolog.Logger
+	pollInterval time.Duration
+	batchSize    int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+}
+
+func NewRelay(store Store, publisher Publisher, logger zerolog.Logger, opts ...Option) *Relay {
+	r := &Relay{
+		store:        store,
+		publisher:    publisher,
+		logger:       logger,
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		minBackoff:   defaultMinBackoff,
+		maxBackoff:   defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info().Msg("outbox relay started")
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for r.publishBatch(ctx) == r.batchSize {
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			r.logger.Info().Msg("outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishBatch returns the number of messages fetched.
+func (r *Relay) publishBatch(ctx context.Context) int {
+	messages, err := r.store.FetchPending(ctx, r.batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error().Err(err).Msg("Relay -> publishBatch - r.store.FetchPending")
+		}
+		return 0
+	}
+
+	for _, message := range messages {
+		err := r.publisher.PublishMessageWithID(message.Subject, message.ID, message.Payload)
+		if err != nil {
+			attempts := message.Attempts + 1
+			r.logger.Error().Err(err).
+				Str("messageID", message.ID).
+				Str("subject", message.Subject).
+				Int("attempts", attempts).
+				Msg("Relay -> publishBatch - r.publisher.PublishMessageWithID")
+			err = r.store.MarkFailed(ctx, message.ID, attempts, time.Now().UTC().Add(r.backoff(attempts)), err.Error())
+			if err != nil {
+				r.logger.Error().Err(err).Msg("Relay -> publishBatch - r.store.MarkFailed")
+			}
+			continue
+		}
+		err = r.store.MarkPublished(ctx, message.ID)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("Relay -> publishBatch - r.store.MarkPublished")
+		}
+	}
+	return len(messages)
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.minBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= r.maxBackoff {
+			return r.maxBackoff
+		}
+	}
+	return backoff
+}
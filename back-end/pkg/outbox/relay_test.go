@@ -0,0 +1,217 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore keeps the messages in memory, FetchPending returns the due
+// unpublished ones oldest first.
+type memoryStore struct {
+	mu       sync.Mutex
+	messages map[string]*Message
+	fetchErr error
+}
+
+func newMemoryStore(messages ...Message) *memoryStore {
+	s := &memoryStore{messages: map[string]*Message{}}
+	_ = s.Save(context.Background(), messages...)
+	return s
+}
+
+func (s *memoryStore) Save(_ context.Context, messages ...Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, message := range messages {
+		message := message
+		s.messages[message.ID] = &message
+	}
+	return nil
+}
+
+func (s *memoryStore) FetchPending(_ context.Context, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fetchErr != nil {
+		return nil, s.fetchErr
+	}
+	var due []Message
+	for _, message := range s.messages {
+		if message.PublishedAt == nil && !message.NextAttemptAt.After(time.Now()) {
+			due = append(due, *message)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].CreatedAt.Before(due[j].CreatedAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (s *memoryStore) MarkPublished(_ context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.messages[messageID].PublishedAt = &now
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(_ context.Context, messageID string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	message := s.messages[messageID]
+	message.Attempts = attempts
+	message.NextAttemptAt = nextAttemptAt
+	message.LastError = lastError
+	return nil
+}
+
+func (s *memoryStore) get(messageID string) Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.messages[messageID]
+}
+
+// publisher records the published message IDs, failures are the number of
+// times each ID fails before it is published.
+type publisher struct {
+	mu        sync.Mutex
+	published []string
+	failures  map[string]int
+}
+
+func (p *publisher) PublishMessageWithID(subject string, messageID string, data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures[messageID] > 0 {
+		p.failures[messageID]--
+		return errors.New("nats: no responders available for request")
+	}
+	p.published = append(p.published, messageID)
+	return nil
+}
+
+func (p *publisher) ids() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.published...)
+}
+
+func newTestMessages(ids ...string) []Message {
+	start := time.Now().Add(-time.Minute)
+	messages := make([]Message, 0, len(ids))
+	for i, id := range ids {
+		createdAt := start.Add(time.Duration(i) * time.Second)
+		messages = append(messages, Message{
+			ID:            id,
+			Subject:       "orders.created",
+			Payload:       []byte(`{"id":"` + id + `"}`),
+			CreatedAt:     createdAt,
+			NextAttemptAt: createdAt,
+		})
+	}
+	return messages
+}
+
+func TestRelay_PublishBatch(t *testing.T) {
+	t.Parallel()
+	store := newMemoryStore(newTestMessages("1", "2", "3")...)
+	p := &publisher{}
+	relay := NewRelay(store, p, zerolog.Nop(), BatchSize(2))
+
+	assert.Equal(t, 2, relay.publishBatch(context.Background()))
+	assert.Equal(t, 1, relay.publishBatch(context.Background()))
+	assert.Equal(t, 0, relay.publishBatch(context.Background()))
+
+	assert.Equal(t, []string{"1", "2", "3"}, p.ids())
+	for _, id := range []string{"1", "2", "3"} {
+		assert.NotNil(t, store.get(id).PublishedAt)
+	}
+}
+
+func TestRelay_PublishBatch_Failure(t *testing.T) {
+	t.Parallel()
+	store := newMemoryStore(newTestMessages("1", "2")...)
+	p := &publisher{failures: map[string]int{"1": 1}}
+	relay := NewRelay(store, p, zerolog.Nop(), Backoff(time.Minute, time.Hour))
+
+	before := time.Now().UTC()
+	assert.Equal(t, 2, relay.publishBatch(context.Background()))
+
+	// the failed message does not hold back the others
+	assert.Equal(t, []string{"2"}, p.ids())
+	failed := store.get("1")
+	assert.Nil(t, failed.PublishedAt)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, "nats: no responders available for request", failed.LastError)
+	assert.WithinDuration(t, before.Add(time.Minute), failed.NextAttemptAt, time.Second)
+
+	// not due before the backoff
+	assert.Equal(t, 0, relay.publishBatch(context.Background()))
+}
+
+func TestRelay_PublishBatch_FetchError(t *testing.T) {
+	t.Parallel()
+	store := newMemoryStore(newTestMessages("1")...)
+	store.fetchErr = errors.New("connection refused")
+	p := &publisher{}
+	relay := NewRelay(store, p, zerolog.Nop())
+
+	assert.Equal(t, 0, relay.publishBatch(context.Background()))
+	assert.Empty(t, p.ids())
+}
+
+func TestRelay_Run_RetriesFailedMessages(t *testing.T) {
+	t.Parallel()
+	store := newMemoryStore(newTestMessages("1", "2", "3")...)
+	p := &publisher{failures: map[string]int{"2": 2}}
+	relay := NewRelay(store, p, zerolog.Nop(),
+		PollInterval(5*time.Millisecond),
+		BatchSize(2),
+		Backoff(10*time.Millisecond, time.Second),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		relay.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(p.ids()) == 3
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, []string{"1", "3", "2"}, p.ids())
+	retried := store.get("2")
+	assert.NotNil(t, retried.PublishedAt)
+	assert.Equal(t, 2, retried.Attempts)
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	t.Parallel()
+	relay := NewRelay(nil, nil, zerolog.Nop(), Backoff(time.Second, time.Minute))
+
+	testCases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 6, want: 32 * time.Second},
+		{attempts: 7, want: time.Minute},
+		{attempts: 100, want: time.Minute},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, relay.backoff(tc.attempts), "attempts %d", tc.attempts)
+	}
+}
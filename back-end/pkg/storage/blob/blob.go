@@ -0,0 +1,19 @@
+// Package blob stores binary objects such as uploaded images.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrInvalidKey = errors.New("blob: invalid key")
+
+// Store keeps objects under slash separated keys and hands out the public
+// URL each object is served from.
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	// Delete removes the object, deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
@@ -0,0 +1,85 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var _ Store = (*LocalStore)(nil)
+
+// LocalStore keeps objects in a directory of the local filesystem. It is
+// meant for development and tests, the directory has to be served at baseURL.
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStore(dir string, baseURL string) (*LocalStore, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("blob NewLocalStore -> os.MkdirAll: %w", err)
+	}
+	return &LocalStore{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (s *LocalStore) Dir() string {
+	return s.dir
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("blob LocalStore Put -> os.MkdirAll: %w", err)
+	}
+
+	// readers never see a partially written object
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("blob LocalStore Put -> os.CreateTemp: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("blob LocalStore Put -> io.Copy: %w", err)
+	}
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return fmt.Errorf("blob LocalStore Put -> os.Rename: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("blob LocalStore Delete -> os.Remove: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	path := filepath.FromSlash(key)
+	if key == "" || !filepath.IsLocal(path) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, path), nil
+}
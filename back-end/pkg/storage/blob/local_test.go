@@ -0,0 +1,48 @@
+package blob_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"shared/storage/blob"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_PutAndDelete(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	store, err := blob.NewLocalStore(dir, "http://localhost:4002/media/")
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "products/1/image.png", strings.NewReader("image"), "image/png")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "products", "1", "image.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "image", string(content))
+	assert.Equal(t, "http://localhost:4002/media/products/1/image.png", store.URL("products/1/image.png"))
+
+	err = store.Delete(context.Background(), "products/1/image.png")
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "products", "1", "image.png"))
+	assert.True(t, os.IsNotExist(err))
+
+	err = store.Delete(context.Background(), "products/1/image.png")
+	assert.NoError(t, err)
+}
+
+func TestLocalStore_RejectsKeysOutsideTheDirectory(t *testing.T) {
+	t.Parallel()
+	store, err := blob.NewLocalStore(t.TempDir(), "http://localhost")
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../image.png", "/etc/passwd", "products/../../image.png"} {
+		err := store.Put(context.Background(), key, strings.NewReader("image"), "image/png")
+		assert.ErrorIs(t, err, blob.ErrInvalidKey, key)
+	}
+}
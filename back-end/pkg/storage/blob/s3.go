@@ -0,0 +1,179 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+var _ Store = (*S3Store)(nil)
+
+type S3Config struct {
+	// Endpoint of the S3 compatible service, e.g. https://s3.eu-central-1.amazonaws.com
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicURL is the base of the object URLs, e.g. a CDN in front of the
+	// bucket. Objects are linked through the endpoint when it is empty.
+	PublicURL string
+}
+
+// S3Store keeps objects in a bucket of an S3 compatible service. Requests
+// use path-style addressing, which every S3 compatible service supports.
+type S3Store struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Store(config S3Config, client *http.Client) *S3Store {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &S3Store{config: config, client: client, now: time.Now}
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	if key == "" {
+		return ErrInvalidKey
+	}
+	// the payload is signed, so it has to be read up front
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("blob S3Store Put -> io.ReadAll: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("blob S3Store Put -> http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	return s.do(req, payload, http.StatusOK)
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if key == "" {
+		return ErrInvalidKey
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("blob S3Store Delete -> http.NewRequestWithContext: %w", err)
+	}
+	return s.do(req, nil, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+func (s *S3Store) URL(key string) string {
+	if s.config.PublicURL != "" {
+		return s.config.PublicURL + "/" + escapePath(key)
+	}
+	return s.objectURL(key)
+}
+
+func (s *S3Store) objectURL(key string) string {
+	return s.config.Endpoint + "/" + escapePath(s.config.Bucket) + "/" + escapePath(key)
+}
+
+func (s *S3Store) do(req *http.Request, payload []byte, okStatuses ...int) error {
+	s.sign(req, sha256Hex(payload), s.now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("blob S3Store %s -> s.client.Do: %w", req.Method, err)
+	}
+	defer resp.Body.Close()
+	for _, status := range okStatuses {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("blob S3Store %s %s: unexpected status %d: %s", req.Method, req.URL.Path, resp.StatusCode, message)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header covering every
+// header already set on the request.
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath percent-encodes everything but unreserved characters and
+// slashes, as the signature requires.
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/uptrace/bun"
+)
+
+type txKey struct{}
+
+// Transactor runs a function inside a database transaction. Repositories
+// join the transaction by resolving their connection with Conn.
+type Transactor interface {
+	RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+var _ Transactor = (*transactor)(nil)
+
+type transactor struct {
+	db *bun.DB
+}
+
+func NewTransactor(db *bun.DB) *transactor {
+	return &transactor{db: db}
+}
+
+// RunInTransaction commits when fn returns nil and rolls back otherwise.
+// Nested calls reuse the transaction already stored in the context.
+func (t *transactor) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return fn(ctx)
+	}
+	return t.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction carried by ctx, or db when there is none.
+func Conn(ctx context.Context, db *bun.DB) bun.IDB {
+	if tx, ok := ctx.Value(txKey{}).(bun.Tx); ok {
+		return tx
+	}
+	return db
+}
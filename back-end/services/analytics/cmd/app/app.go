@@ -2,13 +2,12 @@ package main
 
 import (
 	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"analytics/config"
+	"shared/lifecycle"
 )
 
 func run() {
@@ -18,30 +17,19 @@ func run() {
 	}
 
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	logger := zerolog.New(os.Stdout)
+	runner := lifecycle.NewRunner(logger)
 
-	// TODO: defer pg
-	userMessagingHandler, httpServer, err := buildDependencies()
-
-	userMessagingHandler.Init()
-
+	userMessagingHandler, err := buildDependencies(runner, logger)
 	if err != nil {
 		log.Panic().Err(err).Msg("c.Invoke")
 	}
-	// Waiting signal
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
-
-	select {
-	case s := <-interrupt:
-		log.Info().Msg("app - Run - signal: " + s.String())
-	case err := <-httpServer.Notify():
-		log.Error().Err(err).Msg("app - Run - httpServer.Notify")
-	}
 
-	// Shutdown
-	err = httpServer.Shutdown()
+	userMessagingHandler.Init()
+
+	err = runner.Run()
 	if err != nil {
-		log.Error().Err(err).Msg("app - Run - httpServer.Shutdown")
+		log.Error().Err(err).Msg("app - Run - runner.Run")
 	}
 
 }
@@ -1,18 +1,20 @@
 package main
 
 import (
-	"os"
+	"context"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
 	"analytics/config"
+	"shared/health"
+	inboxStore "shared/inbox/pg"
+	"shared/lifecycle"
 	pgStorage "shared/storage/pg"
 
 	httpServ "analytics/internal/transport/http"
 
 	applicationServices "analytics/internal/services"
-	"analytics/pkg/httpserver"
 
 	domainServices "analytics/internal/domain/services"
 	repository "analytics/internal/repositories/user/pg"
@@ -21,25 +23,39 @@ import (
 	messaging "analytics/internal/transport/messaging"
 )
 
-func buildDependencies() (messaging.UserMessagingHandlers, *httpserver.Server, error) {
+const maxConsumerLag = 1000
 
-	logger := zerolog.New(os.Stdout)
+func buildDependencies(runner *lifecycle.Runner, logger zerolog.Logger) (messaging.UserMessagingHandlers, error) {
 	config, err := config.NewConfig()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	checks := health.NewHealth(runner)
 
 	pg := pgStorage.NewClient(logger, pgStorage.Config{DSN: config.PgSDN})
+	runner.OnShutdown("postgres", lifecycle.Closer(pg))
+	checks.AddReadinessCheck("postgres", health.Ping(pg))
+	transactor := pgStorage.NewTransactor(pg)
+	inbox := inboxStore.NewInbox(pg, transactor)
+	inboxCleaner := inboxStore.NewCleaner(pg, logger)
+	runner.Go("inbox cleaner", func(ctx context.Context) error {
+		inboxCleaner.Run(ctx)
+		return nil
+	})
 
 	userRepo := repository.NewUserRepository(pg, logger)
 	userDomainService := domainServices.NewUserService(logger, userRepo)
 	nats := nats.NewNatsClient()
+	runner.OnShutdown("nats", nats.Drain)
+	checks.AddReadinessCheck("nats", health.Connection(nats))
+	checks.AddDegradedCheck("consumer lag", health.ConsumerLag(nats, maxConsumerLag))
 
 	userAppService := applicationServices.NewUserApplicationService(userRepo, logger, userDomainService)
 
-	userMessagingHandlers := messaging.NewUserMessagingHandlers(nats, userAppService, logger)
+	userMessagingHandlers := messaging.NewUserMessagingHandlers(nats, inbox, userAppService, logger)
 
-	httpServer := httpServ.NewHTTPServer(userAppService, gin.New(), logger, config, pg)
+	httpServer := httpServ.NewHTTPServer(userAppService, gin.New(), logger, config, pg, checks)
+	runner.AddServer("http server", httpServer)
 
-	return userMessagingHandlers, httpServer, nil
+	return userMessagingHandlers, nil
 }
@@ -10,6 +10,8 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/uptrace/bun"
+
+	pgStorage "shared/storage/pg"
 )
 
 var _ repositories.UserRepository = (*userPGRepository)(nil)
@@ -61,7 +63,7 @@ func NewUserRepository(sql *bun.DB, logger zerolog.Logger) *userPGRepository {
 func (r *userPGRepository) GetByID(ctx context.Context, id string) (*userEntity.User, error) {
 
 	var user UserModel
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&user).
 		Where("id IN (?)", id).
 		Scan(ctx)
@@ -83,7 +85,7 @@ func (r *userPGRepository) GetByID(ctx context.Context, id string) (*userEntity.
 
 func (r *userPGRepository) GetByEmail(ctx context.Context, email string) (*userEntity.User, error) {
 	var user UserModel
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&user).
 		Where("email IN (?)", email).
 		Scan(ctx)
@@ -106,7 +108,7 @@ func (r *userPGRepository) Create(ctx context.Context, u userEntity.User) error
 	if err != nil {
 		return err
 	}
-	_, err = r.db.NewInsert().Model(&dbUser).Exec(ctx)
+	_, err = pgStorage.Conn(ctx, r.db).NewInsert().Model(&dbUser).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -119,7 +121,7 @@ func (r *userPGRepository) Update(ctx context.Context, user userEntity.User) err
 		return fmt.Errorf("userPGRepository Update -> toDB(user): %w", err)
 	}
 
-	_, err = r.db.NewUpdate().Model(&sqlUser).Where("id = ?", sqlUser.ID).Exec(ctx)
+	_, err = pgStorage.Conn(ctx, r.db).NewUpdate().Model(&sqlUser).Where("id = ?", sqlUser.ID).Exec(ctx)
 
 	if err != nil {
 		return fmt.Errorf("userPGRepository Update -> r.db.NewUpdate: %w", err)
@@ -130,7 +132,7 @@ func (r *userPGRepository) Update(ctx context.Context, user userEntity.User) err
 
 func (r *userPGRepository) Delete(ctx context.Context, ID string) error {
 	var user UserModel
-	_, err := r.db.NewDelete().Model(&user).Where("id = ?", ID).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().Model(&user).Where("id = ?", ID).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("userPGRepository Delete -> NewDelete: %w", err)
 	}
@@ -10,6 +10,7 @@ import (
 
 	"analytics/config"
 	"analytics/pkg/httpserver"
+	"shared/health"
 
 	applicationServices "analytics/internal/services"
 	routes "analytics/internal/transport/http/routes"
@@ -21,8 +22,9 @@ func NewHTTPServer(
 	logger zerolog.Logger,
 	config *config.Config,
 	db *bun.DB,
+	checks *health.Health,
 ) *httpserver.Server {
-	routes.NewRouter(handler, userApplicationService, logger, config)
+	routes.NewRouter(handler, userApplicationService, logger, config, checks)
 	logger.Info().Msg(fmt.Sprintf("Listening on %s port", config.HTTP.Port))
 	return httpserver.New(http.Handler(handler), httpserver.Port(config.HTTP.Port))
 }
@@ -2,13 +2,13 @@ package messaging
 
 import (
 	"context"
-	"encoding/json"
+	"shared/events"
+	"shared/inbox"
 	natsClient "shared/messaging/nats"
 
 	applicationServices "analytics/internal/services"
 	"analytics/internal/services/dto"
 
-	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
@@ -16,9 +16,6 @@ import (
 )
 
 const (
-	userCreationSubject           = "users.created"
-	userUpdateSubject             = "users.updated"
-	userDeletionSubject           = "users.deleted"
 	usersStreamName               = "users"
 	userCreateDurableConsumerName = "analytics-user-create"
 	userDeleteDurableConsumerName = "analytics-user-delete"
@@ -34,16 +31,18 @@ type UserMessagingHandlers interface {
 
 type userMessagingHandlers struct {
 	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
 	logger     zerolog.Logger
 	appService applicationServices.UserApplicationService
 }
 
 func NewUserMessagingHandlers(
 	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
 	appService applicationServices.UserApplicationService,
 	logger zerolog.Logger,
 ) *userMessagingHandlers {
-	u := userMessagingHandlers{natsClient: natsClient, appService: appService, logger: logger}
+	u := userMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
 	return &u
 }
 
@@ -54,34 +53,12 @@ func (u *userMessagingHandlers) Init() {
 	u.UserDeletedListener()
 }
 
-type UserCreatedEvent struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	ID    string `json:"id"`
-}
-
-type UserUpdatedEvent struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
-}
-
-type UserDeletedEvent struct {
-	ID string `json:"id"`
-}
-
 func (u *userMessagingHandlers) UserCreationListener() {
 	u.logger.Info().Msg("UserCreationListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("UserCreationListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, userCreatedEvent events.UserCreated, envelope events.Envelope) error {
+		log.Info().Msg("UserCreationListener -> Received a message: " + string(envelope.Payload))
 
-		var userCreatedEvent UserCreatedEvent
-		err := json.Unmarshal(messageData, &userCreatedEvent)
-		if err != nil {
-			log.Error().Msg("UserCreationListener -> Error in unmarshalling the message")
-			return err
-		}
-		_, err = u.appService.CreateUser(context.Background(), userEntity.CreateUserParams{
+		_, err := u.appService.CreateUser(ctx, userEntity.CreateUserParams{
 			Name:  userCreatedEvent.Name,
 			Email: userCreatedEvent.Email,
 			ID:    userCreatedEvent.ID,
@@ -92,23 +69,16 @@ func (u *userMessagingHandlers) UserCreationListener() {
 		}
 		return nil
 	}
-	u.natsClient.SubscribeDurable(userCreationSubject, usersStreamName, userCreateDurableConsumerName, handler)
+	events.Subscribe(u.natsClient, usersStreamName, userCreateDurableConsumerName, inbox.Idempotent(u.inbox, userCreateDurableConsumerName, handler))
 }
 
 func (u *userMessagingHandlers) UserUpdateListener() {
 	u.logger.Info().Msg("UserUpdateListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("UserUpdateListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, userUpdatedEvent events.UserUpdated, envelope events.Envelope) error {
+		log.Info().Msg("UserUpdateListener -> Received a message: " + string(envelope.Payload))
 
-		var userUpdatedEvent UserUpdatedEvent
-		err := json.Unmarshal(messageData, &userUpdatedEvent)
-		if err != nil {
-			log.Error().Msg("UserUpdateListener -> Error in unmarshalling the message")
-			return err
-		}
-		err = u.appService.UpdateUser(
-			context.Background(),
+		err := u.appService.UpdateUser(
+			ctx,
 			dto.UpdateUserInput{
 				Name: userUpdatedEvent.Name,
 				ID:   userUpdatedEvent.ID,
@@ -120,28 +90,25 @@ func (u *userMessagingHandlers) UserUpdateListener() {
 		}
 		return nil
 	}
-	u.natsClient.SubscribeDurable(userUpdateSubject, usersStreamName, userUpdateDurableConsumerName, handler)
+	events.Subscribe(u.natsClient, usersStreamName, userUpdateDurableConsumerName, inbox.Idempotent(u.inbox, userUpdateDurableConsumerName, handler))
 }
 
 func (u *userMessagingHandlers) UserDeletedListener() {
 	u.logger.Info().Msg("UserDeletedListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("UserDeletedListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, userDeletedEvent events.UserDeleted, envelope events.Envelope) error {
+		log.Info().Msg("UserDeletedListener -> Received a message: " + string(envelope.Payload))
 
-		var userDeletedEvent UserDeletedEvent
-		err := json.Unmarshal(messageData, &userDeletedEvent)
-		if err != nil {
-			log.Error().Msg("UserUpdateListener -> Error in unmarshalling the message")
-			return err
-		}
-		u.appService.DeleteUser(
-			context.Background(),
+		err := u.appService.DeleteUser(
+			ctx,
 			dto.DeleteUserInput{
 				ID: userDeletedEvent.ID,
 			},
 		)
+		if err != nil {
+			log.Error().Err(err).Msg("UserDeletedListener -> u.appService.DeleteUser")
+			return err
+		}
 		return nil
 	}
-	u.natsClient.SubscribeDurable(userDeletionSubject, usersStreamName, userDeleteDurableConsumerName, handler)
+	events.Subscribe(u.natsClient, usersStreamName, userDeleteDurableConsumerName, inbox.Idempotent(u.inbox, userDeleteDurableConsumerName, handler))
 }
@@ -1,35 +1,46 @@
 package main
 
 import (
-	"os"
+	"context"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
 	"authentication/config"
-	"authentication/pkg/httpserver"
 	storage "authentication/pkg/storage/mongo"
 
 	domainServices "authentication/internal/domain/services"
 	authRepository "authentication/internal/repositories/authentication/mongo"
+	outboxRepository "authentication/internal/repositories/outbox/mongo"
 	userRepository "authentication/internal/repositories/user/mongo"
 	applicationServices "authentication/internal/services"
 	httpServ "authentication/internal/transport/http"
 	middlewares "authentication/internal/transport/http/middlewares"
+	"shared/health"
+	"shared/lifecycle"
 	nats "shared/messaging/nats"
+	"shared/outbox"
 )
 
-func buildDependencies() (*httpserver.Server, error) {
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-
-	logger := zerolog.New(os.Stdout)
+const (
+	usersStream                = "users"
+	usersStreamSubjects        = "users.*"
+	notificationStream         = "notifications"
+	notificationStreamSubjects = "notifications.*"
+)
 
+func buildDependencies(runner *lifecycle.Runner, logger zerolog.Logger) error {
 	config, err := config.NewConfig()
 	if err != nil {
-		return nil, err
+		return err
 	}
+	checks := health.NewHealth(runner)
 
 	mongo := storage.NewMongoClient(logger, config)
+	runner.OnShutdown("mongodb", func(ctx context.Context) error {
+		return mongo.Client().Disconnect(ctx)
+	})
+	checks.AddReadinessCheck("mongodb", storage.NewHealthChecker(mongo))
 
 	authenticationRepo := authRepository.NewAuthenticationRepository(mongo, logger)
 	userRepo := userRepository.NewUserRepository(mongo, logger)
@@ -38,9 +49,29 @@ func buildDependencies() (*httpserver.Server, error) {
 	userDomainService := domainServices.NewUserService(logger, authenticationDomainService, userRepo)
 
 	nats := nats.NewNatsClient()
+	runner.OnShutdown("nats", nats.Drain)
+	checks.AddReadinessCheck("nats", health.Connection(nats))
+	err = nats.CreateStream(usersStream, usersStreamSubjects)
+	if err != nil {
+		return err
+	}
+	err = nats.CreateStream(notificationStream, notificationStreamSubjects)
+	if err != nil {
+		return err
+	}
+	transactor, err := storage.NewTransactor(mongo)
+	if err != nil {
+		return err
+	}
+	outboxRepo := outboxRepository.NewOutboxRepository(mongo, logger)
+	relay := outbox.NewRelay(outboxRepo, nats, logger)
+	runner.Go("outbox relay", func(ctx context.Context) error {
+		relay.Run(ctx)
+		return nil
+	})
 
 	userApplicationService := applicationServices.NewUserApplicationService(
-		userRepo, authenticationRepo, logger, userDomainService, authenticationDomainService, nats)
+		userRepo, authenticationRepo, logger, userDomainService, authenticationDomainService, transactor, outboxRepo)
 
 	sessionStore := middlewares.NewSessionStore(mongo, config)
 	session := middlewares.NewSession(sessionStore)
@@ -48,7 +79,8 @@ func buildDependencies() (*httpserver.Server, error) {
 	middlewaresContainer := middlewares.Middlewares{
 		Session: session,
 	}
-	server := httpServ.NewHTTPServer(userApplicationService, gin.New(), middlewaresContainer, logger, config, sessionStore)
+	server := httpServ.NewHTTPServer(userApplicationService, gin.New(), middlewaresContainer, logger, config, sessionStore, checks)
+	runner.AddServer("http server", server)
 
-	return server, nil
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"time"
 
+	"shared/authz"
 	customErrors "shared/errors"
 
 	mfaSettingsEntity "authentication/internal/domain/entities/mfa_settings"
@@ -15,6 +16,7 @@ import (
 
 var ErrInvalidPassword = customErrors.NewIncorrectInputError("invalid_password", "invalid password")
 var ErrInvalidEmailFormat = customErrors.NewIncorrectInputError("invalid_email", "invalid email format")
+var ErrNoRoles = customErrors.NewIncorrectInputError("no_roles", "a user needs at least one role")
 
 type User struct {
 	id             string
@@ -23,6 +25,7 @@ type User struct {
 	password       string
 	mfaSettings    mfaSettingsEntity.MfaSettings
 	socialAccounts []socialAccountEntity.SocialAccount
+	roles          []authz.Role
 	createdAt      time.Time
 	updatedAt      *time.Time
 }
@@ -76,6 +79,7 @@ func NewUser(createUserParams CreateUserParams) (*User, error) {
 		updatedAt:      nil,
 		mfaSettings:    mfaSettings,
 		socialAccounts: socialAccounts,
+		roles:          append([]authz.Role(nil), authz.DefaultRoles...),
 	}
 	return &user, nil
 }
@@ -89,7 +93,12 @@ func NewUserFromDatabase(
 	UpdatedAt *time.Time,
 	socialAccounts []socialAccountEntity.SocialAccount,
 	mfaSettings mfaSettingsEntity.MfaSettings,
+	roles []authz.Role,
 ) (*User, error) {
+	// users stored before roles existed are customers
+	if len(roles) == 0 {
+		roles = append([]authz.Role(nil), authz.DefaultRoles...)
+	}
 	user := User{id: id,
 		email:          email,
 		name:           name,
@@ -98,6 +107,7 @@ func NewUserFromDatabase(
 		createdAt:      createdAt,
 		updatedAt:      nil,
 		socialAccounts: socialAccounts,
+		roles:          roles,
 	}
 	return &user, nil
 }
@@ -130,6 +140,22 @@ func (u User) SocialAccounts() []socialAccountEntity.SocialAccount {
 	return u.socialAccounts
 }
 
+func (u User) Roles() []authz.Role {
+	return u.roles
+}
+
+func (u User) Can(permission authz.Permission) bool {
+	return authz.Can(u.roles, permission)
+}
+
+func (u *User) SetRoles(roles []authz.Role) error {
+	if len(roles) == 0 {
+		return ErrNoRoles
+	}
+	u.roles = roles
+	return nil
+}
+
 func (u *User) SetName(name string) {
 	u.name = name
 }
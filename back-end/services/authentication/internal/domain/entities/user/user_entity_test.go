@@ -2,6 +2,7 @@ package user_test
 
 import (
 	user "authentication/internal/domain/entities/user"
+	"shared/authz"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,7 +58,22 @@ func TestUserEntity_NewUser(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.NotNil(t, user)
+				assert.Equal(t, []authz.Role{authz.RoleCustomer}, user.Roles())
 			}
 		})
 	}
 }
+
+func TestUserEntity_SetRoles(t *testing.T) {
+	t.Parallel()
+	u, err := user.NewUser(user.CreateUserParams{Email: "example@gmail.com", Password: "password"})
+	require.NoError(t, err)
+	assert.False(t, u.Can(authz.PermissionSellProducts))
+
+	err = u.SetRoles([]authz.Role{authz.RoleCustomer, authz.RoleSeller})
+	require.NoError(t, err)
+	assert.True(t, u.Can(authz.PermissionSellProducts))
+
+	err = u.SetRoles(nil)
+	assert.Equal(t, user.ErrNoRoles, err)
+}
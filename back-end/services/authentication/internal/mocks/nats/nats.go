@@ -5,7 +5,9 @@
 package mock_nats
 
 import (
+	context "context"
 	reflect "reflect"
+	app "shared/messaging/nats"
 
 	gomock "github.com/golang/mock/gomock"
 	nats "github.com/nats-io/nats.go"
@@ -48,6 +50,21 @@ func (mr *MockNatsClientMockRecorder) AddConsumer(streamName, consumerName, subj
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddConsumer", reflect.TypeOf((*MockNatsClient)(nil).AddConsumer), streamName, consumerName, subject)
 }
 
+// ConsumersPending mocks base method.
+func (m *MockNatsClient) ConsumersPending() (map[string]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumersPending")
+	ret0, _ := ret[0].(map[string]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumersPending indicates an expected call of ConsumersPending.
+func (mr *MockNatsClientMockRecorder) ConsumersPending() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumersPending", reflect.TypeOf((*MockNatsClient)(nil).ConsumersPending))
+}
+
 // CreateStream mocks base method.
 func (m *MockNatsClient) CreateStream(streamName, streamSubjects string) error {
 	m.ctrl.T.Helper()
@@ -62,6 +79,63 @@ func (mr *MockNatsClientMockRecorder) CreateStream(streamName, streamSubjects in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStream", reflect.TypeOf((*MockNatsClient)(nil).CreateStream), streamName, streamSubjects)
 }
 
+// DeleteConsumer mocks base method.
+func (m *MockNatsClient) DeleteConsumer(streamName, consumerName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConsumer", streamName, consumerName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConsumer indicates an expected call of DeleteConsumer.
+func (mr *MockNatsClientMockRecorder) DeleteConsumer(streamName, consumerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConsumer", reflect.TypeOf((*MockNatsClient)(nil).DeleteConsumer), streamName, consumerName)
+}
+
+// Drain mocks base method.
+func (m *MockNatsClient) Drain(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockNatsClientMockRecorder) Drain(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockNatsClient)(nil).Drain), ctx)
+}
+
+// IsConnected mocks base method.
+func (m *MockNatsClient) IsConnected() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsConnected")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsConnected indicates an expected call of IsConnected.
+func (mr *MockNatsClientMockRecorder) IsConnected() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsConnected", reflect.TypeOf((*MockNatsClient)(nil).IsConnected))
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockNatsClient) ListDeadLetters(consumerName string) ([]app.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", consumerName)
+	ret0, _ := ret[0].([]app.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockNatsClientMockRecorder) ListDeadLetters(consumerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockNatsClient)(nil).ListDeadLetters), consumerName)
+}
+
 // PublishMessage mocks base method.
 func (m *MockNatsClient) PublishMessage(subject, message string) {
 	m.ctrl.T.Helper()
@@ -86,16 +160,55 @@ func (mr *MockNatsClientMockRecorder) PublishMessageEphemeral(subject, message i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishMessageEphemeral", reflect.TypeOf((*MockNatsClient)(nil).PublishMessageEphemeral), subject, message)
 }
 
+// PublishMessageWithID mocks base method.
+func (m *MockNatsClient) PublishMessageWithID(subject, messageID string, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishMessageWithID", subject, messageID, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishMessageWithID indicates an expected call of PublishMessageWithID.
+func (mr *MockNatsClientMockRecorder) PublishMessageWithID(subject, messageID, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishMessageWithID", reflect.TypeOf((*MockNatsClient)(nil).PublishMessageWithID), subject, messageID, data)
+}
+
+// ReplayDeadLetters mocks base method.
+func (m *MockNatsClient) ReplayDeadLetters(consumerName string, sequences ...uint64) (int, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{consumerName}
+	for _, a := range sequences {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReplayDeadLetters", varargs...)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayDeadLetters indicates an expected call of ReplayDeadLetters.
+func (mr *MockNatsClientMockRecorder) ReplayDeadLetters(consumerName interface{}, sequences ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{consumerName}, sequences...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetters", reflect.TypeOf((*MockNatsClient)(nil).ReplayDeadLetters), varargs...)
+}
+
 // SubscribeDurable mocks base method.
-func (m *MockNatsClient) SubscribeDurable(subject, streamName, consumerName string, handler func(*nats.Msg) error) {
+func (m *MockNatsClient) SubscribeDurable(subject, streamName, consumerName string, handler func(*nats.Msg) error, opts ...app.SubscribeOption) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SubscribeDurable", subject, streamName, consumerName, handler)
+	varargs := []interface{}{subject, streamName, consumerName, handler}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "SubscribeDurable", varargs...)
 }
 
 // SubscribeDurable indicates an expected call of SubscribeDurable.
-func (mr *MockNatsClientMockRecorder) SubscribeDurable(subject, streamName, consumerName, handler interface{}) *gomock.Call {
+func (mr *MockNatsClientMockRecorder) SubscribeDurable(subject, streamName, consumerName, handler interface{}, opts ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeDurable", reflect.TypeOf((*MockNatsClient)(nil).SubscribeDurable), subject, streamName, consumerName, handler)
+	varargs := append([]interface{}{subject, streamName, consumerName, handler}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeDurable", reflect.TypeOf((*MockNatsClient)(nil).SubscribeDurable), varargs...)
 }
 
 // SubscribeEphemeral mocks base method.
@@ -168,6 +168,35 @@ func (mr *MockUserApplicationServiceMockRecorder) LoginWithTotpCode(ctx, passwor
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginWithTotpCode", reflect.TypeOf((*MockUserApplicationService)(nil).LoginWithTotpCode), ctx, passwordVerificationTokenID, code)
 }
 
+// Logout mocks base method.
+func (m *MockUserApplicationService) Logout(ctx context.Context, logoutInput dto.LogoutInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, logoutInput)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserApplicationServiceMockRecorder) Logout(ctx, logoutInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserApplicationService)(nil).Logout), ctx, logoutInput)
+}
+
+// SetUserRoles mocks base method.
+func (m *MockUserApplicationService) SetUserRoles(ctx context.Context, setRolesInput dto.SetRolesInput) (*dto.UserOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRoles", ctx, setRolesInput)
+	ret0, _ := ret[0].(*dto.UserOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRoles indicates an expected call of SetUserRoles.
+func (mr *MockUserApplicationServiceMockRecorder) SetUserRoles(ctx, setRolesInput interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockUserApplicationService)(nil).SetUserRoles), ctx, setRolesInput)
+}
+
 // SocialLogin mocks base method.
 func (m *MockUserApplicationService) SocialLogin(ctx context.Context, socialAccount dto.SocialLoginInput) (*dto.LoginOutput, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,143 @@
+package mongorepositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"shared/outbox"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Messages fetched by a relay are leased for this long so that other
+// replicas skip them while they are being published.
+const leaseDuration = 30 * time.Second
+
+var _ outbox.Store = (*outboxMongoDbRepository)(nil)
+
+type MessageModel struct {
+	ID            string              `bson:"_id"`
+	Subject       string              `bson:"subject"`
+	Payload       string              `bson:"payload"`
+	CreatedAt     primitive.DateTime  `bson:"createdAt"`
+	Attempts      int                 `bson:"attempts"`
+	LastError     string              `bson:"lastError,omitempty"`
+	NextAttemptAt primitive.DateTime  `bson:"nextAttemptAt"`
+	PublishedAt   *primitive.DateTime `bson:"publishedAt"`
+}
+
+type outboxMongoDbRepository struct {
+	mongoDB            *mongo.Database
+	messagesCollection *mongo.Collection
+	logger             zerolog.Logger
+}
+
+func (m MessageModel) toEntity() outbox.Message {
+	var publishedAt *time.Time
+	if m.PublishedAt != nil {
+		t := m.PublishedAt.Time()
+		publishedAt = &t
+	}
+	return outbox.Message{
+		ID:            m.ID,
+		Subject:       m.Subject,
+		Payload:       []byte(m.Payload),
+		CreatedAt:     m.CreatedAt.Time(),
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		NextAttemptAt: m.NextAttemptAt.Time(),
+		PublishedAt:   publishedAt,
+	}
+}
+
+func toMongoDB(m outbox.Message) MessageModel {
+	return MessageModel{
+		ID:            m.ID,
+		Subject:       m.Subject,
+		Payload:       string(m.Payload),
+		CreatedAt:     primitive.NewDateTimeFromTime(m.CreatedAt),
+		Attempts:      m.Attempts,
+		LastError:     m.LastError,
+		NextAttemptAt: primitive.NewDateTimeFromTime(m.NextAttemptAt),
+	}
+}
+
+func NewOutboxRepository(m *mongo.Database, logger zerolog.Logger) *outboxMongoDbRepository {
+	messagesCollection := m.Collection("outbox_messages")
+	return &outboxMongoDbRepository{m, messagesCollection, logger}
+}
+
+func (r *outboxMongoDbRepository) Save(ctx context.Context, messages ...outbox.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, 0, len(messages))
+	for _, message := range messages {
+		documents = append(documents, toMongoDB(message))
+	}
+	_, err := r.messagesCollection.InsertMany(ctx, documents)
+	if err != nil {
+		return fmt.Errorf("outboxMongoDbRepository Save -> InsertMany: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxMongoDbRepository) FetchPending(ctx context.Context, limit int) ([]outbox.Message, error) {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"publishedAt":   nil,
+		"nextAttemptAt": bson.M{"$lte": primitive.NewDateTimeFromTime(now)},
+	}
+	update := bson.M{"$set": bson.M{"nextAttemptAt": primitive.NewDateTimeFromTime(now.Add(leaseDuration))}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"createdAt": 1}).
+		SetReturnDocument(options.After)
+
+	messages := make([]outbox.Message, 0, limit)
+	for len(messages) < limit {
+		var message MessageModel
+		err := r.messagesCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&message)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				break
+			}
+			return nil, fmt.Errorf("outboxMongoDbRepository FetchPending -> FindOneAndUpdate: %w", err)
+		}
+		messages = append(messages, message.toEntity())
+	}
+	return messages, nil
+}
+
+func (r *outboxMongoDbRepository) MarkPublished(ctx context.Context, messageID string) error {
+	update := bson.M{"$set": bson.M{"publishedAt": primitive.NewDateTimeFromTime(time.Now().UTC())}}
+	_, err := r.messagesCollection.UpdateByID(ctx, messageID, update)
+	if err != nil {
+		return fmt.Errorf("outboxMongoDbRepository MarkPublished -> UpdateByID: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxMongoDbRepository) MarkFailed(
+	ctx context.Context,
+	messageID string,
+	attempts int,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	update := bson.M{"$set": bson.M{
+		"attempts":      attempts,
+		"nextAttemptAt": primitive.NewDateTimeFromTime(nextAttemptAt),
+		"lastError":     lastError,
+	}}
+	_, err := r.messagesCollection.UpdateByID(ctx, messageID, update)
+	if err != nil {
+		return fmt.Errorf("outboxMongoDbRepository MarkFailed -> UpdateByID: %w", err)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"shared/authz"
 
 	"github.com/rs/zerolog"
 	"go.mongodb.org/mongo-driver/bson"
@@ -24,6 +25,7 @@ type UserModel struct {
 	UpdatedAt      *primitive.DateTime  `bson:"updatedAt,omitempty"`
 	SocialAccounts []SocialAccountModel `bson:"socialAccounts,omitempty"`
 	MfaSettings    MfaSettingsModel     `bson:"mfaSettingsModel,omitempty"`
+	Roles          []string             `bson:"roles,omitempty"`
 }
 
 type SocialAccountModel struct {
@@ -71,6 +73,11 @@ func toEntity(u UserModel) (*userEntity.User, error) {
 		u.MfaSettings.UpdatedAt.Time(),
 	)
 
+	roles := make([]authz.Role, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		roles = append(roles, authz.Role(role))
+	}
+
 	user, err := userEntity.NewUserFromDatabase(
 		u.ID,
 		u.Email,
@@ -80,6 +87,7 @@ func toEntity(u UserModel) (*userEntity.User, error) {
 		nil,
 		socialAccounts,
 		mfaSettings,
+		roles,
 	)
 	if err != nil {
 		return nil, err
@@ -106,6 +114,11 @@ func toMongoDB(u userEntity.User) (UserModel, error) {
 		UpdatedAt:    primitive.NewDateTimeFromTime(u.MfaSettings().UpdatedAt()),
 	}
 
+	roles := make([]string, 0, len(u.Roles()))
+	for _, role := range u.Roles() {
+		roles = append(roles, string(role))
+	}
+
 	return UserModel{
 		ID:        u.ID(),
 		Name:      u.Name(),
@@ -116,6 +129,7 @@ func toMongoDB(u userEntity.User) (UserModel, error) {
 		UpdatedAt:      nil,
 		SocialAccounts: socialAccountMongo,
 		MfaSettings:    mfaSettings,
+		Roles:          roles,
 	}, nil
 }
 
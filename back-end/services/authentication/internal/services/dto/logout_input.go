@@ -0,0 +1,6 @@
+package dto
+
+type LogoutInput struct {
+	UserID    string
+	SessionID string
+}
@@ -0,0 +1,9 @@
+package dto
+
+// SetRolesInput replaces the roles of UserID, ActorID is the user making the
+// change.
+type SetRolesInput struct {
+	ActorID string
+	UserID  string
+	Roles   []string
+}
@@ -1,8 +1,11 @@
 package dto
 
+import "shared/authz"
+
 type UserOutput struct {
-	ID           string `json:"id"`
-	Name         string `json:"name"`
-	Email        string `json:"email"`
-	IsMfaEnabled bool   `json:"isMfaEnabled"`
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	Email        string       `json:"email"`
+	IsMfaEnabled bool         `json:"isMfaEnabled"`
+	Roles        []authz.Role `json:"roles"`
 }
@@ -7,7 +7,6 @@ import (
 	authRepository "authentication/internal/repositories/authentication"
 	userRepository "authentication/internal/repositories/user"
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -15,8 +14,11 @@ import (
 	"github.com/rs/zerolog"
 
 	domainDto "authentication/internal/services/dto"
+	storage "authentication/pkg/storage/mongo"
+	"shared/authz"
 	customErrors "shared/errors"
-	nats "shared/messaging/nats"
+	"shared/events"
+	"shared/outbox"
 )
 
 var (
@@ -29,6 +31,8 @@ var (
 	ErrTotpCodeNotValid             = customErrors.NewIncorrectInputError("totp_not_valid", "Not valid code")
 	ErrTotpMfaAlreadyActiveNotValid = customErrors.NewIncorrectInputError("totp_already_enabled", "TOTP MFA already enabled")
 	ErrTotpMfaNotEnabled            = customErrors.NewIncorrectInputError("totp_not_enabled", "TOTP MFA is not enabled")
+	ErrUnknownRole                  = customErrors.NewIncorrectInputError("unknown_role", "Unknown role")
+	ErrRolesForbidden               = customErrors.NewForbiddenError("roles_forbidden", "You are not allowed to change roles")
 )
 
 var _ UserApplicationService = (*userApplicationService)(nil)
@@ -39,7 +43,8 @@ type userApplicationService struct {
 	userDomainService           domainServices.UserDomainService
 	logger                      zerolog.Logger
 	authenticationDomainService domainServices.AuthenticationDomainService
-	natsClient                  nats.NatsClient
+	transactor                  storage.Transactor
+	outbox                      outbox.Writer
 }
 
 func UserEntityToOutput(user *userEntity.User) *domainDto.UserOutput {
@@ -51,6 +56,7 @@ func UserEntityToOutput(user *userEntity.User) *domainDto.UserOutput {
 		Name:         user.Name(),
 		Email:        user.Email(),
 		IsMfaEnabled: user.MfaSettings().IsMfaEnabled(),
+		Roles:        user.Roles(),
 	}
 }
 
@@ -66,6 +72,8 @@ type UserApplicationService interface {
 	GenerateTotpSetup(ctx context.Context, userID string) (domainServices.TotpSetupInfo, error)
 	EnableTotp(ctx context.Context, userID string, otp string) error
 	DisableTotp(ctx context.Context, userID string, otp string) error
+	SetUserRoles(ctx context.Context, setRolesInput domainDto.SetRolesInput) (*domainDto.UserOutput, error)
+	Logout(ctx context.Context, logoutInput domainDto.LogoutInput) error
 }
 
 func NewUserApplicationService(
@@ -74,9 +82,10 @@ func NewUserApplicationService(
 	logger zerolog.Logger,
 	userDomainService domainServices.UserDomainService,
 	authenticationDomainService domainServices.AuthenticationDomainService,
-	natsClient nats.NatsClient,
+	transactor storage.Transactor,
+	outboxWriter outbox.Writer,
 ) userApplicationService {
-	return userApplicationService{userRepository, authenticationRepository, userDomainService, logger, authenticationDomainService, natsClient}
+	return userApplicationService{userRepository, authenticationRepository, userDomainService, logger, authenticationDomainService, transactor, outboxWriter}
 }
 
 func (u userApplicationService) GetUserByID(ctx context.Context, userID string) (*domainDto.UserOutput, error) {
@@ -87,57 +96,40 @@ func (u userApplicationService) GetUserByID(ctx context.Context, userID string)
 	return UserEntityToOutput(user), err
 }
 
-type UserCreatedEvent struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	ID    string `json:"id"`
-}
-
-type UserUpdatedEvent struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
-}
-
-type UserDeletedEvent struct {
-	ID string `json:"id"`
-}
-
-const (
-	userNotificationCreationSubject = "notifications.created"
-)
+const producerName = "authentication"
 
 var (
 	MFAEnabledNotificationTypeID  = "mfa-enabled-v1"
 	MFADisabledNotificationTypeID = "mfa-disabled-v1"
 )
 
-type NotificationCreatedEvent struct {
-	NotificationTypeID string      `json:"notificationTypeID"`
-	UserID             string      `json:"userID"`
-	Data               interface{} `json:"data,omitempty"`
-}
-
 // Creates a user from user input
 func (u userApplicationService) CreateUser(
 	ctx context.Context,
 	createUserParams userEntity.CreateUserParams,
 ) (*domainDto.UserOutput, error) {
-	createdUser, err := u.userDomainService.CreateUser(ctx, createUserParams)
-	if err != nil {
-		return nil, fmt.Errorf("userApplicationService -> CreateUser -  u.userDomainService.CreateUser: %w", err)
-	}
+	var createdUser *userEntity.User
+	err := u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		createdUser, err = u.userDomainService.CreateUser(ctx, createUserParams)
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> CreateUser -  u.userDomainService.CreateUser: %w", err)
+		}
 
-	bytes, err := json.Marshal(UserCreatedEvent{
-		Name:  createdUser.Name(),
-		Email: createdUser.Email(),
-		ID:    createdUser.ID(),
+		message, err := events.NewOutboxMessage(ctx, producerName, events.UserCreated{
+			Name:  createdUser.Name(),
+			Email: createdUser.Email(),
+			ID:    createdUser.ID(),
+		})
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> CreateUser -  events.NewOutboxMessage: %w", err)
+		}
+		return u.outbox.Save(ctx, message)
 	})
-
 	if err != nil {
-		return nil, fmt.Errorf("userApplicationService -> CreateUser -  json.Marshal: %w", err)
+		return nil, err
 	}
 
-	u.natsClient.PublishMessage("users.created", string(bytes))
 	return UserEntityToOutput(createdUser), nil
 }
 
@@ -159,24 +151,82 @@ func (u userApplicationService) UpdateUser(
 		user.SetName(updateUserInput.Name)
 	}
 
-	err = u.userRepository.Update(ctx, *user)
+	var updatedUser *userEntity.User
+	err = u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := u.userRepository.Update(ctx, *user)
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> UpdateUser - u.userRepository.Update: %w", err)
+		}
+
+		updatedUser, err = u.userRepository.GetByID(ctx, updateUserInput.ID)
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> UpdateUser - u.userRepository.GetByID: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, events.UserUpdated{
+			Name:  updatedUser.Name(),
+			ID:    updatedUser.ID(),
+			Roles: rolesEvent(updatedUser.Roles()),
+		})
+		if err != nil {
+			return err
+		}
+		return u.outbox.Save(ctx, message)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("userApplicationService -> UpdateUser - u.userRepository.Update: %w", err)
+		return nil, err
 	}
+	return UserEntityToOutput(updatedUser), nil
+}
 
-	updatedUser, err := u.userRepository.GetByID(ctx, updateUserInput.ID)
+// Replaces the roles of a user, only users permitted to manage roles can
+// do it
+func (u userApplicationService) SetUserRoles(
+	ctx context.Context,
+	setRolesInput domainDto.SetRolesInput,
+) (*domainDto.UserOutput, error) {
+	actor, err := u.userRepository.GetByID(ctx, setRolesInput.ActorID)
 	if err != nil {
-		return nil, fmt.Errorf("userApplicationService -> UpdateUser - u.userRepository.GetByID: %w", err)
+		return nil, fmt.Errorf("userApplicationService -> SetUserRoles - u.userRepository.GetByID: %w", err)
+	}
+	if actor == nil || !actor.Can(authz.PermissionManageRoles) {
+		return nil, ErrRolesForbidden
+	}
+	roles, err := authz.ParseRoles(setRolesInput.Roles)
+	if err != nil {
+		return nil, ErrUnknownRole
 	}
-	bytes, err := json.Marshal(UserUpdatedEvent{
-		Name: updatedUser.Name(),
-		ID:   updatedUser.ID(),
+
+	user, err := u.userRepository.GetByID(ctx, setRolesInput.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("userApplicationService -> SetUserRoles - u.userRepository.GetByID: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	err = user.SetRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := u.userRepository.Update(ctx, *user)
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> SetUserRoles - u.userRepository.Update: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, events.UserUpdated{
+			Name:  user.Name(),
+			ID:    user.ID(),
+			Roles: rolesEvent(user.Roles()),
+		})
+		if err != nil {
+			return err
+		}
+		return u.outbox.Save(ctx, message)
 	})
 	if err != nil {
 		return nil, err
 	}
-	u.natsClient.PublishMessage("users.updated", string(bytes))
-	return UserEntityToOutput(updatedUser), nil
+	return UserEntityToOutput(user), nil
 }
 
 // Deletes a user
@@ -193,19 +243,20 @@ func (u userApplicationService) DeleteUser(
 		return ErrUserNotFound
 	}
 
-	err = u.userRepository.Delete(ctx, deleteUserInput.ID)
-	if err != nil {
-		return fmt.Errorf("userApplicationService -> DeleteUser - u.userRepository.Delete: %w", err)
-	}
+	return u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := u.userRepository.Delete(ctx, deleteUserInput.ID)
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> DeleteUser - u.userRepository.Delete: %w", err)
+		}
 
-	bytes, err := json.Marshal(UserDeletedEvent{
-		ID: deleteUserInput.ID,
+		message, err := events.NewOutboxMessage(ctx, producerName, events.UserDeleted{
+			ID: deleteUserInput.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> DeleteUser - events.NewOutboxMessage: %w", err)
+		}
+		return u.outbox.Save(ctx, message)
 	})
-	if err != nil {
-		return fmt.Errorf("userApplicationService -> DeleteUser - json.Marshal: %w", err)
-	}
-	u.natsClient.PublishMessage("users.deleted", string(bytes))
-	return nil
 }
 
 func (u userApplicationService) LoginWithEmailAndPassword(ctx context.Context, email string, password string) (domainDto.LoginOutput, error) {
@@ -276,6 +327,7 @@ func (u userApplicationService) LoginWithTotpCode(ctx context.Context, passwordV
 		ID:    user.ID(),
 		Email: user.Email(),
 		Name:  user.Name(),
+		Roles: user.Roles(),
 	}, nil
 }
 
@@ -366,11 +418,38 @@ func (u userApplicationService) ChangeCurrentPassword(
 	}
 
 	user.SetPasswordHash(newPasswordHash)
-	err = u.userRepository.Update(ctx, *user)
+	return u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := u.userRepository.Update(ctx, *user)
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> ChangeCurrentPassword - u.userRepository.Update: %w", err)
+		}
+
+		message, err := events.NewOutboxMessage(ctx, producerName, events.UserPasswordChanged{
+			ID: user.ID(),
+		})
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> ChangeCurrentPassword - events.NewOutboxMessage: %w", err)
+		}
+		return u.outbox.Save(ctx, message)
+	})
+}
+
+// Announces the end of a session, the session itself is cleared by the caller
+func (u userApplicationService) Logout(
+	ctx context.Context,
+	logoutInput domainDto.LogoutInput,
+) error {
+	message, err := events.NewOutboxMessage(ctx, producerName, events.UserLoggedOut{
+		ID:        logoutInput.UserID,
+		SessionID: logoutInput.SessionID,
+	})
 	if err != nil {
-		return fmt.Errorf("userApplicationService -> ChangeCurrentPassword - u.userRepository.Update: %w", err)
+		return fmt.Errorf("userApplicationService -> Logout - events.NewOutboxMessage: %w", err)
+	}
+	err = u.outbox.Save(ctx, message)
+	if err != nil {
+		return fmt.Errorf("userApplicationService -> Logout - u.outbox.Save: %w", err)
 	}
-
 	return nil
 }
 
@@ -434,19 +513,21 @@ func (u userApplicationService) EnableTotp(
 	}
 
 	user.MfaSettings().SetMfaStatus(true)
-	err = u.userRepository.Update(ctx, *user)
-	if err != nil {
-		return fmt.Errorf("userApplicationService -> EnableTotp - u.userRepository.Update: %w", err)
-	}
+	return u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := u.userRepository.Update(ctx, *user)
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> EnableTotp - u.userRepository.Update: %w", err)
+		}
 
-	bytes, err := json.Marshal(NotificationCreatedEvent{
-		UserID:             user.ID(),
-		NotificationTypeID: MFAEnabledNotificationTypeID,
+		message, err := events.NewOutboxMessage(ctx, producerName, events.NotificationCreated{
+			UserID:             user.ID(),
+			NotificationTypeID: MFAEnabledNotificationTypeID,
+		})
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> EnableTotp - events.NewOutboxMessage: %w", err)
+		}
+		return u.outbox.Save(ctx, message)
 	})
-
-	u.natsClient.PublishMessage(userNotificationCreationSubject, string(bytes))
-
-	return nil
 }
 
 // Enables MFA by validating the provided OTP
@@ -478,17 +559,27 @@ func (u userApplicationService) DisableTotp(
 
 	user.MfaSettings().SetMfaStatus(false)
 	user.MfaSettings().SetTotpSecret("")
-	err = u.userRepository.Update(ctx, *user)
-	if err != nil {
-		return fmt.Errorf("userApplicationService -> EnableTotp - u.userRepository.Update: %w", err)
-	}
+	return u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := u.userRepository.Update(ctx, *user)
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> EnableTotp - u.userRepository.Update: %w", err)
+		}
 
-	bytes, err := json.Marshal(NotificationCreatedEvent{
-		UserID:             user.ID(),
-		NotificationTypeID: MFADisabledNotificationTypeID,
+		message, err := events.NewOutboxMessage(ctx, producerName, events.NotificationCreated{
+			UserID:             user.ID(),
+			NotificationTypeID: MFADisabledNotificationTypeID,
+		})
+		if err != nil {
+			return fmt.Errorf("userApplicationService -> DisableTotp - events.NewOutboxMessage: %w", err)
+		}
+		return u.outbox.Save(ctx, message)
 	})
+}
 
-	u.natsClient.PublishMessage(userNotificationCreationSubject, string(bytes))
-
-	return nil
+func rolesEvent(roles []authz.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	return names
 }
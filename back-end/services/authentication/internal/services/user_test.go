@@ -6,7 +6,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/golang/mock/gomock"
 	"github.com/pquerna/otp/totp"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -15,6 +14,7 @@ import (
 	"authentication/config"
 
 	authRepository "authentication/internal/repositories/authentication/mongo"
+	outboxRepository "authentication/internal/repositories/outbox/mongo"
 	userRepository "authentication/internal/repositories/user/mongo"
 	fixtures "authentication/internal/test/fixtures"
 	storage "authentication/pkg/storage/mongo"
@@ -26,7 +26,6 @@ import (
 	applicationServices "authentication/internal/services"
 	dto "authentication/internal/services/dto"
 
-	mocks "authentication/internal/mocks/nats"
 	authRepo "authentication/internal/repositories/authentication"
 	userRepo "authentication/internal/repositories/user"
 	middlewares "authentication/internal/transport/http/middlewares"
@@ -68,11 +67,8 @@ func NewTestApplicationService(
 	logger zerolog.Logger,
 	t *testing.T,
 ) (applicationServices.UserApplicationService, userRepo.UserRepository, authRepo.AuthenticationRepository) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-	mockNatsClient := mocks.NewMockNatsClient(mockCtrl)
-	mockNatsClient.EXPECT().CreateStream(gomock.Any(), gomock.Any()).MinTimes(0)
-	mockNatsClient.EXPECT().PublishMessage(gomock.Any(), gomock.Any()).MinTimes(0)
+	transactor, err := storage.NewTransactor(mongo)
+	require.NoError(t, err)
 	userRepository := userRepository.NewUserRepository(mongo, logger)
 	authenticationRepository := authRepository.NewAuthenticationRepository(mongo, logger)
 	authenticationDomainService := domainServices.NewAuthenticationService(logger, authenticationRepository)
@@ -83,7 +79,8 @@ func NewTestApplicationService(
 		logger,
 		userDomainService,
 		authenticationDomainService,
-		mockNatsClient,
+		transactor,
+		outboxRepository.NewOutboxRepository(mongo, logger),
 	)
 	return applicationService, userRepository, authenticationRepository
 }
@@ -812,6 +809,21 @@ func TestUserApplicationService_ChangeCurrentPassword(t *testing.T) {
 	}
 }
 
+func TestUserApplicationService_Logout(t *testing.T) {
+	t.Parallel()
+	testConf := NewTestConfigWithDockerizedMongo(t)
+	logger := zerolog.New(os.Stdout).Level(zerolog.Disabled)
+	mongo := storage.NewMongoClient(logger, testConf)
+
+	applicationService, _, _ := NewTestApplicationService(testConf, mongo, logger, t)
+
+	err := applicationService.Logout(context.Background(), dto.LogoutInput{
+		UserID:    fixtures.GenerateUUID(),
+		SessionID: "session-id",
+	})
+	require.NoError(t, err)
+}
+
 func TestUserApplicationService_GenerateTotpSetup(t *testing.T) {
 	t.Parallel()
 	testConf := NewTestConfigWithDockerizedMongo(t)
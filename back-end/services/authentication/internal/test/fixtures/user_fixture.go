@@ -94,6 +94,7 @@ func GenerateUserEntity(t *testing.T, c CreateTestUser) userEntity.User {
 		nil,
 		[]socialAccountEntity.SocialAccount{},
 		mfaSettings,
+		nil,
 	)
 	if err != nil {
 		t.Fatal(err)
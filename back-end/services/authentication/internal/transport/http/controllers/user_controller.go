@@ -270,6 +270,28 @@ func (h *UserControllers) UpdateUser(c *gin.Context) {
 	handleOkResponse(c)
 }
 
+// Replaces the roles of a user
+func (h *UserControllers) SetUserRoles(c *gin.Context) {
+	var setRolesInput httpDto.SetRolesInput
+	if err := c.ShouldBindJSON(&setRolesInput); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	user, err := h.ApplicationService.SetUserRoles(
+		c.Request.Context(),
+		domainDto.SetRolesInput{
+			ActorID: h.SessionManager.GetUserID(c),
+			UserID:  c.Param("userID"),
+			Roles:   setRolesInput.Roles,
+		},
+	)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	handleResponseWithBody(c, httpDto.UserOutput{User: user})
+}
+
 // Deletes a user
 func (h *UserControllers) DeleteUser(c *gin.Context) {
 	userIDfromParams := c.Param("userID")
@@ -363,12 +385,24 @@ func (h *UserControllers) LoginWithTotpCode(c *gin.Context) {
 
 func (h *UserControllers) Logout(c *gin.Context) {
 	session := sessions.Default(c)
+	userID := getUserIDFromSession(session)
+	sessionID := session.ID()
 	session.Clear()
 	err := session.Save()
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
 		return
 	}
+	if userID != "" {
+		err = h.ApplicationService.Logout(c.Request.Context(), domainDto.LogoutInput{
+			UserID:    userID,
+			SessionID: sessionID,
+		})
+		if err != nil {
+			httpErrors.RespondWithError(c, err)
+			return
+		}
+	}
 	handleOkResponse(c)
 }
 
@@ -14,6 +14,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"shared/authz"
+	"shared/health"
+	"shared/lifecycle"
 	"testing"
 
 	"github.com/gin-contrib/sessions/cookie"
@@ -62,7 +65,7 @@ func NewServer(
 	}
 	config := &config.Config{}
 
-	routes.NewRouter(handler, applicationServiceMock, m, logger, config, sessionStore, sessionManager)
+	routes.NewRouter(handler, applicationServiceMock, m, logger, config, sessionStore, sessionManager, health.NewHealth(lifecycle.NewRunner(logger)))
 
 	server := httptest.NewServer(http.Handler(handler))
 
@@ -112,7 +115,8 @@ func TestUserControllers_GetUserByID(t *testing.T) {
 					"id": "abc",
 					"name": "abc",
 					"email": "abc",
-					"isMfaEnabled": false
+					"isMfaEnabled": false,
+					"roles": ["customer"]
 				}
 			  }`, statusCode: http.StatusOK},
 			prepareMocks: func() {
@@ -121,6 +125,7 @@ func TestUserControllers_GetUserByID(t *testing.T) {
 					Name:         "abc",
 					Email:        "abc",
 					IsMfaEnabled: false,
+					Roles:        []authz.Role{authz.RoleCustomer},
 				}
 				sessionManagerMock.EXPECT().GetUserID(gomock.Any()).Return("abc")
 				applicationServiceMock.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Return(&userOutput, nil)
@@ -134,7 +139,8 @@ func TestUserControllers_GetUserByID(t *testing.T) {
 					"id": "567",
 					"name": "568",
 					"email": "16",
-					"isMfaEnabled": true
+					"isMfaEnabled": true,
+					"roles": ["customer", "seller"]
 				}
 			  }`, statusCode: http.StatusOK},
 			prepareMocks: func() {
@@ -143,6 +149,7 @@ func TestUserControllers_GetUserByID(t *testing.T) {
 					Name:         "568",
 					Email:        "16",
 					IsMfaEnabled: true,
+					Roles:        []authz.Role{authz.RoleCustomer, authz.RoleSeller},
 				}
 				sessionManagerMock.EXPECT().GetUserID(gomock.Any()).Return("abz")
 				applicationServiceMock.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Return(&userOutput, nil)
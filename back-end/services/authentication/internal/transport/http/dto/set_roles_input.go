@@ -0,0 +1,5 @@
+package dto
+
+type SetRolesInput struct {
+	Roles []string `json:"roles" binding:"required,min=1"`
+}
@@ -5,7 +5,7 @@ import (
 	applicationServices "authentication/internal/services"
 	controllers "authentication/internal/transport/http/controllers"
 	middlewares "authentication/internal/transport/http/middlewares"
-	"net/http"
+	"shared/health"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -20,11 +20,12 @@ func NewRouter(
 	config *config.Config,
 	sessionsStore sessions.Store,
 	sessionManager controllers.SessionManager,
+	checks *health.Health,
 ) {
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
 	handler.Use(m.Session.Apply)
-	handler.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	checks.Register(handler)
 
 	controllers.SetupSocialLogin(sessionsStore, config)
 	r := controllers.NewUserControllers(u, logger, config, sessionManager)
@@ -36,6 +37,7 @@ func NewRouter(
 	v1.GET("/users/:userID", r.GetUserByID)
 	v1.PATCH("/users/:userID", r.UpdateUser)
 	v1.DELETE("/users/:userID", r.DeleteUser)
+	v1.PUT("/users/:userID/roles", r.SetUserRoles)
 	v1.GET("/users/me", r.GetCurrentUser)
 	v1.GET("/users/me/internal", r.GetCurrentUserInternal)
 
@@ -10,6 +10,7 @@ import (
 
 	"authentication/config"
 	"authentication/pkg/httpserver"
+	"shared/health"
 
 	applicationServices "authentication/internal/services"
 	controller "authentication/internal/transport/http/controllers"
@@ -24,9 +25,10 @@ func NewHTTPServer(
 	logger zerolog.Logger,
 	config *config.Config,
 	sessionsStore sessions.Store,
+	checks *health.Health,
 ) *httpserver.Server {
 	sessionManager := controller.NewSessionManager()
-	routes.NewRouter(handler, userApplicationService, m, logger, config, sessionsStore, sessionManager)
+	routes.NewRouter(handler, userApplicationService, m, logger, config, sessionsStore, sessionManager, checks)
 	logger.Info().Msg(fmt.Sprintf("Listening on %s port", config.HTTP.Port))
 	return httpserver.New(http.Handler(handler), httpserver.Port(config.HTTP.Port))
 }
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"context"
+	"shared/health"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func NewHealthChecker(db *mongo.Database) health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		return db.Client().Ping(ctx, readpref.Primary())
+	})
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor runs a function inside a MongoDB transaction. Collections
+// join it automatically when they receive the context passed to fn.
+type Transactor interface {
+	RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+var _ Transactor = (*transactor)(nil)
+
+type transactor struct {
+	client *mongo.Client
+}
+
+// ErrTransactionsNotSupported is returned for a standalone server, writes
+// spanning several collections would not be atomic on it.
+var ErrTransactionsNotSupported = errors.New("MongoDB deployment does not support transactions, run it as a replica set")
+
+// Transactions need a replica set or a sharded cluster, a single node replica
+// set is enough.
+func NewTransactor(db *mongo.Database) (*transactor, error) {
+	var hello bson.M
+	err := db.RunCommand(context.Background(), bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return nil, fmt.Errorf("NewTransactor -> db.RunCommand(hello): %w", err)
+	}
+	_, isReplicaSet := hello["setName"]
+	isSharded := hello["msg"] == "isdbgrid"
+	if !isReplicaSet && !isSharded {
+		return nil, ErrTransactionsNotSupported
+	}
+	return &transactor{client: db.Client()}, nil
+}
+
+func (t *transactor) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	return t.client.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		_, err := sessionContext.WithTransaction(sessionContext, func(sessionContext mongo.SessionContext) (interface{}, error) {
+			return nil, fn(sessionContext)
+		})
+		return err
+	})
+}
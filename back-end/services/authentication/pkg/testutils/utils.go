@@ -9,6 +9,7 @@ import (
 	"github.com/docker/go-connections/nat"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -37,7 +38,9 @@ func InitializeMongoContainer(ctx context.Context) (testcontainers.Container, st
 	req := testcontainers.ContainerRequest{
 		Image:        mongoImage.name,
 		ExposedPorts: []string{mongoImage.port + "/tcp"},
-		WaitingFor:   wait.ForListeningPort(nat.Port(mongoImage.port)),
+		// transactions need a replica set
+		Cmd:        []string{"--replSet", "rs0", "--bind_ip_all"},
+		WaitingFor: wait.ForListeningPort(nat.Port(mongoImage.port)),
 	}
 
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
@@ -58,9 +61,11 @@ func InitializeMongoContainer(ctx context.Context) (testcontainers.Container, st
 		return nil, "", err
 	}
 
-	uri := fmt.Sprintf("mongodb://%s:%s", hostIP, mappedPort.Port())
+	// the member is only known by its address inside the container, so
+	// clients connect to it directly
+	uri := fmt.Sprintf("mongodb://%s:%s/?directConnection=true", hostIP, mappedPort.Port())
 
-	_, err = mongo.NewClient(options.Client().ApplyURI(uri))
+	err = initiateReplicaSet(ctx, uri)
 	if err != nil {
 		return nil, "", err
 	}
@@ -69,3 +74,38 @@ func InitializeMongoContainer(ctx context.Context) (testcontainers.Container, st
 	log.Printf("TestContainers: container took %s\n", elapsed)
 	return container, uri, nil
 }
+
+func initiateReplicaSet(ctx context.Context, uri string) error {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+
+	admin := client.Database("admin")
+	err = admin.RunCommand(ctx, bson.D{{Key: "replSetInitiate", Value: bson.M{
+		"_id":     "rs0",
+		"members": bson.A{bson.M{"_id": 0, "host": "localhost:27017"}},
+	}}}).Err()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for {
+		var hello bson.M
+		err = admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+		if err != nil {
+			return err
+		}
+		if hello["isWritablePrimary"] == true {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
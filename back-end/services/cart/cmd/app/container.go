@@ -1,19 +1,27 @@
 package main
 
 import (
-	"os"
+	"context"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
 	"cart/config"
-	"cart/pkg/httpserver"
+	"shared/authz"
+	"shared/health"
+	inboxStore "shared/inbox/pg"
+	"shared/lifecycle"
+	"shared/outbox"
+	outboxStore "shared/outbox/pg"
 	pgStorage "shared/storage/pg"
 
 	httpServ "cart/internal/transport/http"
 
 	cartInfraRepository "cart/internal/repositories/cart/pg"
 	userInfraRepository "cart/internal/repositories/customer/pg"
+	exchangeRateInfraRepository "cart/internal/repositories/exchangerate/pg"
 	productInfraRepository "cart/internal/repositories/product/pg"
 	applicationServices "cart/internal/services"
 	nats "shared/messaging/nats"
@@ -23,30 +31,67 @@ import (
 	controllers "cart/internal/transport/http/controllers"
 )
 
-func buildDependencies() (
+const (
+	cartStream         = "carts"
+	cartStreamSubjects = "carts.*"
+)
+
+const maxConsumerLag = 1000
+
+func buildDependencies(runner *lifecycle.Runner, logger zerolog.Logger) (
 	messaging.UserMessagingHandlers,
 	messaging.ProductMessagingHandlers,
-	*httpserver.Server,
+	messaging.OrderMessagingHandlers,
+	messaging.ExchangeRateMessagingHandlers,
 	error,
 ) {
-	logger := zerolog.New(os.Stdout)
 	config, err := config.NewConfig()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
+	checks := health.NewHealth(runner)
 	pg := pgStorage.NewClient(logger, pgStorage.Config{DSN: config.PgSDN})
+	runner.OnShutdown("postgres", lifecycle.Closer(pg))
+	checks.AddReadinessCheck("postgres", health.Ping(pg))
 	nats := nats.NewNatsClient()
+	runner.OnShutdown("nats", nats.Drain)
+	checks.AddReadinessCheck("nats", health.Connection(nats))
+	checks.AddDegradedCheck("consumer lag", health.ConsumerLag(nats, maxConsumerLag))
+	err = nats.CreateStream(cartStream, cartStreamSubjects)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	transactor := pgStorage.NewTransactor(pg)
+	inbox := inboxStore.NewInbox(pg, transactor)
+	inboxCleaner := inboxStore.NewCleaner(pg, logger)
+	runner.Go("inbox cleaner", func(ctx context.Context) error {
+		inboxCleaner.Run(ctx)
+		return nil
+	})
+	outboxRepo := outboxStore.NewStore(pg)
+	relay := outbox.NewRelay(outboxRepo, nats, logger)
+	runner.Go("outbox relay", func(ctx context.Context) error {
+		relay.Run(ctx)
+		return nil
+	})
 	userRepo := userInfraRepository.NewCustomerRepository(pg, logger)
 	productRepo := productInfraRepository.NewProductRepository(pg, logger)
 	cartRepo := cartInfraRepository.NewCartRepository(pg, logger)
+	exchangeRateRepo := exchangeRateInfraRepository.NewExchangeRateRepository(pg, logger)
 
 	userApplicationService := applicationServices.NewCustomerApplicationService(userRepo, logger)
-	productAppService := applicationServices.NewProductApplicationService(productRepo, cartRepo, logger, nats)
+	productAppService := applicationServices.NewProductApplicationService(productRepo, cartRepo, exchangeRateRepo, logger, nats, transactor, outboxRepo)
+
+	exchangeRateAppService := applicationServices.NewExchangeRateApplicationService(exchangeRateRepo, logger)
 
 	productController := controllers.NewProductController(productAppService, logger, config)
 
-	userMessageHandlers := messaging.NewCustomerMessagingHandlers(nats, userApplicationService, logger)
-	productMessageHandlers := messaging.NewProductMessagingHandlers(nats, productAppService, logger)
-	httpServer := httpServ.NewHTTPServer(productController, gin.New(), logger, config, pg)
-	return userMessageHandlers, productMessageHandlers, httpServer, nil
+	userMessageHandlers := messaging.NewCustomerMessagingHandlers(nats, inbox, userApplicationService, logger)
+	productMessageHandlers := messaging.NewProductMessagingHandlers(nats, inbox, productAppService, logger)
+	orderMessageHandlers := messaging.NewOrderMessagingHandlers(nats, inbox, productAppService, logger)
+	exchangeRateMessageHandlers := messaging.NewExchangeRateMessagingHandlers(nats, inbox, exchangeRateAppService, logger)
+	verifier := authz.NewJWKSVerifier(config.Identity.JWKSURL, config.Identity.Issuer, &http.Client{Timeout: 5 * time.Second})
+	httpServer := httpServ.NewHTTPServer(productController, gin.New(), logger, config, pg, checks, verifier)
+	runner.AddServer("http server", httpServer)
+	return userMessageHandlers, productMessageHandlers, orderMessageHandlers, exchangeRateMessageHandlers, nil
 }
@@ -12,9 +12,10 @@ import (
 
 type (
 	Config struct {
-		App   App    `yaml:"app" validate:"required"`
-		HTTP  HTTP   `yaml:"http" validate:"required"`
-		PgSDN string `yaml:"pg_dsn"  validate:"required"`
+		App      App      `yaml:"app" validate:"required"`
+		HTTP     HTTP     `yaml:"http" validate:"required"`
+		PgSDN    string   `yaml:"pg_dsn"  validate:"required"`
+		Identity Identity `yaml:"identity" validate:"required"`
 	}
 	App struct {
 		Name    string `yaml:"name" validate:"required"`
@@ -24,6 +25,12 @@ type (
 	HTTP struct {
 		Port string `yaml:"port"  validate:"required"`
 	}
+
+	// Identity verifies the identity tokens the gateway signs
+	Identity struct {
+		JWKSURL string `yaml:"jwks_url" validate:"required,url"`
+		Issuer  string `yaml:"issuer" validate:"required"`
+	}
 )
 
 func (c Config) Validate() error {
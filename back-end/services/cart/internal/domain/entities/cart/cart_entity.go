@@ -1,17 +1,28 @@
 package cart
 
 import (
+	productEntity "cart/internal/domain/entities/product"
 	customErrors "shared/errors"
+	"shared/money"
 )
 
 var (
 	ErrInvalidCustomerID = customErrors.NewIncorrectInputError("cart.products.add.invalid_customer_id", "invalid customer ID")
+	ErrProductNotFound   = customErrors.NewNotFoundError("cart.products.add.product_not_found", "Product not found")
+	ErrInsufficientStock = customErrors.NewIncorrectInputError("cart.products.add.insufficient_stock", "Not enough products in stock")
+	ErrVariantNotFound   = customErrors.NewNotFoundError("cart.products.add.variant_not_found", "Variant not found")
+	ErrVariantRequired   = customErrors.NewIncorrectInputError("cart.products.add.variant_required", "Choose a variant of the product")
 )
 
 // TODO: add created_at/updated_at
+// CartProduct is a cart line. A product with variants has a line per chosen
+// variant, VariantID is empty for products without variants.
 type CartProduct struct {
 	ProductID string
+	VariantID string
 	Quantity  int
+	// price of the product when it was added to the cart
+	AddedPrice money.Money
 }
 
 type Cart struct {
@@ -39,6 +50,7 @@ func (p ProductQuantityChanged) EventType() string {
 
 type ProductRemoved struct {
 	ProductID string
+	VariantID string
 }
 
 func (r ProductRemoved) EventType() string {
@@ -77,14 +89,111 @@ type Event interface {
 	EventType() string
 }
 
-func (cart Cart) UpdateProductsInCart(productToUpdate CartProduct) (Cart, error) {
+// UpdateProductsInCart sets the quantity of a cart line. product is the local
+// copy of the catalog product and is zero when the product is unknown or was
+// deleted; lines can always be removed, even for such products.
+func (cart Cart) UpdateProductsInCart(productToUpdate CartProduct, product productEntity.Product) (Cart, error) {
 	if productToUpdate.Quantity <= 0 {
 		return cart.deleteProductFromCart(productToUpdate)
 	}
+	if product.IsZero() || product.ID() != productToUpdate.ProductID {
+		return Cart{}, ErrProductNotFound
+	}
+
+	stock, price := product.Quantity(), product.Price()
+	if productToUpdate.VariantID == "" {
+		if len(product.Variants()) > 0 {
+			return Cart{}, ErrVariantRequired
+		}
+	} else {
+		variant, ok := product.Variant(productToUpdate.VariantID)
+		if !ok {
+			return Cart{}, ErrVariantNotFound
+		}
+		stock, price = variant.Quantity(), variant.Price()
+	}
+	if productToUpdate.Quantity > stock {
+		return Cart{}, ErrInsufficientStock
+	}
+	productToUpdate.AddedPrice = price
+	return cart.setProductQuantity(productToUpdate), nil
+}
+
+// ClampToStock lowers the cart line of product to the available stock and
+// removes it when the product is sold out.
+func (cart Cart) ClampToStock(product productEntity.Product) Cart {
+	return cart.clampLine(CartProduct{ProductID: product.ID()}, product.Quantity())
+}
+
+// ClampVariantToStock does the same as ClampToStock for the line of a variant.
+func (cart Cart) ClampVariantToStock(variant productEntity.Variant) Cart {
+	return cart.clampLine(CartProduct{ProductID: variant.ProductID(), VariantID: variant.ID()}, variant.Quantity())
+}
+
+// AcceptCurrentPrice sets the added price of the lines of product to its
+// current price once the shopper agreed to pay it, orders refuses checkout
+// while the two differ. Lines of deleted variants are left as they are.
+func (cart Cart) AcceptCurrentPrice(product productEntity.Product) Cart {
+	for i := range cart.products {
+		productInCart := &cart.products[i]
+		if productInCart.ProductID != product.ID() {
+			continue
+		}
+		price := product.Price()
+		if productInCart.VariantID != "" {
+			variant, ok := product.Variant(productInCart.VariantID)
+			if !ok {
+				continue
+			}
+			price = variant.Price()
+		}
+		if productInCart.AddedPrice.Equal(price) {
+			continue
+		}
+		productInCart.AddedPrice = price
+		cart.events = append(cart.events, ProductQuantityChanged{Product: *productInCart})
+	}
+	return cart
+}
+
+// RemoveProduct removes every line of the product, whatever its variant.
+func (cart Cart) RemoveProduct(productID string) Cart {
+	lines := make([]CartProduct, 0, len(cart.products))
+	for _, productInCart := range cart.products {
+		if productInCart.ProductID == productID {
+			lines = append(lines, productInCart)
+		}
+	}
+	for _, line := range lines {
+		cart, _ = cart.deleteProductFromCart(line)
+	}
+	return cart
+}
+
+func (cart Cart) clampLine(line CartProduct, available int) Cart {
+	for _, productInCart := range cart.products {
+		if !productInCart.sameLine(line) || productInCart.Quantity <= available {
+			continue
+		}
+		productInCart.Quantity = available
+		if productInCart.Quantity <= 0 {
+			cart, _ = cart.deleteProductFromCart(productInCart)
+			return cart
+		}
+		return cart.setProductQuantity(productInCart)
+	}
+	return cart
+}
+
+func (c CartProduct) sameLine(other CartProduct) bool {
+	return c.ProductID == other.ProductID && c.VariantID == other.VariantID
+}
+
+func (cart Cart) setProductQuantity(productToUpdate CartProduct) Cart {
 	isProductInCart := false
 	for i := range cart.products {
 		productInCart := &cart.products[i]
-		if productInCart.ProductID == productToUpdate.ProductID {
+		if productInCart.sameLine(productToUpdate) {
 			isProductInCart = true
 			productInCart.Quantity = productToUpdate.Quantity
 			cart.events = append(cart.events, ProductQuantityChanged{Product: *productInCart})
@@ -94,7 +203,7 @@ func (cart Cart) UpdateProductsInCart(productToUpdate CartProduct) (Cart, error)
 		cart.products = append(cart.products, productToUpdate)
 		cart.events = append(cart.events, AddedProduct{Product: productToUpdate})
 	}
-	return cart, nil
+	return cart
 }
 
 func (cart Cart) deleteProductFromCart(productToRemove CartProduct) (Cart, error) {
@@ -102,7 +211,7 @@ func (cart Cart) deleteProductFromCart(productToRemove CartProduct) (Cart, error
 	var indexOfProductToDelete int
 	for i := range cart.products {
 		productInCart := &cart.products[i]
-		if productInCart.ProductID == productToRemove.ProductID {
+		if productInCart.sameLine(productToRemove) {
 			isProductInCart = true
 			indexOfProductToDelete = i
 		}
@@ -110,7 +219,7 @@ func (cart Cart) deleteProductFromCart(productToRemove CartProduct) (Cart, error
 
 	if isProductInCart {
 		cart.products = append(cart.products[:indexOfProductToDelete], cart.products[indexOfProductToDelete+1:]...)
-		cart.events = append(cart.events, ProductRemoved{ProductID: productToRemove.ProductID})
+		cart.events = append(cart.events, ProductRemoved{ProductID: productToRemove.ProductID, VariantID: productToRemove.VariantID})
 	}
 	return cart, nil
 }
@@ -118,3 +227,23 @@ func (cart Cart) deleteProductFromCart(productToRemove CartProduct) (Cart, error
 func (cart Cart) isZero() bool {
 	return cart.customerID == ""
 }
+
+// RemoveOrderedProducts takes the ordered quantities out of the cart. Lines
+// that were raised after checkout keep the difference.
+func (cart Cart) RemoveOrderedProducts(orderedProducts []CartProduct) Cart {
+	for _, ordered := range orderedProducts {
+		for _, productInCart := range cart.products {
+			if !productInCart.sameLine(ordered) {
+				continue
+			}
+			productInCart.Quantity -= ordered.Quantity
+			if productInCart.Quantity <= 0 {
+				cart, _ = cart.deleteProductFromCart(productInCart)
+			} else {
+				cart = cart.setProductQuantity(productInCart)
+			}
+			break
+		}
+	}
+	return cart
+}
@@ -2,7 +2,10 @@ package cart_test
 
 import (
 	cartEntity "cart/internal/domain/entities/cart"
+	productEntity "cart/internal/domain/entities/product"
+	"shared/money"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gotest.tools/v3/assert"
@@ -19,6 +22,15 @@ func NewTestCart(t *testing.T, customerID string, products []cartEntity.CartProd
 	return cart
 }
 
+func NewTestProduct(id string, quantity int) productEntity.Product {
+	return productEntity.NewProductFromDatabase(id, money.MustParse("1.5", money.USD), quantity, "Banana", time.Now(), time.Now())
+}
+
+func NewTestProductWithVariant(id string, variantID string, quantity int) productEntity.Product {
+	variant := productEntity.NewVariantFromDatabase(variantID, id, "BANANA-XL", money.MustParse("2", money.USD), quantity, time.Now(), time.Now())
+	return NewTestProduct(id, 0).WithVariants([]productEntity.Variant{variant})
+}
+
 func TestCartEntity_NewCart(t *testing.T) {
 	t.Parallel()
 	type want struct {
@@ -94,11 +106,12 @@ func TestCartEntity_UpdateProductsInCart(t *testing.T) {
 		Events     []cartEntity.Event
 	}
 	testCases := []struct {
-		name   string
-		args   cartEntity.CartProduct
-		cart   cartEntity.Cart
-		want   want
-		expErr error
+		name    string
+		args    cartEntity.CartProduct
+		product productEntity.Product
+		cart    cartEntity.Cart
+		want    want
+		expErr  error
 	}{
 		{
 			name: "add_product_to_empty_cart",
@@ -107,18 +120,21 @@ func TestCartEntity_UpdateProductsInCart(t *testing.T) {
 				ProductID: "123",
 				Quantity:  2,
 			},
+			product: NewTestProduct("123", 10),
 			want: want{
 				CustomerID: "123",
 				Products: []cartEntity.CartProduct{
 					{
-						ProductID: "123",
-						Quantity:  2,
+						ProductID:  "123",
+						Quantity:   2,
+						AddedPrice: money.MustParse("1.5", money.USD),
 					},
 				},
 				Events: []cartEntity.Event{cartEntity.AddedProduct{
 					Product: cartEntity.CartProduct{
-						ProductID: "123",
-						Quantity:  2,
+						ProductID:  "123",
+						Quantity:   2,
+						AddedPrice: money.MustParse("1.5", money.USD),
 					},
 				},
 				},
@@ -137,6 +153,7 @@ func TestCartEntity_UpdateProductsInCart(t *testing.T) {
 				ProductID: "product_id_two",
 				Quantity:  2,
 			},
+			product: NewTestProduct("product_id_two", 10),
 			want: want{
 				CustomerID: "customer_id",
 				Products: []cartEntity.CartProduct{
@@ -145,14 +162,16 @@ func TestCartEntity_UpdateProductsInCart(t *testing.T) {
 						Quantity:  5,
 					},
 					{
-						ProductID: "product_id_two",
-						Quantity:  2,
+						ProductID:  "product_id_two",
+						Quantity:   2,
+						AddedPrice: money.MustParse("1.5", money.USD),
 					},
 				},
 				Events: []cartEntity.Event{cartEntity.AddedProduct{
 					Product: cartEntity.CartProduct{
-						ProductID: "product_id_two",
-						Quantity:  2,
+						ProductID:  "product_id_two",
+						Quantity:   2,
+						AddedPrice: money.MustParse("1.5", money.USD),
 					},
 				}},
 			},
@@ -170,6 +189,7 @@ func TestCartEntity_UpdateProductsInCart(t *testing.T) {
 				ProductID: "product_id_one",
 				Quantity:  2,
 			},
+			product: NewTestProduct("product_id_one", 10),
 			want: want{
 				CustomerID: "customer_id",
 				Products: []cartEntity.CartProduct{
@@ -269,13 +289,133 @@ func TestCartEntity_UpdateProductsInCart(t *testing.T) {
 			},
 			expErr: nil,
 		},
+		{
+			name: "error_unknown_product",
+			cart: NewTestCart(t, "customer_id", nil),
+			args: cartEntity.CartProduct{
+				ProductID: "product_id_one",
+				Quantity:  1,
+			},
+			expErr: cartEntity.ErrProductNotFound,
+		},
+		{
+			name: "error_quantity_above_stock",
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  1,
+				},
+			}),
+			args: cartEntity.CartProduct{
+				ProductID: "product_id_one",
+				Quantity:  4,
+			},
+			product: NewTestProduct("product_id_one", 3),
+			expErr:  cartEntity.ErrInsufficientStock,
+		},
+		{
+			name: "delete_unknown_product_from_cart",
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  1,
+				},
+			}),
+			args: cartEntity.CartProduct{
+				ProductID: "product_id_one",
+				Quantity:  0,
+			},
+			want: want{
+				CustomerID: "customer_id",
+				Products:   []cartEntity.CartProduct{},
+				Events: []cartEntity.Event{
+					cartEntity.ProductRemoved{
+						ProductID: "product_id_one",
+					},
+				},
+			},
+		},
+		{
+			name: "add_variant_next_to_another_variant",
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					VariantID: "variant_id_two",
+					Quantity:  1,
+				},
+			}),
+			args: cartEntity.CartProduct{
+				ProductID: "product_id_one",
+				VariantID: "variant_id_one",
+				Quantity:  2,
+			},
+			product: NewTestProductWithVariant("product_id_one", "variant_id_one", 5),
+			want: want{
+				CustomerID: "customer_id",
+				Products: []cartEntity.CartProduct{
+					{
+						ProductID: "product_id_one",
+						VariantID: "variant_id_two",
+						Quantity:  1,
+					},
+					{
+						ProductID:  "product_id_one",
+						VariantID:  "variant_id_one",
+						Quantity:   2,
+						AddedPrice: money.MustParse("2", money.USD),
+					},
+				},
+				Events: []cartEntity.Event{
+					cartEntity.AddedProduct{
+						Product: cartEntity.CartProduct{
+							ProductID:  "product_id_one",
+							VariantID:  "variant_id_one",
+							Quantity:   2,
+							AddedPrice: money.MustParse("2", money.USD),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "error_variant_required",
+			cart: NewTestCart(t, "customer_id", nil),
+			args: cartEntity.CartProduct{
+				ProductID: "product_id_one",
+				Quantity:  1,
+			},
+			product: NewTestProductWithVariant("product_id_one", "variant_id_one", 5),
+			expErr:  cartEntity.ErrVariantRequired,
+		},
+		{
+			name: "error_variant_not_found",
+			cart: NewTestCart(t, "customer_id", nil),
+			args: cartEntity.CartProduct{
+				ProductID: "product_id_one",
+				VariantID: "variant_id_two",
+				Quantity:  1,
+			},
+			product: NewTestProductWithVariant("product_id_one", "variant_id_one", 5),
+			expErr:  cartEntity.ErrVariantNotFound,
+		},
+		{
+			name: "error_quantity_above_variant_stock",
+			cart: NewTestCart(t, "customer_id", nil),
+			args: cartEntity.CartProduct{
+				ProductID: "product_id_one",
+				VariantID: "variant_id_one",
+				Quantity:  6,
+			},
+			product: NewTestProductWithVariant("product_id_one", "variant_id_one", 5),
+			expErr:  cartEntity.ErrInsufficientStock,
+		},
 	}
 
 	for _, tc := range testCases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			cart, err := tc.cart.UpdateProductsInCart(tc.args)
+			cart, err := tc.cart.UpdateProductsInCart(tc.args, tc.product)
 
 			require.Equal(t, tc.expErr, err)
 			assert.Equal(t, tc.want.CustomerID, cart.CustomerID())
@@ -284,3 +424,295 @@ func TestCartEntity_UpdateProductsInCart(t *testing.T) {
 		})
 	}
 }
+
+func TestCartEntity_RemoveOrderedProducts(t *testing.T) {
+	t.Parallel()
+	type want struct {
+		Products []cartEntity.CartProduct
+		Events   []cartEntity.Event
+	}
+	testCases := []struct {
+		name string
+		args []cartEntity.CartProduct
+		cart cartEntity.Cart
+		want want
+	}{
+		{
+			name: "remove_fully_ordered_product",
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  2,
+				},
+				{
+					ProductID: "product_id_two",
+					Quantity:  1,
+				},
+			}),
+			args: []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  2,
+				},
+			},
+			want: want{
+				Products: []cartEntity.CartProduct{
+					{
+						ProductID: "product_id_two",
+						Quantity:  1,
+					},
+				},
+				Events: []cartEntity.Event{
+					cartEntity.ProductRemoved{
+						ProductID: "product_id_one",
+					},
+				},
+			},
+		},
+		{
+			name: "keep_quantity_added_after_checkout",
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  5,
+				},
+			}),
+			args: []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  2,
+				},
+			},
+			want: want{
+				Products: []cartEntity.CartProduct{
+					{
+						ProductID: "product_id_one",
+						Quantity:  3,
+					},
+				},
+				Events: []cartEntity.Event{
+					cartEntity.ProductQuantityChanged{
+						Product: cartEntity.CartProduct{
+							ProductID: "product_id_one",
+							Quantity:  3,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ignore_product_no_longer_in_cart",
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  1,
+				},
+			}),
+			args: []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_two",
+					Quantity:  1,
+				},
+			},
+			want: want{
+				Products: []cartEntity.CartProduct{
+					{
+						ProductID: "product_id_one",
+						Quantity:  1,
+					},
+				},
+				Events: nil,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cart := tc.cart.RemoveOrderedProducts(tc.args)
+
+			assert.DeepEqual(t, tc.want.Products, cart.Products())
+			assert.DeepEqual(t, tc.want.Events, cart.Events())
+		})
+	}
+}
+
+func TestCartEntity_ClampToStock(t *testing.T) {
+	t.Parallel()
+	type want struct {
+		Products []cartEntity.CartProduct
+		Events   []cartEntity.Event
+	}
+	testCases := []struct {
+		name    string
+		product productEntity.Product
+		cart    cartEntity.Cart
+		want    want
+	}{
+		{
+			name:    "lower_quantity_to_stock",
+			product: NewTestProduct("product_id_one", 2),
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  5,
+				},
+			}),
+			want: want{
+				Products: []cartEntity.CartProduct{
+					{
+						ProductID: "product_id_one",
+						Quantity:  2,
+					},
+				},
+				Events: []cartEntity.Event{
+					cartEntity.ProductQuantityChanged{
+						Product: cartEntity.CartProduct{
+							ProductID: "product_id_one",
+							Quantity:  2,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "remove_sold_out_product",
+			product: NewTestProduct("product_id_one", 0),
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  1,
+				},
+			}),
+			want: want{
+				Products: []cartEntity.CartProduct{},
+				Events: []cartEntity.Event{
+					cartEntity.ProductRemoved{
+						ProductID: "product_id_one",
+					},
+				},
+			},
+		},
+		{
+			name:    "keep_quantity_within_stock",
+			product: NewTestProduct("product_id_one", 10),
+			cart: NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+				{
+					ProductID: "product_id_one",
+					Quantity:  5,
+				},
+			}),
+			want: want{
+				Products: []cartEntity.CartProduct{
+					{
+						ProductID: "product_id_one",
+						Quantity:  5,
+					},
+				},
+				Events: nil,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cart := tc.cart.ClampToStock(tc.product)
+
+			assert.DeepEqual(t, tc.want.Products, cart.Products())
+			assert.DeepEqual(t, tc.want.Events, cart.Events())
+		})
+	}
+}
+
+func TestCartEntity_ClampVariantToStock(t *testing.T) {
+	t.Parallel()
+	variant := productEntity.NewVariantFromDatabase("variant_id_one", "product_id_one", "BANANA-XL", money.MustParse("2", money.USD), 0, time.Now(), time.Now())
+	cart := NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+		{
+			ProductID: "product_id_one",
+			Quantity:  3,
+		},
+		{
+			ProductID: "product_id_one",
+			VariantID: "variant_id_one",
+			Quantity:  1,
+		},
+	})
+
+	cart = cart.ClampVariantToStock(variant)
+
+	assert.DeepEqual(t, []cartEntity.CartProduct{{ProductID: "product_id_one", Quantity: 3}}, cart.Products())
+	assert.DeepEqual(t, []cartEntity.Event{
+		cartEntity.ProductRemoved{ProductID: "product_id_one", VariantID: "variant_id_one"},
+	}, cart.Events())
+}
+
+func TestCartEntity_RemoveProduct(t *testing.T) {
+	t.Parallel()
+	cart := NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+		{
+			ProductID: "product_id_one",
+			VariantID: "variant_id_one",
+			Quantity:  1,
+		},
+		{
+			ProductID: "product_id_two",
+			Quantity:  2,
+		},
+		{
+			ProductID: "product_id_one",
+			VariantID: "variant_id_two",
+			Quantity:  1,
+		},
+	})
+
+	cart = cart.RemoveProduct("product_id_one")
+
+	assert.DeepEqual(t, []cartEntity.CartProduct{{ProductID: "product_id_two", Quantity: 2}}, cart.Products())
+	assert.DeepEqual(t, []cartEntity.Event{
+		cartEntity.ProductRemoved{ProductID: "product_id_one", VariantID: "variant_id_one"},
+		cartEntity.ProductRemoved{ProductID: "product_id_one", VariantID: "variant_id_two"},
+	}, cart.Events())
+}
+
+func TestCartEntity_AcceptCurrentPrice(t *testing.T) {
+	t.Parallel()
+	product := NewTestProductWithVariant("product_id_one", "variant_id_one", 5)
+	cart := NewTestCart(t, "customer_id", []cartEntity.CartProduct{
+		{
+			ProductID:  "product_id_one",
+			VariantID:  "variant_id_one",
+			Quantity:   2,
+			AddedPrice: money.MustParse("1.8", money.USD),
+		},
+		{
+			ProductID:  "product_id_one",
+			VariantID:  "variant_id_deleted",
+			Quantity:   1,
+			AddedPrice: money.MustParse("3", money.USD),
+		},
+		{
+			ProductID:  "product_id_two",
+			Quantity:   1,
+			AddedPrice: money.MustParse("1", money.USD),
+		},
+	})
+
+	cart = cart.AcceptCurrentPrice(product)
+
+	accepted := cartEntity.CartProduct{
+		ProductID:  "product_id_one",
+		VariantID:  "variant_id_one",
+		Quantity:   2,
+		AddedPrice: money.MustParse("2", money.USD),
+	}
+	assert.DeepEqual(t, accepted, cart.Products()[0])
+	assert.DeepEqual(t, []cartEntity.Event{cartEntity.ProductQuantityChanged{Product: accepted}}, cart.Events())
+
+	// accepting the same prices again changes nothing
+	cart = NewTestCart(t, "customer_id", cart.Products()).AcceptCurrentPrice(product)
+	require.Empty(t, cart.Events())
+}
@@ -1,43 +1,107 @@
 package cart
 
 import (
-	"math"
+	"errors"
+	"fmt"
+	customErrors "shared/errors"
+	"shared/money"
 )
 
+var ErrUnsupportedCurrency = customErrors.NewIncorrectInputError("cart.get.unsupported_currency", "Prices are not available in this currency")
+
+// CartReadModelProduct shows the current catalog price next to the price the
+// product had when it was added to the cart.
 type CartReadModelProduct struct {
-	ProductID string  `json:"productId"`
-	Name      string  `json:"name"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ProductID    string      `json:"productId"`
+	VariantID    string      `json:"variantId,omitempty"`
+	SKU          string      `json:"sku,omitempty"`
+	Name         string      `json:"name"`
+	Quantity     int         `json:"quantity"`
+	Price        money.Money `json:"price"`
+	AddedPrice   money.Money `json:"addedPrice"`
+	PriceChanged bool        `json:"priceChanged"`
 }
 
 type CartReadModel struct {
-	Type       string                 `json:"type"`
-	CustomerID string                 `json:"customerId"`
-	Products   []CartReadModelProduct `json:"products"`
-	TotalPrice float64                `json:"totalPrice"`
+	Type         string                 `json:"type"`
+	CustomerID   string                 `json:"customerId"`
+	Products     []CartReadModelProduct `json:"products"`
+	TotalPrice   money.Money            `json:"totalPrice"`
+	PriceChanged bool                   `json:"priceChanged"`
 }
 
-func NewCartReadModel(customerID string, products []CartReadModelProduct) CartReadModel {
+// NewCartReadModel prices the cart in currency. Products priced in another
+// currency are converted with rates, their price change is detected before
+// the conversion so that rate updates do not flag them.
+func NewCartReadModel(
+	customerID string,
+	products []CartReadModelProduct,
+	currency money.Currency,
+	rates money.RateTable,
+) (CartReadModel, error) {
+	for i := range products {
+		product := &products[i]
+		product.PriceChanged = !product.AddedPrice.IsZero() && !product.AddedPrice.Equal(product.Price)
+
+		var err error
+		product.Price, err = convert(rates, product.Price, currency)
+		if err != nil {
+			return CartReadModel{}, err
+		}
+		if !product.AddedPrice.IsZero() {
+			product.AddedPrice, err = convert(rates, product.AddedPrice, currency)
+			if err != nil {
+				return CartReadModel{}, err
+			}
+		}
+	}
 	cart := CartReadModel{
 		Type:       "cart",
 		CustomerID: customerID,
 		Products:   products,
 	}
-	cart.TotalPrice = cart.calculateTotalPrice()
-	return cart
+	totalPrice, err := cart.calculateTotalPrice(currency)
+	if err != nil {
+		return CartReadModel{}, err
+	}
+	cart.TotalPrice = totalPrice
+	cart.PriceChanged = cart.hasPriceChanges()
+	return cart, nil
 }
 
-func (c CartReadModel) calculateTotalPrice() float64 {
-	var totalPrice float64
-	for _, product := range c.Products {
-		totalPrice += product.Price * float64(product.Quantity)
+func convert(rates money.RateTable, price money.Money, currency money.Currency) (money.Money, error) {
+	converted, err := rates.Convert(price, currency)
+	if errors.Is(err, money.ErrRateNotFound) || errors.Is(err, money.ErrUnknownCurrency) {
+		return money.Money{}, ErrUnsupportedCurrency
+	}
+	if err != nil {
+		return money.Money{}, fmt.Errorf("cartReadModel -> convert: %w", err)
 	}
+	return converted, nil
+}
 
-	// format to two decimal places
-	totalPriceTwoDecimals := math.Round(totalPrice*100) / 100
+func (c CartReadModel) calculateTotalPrice(currency money.Currency) (money.Money, error) {
+	totalPrice := money.Zero(currency)
+	for _, product := range c.Products {
+		linePrice, err := product.Price.Multiply(int64(product.Quantity))
+		if err != nil {
+			return money.Money{}, fmt.Errorf("cartReadModel -> calculateTotalPrice -> product %s: %w", product.ProductID, err)
+		}
+		totalPrice, err = totalPrice.Add(linePrice)
+		if err != nil {
+			return money.Money{}, fmt.Errorf("cartReadModel -> calculateTotalPrice -> product %s: %w", product.ProductID, err)
+		}
+	}
+	return totalPrice, nil
+}
 
-	return totalPriceTwoDecimals
+func (c CartReadModel) hasPriceChanges() bool {
+	for _, product := range c.Products {
+		if product.PriceChanged {
+			return true
+		}
+	}
+	return false
 }
 
 func (c CartReadModel) IsZero() bool {
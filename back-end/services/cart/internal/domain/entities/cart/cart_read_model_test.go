@@ -2,11 +2,17 @@ package cart_test
 
 import (
 	cartEntity "cart/internal/domain/entities/cart"
+	"shared/money"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"gotest.tools/v3/assert"
 )
 
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
+
 func TestCartReadModel_NewCartReadModel(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -29,7 +35,7 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 				Type:       "cart",
 				CustomerID: "1",
 				Products:   nil,
-				TotalPrice: 0,
+				TotalPrice: usd("0"),
 			},
 		},
 		{
@@ -41,7 +47,7 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 						ProductID: "1",
 						Quantity:  10,
 						Name:      "test",
-						Price:     10,
+						Price:     usd("10"),
 					},
 				},
 			},
@@ -54,10 +60,10 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 						ProductID: "1",
 						Quantity:  10,
 						Name:      "test",
-						Price:     10,
+						Price:     usd("10"),
 					},
 				},
-				TotalPrice: 100,
+				TotalPrice: usd("100"),
 			},
 		},
 		{
@@ -69,13 +75,13 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 						ProductID: "1",
 						Quantity:  10,
 						Name:      "apple",
-						Price:     10,
+						Price:     usd("10"),
 					},
 					{
 						ProductID: "2",
 						Quantity:  1,
 						Name:      "banana",
-						Price:     10.55,
+						Price:     usd("10.55"),
 					},
 				},
 			},
@@ -88,16 +94,16 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 						ProductID: "1",
 						Quantity:  10,
 						Name:      "apple",
-						Price:     10,
+						Price:     usd("10"),
 					},
 					{
 						ProductID: "2",
 						Quantity:  1,
 						Name:      "banana",
-						Price:     10.55,
+						Price:     usd("10.55"),
 					},
 				},
-				TotalPrice: 110.55,
+				TotalPrice: usd("110.55"),
 			},
 		},
 		{
@@ -109,13 +115,13 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 						ProductID: "1",
 						Quantity:  1,
 						Name:      "apple",
-						Price:     1.55,
+						Price:     usd("1.55"),
 					},
 					{
 						ProductID: "2",
 						Quantity:  1,
 						Name:      "banana",
-						Price:     10.55,
+						Price:     usd("10.55"),
 					},
 				},
 			},
@@ -128,16 +134,16 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 						ProductID: "1",
 						Quantity:  1,
 						Name:      "apple",
-						Price:     1.55,
+						Price:     usd("1.55"),
 					},
 					{
 						ProductID: "2",
 						Quantity:  1,
 						Name:      "banana",
-						Price:     10.55,
+						Price:     usd("10.55"),
 					},
 				},
-				TotalPrice: 12.1,
+				TotalPrice: usd("12.1"),
 			},
 		},
 		{
@@ -149,13 +155,13 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 						ProductID: "1",
 						Quantity:  3,
 						Name:      "apple",
-						Price:     1.27,
+						Price:     usd("1.27"),
 					},
 					{
 						ProductID: "2",
 						Quantity:  2,
 						Name:      "banana",
-						Price:     12.53,
+						Price:     usd("12.53"),
 					},
 				},
 			},
@@ -168,16 +174,62 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 						ProductID: "1",
 						Quantity:  3,
 						Name:      "apple",
-						Price:     1.27,
+						Price:     usd("1.27"),
 					},
 					{
 						ProductID: "2",
 						Quantity:  2,
 						Name:      "banana",
-						Price:     12.53,
+						Price:     usd("12.53"),
 					},
 				},
-				TotalPrice: 28.87,
+				TotalPrice: usd("28.87"),
+			},
+		},
+		{
+			name: "price_changed_since_added",
+			args: args{
+				CustomerID: "1",
+				Products: []cartEntity.CartReadModelProduct{
+					{
+						ProductID:  "1",
+						Quantity:   2,
+						Name:       "apple",
+						Price:      usd("1.5"),
+						AddedPrice: usd("1.25"),
+					},
+					{
+						ProductID:  "2",
+						Quantity:   1,
+						Name:       "banana",
+						Price:      usd("2"),
+						AddedPrice: usd("2"),
+					},
+				},
+			},
+
+			want: cartEntity.CartReadModel{
+				Type:       "cart",
+				CustomerID: "1",
+				Products: []cartEntity.CartReadModelProduct{
+					{
+						ProductID:    "1",
+						Quantity:     2,
+						Name:         "apple",
+						Price:        usd("1.5"),
+						AddedPrice:   usd("1.25"),
+						PriceChanged: true,
+					},
+					{
+						ProductID:  "2",
+						Quantity:   1,
+						Name:       "banana",
+						Price:      usd("2"),
+						AddedPrice: usd("2"),
+					},
+				},
+				TotalPrice:   usd("5"),
+				PriceChanged: true,
 			},
 		},
 	}
@@ -186,8 +238,49 @@ func TestCartReadModel_NewCartReadModel(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			cart := cartEntity.NewCartReadModel(tc.args.CustomerID, tc.args.Products)
+			cart, err := cartEntity.NewCartReadModel(tc.args.CustomerID, tc.args.Products, money.USD, money.NewRateTable(money.USD, nil))
+			assert.NilError(t, err)
 			assert.DeepEqual(t, tc.want, cart)
 		})
 	}
 }
+
+func TestCartReadModel_NewCartReadModel_ConvertsCurrency(t *testing.T) {
+	t.Parallel()
+	rates := money.NewRateTable(money.USD, map[money.Currency]money.Rate{
+		money.EUR: money.MustParseRate("0.9"),
+	})
+	products := []cartEntity.CartReadModelProduct{
+		{ProductID: "1", Quantity: 2, Name: "apple", Price: usd("1.5"), AddedPrice: usd("1")},
+		{ProductID: "2", Quantity: 1, Name: "banana", Price: money.MustParse("0.45", money.EUR)},
+	}
+
+	cart, err := cartEntity.NewCartReadModel("1", products, money.EUR, rates)
+
+	require.NoError(t, err)
+	assert.DeepEqual(t, cartEntity.CartReadModel{
+		Type:       "cart",
+		CustomerID: "1",
+		Products: []cartEntity.CartReadModelProduct{
+			{
+				ProductID:    "1",
+				Quantity:     2,
+				Name:         "apple",
+				Price:        money.MustParse("1.35", money.EUR),
+				AddedPrice:   money.MustParse("0.90", money.EUR),
+				PriceChanged: true,
+			},
+			{ProductID: "2", Quantity: 1, Name: "banana", Price: money.MustParse("0.45", money.EUR)},
+		},
+		TotalPrice:   money.MustParse("3.15", money.EUR),
+		PriceChanged: true,
+	}, cart)
+}
+
+func TestCartReadModel_NewCartReadModel_UnsupportedCurrency(t *testing.T) {
+	t.Parallel()
+	_, err := cartEntity.NewCartReadModel("1", []cartEntity.CartReadModelProduct{
+		{ProductID: "1", Quantity: 1, Name: "apple", Price: usd("1")},
+	}, money.GBP, money.NewRateTable(money.USD, nil))
+	require.Equal(t, cartEntity.ErrUnsupportedCurrency, err)
+}
@@ -1,14 +1,16 @@
 package product
 
 import (
+	"shared/money"
 	"time"
 )
 
 type Product struct {
 	id        string
 	name      string
-	price     float64
+	price     money.Money
 	quantity  int
+	variants  []Variant
 	createdAt time.Time
 	updatedAt time.Time
 }
@@ -16,7 +18,7 @@ type Product struct {
 type CreateProductParams struct {
 	ID        string
 	Name      string
-	Price     float64
+	Price     money.Money
 	Quantity  int
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -36,7 +38,7 @@ func NewProduct(createProductParams CreateProductParams) (Product, error) {
 
 func NewProductFromDatabase(
 	id string,
-	price float64,
+	price money.Money,
 	quantity int,
 	name string,
 	createdAt time.Time,
@@ -57,7 +59,7 @@ func (d Product) ID() string {
 	return d.id
 }
 
-func (d Product) Price() float64 {
+func (d Product) Price() money.Money {
 	return d.price
 }
 
@@ -77,6 +79,25 @@ func (d Product) UpdatedAt() time.Time {
 	return d.updatedAt
 }
 
+func (d Product) Variants() []Variant {
+	return d.variants
+}
+
+// Variant looks up a variant of the product by its ID
+func (d Product) Variant(id string) (Variant, bool) {
+	for _, variant := range d.variants {
+		if variant.ID() == id {
+			return variant, true
+		}
+	}
+	return Variant{}, false
+}
+
+func (d Product) WithVariants(variants []Variant) Product {
+	d.variants = variants
+	return d
+}
+
 func (d Product) IsZero() bool {
-	return d == Product{}
+	return d.id == ""
 }
@@ -0,0 +1,92 @@
+package product
+
+import (
+	"shared/money"
+	"time"
+)
+
+// Variant is the local copy of a catalog product variant
+type Variant struct {
+	id        string
+	productID string
+	sku       string
+	price     money.Money
+	quantity  int
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+type CreateVariantParams struct {
+	ID        string
+	ProductID string
+	SKU       string
+	Price     money.Money
+	Quantity  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewVariant(createVariantParams CreateVariantParams) (Variant, error) {
+	variant := Variant{
+		id:        createVariantParams.ID,
+		productID: createVariantParams.ProductID,
+		sku:       createVariantParams.SKU,
+		price:     createVariantParams.Price,
+		quantity:  createVariantParams.Quantity,
+		createdAt: createVariantParams.CreatedAt,
+		updatedAt: createVariantParams.UpdatedAt,
+	}
+	return variant, nil
+}
+
+func NewVariantFromDatabase(
+	id string,
+	productID string,
+	sku string,
+	price money.Money,
+	quantity int,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Variant {
+	return Variant{
+		id:        id,
+		productID: productID,
+		sku:       sku,
+		price:     price,
+		quantity:  quantity,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+func (v Variant) ID() string {
+	return v.id
+}
+
+func (v Variant) ProductID() string {
+	return v.productID
+}
+
+func (v Variant) SKU() string {
+	return v.sku
+}
+
+func (v Variant) Price() money.Money {
+	return v.price
+}
+
+func (v Variant) Quantity() int {
+	return v.quantity
+}
+
+func (v Variant) CreatedAt() time.Time {
+	return v.createdAt
+}
+
+func (v Variant) UpdatedAt() time.Time {
+	return v.updatedAt
+}
+
+func (v Variant) IsZero() bool {
+	return v == Variant{}
+}
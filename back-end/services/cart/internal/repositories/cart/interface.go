@@ -6,6 +6,8 @@ import (
 )
 
 type CartRepository interface {
-	GetByCustomerID(ctx context.Context, customerID string) (cartEntity.CartReadModel, error)
+	GetProductsByCustomerID(ctx context.Context, customerID string) ([]cartEntity.CartReadModelProduct, error)
 	SaveCart(ctx context.Context, customerID string, updateFunc func(cart cartEntity.Cart) (cartEntity.Cart, error)) error
+	GetCustomerIDsExceedingStock(ctx context.Context, productID string, variantID string, available int) ([]string, error)
+	GetCustomerIDsByProductID(ctx context.Context, productID string) ([]string, error)
 }
@@ -9,6 +9,8 @@ import (
 	cartEntity "cart/internal/domain/entities/cart"
 	repository "cart/internal/repositories/cart"
 	productPGRepo "cart/internal/repositories/product/pg"
+	"shared/money"
+	pgStorage "shared/storage/pg"
 
 	"github.com/rs/zerolog"
 	"github.com/uptrace/bun"
@@ -25,53 +27,75 @@ type CartProductModel struct {
 	bun.BaseModel `bun:"table:cart_products,alias:cart_products"`
 	CustomerID    string                     `bun:"customer_id,pk"`
 	ProductID     string                     `bun:"product_id,pk"`
+	VariantID     string                     `bun:"variant_id,pk"`
 	Quantity      int                        `bun:"quantity"`
+	AddedAmount   int64                      `bun:"added_price_amount,nullzero"`
+	AddedCurrency string                     `bun:"added_price_currency,nullzero"`
 	CreatedAt     time.Time                  `bun:"created_at"`
 	UpdatedAt     time.Time                  `bun:"updated_at"`
 	Product       productPGRepo.ProductModel `bun:"rel:has-one,join:product_id=id"`
+
+	VariantSKU           string `bun:"variant_sku,scanonly"`
+	VariantPriceAmount   int64  `bun:"variant_price_amount,scanonly"`
+	VariantPriceCurrency string `bun:"variant_price_currency,scanonly"`
+}
+
+func (c CartProductModel) AddedPrice() money.Money {
+	return money.New(c.AddedAmount, money.Currency(c.AddedCurrency))
+}
+
+// Price is the current price of the line, the variant's price when the line
+// is for a variant.
+func (c CartProductModel) Price() money.Money {
+	if c.VariantID != "" {
+		return money.New(c.VariantPriceAmount, money.Currency(c.VariantPriceCurrency))
+	}
+	return c.Product.Price()
 }
 
 type cartRepository struct {
-	logger zerolog.Logger
-	db     *bun.DB
+	logger     zerolog.Logger
+	db         *bun.DB
+	transactor pgStorage.Transactor
 }
 
 func NewCartRepository(sql *bun.DB, logger zerolog.Logger) *cartRepository {
-	return &cartRepository{logger: logger, db: sql}
+	return &cartRepository{logger: logger, db: sql, transactor: pgStorage.NewTransactor(sql)}
 }
 
-func (r *cartRepository) GetByCustomerID(ctx context.Context, customerID string) (cartEntity.CartReadModel, error) {
+func (r *cartRepository) GetProductsByCustomerID(ctx context.Context, customerID string) ([]cartEntity.CartReadModelProduct, error) {
 
 	var cartProducts []CartProductModel
 	err := r.db.NewSelect().
 		Model(&cartProducts).
+		ColumnExpr("cart_products.*").
+		ColumnExpr("v.sku AS variant_sku, v.price_amount AS variant_price_amount, v.price_currency AS variant_price_currency").
 		Relation("Product").
+		Join("LEFT JOIN product_variants AS v ON v.id::text = cart_products.variant_id").
 		Where("cart_products.customer_id = ?", customerID).
 		Scan(ctx)
 
 	if err != nil {
 		if err != sql.ErrNoRows {
-			return cartEntity.CartReadModel{}, fmt.Errorf("cartProductRepository -> GetByCustomerID -> r.db.NewSelect(): %w", err)
+			return nil, fmt.Errorf("cartProductRepository -> GetProductsByCustomerID -> r.db.NewSelect(): %w", err)
 		}
 	}
 
 	cartReadModelProducts := make([]cartEntity.CartReadModelProduct, 0, len(cartProducts))
 	for _, cartProduct := range cartProducts {
 		cartReadModelProduct := cartEntity.CartReadModelProduct{
-			ProductID: cartProduct.ProductID,
-			Quantity:  cartProduct.Quantity,
-			Name:      cartProduct.Product.Name,
-			Price:     cartProduct.Product.Price,
+			ProductID:  cartProduct.ProductID,
+			VariantID:  cartProduct.VariantID,
+			SKU:        cartProduct.VariantSKU,
+			Quantity:   cartProduct.Quantity,
+			Name:       cartProduct.Product.Name,
+			Price:      cartProduct.Price(),
+			AddedPrice: cartProduct.AddedPrice(),
 		}
 		cartReadModelProducts = append(cartReadModelProducts, cartReadModelProduct)
 	}
 
-	cart := cartEntity.NewCartReadModel(
-		customerID,
-		cartReadModelProducts,
-	)
-	fmt.Printf("cart: %+v\n", cart)
-	return cart, nil
+	return cartReadModelProducts, nil
 }
 
 func (r *cartRepository) SaveCart(
@@ -80,7 +104,7 @@ func (r *cartRepository) SaveCart(
 	updateFunc func(cart cartEntity.Cart,
 	) (cartEntity.Cart, error)) error {
 	var cartProductModels []CartProductModel
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&cartProductModels).
 		ColumnExpr("cart_products.*").
 		Join("JOIN products AS p ON p.id = cart_products.product_id").
@@ -96,8 +120,10 @@ func (r *cartRepository) SaveCart(
 	cartProducts := make([]cartEntity.CartProduct, 0, len(cartProductModels))
 	for _, cartProduct := range cartProductModels {
 		product := cartEntity.CartProduct{
-			ProductID: cartProduct.ProductID,
-			Quantity:  cartProduct.Quantity,
+			ProductID:  cartProduct.ProductID,
+			VariantID:  cartProduct.VariantID,
+			Quantity:   cartProduct.Quantity,
+			AddedPrice: cartProduct.AddedPrice(),
 		}
 		cartProducts = append(cartProducts, product)
 	}
@@ -116,60 +142,87 @@ func (r *cartRepository) SaveCart(
 
 	events := cart.Events()
 
-	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
-	if err != nil {
-		return fmt.Errorf("cartRepository -> SaveCart -> r.db.BeginTx(): %w", err)
-	}
-
-	for _, event := range events {
-		switch ev := event.(type) {
-		case cartEntity.AddedProduct:
-			product := CartProductModel{
-				CustomerID: cart.CustomerID(),
-				ProductID:  ev.Product.ProductID,
-				Quantity:   ev.Product.Quantity,
-			}
-			_, err := r.db.NewInsert().Model(&product).Exec(ctx)
-			if err != nil {
-				txError := tx.Rollback()
-				if txError != nil {
-					return fmt.Errorf("cartRepository -> SaveCart -> tx.Rollback(): %w", txError)
+	err = r.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		db := pgStorage.Conn(ctx, r.db)
+		for _, event := range events {
+			switch ev := event.(type) {
+			case cartEntity.AddedProduct:
+				product := CartProductModel{
+					CustomerID:    cart.CustomerID(),
+					ProductID:     ev.Product.ProductID,
+					VariantID:     ev.Product.VariantID,
+					Quantity:      ev.Product.Quantity,
+					AddedAmount:   ev.Product.AddedPrice.MinorUnits(),
+					AddedCurrency: string(ev.Product.AddedPrice.Currency()),
 				}
-				return fmt.Errorf("cartRepository -> SaveCart -> r.db.NewInsert(): %w", err)
-			}
-		case cartEntity.ProductQuantityChanged:
-			_, err := r.db.NewUpdate().
-				Model(&CartProductModel{}).
-				Set("quantity = ?", ev.Product.Quantity).
-				Where("customer_id = ? AND product_id = ?", cart.CustomerID(), ev.Product.ProductID).
-				Exec(ctx)
-			if err != nil {
-				txError := tx.Rollback()
-				if txError != nil {
-					return fmt.Errorf("cartRepository -> SaveCart -> tx.Rollback(): %w", txError)
+				_, err := db.NewInsert().Model(&product).Exec(ctx)
+				if err != nil {
+					return fmt.Errorf("cartRepository -> SaveCart -> r.db.NewInsert(): %w", err)
 				}
-				return fmt.Errorf("cartRepository -> SaveCart -> r.db.NewUpdate(): %w", err)
-			}
-		case cartEntity.ProductRemoved:
-			_, err := r.db.NewDelete().
-				Model(&CartProductModel{}).
-				Where("customer_id = ? AND product_id = ?", cart.CustomerID(), ev.ProductID).
-				Exec(ctx)
-			if err != nil {
-				txError := tx.Rollback()
-				if txError != nil {
-					return fmt.Errorf("cartRepository -> SaveCart -> tx.Rollback(): %w", txError)
+			case cartEntity.ProductQuantityChanged:
+				product := CartProductModel{
+					Quantity:      ev.Product.Quantity,
+					AddedAmount:   ev.Product.AddedPrice.MinorUnits(),
+					AddedCurrency: string(ev.Product.AddedPrice.Currency()),
+				}
+				// the added price changes when the shopper accepts the current price
+				_, err := db.NewUpdate().
+					Model(&product).
+					Column("quantity", "added_price_amount", "added_price_currency").
+					Where("customer_id = ? AND product_id = ? AND variant_id = ?", cart.CustomerID(), ev.Product.ProductID, ev.Product.VariantID).
+					Exec(ctx)
+				if err != nil {
+					return fmt.Errorf("cartRepository -> SaveCart -> r.db.NewUpdate(): %w", err)
+				}
+			case cartEntity.ProductRemoved:
+				_, err := db.NewDelete().
+					Model(&CartProductModel{}).
+					Where("customer_id = ? AND product_id = ? AND variant_id = ?", cart.CustomerID(), ev.ProductID, ev.VariantID).
+					Exec(ctx)
+				if err != nil {
+					return fmt.Errorf("cartRepository -> SaveCart ->r.db.NewDelete(): %w", err)
 				}
-				return fmt.Errorf("cartRepository -> SaveCart ->r.db.NewDelete(): %w", err)
+			default:
+				return fmt.Errorf("cartRepository -> SaveCart -> unknown event: %v", event)
 			}
-		default:
-			return fmt.Errorf("cartRepository -> SaveCart -> unknown event: %v", event)
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cartRepository -> SaveCart -> r.transactor.RunInTransaction(): %w", err)
+	}
+	return nil
+}
+
+// GetCustomerIDsExceedingStock returns the customers whose cart holds more of
+// the product, or of its variant when variantID is set, than is available.
+func (r *cartRepository) GetCustomerIDsExceedingStock(ctx context.Context, productID string, variantID string, available int) ([]string, error) {
+	var customerIDs []string
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model((*CartProductModel)(nil)).
+		Column("customer_id").
+		Where("product_id = ?", productID).
+		Where("variant_id = ?", variantID).
+		Where("quantity > ?", available).
+		Scan(ctx, &customerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("cartRepository -> GetCustomerIDsExceedingStock -> r.db.NewSelect(): %w", err)
 	}
+	return customerIDs, nil
+}
 
-	err = tx.Commit()
+// GetCustomerIDsByProductID returns the customers with any line of the product
+// in their cart.
+func (r *cartRepository) GetCustomerIDsByProductID(ctx context.Context, productID string) ([]string, error) {
+	var customerIDs []string
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model((*CartProductModel)(nil)).
+		Distinct().
+		Column("customer_id").
+		Where("product_id = ?", productID).
+		Scan(ctx, &customerIDs)
 	if err != nil {
-		return fmt.Errorf("cartRepository -> SaveCart -> tx.Commit(): %w", err)
+		return nil, fmt.Errorf("cartRepository -> GetCustomerIDsByProductID -> r.db.NewSelect(): %w", err)
 	}
-	return nil
+	return customerIDs, nil
 }
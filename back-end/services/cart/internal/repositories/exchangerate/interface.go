@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"shared/money"
+	"time"
+)
+
+// ExchangeRateRepository keeps a local copy of the catalog's exchange rates so
+// carts can be priced while the catalog is unavailable.
+type ExchangeRateRepository interface {
+	SaveExchangeRate(ctx context.Context, base money.Currency, currency money.Currency, rate money.Rate, updatedAt time.Time) error
+	DeleteExchangeRate(ctx context.Context, currency money.Currency, updatedAt time.Time) error
+	GetRateTable(ctx context.Context) (money.RateTable, error)
+}
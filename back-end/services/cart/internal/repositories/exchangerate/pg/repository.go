@@ -0,0 +1,98 @@
+package pgrepositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	repository "cart/internal/repositories/exchangerate"
+	"shared/money"
+	pgStorage "shared/storage/pg"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+)
+
+var _ repository.ExchangeRateRepository = (*exchangeRateRepository)(nil)
+
+type ExchangeRateModel struct {
+	bun.BaseModel `bun:"table:exchange_rates,alias:er"`
+
+	Currency     string    `bun:"currency,pk"`
+	BaseCurrency string    `bun:"base_currency"`
+	Rate         string    `bun:"rate"`
+	UpdatedAt    time.Time `bun:"updated_at"`
+}
+
+type exchangeRateRepository struct {
+	db     *bun.DB
+	logger zerolog.Logger
+}
+
+func NewExchangeRateRepository(sql *bun.DB, logger zerolog.Logger) *exchangeRateRepository {
+	return &exchangeRateRepository{db: sql, logger: logger}
+}
+
+// SaveExchangeRate ignores updates older than the stored rate, events of
+// different currencies are not ordered.
+func (r *exchangeRateRepository) SaveExchangeRate(
+	ctx context.Context,
+	base money.Currency,
+	currency money.Currency,
+	rate money.Rate,
+	updatedAt time.Time,
+) error {
+	model := ExchangeRateModel{
+		Currency:     string(currency),
+		BaseCurrency: string(base),
+		Rate:         rate.String(),
+		UpdatedAt:    updatedAt,
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().
+		Model(&model).
+		On("CONFLICT (currency) DO UPDATE").
+		Set("base_currency = EXCLUDED.base_currency").
+		Set("rate = EXCLUDED.rate").
+		Set("updated_at = EXCLUDED.updated_at").
+		Where("er.updated_at <= EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("exchangeRateRepository SaveExchangeRate -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *exchangeRateRepository) DeleteExchangeRate(ctx context.Context, currency money.Currency, updatedAt time.Time) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().
+		Model((*ExchangeRateModel)(nil)).
+		Where("currency = ? AND updated_at <= ?", string(currency), updatedAt).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("exchangeRateRepository DeleteExchangeRate -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
+
+// GetRateTable falls back to money.DefaultCurrency as the base until the
+// first rate is received.
+func (r *exchangeRateRepository) GetRateTable(ctx context.Context) (money.RateTable, error) {
+	var models []ExchangeRateModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&models).
+		Scan(ctx)
+	if err != nil {
+		return money.RateTable{}, fmt.Errorf("exchangeRateRepository GetRateTable -> r.db.NewSelect(): %w", err)
+	}
+
+	base := money.DefaultCurrency
+	rates := make(map[money.Currency]money.Rate, len(models))
+	for _, model := range models {
+		rate, err := money.ParseRate(model.Rate)
+		if err != nil {
+			return money.RateTable{}, fmt.Errorf("exchangeRateRepository GetRateTable -> money.ParseRate(): %w", err)
+		}
+		base = money.Currency(model.BaseCurrency)
+		rates[money.Currency(model.Currency)] = rate
+	}
+	return money.NewRateTable(base, rates), nil
+}
@@ -10,4 +10,6 @@ type ProductRepository interface {
 	GetProductByID(ctx context.Context, id string) (productEntity.Product, error)
 	UpdateProductByID(ctx context.Context, updatedProduct productEntity.Product) error
 	DeleteProductByID(ctx context.Context, id string) error
+	SaveVariant(ctx context.Context, variant productEntity.Variant) error
+	DeleteVariantByID(ctx context.Context, id string) error
 }
@@ -10,17 +10,46 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/uptrace/bun"
+
+	"shared/money"
+	pgStorage "shared/storage/pg"
 )
 
 type ProductModel struct {
 	bun.BaseModel `bun:"table:products"`
 
-	ID        string    `bun:"id,pk,alias:products"`
-	Price     float64   `bun:"price"`
-	Name      string    `bun:"name"`
-	Quantity  int       `bun:"quantity"`
-	CreatedAt time.Time `bun:"created_at,nullzero"`
-	UpdatedAt time.Time `bun:"updated_at,nullzero"`
+	ID            string    `bun:"id,pk,alias:products"`
+	PriceAmount   int64     `bun:"price_amount"`
+	PriceCurrency string    `bun:"price_currency"`
+	Name          string    `bun:"name"`
+	Quantity      int       `bun:"quantity"`
+	CreatedAt     time.Time `bun:"created_at,nullzero"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero"`
+}
+
+type VariantModel struct {
+	bun.BaseModel `bun:"table:product_variants"`
+
+	ID            string    `bun:"id,pk"`
+	ProductID     string    `bun:"product_id"`
+	SKU           string    `bun:"sku"`
+	PriceAmount   int64     `bun:"price_amount"`
+	PriceCurrency string    `bun:"price_currency"`
+	Quantity      int       `bun:"quantity"`
+	CreatedAt     time.Time `bun:"created_at,nullzero"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero"`
+}
+
+func (v VariantModel) toEntity() productEntity.Variant {
+	return productEntity.NewVariantFromDatabase(
+		v.ID,
+		v.ProductID,
+		v.SKU,
+		money.New(v.PriceAmount, money.Currency(v.PriceCurrency)),
+		v.Quantity,
+		v.CreatedAt,
+		v.UpdatedAt,
+	)
 }
 
 var _ repository.ProductRepository = (*productPGRepository)(nil)
@@ -32,12 +61,13 @@ type productPGRepository struct {
 
 func (p *ProductModel) toDB(product productEntity.Product) ProductModel {
 	return ProductModel{
-		ID:        product.ID(),
-		Name:      product.Name(),
-		Price:     product.Price(),
-		Quantity:  product.Quantity(),
-		CreatedAt: product.CreatedAt(),
-		UpdatedAt: product.UpdatedAt(),
+		ID:            product.ID(),
+		Name:          product.Name(),
+		PriceAmount:   product.Price().MinorUnits(),
+		PriceCurrency: string(product.Price().Currency()),
+		Quantity:      product.Quantity(),
+		CreatedAt:     product.CreatedAt(),
+		UpdatedAt:     product.UpdatedAt(),
 	}
 }
 
@@ -45,7 +75,7 @@ func (p *ProductModel) toEntity() productEntity.Product {
 
 	product := productEntity.NewProductFromDatabase(
 		p.ID,
-		p.Price,
+		p.Price(),
 		p.Quantity,
 		p.Name,
 		p.CreatedAt,
@@ -55,6 +85,10 @@ func (p *ProductModel) toEntity() productEntity.Product {
 	return product
 }
 
+func (p *ProductModel) Price() money.Money {
+	return money.New(p.PriceAmount, money.Currency(p.PriceCurrency))
+}
+
 func NewProductRepository(sql *bun.DB, logger zerolog.Logger) *productPGRepository {
 	return &productPGRepository{sql, logger}
 }
@@ -63,7 +97,7 @@ func (r *productPGRepository) CreateProduct(ctx context.Context, product product
 	var dbProduct ProductModel
 
 	dbProduct = dbProduct.toDB(product)
-	_, err := r.db.NewInsert().Model(&dbProduct).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().Model(&dbProduct).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -73,7 +107,7 @@ func (r *productPGRepository) CreateProduct(ctx context.Context, product product
 func (r *productPGRepository) GetProductByID(ctx context.Context, id string) (productEntity.Product, error) {
 
 	var productDB ProductModel
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&productDB).
 		Where("id IN (?)", id).
 		Scan(ctx)
@@ -86,19 +120,34 @@ func (r *productPGRepository) GetProductByID(ctx context.Context, id string) (pr
 		return productEntity.Product{}, fmt.Errorf("customerPGRepository -> GetByID -> r.db.NewSelect(): %w", err)
 	}
 
-	product := productDB.toEntity()
+	var variantsDB []VariantModel
+	err = pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&variantsDB).
+		Where("product_id = ?", id).
+		Order("created_at", "id").
+		Scan(ctx)
 	if err != nil {
-		return productEntity.Product{}, fmt.Errorf("customerPGRepository -> GetByID -> toEntity: %w", err)
+		return productEntity.Product{}, fmt.Errorf("productPGRepository -> GetProductByID -> variants r.db.NewSelect(): %w", err)
 	}
-	return product, nil
+	variants := make([]productEntity.Variant, 0, len(variantsDB))
+	for _, variantDB := range variantsDB {
+		variants = append(variants, variantDB.toEntity())
+	}
+
+	product := productDB.toEntity()
+	return product.WithVariants(variants), nil
 }
 
 func (r *productPGRepository) DeleteProductByID(ctx context.Context, productID string) error {
 	var customer ProductModel
-	_, err := r.db.NewDelete().Model(&customer).Where("id = ?", productID).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().Model(&customer).Where("id = ?", productID).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("productPGRepository Delete -> NewDelete: %w", err)
 	}
+	_, err = pgStorage.Conn(ctx, r.db).NewDelete().Model((*VariantModel)(nil)).Where("product_id = ?", productID).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository Delete -> variants NewDelete: %w", err)
+	}
 
 	return nil
 }
@@ -111,10 +160,47 @@ func (r *productPGRepository) UpdateProductByID(
 
 	productModel := dbProduct.toDB(updatedProduct)
 
-	_, err := r.db.NewUpdate().Model(&productModel).Where("id = ?", updatedProduct.ID()).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewUpdate().Model(&productModel).Where("id = ?", updatedProduct.ID()).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("productPGRepository UpdateProductByID -> r.db.NewUpdate(): %w", err)
 	}
 
 	return nil
 }
+
+// SaveVariant inserts or replaces the local copy of a variant. Variant events
+// are consumed independently of product events, so a variant may be saved
+// before its product.
+func (r *productPGRepository) SaveVariant(ctx context.Context, variant productEntity.Variant) error {
+	variantDB := VariantModel{
+		ID:            variant.ID(),
+		ProductID:     variant.ProductID(),
+		SKU:           variant.SKU(),
+		PriceAmount:   variant.Price().MinorUnits(),
+		PriceCurrency: string(variant.Price().Currency()),
+		Quantity:      variant.Quantity(),
+		CreatedAt:     variant.CreatedAt(),
+		UpdatedAt:     variant.UpdatedAt(),
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().
+		Model(&variantDB).
+		On("CONFLICT (id) DO UPDATE").
+		Set("sku = EXCLUDED.sku").
+		Set("price_amount = EXCLUDED.price_amount").
+		Set("price_currency = EXCLUDED.price_currency").
+		Set("quantity = EXCLUDED.quantity").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository SaveVariant -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *productPGRepository) DeleteVariantByID(ctx context.Context, id string) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().Model((*VariantModel)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository DeleteVariantByID -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
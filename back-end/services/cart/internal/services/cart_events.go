@@ -0,0 +1,61 @@
+package applicationservices
+
+import (
+	"context"
+	"fmt"
+
+	cartEntity "cart/internal/domain/entities/cart"
+	"shared/events"
+	"shared/money"
+	"shared/outbox"
+)
+
+const producerName = "cart"
+
+// Maps the cart's domain events to outbox messages
+func cartEventsToMessages(ctx context.Context, cart cartEntity.Cart) ([]outbox.Message, error) {
+	messages := make([]outbox.Message, 0, len(cart.Events()))
+	for _, event := range cart.Events() {
+		var payload events.Event
+		switch ev := event.(type) {
+		case cartEntity.AddedProduct:
+			payload = events.CartProductAdded{
+				CustomerID: cart.CustomerID(),
+				ProductID:  ev.Product.ProductID,
+				VariantID:  ev.Product.VariantID,
+				Quantity:   ev.Product.Quantity,
+				AddedPrice: addedPrice(ev.Product),
+			}
+		case cartEntity.ProductQuantityChanged:
+			payload = events.CartProductQuantityChanged{
+				CustomerID: cart.CustomerID(),
+				ProductID:  ev.Product.ProductID,
+				VariantID:  ev.Product.VariantID,
+				Quantity:   ev.Product.Quantity,
+				AddedPrice: addedPrice(ev.Product),
+			}
+		case cartEntity.ProductRemoved:
+			payload = events.CartProductRemoved{
+				CustomerID: cart.CustomerID(),
+				ProductID:  ev.ProductID,
+				VariantID:  ev.VariantID,
+			}
+		default:
+			return nil, fmt.Errorf("cartEventsToMessages -> unknown event: %v", event)
+		}
+
+		message, err := events.NewOutboxMessage(ctx, producerName, payload)
+		if err != nil {
+			return nil, fmt.Errorf("cartEventsToMessages -> events.NewOutboxMessage: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+func addedPrice(product cartEntity.CartProduct) *money.Money {
+	if product.AddedPrice.IsZero() {
+		return nil
+	}
+	return &product.AddedPrice
+}
@@ -0,0 +1,56 @@
+package applicationservices
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	exchangeRateRepo "cart/internal/repositories/exchangerate"
+	"shared/money"
+
+	"github.com/rs/zerolog"
+)
+
+var _ ExchangeRateApplicationService = (*exchangeRateApplicationService)(nil)
+
+type ExchangeRateApplicationService interface {
+	SaveExchangeRate(ctx context.Context, base money.Currency, currency money.Currency, rate money.Rate, updatedAt time.Time) error
+	DeleteExchangeRate(ctx context.Context, currency money.Currency, updatedAt time.Time) error
+}
+
+type exchangeRateApplicationService struct {
+	exchangeRateRepository exchangeRateRepo.ExchangeRateRepository
+	logger                 zerolog.Logger
+}
+
+func NewExchangeRateApplicationService(
+	exchangeRateRepository exchangeRateRepo.ExchangeRateRepository,
+	logger zerolog.Logger,
+) exchangeRateApplicationService {
+	return exchangeRateApplicationService{
+		exchangeRateRepository: exchangeRateRepository,
+		logger:                 logger,
+	}
+}
+
+func (e exchangeRateApplicationService) SaveExchangeRate(
+	ctx context.Context,
+	base money.Currency,
+	currency money.Currency,
+	rate money.Rate,
+	updatedAt time.Time,
+) error {
+	err := e.exchangeRateRepository.SaveExchangeRate(ctx, base, currency, rate, updatedAt)
+	if err != nil {
+		return fmt.Errorf("exchangeRateApplicationService SaveExchangeRate -> exchangeRateRepository.SaveExchangeRate: %w", err)
+	}
+	return nil
+}
+
+func (e exchangeRateApplicationService) DeleteExchangeRate(ctx context.Context, currency money.Currency, updatedAt time.Time) error {
+	err := e.exchangeRateRepository.DeleteExchangeRate(ctx, currency, updatedAt)
+	if err != nil {
+		return fmt.Errorf("exchangeRateApplicationService DeleteExchangeRate -> exchangeRateRepository.DeleteExchangeRate: %w", err)
+	}
+	return nil
+}
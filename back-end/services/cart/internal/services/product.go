@@ -7,9 +7,12 @@ import (
 	cartEntity "cart/internal/domain/entities/cart"
 	productEntity "cart/internal/domain/entities/product"
 	cartRepo "cart/internal/repositories/cart"
+	exchangeRateRepo "cart/internal/repositories/exchangerate"
 	productRepo "cart/internal/repositories/product"
-	customErrors "shared/errors"
 	nats "shared/messaging/nats"
+	"shared/money"
+	"shared/outbox"
+	pgStorage "shared/storage/pg"
 
 	"github.com/rs/zerolog"
 )
@@ -19,29 +22,42 @@ var _ ProductApplicationService = (*productApplicationService)(nil)
 type productApplicationService struct {
 	productRepository productRepo.ProductRepository
 	cartRepository    cartRepo.CartRepository
+	exchangeRates     exchangeRateRepo.ExchangeRateRepository
 	logger            zerolog.Logger
 	natsClient        nats.NatsClient
+	transactor        pgStorage.Transactor
+	outbox            outbox.Writer
 }
 
 type ProductApplicationService interface {
-	GetCart(ctx context.Context, customerID string) (cartEntity.CartReadModel, error)
+	GetCart(ctx context.Context, customerID string, currency string) (cartEntity.CartReadModel, error)
 	CreateProduct(ctx context.Context, createProductParams productEntity.CreateProductParams) error
 	DeleteProduct(ctx context.Context, productID string) error
 	UpdateProduct(ctx context.Context, createProductParams productEntity.CreateProductParams) error
-	UpdateProductsInCart(ctx context.Context, productID string, quantity int, customerID string) error
+	SaveVariant(ctx context.Context, createVariantParams productEntity.CreateVariantParams) error
+	DeleteVariant(ctx context.Context, productID string, variantID string) error
+	UpdateProductsInCart(ctx context.Context, productID string, variantID string, quantity int, customerID string) error
+	RemoveOrderedProducts(ctx context.Context, customerID string, orderedProducts []cartEntity.CartProduct) error
+	AcceptCartPrices(ctx context.Context, customerID string) error
 }
 
 func NewProductApplicationService(
 	productRepository productRepo.ProductRepository,
 	cartRepository cartRepo.CartRepository,
+	exchangeRates exchangeRateRepo.ExchangeRateRepository,
 	logger zerolog.Logger,
 	natsClient nats.NatsClient,
+	transactor pgStorage.Transactor,
+	outboxWriter outbox.Writer,
 ) productApplicationService {
 	return productApplicationService{
 		productRepository: productRepository,
 		cartRepository:    cartRepository,
+		exchangeRates:     exchangeRates,
 		logger:            logger,
 		natsClient:        natsClient,
+		transactor:        transactor,
+		outbox:            outboxWriter,
 	}
 }
 
@@ -60,52 +76,171 @@ func (p productApplicationService) CreateProduct(
 	return nil
 }
 
-var ErrInvalidEmailFormat = customErrors.NewNotFoundError("cart/products", "Product not found")
-
 func (p productApplicationService) UpdateProductsInCart(
 	ctx context.Context,
 	productID string,
+	variantID string,
 	quantity int,
 	customerID string,
 ) error {
-	updateOperation := func(cart cartEntity.Cart) (cartEntity.Cart, error) {
+	return p.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		product, err := p.productRepository.GetProductByID(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService UpdateProductsInCart -> p.productRepository.GetProductByID: %w", err)
+		}
+
+		var updatedCart cartEntity.Cart
+		updateOperation := func(cart cartEntity.Cart) (cartEntity.Cart, error) {
+			cart, err := cart.UpdateProductsInCart(
+				cartEntity.CartProduct{
+					ProductID: productID,
+					VariantID: variantID,
+					Quantity:  quantity,
+				},
+				product,
+			)
+			if err != nil {
+				return cartEntity.Cart{}, fmt.Errorf("productApplicationService UpdateProductsInCart -> cart.UpdateProductsInCart: %w", err)
+			}
+			updatedCart = cart
+			return cart, nil
+		}
+
+		err = p.cartRepository.SaveCart(ctx, customerID, updateOperation)
+		if err != nil {
+			return fmt.Errorf("productApplicationService UpdateProductsInCart -> p.cartRepository.SaveCart: %w", err)
+		}
+
+		messages, err := cartEventsToMessages(ctx, updatedCart)
+		if err != nil {
+			return fmt.Errorf("productApplicationService UpdateProductsInCart -> cartEventsToMessages: %w", err)
+		}
+		err = p.outbox.Save(ctx, messages...)
+		if err != nil {
+			return fmt.Errorf("productApplicationService UpdateProductsInCart -> p.outbox.Save: %w", err)
+		}
+		return nil
+	})
+}
 
-		cart, err := cart.UpdateProductsInCart(
-			cartEntity.CartProduct{
-				ProductID: productID,
-				Quantity:  quantity,
-			},
-		)
+// AcceptCartPrices takes the current prices of the cart's products as the
+// prices the shopper agreed to pay.
+func (p productApplicationService) AcceptCartPrices(ctx context.Context, customerID string) error {
+	return p.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		var updatedCart cartEntity.Cart
+		updateOperation := func(cart cartEntity.Cart) (cartEntity.Cart, error) {
+			accepted := map[string]bool{}
+			for _, productInCart := range cart.Products() {
+				if accepted[productInCart.ProductID] {
+					continue
+				}
+				accepted[productInCart.ProductID] = true
+				product, err := p.productRepository.GetProductByID(ctx, productInCart.ProductID)
+				if err != nil {
+					return cartEntity.Cart{}, fmt.Errorf("productApplicationService AcceptCartPrices -> p.productRepository.GetProductByID: %w", err)
+				}
+				if product.IsZero() {
+					continue
+				}
+				cart = cart.AcceptCurrentPrice(product)
+			}
+			updatedCart = cart
+			return cart, nil
+		}
 
+		err := p.cartRepository.SaveCart(ctx, customerID, updateOperation)
 		if err != nil {
-			return cartEntity.Cart{}, fmt.Errorf("productApplicationService UpdateProductsInCart -> cartEntity.NewCart: %w", err)
+			return fmt.Errorf("productApplicationService AcceptCartPrices -> p.cartRepository.SaveCart: %w", err)
 		}
-		return cart, nil
+
+		messages, err := cartEventsToMessages(ctx, updatedCart)
+		if err != nil {
+			return fmt.Errorf("productApplicationService AcceptCartPrices -> cartEventsToMessages: %w", err)
+		}
+		err = p.outbox.Save(ctx, messages...)
+		if err != nil {
+			return fmt.Errorf("productApplicationService AcceptCartPrices -> p.outbox.Save: %w", err)
+		}
+		return nil
+	})
+}
+
+func (p productApplicationService) RemoveOrderedProducts(
+	ctx context.Context,
+	customerID string,
+	orderedProducts []cartEntity.CartProduct,
+) error {
+	var updatedCart cartEntity.Cart
+	updateOperation := func(cart cartEntity.Cart) (cartEntity.Cart, error) {
+		updatedCart = cart.RemoveOrderedProducts(orderedProducts)
+		return updatedCart, nil
 	}
 
-	err := p.cartRepository.SaveCart(ctx, customerID, updateOperation)
+	return p.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := p.cartRepository.SaveCart(ctx, customerID, updateOperation)
+		if err != nil {
+			return fmt.Errorf("productApplicationService RemoveOrderedProducts -> p.cartRepository.SaveCart: %w", err)
+		}
+
+		messages, err := cartEventsToMessages(ctx, updatedCart)
+		if err != nil {
+			return fmt.Errorf("productApplicationService RemoveOrderedProducts -> cartEventsToMessages: %w", err)
+		}
+		err = p.outbox.Save(ctx, messages...)
+		if err != nil {
+			return fmt.Errorf("productApplicationService RemoveOrderedProducts -> p.outbox.Save: %w", err)
+		}
+		return nil
+	})
+}
 
+// GetCart prices the cart in currency, or in the base currency of the
+// exchange rates when no currency is given.
+func (p productApplicationService) GetCart(ctx context.Context, customerID string, currency string) (cartEntity.CartReadModel, error) {
+	products, err := p.cartRepository.GetProductsByCustomerID(ctx, customerID)
+	if err != nil {
+		return cartEntity.CartReadModel{}, fmt.Errorf("productApplicationService GetCart -> cartRepository.GetProductsByCustomerID: %w", err)
+	}
+	rates, err := p.exchangeRates.GetRateTable(ctx)
 	if err != nil {
-		return fmt.Errorf("productApplicationService UpdateProductsInCart -> productRepository.GetProductByID: %w", err)
+		return cartEntity.CartReadModel{}, fmt.Errorf("productApplicationService GetCart -> exchangeRates.GetRateTable: %w", err)
 	}
 
-	return nil
-}
+	cartCurrency := rates.Base()
+	if currency != "" {
+		cartCurrency, err = money.ParseCurrency(currency)
+		if err != nil {
+			return cartEntity.CartReadModel{}, cartEntity.ErrUnsupportedCurrency
+		}
+	}
 
-func (p productApplicationService) GetCart(ctx context.Context, customerID string) (cartEntity.CartReadModel, error) {
-	cart, err := p.cartRepository.GetByCustomerID(ctx, customerID)
+	cart, err := cartEntity.NewCartReadModel(customerID, products, cartCurrency, rates)
 	if err != nil {
-		return cartEntity.CartReadModel{}, fmt.Errorf("productApplicationService GetCart -> cartRepository.GetByCustomerID: %w", err)
+		return cartEntity.CartReadModel{}, fmt.Errorf("productApplicationService GetCart -> cartEntity.NewCartReadModel: %w", err)
 	}
 	return cart, nil
 }
 
+// Removes the product and its variants from every cart before deleting the
+// local copy.
 func (p productApplicationService) DeleteProduct(ctx context.Context, productID string) error {
-	err := p.productRepository.DeleteProductByID(ctx, productID)
-	if err != nil {
-		return fmt.Errorf("productApplicationService DeleteProduct -> p.productRepository.DeleteProductByID: %w", err)
-	}
-	return nil
+	return p.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		customerIDs, err := p.cartRepository.GetCustomerIDsByProductID(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService DeleteProduct -> p.cartRepository.GetCustomerIDsByProductID: %w", err)
+		}
+		err = p.updateCarts(ctx, customerIDs, func(cart cartEntity.Cart) cartEntity.Cart {
+			return cart.RemoveProduct(productID)
+		})
+		if err != nil {
+			return fmt.Errorf("productApplicationService DeleteProduct -> p.updateCarts: %w", err)
+		}
+		err = p.productRepository.DeleteProductByID(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService DeleteProduct -> p.productRepository.DeleteProductByID: %w", err)
+		}
+		return nil
+	})
 }
 
 func (p productApplicationService) UpdateProduct(
@@ -116,9 +251,101 @@ func (p productApplicationService) UpdateProduct(
 	if err != nil {
 		return fmt.Errorf("productApplicationService CreateProduct ->  productEntity.NewProduct: %w", err)
 	}
-	err = p.productRepository.UpdateProductByID(ctx, product)
+	// carts holding more than the new stock are clamped to it
+	return p.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err = p.productRepository.UpdateProductByID(ctx, product)
+		if err != nil {
+			return fmt.Errorf("productApplicationService UpdateProduct -> productRepository.UpdateProductByID: %w", err)
+		}
+		err = p.updateCartsExceedingStock(ctx, product.ID(), "", product.Quantity(), func(cart cartEntity.Cart) cartEntity.Cart {
+			return cart.ClampToStock(product)
+		})
+		if err != nil {
+			return fmt.Errorf("productApplicationService UpdateProduct -> p.updateCartsExceedingStock: %w", err)
+		}
+		return nil
+	})
+}
+
+// SaveVariant stores the local copy of a variant and clamps the carts holding
+// more of it than its stock.
+func (p productApplicationService) SaveVariant(
+	ctx context.Context,
+	createVariantParams productEntity.CreateVariantParams,
+) error {
+	variant, err := productEntity.NewVariant(createVariantParams)
 	if err != nil {
-		return fmt.Errorf("productApplicationService CreateProduct -> productRepository.CreateProduct: %w", err)
+		return fmt.Errorf("productApplicationService SaveVariant -> productEntity.NewVariant: %w", err)
+	}
+	return p.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err = p.productRepository.SaveVariant(ctx, variant)
+		if err != nil {
+			return fmt.Errorf("productApplicationService SaveVariant -> p.productRepository.SaveVariant: %w", err)
+		}
+		err = p.updateCartsExceedingStock(ctx, variant.ProductID(), variant.ID(), variant.Quantity(), func(cart cartEntity.Cart) cartEntity.Cart {
+			return cart.ClampVariantToStock(variant)
+		})
+		if err != nil {
+			return fmt.Errorf("productApplicationService SaveVariant -> p.updateCartsExceedingStock: %w", err)
+		}
+		return nil
+	})
+}
+
+// Removes the variant from every cart before deleting the local copy.
+func (p productApplicationService) DeleteVariant(ctx context.Context, productID string, variantID string) error {
+	return p.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := p.updateCartsExceedingStock(ctx, productID, variantID, 0, func(cart cartEntity.Cart) cartEntity.Cart {
+			cart, _ = cart.UpdateProductsInCart(cartEntity.CartProduct{ProductID: productID, VariantID: variantID}, productEntity.Product{})
+			return cart
+		})
+		if err != nil {
+			return fmt.Errorf("productApplicationService DeleteVariant -> p.updateCartsExceedingStock: %w", err)
+		}
+		err = p.productRepository.DeleteVariantByID(ctx, variantID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService DeleteVariant -> p.productRepository.DeleteVariantByID: %w", err)
+		}
+		return nil
+	})
+}
+
+func (p productApplicationService) updateCartsExceedingStock(
+	ctx context.Context,
+	productID string,
+	variantID string,
+	available int,
+	update func(cart cartEntity.Cart) cartEntity.Cart,
+) error {
+	customerIDs, err := p.cartRepository.GetCustomerIDsExceedingStock(ctx, productID, variantID, available)
+	if err != nil {
+		return fmt.Errorf("productApplicationService updateCartsExceedingStock -> p.cartRepository.GetCustomerIDsExceedingStock: %w", err)
+	}
+	return p.updateCarts(ctx, customerIDs, update)
+}
+
+func (p productApplicationService) updateCarts(
+	ctx context.Context,
+	customerIDs []string,
+	update func(cart cartEntity.Cart) cartEntity.Cart,
+) error {
+	for _, customerID := range customerIDs {
+		var updatedCart cartEntity.Cart
+		err := p.cartRepository.SaveCart(ctx, customerID, func(cart cartEntity.Cart) (cartEntity.Cart, error) {
+			updatedCart = update(cart)
+			return updatedCart, nil
+		})
+		if err != nil {
+			return fmt.Errorf("productApplicationService updateCarts -> p.cartRepository.SaveCart: %w", err)
+		}
+		messages, err := cartEventsToMessages(ctx, updatedCart)
+		if err != nil {
+			return fmt.Errorf("productApplicationService updateCarts -> cartEventsToMessages: %w", err)
+		}
+		err = p.outbox.Save(ctx, messages...)
+		if err != nil {
+			return fmt.Errorf("productApplicationService updateCarts -> p.outbox.Save: %w", err)
+		}
 	}
 	return nil
 }
@@ -3,11 +3,11 @@ package controllers
 import (
 	"cart/config"
 	applicationServices "cart/internal/services"
-	"encoding/json"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
+	"shared/authz"
 	httpErrors "shared/errors/http"
 )
 
@@ -29,12 +29,9 @@ func NewProductController(
 	}
 }
 
-type AuthInfo struct {
-	UserID string `json:"user_id"`
-}
-
 type UpdateProductsInCartInput struct {
 	ProductID string `json:"productId" binding:"required"`
+	VariantID string `json:"variantId"`
 	Quantity  int    `json:"quantity" binding:"gte=0"`
 }
 
@@ -45,15 +42,14 @@ func (p *ProductController) UpdateProductsInCart(c *gin.Context) {
 		return
 	}
 
-	var authInfo AuthInfo
-	authValue := c.Request.Header.Get("X-Authentication-Info")
-	json.Unmarshal([]byte(authValue), &authInfo)
+	identity := authz.FromRequest(c.Request)
 
 	err := p.ApplicationService.UpdateProductsInCart(
 		c.Request.Context(),
 		UpdateProductsInCartInput.ProductID,
+		UpdateProductsInCartInput.VariantID,
 		UpdateProductsInCartInput.Quantity,
-		authInfo.UserID,
+		identity.UserID,
 	)
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
@@ -68,13 +64,12 @@ type GetCartInput struct {
 
 func (p *ProductController) GetCart(c *gin.Context) {
 
-	var authInfo AuthInfo
-	authValue := c.Request.Header.Get("X-Authentication-Info")
-	json.Unmarshal([]byte(authValue), &authInfo)
+	identity := authz.FromRequest(c.Request)
 
 	cart, err := p.ApplicationService.GetCart(
 		c.Request.Context(),
-		authInfo.UserID,
+		identity.UserID,
+		c.Query("currency"),
 	)
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
@@ -82,3 +77,16 @@ func (p *ProductController) GetCart(c *gin.Context) {
 	}
 	handleResponseWithBody(c, cart)
 }
+
+// AcceptCartPrices agrees to the current prices of the cart, checkout is
+// refused while a price differs from the one the product was added at.
+func (p *ProductController) AcceptCartPrices(c *gin.Context) {
+	identity := authz.FromRequest(c.Request)
+
+	err := p.ApplicationService.AcceptCartPrices(c.Request.Context(), identity.UserID)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	handleOkResponse(c)
+}
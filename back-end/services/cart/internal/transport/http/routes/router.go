@@ -3,8 +3,8 @@ package routes
 import (
 	"cart/config"
 	controllers "cart/internal/transport/http/controllers"
-
-	"net/http"
+	"shared/authz"
+	"shared/health"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
@@ -15,14 +15,17 @@ func NewRouter(
 	p *controllers.ProductController,
 	logger zerolog.Logger,
 	config *config.Config,
+	checks *health.Health,
+	verifier authz.Verifier,
 ) {
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
-	handler.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	checks.Register(handler)
 
-	v1 := handler.Group("/v1")
+	v1 := handler.Group("/v1", authz.Middleware(verifier))
 
 	v1.PATCH("/cart/products", p.UpdateProductsInCart)
 	v1.GET("/cart", p.GetCart)
+	v1.PUT("/cart/prices", p.AcceptCartPrices)
 
 }
@@ -11,6 +11,8 @@ import (
 	"cart/config"
 	controllers "cart/internal/transport/http/controllers"
 	routes "cart/internal/transport/http/routes"
+	"shared/authz"
+	"shared/health"
 
 	"cart/pkg/httpserver"
 )
@@ -21,9 +23,10 @@ func NewHTTPServer(
 	logger zerolog.Logger,
 	config *config.Config,
 	db *bun.DB,
-
+	checks *health.Health,
+	verifier authz.Verifier,
 ) *httpserver.Server {
-	routes.NewRouter(handler, productController, logger, config)
+	routes.NewRouter(handler, productController, logger, config, checks, verifier)
 	logger.Info().Msg(fmt.Sprintf("Listening on %s port", config.HTTP.Port))
 	return httpserver.New(http.Handler(handler), httpserver.Port(config.HTTP.Port))
 }
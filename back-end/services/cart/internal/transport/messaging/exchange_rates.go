@@ -0,0 +1,86 @@
+package messaging
+
+import (
+	"context"
+	"shared/events"
+	"shared/inbox"
+	natsClient "shared/messaging/nats"
+
+	applicationServices "cart/internal/services"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	exchangeRateUpdatedDurableConsumerName = "cart-exchange-rate-updated"
+	exchangeRateDeletedDurableConsumerName = "cart-exchange-rate-deleted"
+	exchangeRateStream                     = "exchange_rates"
+)
+
+type ExchangeRateMessagingHandlers interface {
+	ExchangeRateUpdatedListener()
+	ExchangeRateDeletedListener()
+	Init()
+}
+
+var _ ExchangeRateMessagingHandlers = (*exchangeRateMessagingHandlers)(nil)
+
+type exchangeRateMessagingHandlers struct {
+	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
+	logger     zerolog.Logger
+	appService applicationServices.ExchangeRateApplicationService
+}
+
+func NewExchangeRateMessagingHandlers(
+	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
+	appService applicationServices.ExchangeRateApplicationService,
+	logger zerolog.Logger,
+) *exchangeRateMessagingHandlers {
+	e := exchangeRateMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
+	return &e
+}
+
+func (e *exchangeRateMessagingHandlers) Init() {
+	e.logger.Info().Msg("initializing ExchangeRateMessagingHandlers")
+
+	err := e.natsClient.CreateStream(exchangeRateStream, "exchange_rates.*")
+	if err != nil {
+		log.Error().Err(err).Msg("exchangeRateMessagingHandlers Init -> e.natsClient.CreateStream")
+	}
+
+	e.ExchangeRateUpdatedListener()
+	e.ExchangeRateDeletedListener()
+}
+
+func (e *exchangeRateMessagingHandlers) ExchangeRateUpdatedListener() {
+	e.logger.Info().Msg("ExchangeRateUpdatedListener initialized")
+	handler := func(ctx context.Context, rateEvent events.ExchangeRateUpdated, envelope events.Envelope) error {
+		log.Info().Msg("ExchangeRateUpdatedListener -> Received a message: " + string(envelope.Payload))
+
+		err := e.appService.SaveExchangeRate(ctx, rateEvent.BaseCurrency, rateEvent.Currency, rateEvent.Rate, rateEvent.UpdatedAt)
+		if err != nil {
+			log.Error().Err(err).Msg("ExchangeRateUpdatedListener -> e.appService.SaveExchangeRate")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(e.natsClient, exchangeRateStream, exchangeRateUpdatedDurableConsumerName, inbox.Idempotent(e.inbox, exchangeRateUpdatedDurableConsumerName, handler))
+}
+
+func (e *exchangeRateMessagingHandlers) ExchangeRateDeletedListener() {
+	e.logger.Info().Msg("ExchangeRateDeletedListener initialized")
+	handler := func(ctx context.Context, rateEvent events.ExchangeRateDeleted, envelope events.Envelope) error {
+		log.Info().Msg("ExchangeRateDeletedListener -> Received a message: " + string(envelope.Payload))
+
+		err := e.appService.DeleteExchangeRate(ctx, rateEvent.Currency, rateEvent.UpdatedAt)
+		if err != nil {
+			log.Error().Err(err).Msg("ExchangeRateDeletedListener -> e.appService.DeleteExchangeRate")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(e.natsClient, exchangeRateStream, exchangeRateDeletedDurableConsumerName, inbox.Idempotent(e.inbox, exchangeRateDeletedDurableConsumerName, handler))
+}
@@ -0,0 +1,77 @@
+package messaging
+
+import (
+	"context"
+	"shared/events"
+	"shared/inbox"
+	natsClient "shared/messaging/nats"
+
+	cartEntity "cart/internal/domain/entities/cart"
+	applicationServices "cart/internal/services"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	orderCreatedDurableConsumerName = "cart-order-created"
+	orderStream                     = "orders"
+)
+
+type OrderMessagingHandlers interface {
+	OrderCreatedListener()
+	Init()
+}
+
+var _ OrderMessagingHandlers = (*orderMessagingHandlers)(nil)
+
+type orderMessagingHandlers struct {
+	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
+	logger     zerolog.Logger
+	appService applicationServices.ProductApplicationService
+}
+
+func NewOrderMessagingHandlers(
+	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
+	appService applicationServices.ProductApplicationService,
+	logger zerolog.Logger,
+) *orderMessagingHandlers {
+	o := orderMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
+	return &o
+}
+
+func (o *orderMessagingHandlers) Init() {
+	o.logger.Info().Msg("initializing OrderMessagingHandlers")
+
+	err := o.natsClient.CreateStream(orderStream, "orders.*")
+	if err != nil {
+		log.Error().Err(err).Msg("orderMessagingHandlers Init -> o.natsClient.CreateStream")
+	}
+
+	o.OrderCreatedListener()
+}
+
+func (o *orderMessagingHandlers) OrderCreatedListener() {
+	o.logger.Info().Msg("OrderCreatedListener initialized")
+	handler := func(ctx context.Context, orderEvent events.OrderCreated, envelope events.Envelope) error {
+		log.Info().Msg("OrderCreatedListener -> Received a message: " + string(envelope.Payload))
+
+		orderedProducts := make([]cartEntity.CartProduct, 0, len(orderEvent.Items))
+		for _, item := range orderEvent.Items {
+			orderedProducts = append(orderedProducts, cartEntity.CartProduct{
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  item.Quantity,
+			})
+		}
+		err := o.appService.RemoveOrderedProducts(ctx, orderEvent.CustomerID, orderedProducts)
+		if err != nil {
+			log.Error().Err(err).Msg("OrderCreatedListener -> o.appService.RemoveOrderedProducts")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(o.natsClient, orderStream, orderCreatedDurableConsumerName, inbox.Idempotent(o.inbox, orderCreatedDurableConsumerName, handler))
+}
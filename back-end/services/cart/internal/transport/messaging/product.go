@@ -2,25 +2,24 @@ package messaging
 
 import (
 	"context"
-	"encoding/json"
+	"shared/events"
+	"shared/inbox"
 	natsClient "shared/messaging/nats"
-	"time"
 
 	productEntity "cart/internal/domain/entities/product"
 	applicationServices "cart/internal/services"
 
-	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
 const (
-	productCreatedSubject             = "products.created"
-	productDeletedSubject             = "products.deleted"
-	productUpdatedSubject             = "products.updated"
 	productCreatedDurableConsumerName = "cart-product-created"
 	productDeletedDurableConsumerName = "cart-product-deleted"
 	productUpdatedDurableConsumerName = "cart-product-updated"
+	variantCreatedDurableConsumerName = "cart-product-variant-created"
+	variantUpdatedDurableConsumerName = "cart-product-variant-updated"
+	variantDeletedDurableConsumerName = "cart-product-variant-deleted"
 	productStream                     = "products"
 )
 
@@ -33,16 +32,18 @@ var _ ProductMessagingHandlers = (*productMessagingHandlers)(nil)
 
 type productMessagingHandlers struct {
 	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
 	logger     zerolog.Logger
 	appService applicationServices.ProductApplicationService
 }
 
 func NewProductMessagingHandlers(
 	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
 	appService applicationServices.ProductApplicationService,
 	logger zerolog.Logger,
 ) *productMessagingHandlers {
-	d := productMessagingHandlers{natsClient: natsClient, appService: appService, logger: logger}
+	d := productMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
 	return &d
 }
 
@@ -57,43 +58,17 @@ func (d *productMessagingHandlers) Init() {
 	d.ProductCreatedListener()
 	d.ProductDeletedListener()
 	d.ProductUpdatedListener()
-}
-
-type ProductCreatedEvent struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Price     float64   `json:"price"`
-	Quantity  int       `json:"quantity"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
-
-type ProductDeletedEvent struct {
-	ID string `json:"id"`
-}
-
-type ProductUpdatedEvent struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Price     float64   `json:"price"`
-	Quantity  int       `json:"quantity"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	d.VariantCreatedListener()
+	d.VariantUpdatedListener()
+	d.VariantDeletedListener()
 }
 
 func (d *productMessagingHandlers) ProductCreatedListener() {
 	d.logger.Info().Msg("ProductCreatedListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("ProductCreatedListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, productEvent events.ProductCreated, envelope events.Envelope) error {
+		log.Info().Msg("ProductCreatedListener -> Received a message: " + string(envelope.Payload))
 
-		var productEvent ProductCreatedEvent
-		err := json.Unmarshal(messageData, &productEvent)
-		if err != nil {
-			log.Error().Msg("ProductCreatedListener -> Error in unmarshalling the message")
-			return err
-		}
-		err = d.appService.CreateProduct(context.Background(), productEntity.CreateProductParams{
+		err := d.appService.CreateProduct(ctx, productEntity.CreateProductParams{
 			ID:        productEvent.ID,
 			Name:      productEvent.Name,
 			Price:     productEvent.Price,
@@ -107,44 +82,30 @@ func (d *productMessagingHandlers) ProductCreatedListener() {
 		}
 		return nil
 	}
-	d.natsClient.SubscribeDurable(productCreatedSubject, productStream, productCreatedDurableConsumerName, handler)
+	events.Subscribe(d.natsClient, productStream, productCreatedDurableConsumerName, inbox.Idempotent(d.inbox, productCreatedDurableConsumerName, handler))
 }
 
 func (d *productMessagingHandlers) ProductDeletedListener() {
 	d.logger.Info().Msg("ProductDeletedListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("ProductDeletedListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, productDeletedEvent events.ProductDeleted, envelope events.Envelope) error {
+		log.Info().Msg("ProductDeletedListener -> Received a message: " + string(envelope.Payload))
 
-		var productDeletedEvent ProductDeletedEvent
-		err := json.Unmarshal(messageData, &productDeletedEvent)
-		if err != nil {
-			log.Error().Msg("ProductDeletedListener -> Error in unmarshalling the message")
-			return err
-		}
-		err = d.appService.DeleteProduct(context.Background(), productDeletedEvent.ID)
+		err := d.appService.DeleteProduct(ctx, productDeletedEvent.ID)
 		if err != nil {
 			log.Error().Err(err).Msg("ProductDeletedListener -> d.appService.CreateProduct")
 			return err
 		}
 		return nil
 	}
-	d.natsClient.SubscribeDurable(productDeletedSubject, productStream, productDeletedDurableConsumerName, handler)
+	events.Subscribe(d.natsClient, productStream, productDeletedDurableConsumerName, inbox.Idempotent(d.inbox, productDeletedDurableConsumerName, handler))
 }
 
 func (d *productMessagingHandlers) ProductUpdatedListener() {
 	d.logger.Info().Msg("ProductUpdatedListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("ProductUpdatedListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, productUpdatedEvent events.ProductUpdated, envelope events.Envelope) error {
+		log.Info().Msg("ProductUpdatedListener -> Received a message: " + string(envelope.Payload))
 
-		var productUpdatedEvent ProductUpdatedEvent
-		err := json.Unmarshal(messageData, &productUpdatedEvent)
-		if err != nil {
-			log.Error().Msg("ProductUpdatedListener -> Error in unmarshalling the message")
-			return err
-		}
-		err = d.appService.UpdateProduct(context.Background(), productEntity.CreateProductParams{
+		err := d.appService.UpdateProduct(ctx, productEntity.CreateProductParams{
 			ID:        productUpdatedEvent.ID,
 			Name:      productUpdatedEvent.Name,
 			Price:     productUpdatedEvent.Price,
@@ -158,5 +119,66 @@ func (d *productMessagingHandlers) ProductUpdatedListener() {
 		}
 		return nil
 	}
-	d.natsClient.SubscribeDurable(productUpdatedSubject, productStream, productUpdatedDurableConsumerName, handler)
+	events.Subscribe(d.natsClient, productStream, productUpdatedDurableConsumerName, inbox.Idempotent(d.inbox, productUpdatedDurableConsumerName, handler))
+}
+
+func (d *productMessagingHandlers) VariantCreatedListener() {
+	d.logger.Info().Msg("VariantCreatedListener initialized")
+	handler := func(ctx context.Context, variantEvent events.ProductVariantCreated, envelope events.Envelope) error {
+		log.Info().Msg("VariantCreatedListener -> Received a message: " + string(envelope.Payload))
+
+		err := d.appService.SaveVariant(ctx, productEntity.CreateVariantParams{
+			ID:        variantEvent.ID,
+			ProductID: variantEvent.ProductID,
+			SKU:       variantEvent.SKU,
+			Price:     variantEvent.Price,
+			Quantity:  variantEvent.Quantity,
+			CreatedAt: variantEvent.CreatedAt,
+			UpdatedAt: variantEvent.UpdatedAt,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("VariantCreatedListener -> d.appService.SaveVariant")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, variantCreatedDurableConsumerName, inbox.Idempotent(d.inbox, variantCreatedDurableConsumerName, handler))
+}
+
+func (d *productMessagingHandlers) VariantUpdatedListener() {
+	d.logger.Info().Msg("VariantUpdatedListener initialized")
+	handler := func(ctx context.Context, variantEvent events.ProductVariantUpdated, envelope events.Envelope) error {
+		log.Info().Msg("VariantUpdatedListener -> Received a message: " + string(envelope.Payload))
+
+		err := d.appService.SaveVariant(ctx, productEntity.CreateVariantParams{
+			ID:        variantEvent.ID,
+			ProductID: variantEvent.ProductID,
+			SKU:       variantEvent.SKU,
+			Price:     variantEvent.Price,
+			Quantity:  variantEvent.Quantity,
+			CreatedAt: variantEvent.CreatedAt,
+			UpdatedAt: variantEvent.UpdatedAt,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("VariantUpdatedListener -> d.appService.SaveVariant")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, variantUpdatedDurableConsumerName, inbox.Idempotent(d.inbox, variantUpdatedDurableConsumerName, handler))
+}
+
+func (d *productMessagingHandlers) VariantDeletedListener() {
+	d.logger.Info().Msg("VariantDeletedListener initialized")
+	handler := func(ctx context.Context, variantEvent events.ProductVariantDeleted, envelope events.Envelope) error {
+		log.Info().Msg("VariantDeletedListener -> Received a message: " + string(envelope.Payload))
+
+		err := d.appService.DeleteVariant(ctx, variantEvent.ProductID, variantEvent.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("VariantDeletedListener -> d.appService.DeleteVariant")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, variantDeletedDurableConsumerName, inbox.Idempotent(d.inbox, variantDeletedDurableConsumerName, handler))
 }
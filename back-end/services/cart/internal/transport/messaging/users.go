@@ -2,13 +2,13 @@ package messaging
 
 import (
 	"context"
-	"encoding/json"
+	"shared/events"
+	"shared/inbox"
 	natsClient "shared/messaging/nats"
 
 	applicationServices "cart/internal/services"
 	"cart/internal/services/dto"
 
-	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
@@ -16,9 +16,6 @@ import (
 )
 
 const (
-	userCreationSubject           = "users.created"
-	userUpdateSubject             = "users.updated"
-	userDeletionSubject           = "users.deleted"
 	usersStreamName               = "users"
 	userCreateDurableConsumerName = "cart-user-create"
 	userDeleteDurableConsumerName = "cart-user-delete"
@@ -34,16 +31,18 @@ type UserMessagingHandlers interface {
 
 type userMessagingHandlers struct {
 	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
 	logger     zerolog.Logger
 	appService applicationServices.CustomerApplicationService
 }
 
 func NewCustomerMessagingHandlers(
 	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
 	appService applicationServices.CustomerApplicationService,
 	logger zerolog.Logger,
 ) *userMessagingHandlers {
-	u := userMessagingHandlers{natsClient: natsClient, appService: appService, logger: logger}
+	u := userMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
 	return &u
 }
 
@@ -59,34 +58,12 @@ func (u *userMessagingHandlers) Init() {
 	u.UserDeletedListener()
 }
 
-type UserCreatedEvent struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	ID    string `json:"id"`
-}
-
-type UserUpdatedEvent struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
-}
-
-type UserDeletedEvent struct {
-	ID string `json:"id"`
-}
-
 func (u *userMessagingHandlers) UserCreationListener() {
 	u.logger.Info().Msg("UserCreationListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("UserCreationListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, userCreatedEvent events.UserCreated, envelope events.Envelope) error {
+		log.Info().Msg("UserCreationListener -> Received a message: " + string(envelope.Payload))
 
-		var userCreatedEvent UserCreatedEvent
-		err := json.Unmarshal(messageData, &userCreatedEvent)
-		if err != nil {
-			log.Error().Msg("UserCreationListener -> Error in unmarshalling the message")
-			return err
-		}
-		_, err = u.appService.CreateCustomer(context.Background(), customerEntity.CreateCustomerParams{
+		_, err := u.appService.CreateCustomer(ctx, customerEntity.CreateCustomerParams{
 			Name:  userCreatedEvent.Name,
 			Email: userCreatedEvent.Email,
 			ID:    userCreatedEvent.ID,
@@ -97,23 +74,16 @@ func (u *userMessagingHandlers) UserCreationListener() {
 		}
 		return nil
 	}
-	u.natsClient.SubscribeDurable(userCreationSubject, usersStreamName, userCreateDurableConsumerName, handler)
+	events.Subscribe(u.natsClient, usersStreamName, userCreateDurableConsumerName, inbox.Idempotent(u.inbox, userCreateDurableConsumerName, handler))
 }
 
 func (u *userMessagingHandlers) UserUpdateListener() {
 	u.logger.Info().Msg("UserUpdateListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("UserUpdateListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, userUpdatedEvent events.UserUpdated, envelope events.Envelope) error {
+		log.Info().Msg("UserUpdateListener -> Received a message: " + string(envelope.Payload))
 
-		var userUpdatedEvent UserUpdatedEvent
-		err := json.Unmarshal(messageData, &userUpdatedEvent)
-		if err != nil {
-			log.Error().Msg("UserUpdateListener -> Error in unmarshalling the message")
-			return err
-		}
-		err = u.appService.UpdateCustomer(
-			context.Background(),
+		err := u.appService.UpdateCustomer(
+			ctx,
 			dto.UpdateCustomerInput{
 				Name: userUpdatedEvent.Name,
 				ID:   userUpdatedEvent.ID,
@@ -125,28 +95,25 @@ func (u *userMessagingHandlers) UserUpdateListener() {
 		}
 		return nil
 	}
-	u.natsClient.SubscribeDurable(userUpdateSubject, usersStreamName, userUpdateDurableConsumerName, handler)
+	events.Subscribe(u.natsClient, usersStreamName, userUpdateDurableConsumerName, inbox.Idempotent(u.inbox, userUpdateDurableConsumerName, handler))
 }
 
 func (u *userMessagingHandlers) UserDeletedListener() {
 	u.logger.Info().Msg("UserDeletedListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("UserDeletedListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, userDeletedEvent events.UserDeleted, envelope events.Envelope) error {
+		log.Info().Msg("UserDeletedListener -> Received a message: " + string(envelope.Payload))
 
-		var userDeletedEvent UserDeletedEvent
-		err := json.Unmarshal(messageData, &userDeletedEvent)
-		if err != nil {
-			log.Error().Msg("UserUpdateListener -> Error in unmarshalling the message")
-			return err
-		}
-		u.appService.DeleteCustomer(
-			context.Background(),
+		err := u.appService.DeleteCustomer(
+			ctx,
 			dto.DeleteCustomerInput{
 				ID: userDeletedEvent.ID,
 			},
 		)
+		if err != nil {
+			log.Error().Err(err).Msg("UserDeletedListener -> u.appService.DeleteCustomer")
+			return err
+		}
 		return nil
 	}
-	u.natsClient.SubscribeDurable(userDeletionSubject, usersStreamName, userDeleteDurableConsumerName, handler)
+	events.Subscribe(u.natsClient, usersStreamName, userDeleteDurableConsumerName, inbox.Idempotent(u.inbox, userDeleteDurableConsumerName, handler))
 }
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS outbox_messages_pending_idx;
+DROP TABLE IF EXISTS outbox_messages;
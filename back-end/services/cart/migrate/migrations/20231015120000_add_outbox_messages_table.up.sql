@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id uuid NOT NULL,
+    subject varchar NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamptz NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    last_error text NULL,
+    next_attempt_at timestamptz NOT NULL,
+    published_at timestamptz NULL,
+    CONSTRAINT outbox_messages_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS outbox_messages_pending_idx
+    ON outbox_messages (next_attempt_at, created_at)
+    WHERE published_at IS NULL;
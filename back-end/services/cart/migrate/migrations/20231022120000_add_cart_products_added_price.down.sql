@@ -0,0 +1 @@
+ALTER TABLE cart_products DROP COLUMN IF EXISTS added_price;
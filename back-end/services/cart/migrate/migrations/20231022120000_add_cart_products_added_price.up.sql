@@ -0,0 +1,6 @@
+ALTER TABLE cart_products ADD COLUMN IF NOT EXISTS added_price numeric NULL;
+
+UPDATE cart_products
+SET added_price = products.price
+FROM products
+WHERE products.id = cart_products.product_id AND cart_products.added_price IS NULL;
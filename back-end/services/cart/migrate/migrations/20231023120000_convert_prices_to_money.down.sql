@@ -0,0 +1,11 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price numeric NULL;
+ALTER TABLE cart_products ADD COLUMN IF NOT EXISTS added_price numeric NULL;
+
+UPDATE products SET price = price_amount / 100.0 WHERE price IS NULL;
+UPDATE cart_products SET added_price = added_price_amount / 100.0 WHERE added_price IS NULL;
+
+ALTER TABLE products ALTER COLUMN price SET NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE products DROP COLUMN IF EXISTS price_amount;
+ALTER TABLE cart_products DROP COLUMN IF EXISTS added_price_currency;
+ALTER TABLE cart_products DROP COLUMN IF EXISTS added_price_amount;
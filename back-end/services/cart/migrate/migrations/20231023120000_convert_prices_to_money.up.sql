@@ -0,0 +1,16 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_amount bigint NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_currency char(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE cart_products ADD COLUMN IF NOT EXISTS added_price_amount bigint NULL;
+ALTER TABLE cart_products ADD COLUMN IF NOT EXISTS added_price_currency char(3) NULL;
+
+-- prices were stored as decimal USD amounts
+UPDATE products SET price_amount = ROUND(price * 100) WHERE price_amount IS NULL;
+
+UPDATE cart_products
+SET added_price_amount = ROUND(added_price * 100), added_price_currency = 'USD'
+WHERE added_price IS NOT NULL AND added_price_amount IS NULL;
+
+ALTER TABLE products ALTER COLUMN price_amount SET NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS price;
+ALTER TABLE cart_products DROP COLUMN IF EXISTS added_price;
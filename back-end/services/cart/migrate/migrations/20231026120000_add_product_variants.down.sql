@@ -0,0 +1,6 @@
+ALTER TABLE cart_products DROP CONSTRAINT IF EXISTS cart_products_pk;
+DELETE FROM cart_products WHERE variant_id <> '';
+ALTER TABLE cart_products DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE cart_products ADD CONSTRAINT cart_products_pk PRIMARY KEY (customer_id, product_id);
+
+DROP TABLE IF EXISTS product_variants;
@@ -2,12 +2,12 @@ package main
 
 import (
 	"os"
-	"os/signal"
-	"syscall"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"catalog/config"
+	"shared/lifecycle"
 )
 
 func run() {
@@ -16,26 +16,20 @@ func run() {
 		log.Fatal().Err(err).Msg("config.NewConfig")
 	}
 
-	httpServer, err := buildDependencies()
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	logger := zerolog.New(os.Stdout)
+	runner := lifecycle.NewRunner(logger)
 
+	orderMessageHandlers, err := buildDependencies(runner, logger)
 	if err != nil {
 		log.Panic().Err(err).Msg("c.Invoke")
 	}
-	// Waiting signal
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
-
-	select {
-	case s := <-interrupt:
-		log.Info().Msg("app - Run - signal: " + s.String())
-	case err := <-httpServer.Notify():
-		log.Error().Err(err).Msg("app - Run - httpServer.Notify")
-	}
 
-	// Shutdown
-	err = httpServer.Shutdown()
+	orderMessageHandlers.Init()
+
+	err = runner.Run()
 	if err != nil {
-		log.Error().Err(err).Msg("app - Run - httpServer.Shutdown")
+		log.Error().Err(err).Msg("app - Run - runner.Run")
 	}
 
 }
@@ -1,35 +1,163 @@
 package main
 
 import (
-	"os"
+	"context"
+	"errors"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
 	"catalog/config"
+	"shared/authz"
+	"shared/health"
+	inboxStore "shared/inbox/pg"
+	"shared/lifecycle"
 	nats "shared/messaging/nats"
+	"shared/money"
+	"shared/outbox"
+	outboxStore "shared/outbox/pg"
+	"shared/storage/blob"
 	pgStorage "shared/storage/pg"
 
 	applicationServices "catalog/internal/services"
 
+	attributeRepository "catalog/internal/repositories/attribute/pg"
+	categoryRepository "catalog/internal/repositories/category/pg"
+	exchangeRateRepository "catalog/internal/repositories/exchangerate/pg"
 	repository "catalog/internal/repositories/product/pg"
+	reservationRepository "catalog/internal/repositories/reservation/pg"
 	httpServ "catalog/internal/transport/http"
-	"catalog/pkg/httpserver"
+	"catalog/internal/transport/jobs"
+	messaging "catalog/internal/transport/messaging"
 )
 
-func buildDependencies() (*httpserver.Server, error) {
+const (
+	productStream              = "products"
+	productStreamSubjects      = "products.*"
+	inventoryStream            = "inventory"
+	inventoryStreamSubjects    = "inventory.*"
+	exchangeRateStream         = "exchange_rates"
+	exchangeRateStreamSubjects = "exchange_rates.*"
+)
+
+const maxConsumerLag = 1000
+
+func buildDependencies(runner *lifecycle.Runner, logger zerolog.Logger) (messaging.OrderMessagingHandlers, error) {
 	conf, err := config.NewConfig()
 	if err != nil {
 		return nil, err
 	}
+	checks := health.NewHealth(runner)
 
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	logger := zerolog.New(os.Stdout)
 	pgConn := pgStorage.NewClient(logger, pgStorage.Config{DSN: conf.PgDSN})
+	runner.OnShutdown("postgres", lifecycle.Closer(pgConn))
+	checks.AddReadinessCheck("postgres", health.Ping(pgConn))
 	natsClient := nats.NewNatsClient()
+	runner.OnShutdown("nats", natsClient.Drain)
+	checks.AddReadinessCheck("nats", health.Connection(natsClient))
+	checks.AddDegradedCheck("consumer lag", health.ConsumerLag(natsClient, maxConsumerLag))
+	err = natsClient.CreateStream(productStream, productStreamSubjects)
+	if err != nil {
+		return nil, err
+	}
+	err = natsClient.CreateStream(inventoryStream, inventoryStreamSubjects)
+	if err != nil {
+		return nil, err
+	}
+	err = natsClient.CreateStream(exchangeRateStream, exchangeRateStreamSubjects)
+	if err != nil {
+		return nil, err
+	}
+	baseCurrency, err := money.ParseCurrency(conf.Pricing.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	transactor := pgStorage.NewTransactor(pgConn)
+	inbox := inboxStore.NewInbox(pgConn, transactor)
+	inboxCleaner := inboxStore.NewCleaner(pgConn, logger)
+	runner.Go("inbox cleaner", func(ctx context.Context) error {
+		inboxCleaner.Run(ctx)
+		return nil
+	})
+	outboxRepo := outboxStore.NewStore(pgConn)
+	relay := outbox.NewRelay(outboxRepo, natsClient, logger)
+	runner.Go("outbox relay", func(ctx context.Context) error {
+		relay.Run(ctx)
+		return nil
+	})
+
 	productRepo := repository.NewProductRepository(pgConn, logger)
-	productAppService := applicationServices.NewProductApplicationService(productRepo, logger, natsClient)
-	server := httpServ.NewHTTPServer(productAppService, gin.New(), logger, conf)
+	reservationRepo := reservationRepository.NewReservationRepository(pgConn, logger)
+	categoryRepo := categoryRepository.NewCategoryRepository(pgConn, logger)
+	attributeRepo := attributeRepository.NewAttributeRepository(pgConn, logger)
+	mediaStore, err := newMediaStore(conf.Media)
+	if err != nil {
+		return nil, err
+	}
+	productAppService := applicationServices.NewProductApplicationService(
+		productRepo,
+		categoryRepo,
+		attributeRepo,
+		logger,
+		transactor,
+		outboxRepo,
+		mediaStore,
+		conf.Media.ThumbnailSize,
+	)
+	categoryAppService := applicationServices.NewCategoryApplicationService(categoryRepo, logger, transactor)
+	attributeAppService := applicationServices.NewAttributeApplicationService(attributeRepo, logger, transactor)
+	inventoryAppService := applicationServices.NewInventoryApplicationService(
+		productRepo,
+		reservationRepo,
+		logger,
+		transactor,
+		outboxRepo,
+		conf.Inventory.ReservationTTL,
+	)
+	exchangeRateRepo := exchangeRateRepository.NewExchangeRateRepository(pgConn, logger)
+	exchangeRateAppService := applicationServices.NewExchangeRateApplicationService(
+		exchangeRateRepo,
+		logger,
+		transactor,
+		outboxRepo,
+		baseCurrency,
+	)
+	reservationExpiryJob := jobs.NewReservationExpiryJob(inventoryAppService, logger, conf.Inventory.SweepInterval)
+	runner.Go("reservation expiry", reservationExpiryJob.Run)
+
+	orderMessageHandlers := messaging.NewOrderMessagingHandlers(natsClient, inbox, inventoryAppService, logger)
+	verifier := authz.NewJWKSVerifier(conf.Identity.JWKSURL, conf.Identity.Issuer, &http.Client{Timeout: 5 * time.Second})
+	server := httpServ.NewHTTPServer(
+		productAppService,
+		exchangeRateAppService,
+		categoryAppService,
+		attributeAppService,
+		gin.New(),
+		logger,
+		conf,
+		checks,
+		verifier,
+	)
+	runner.AddServer("http server", server)
 
-	return server, nil
+	return orderMessageHandlers, nil
+}
+
+func newMediaStore(conf config.Media) (blob.Store, error) {
+	if conf.Driver == "s3" {
+		if conf.S3.Endpoint == "" || conf.S3.Bucket == "" {
+			return nil, errors.New("media: s3 endpoint and bucket are required")
+		}
+		return blob.NewS3Store(blob.S3Config{
+			Endpoint:        conf.S3.Endpoint,
+			Region:          conf.S3.Region,
+			Bucket:          conf.S3.Bucket,
+			AccessKeyID:     conf.S3.AccessKeyID,
+			SecretAccessKey: conf.S3.SecretAccessKey,
+			PublicURL:       conf.PublicURL,
+		}, &http.Client{Timeout: 30 * time.Second}), nil
+	}
+	return blob.NewLocalStore(conf.LocalDir, conf.PublicURL)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
@@ -12,11 +13,15 @@ import (
 
 type (
 	Config struct {
-		App                 App    `yaml:"app" validate:"required"`
-		HTTP                HTTP   `yaml:"http" validate:"required"`
-		SwaggerUIDomain     string `yaml:"swagger_ui_domain"`
-		SwaggerEditorDomain string `yaml:"swagger_editor_domain"`
-		PgDSN               string `yaml:"pg_dsn" validate:"required"`
+		App                 App       `yaml:"app" validate:"required"`
+		HTTP                HTTP      `yaml:"http" validate:"required"`
+		SwaggerUIDomain     string    `yaml:"swagger_ui_domain"`
+		SwaggerEditorDomain string    `yaml:"swagger_editor_domain"`
+		PgDSN               string    `yaml:"pg_dsn" validate:"required"`
+		Inventory           Inventory `yaml:"inventory" validate:"required"`
+		Pricing             Pricing   `yaml:"pricing" validate:"required"`
+		Media               Media     `yaml:"media" validate:"required"`
+		Identity            Identity  `yaml:"identity" validate:"required"`
 	}
 	App struct {
 		Name    string `yaml:"name" validate:"required"`
@@ -26,6 +31,43 @@ type (
 	HTTP struct {
 		Port string `yaml:"port"  validate:"required"`
 	}
+
+	// Identity verifies the identity tokens the gateway signs
+	Identity struct {
+		JWKSURL string `yaml:"jwks_url" validate:"required,url"`
+		Issuer  string `yaml:"issuer" validate:"required"`
+	}
+
+	Inventory struct {
+		// how long stock stays reserved for an order that is neither
+		// confirmed nor cancelled
+		ReservationTTL time.Duration `yaml:"reservation_ttl" validate:"required"`
+		SweepInterval  time.Duration `yaml:"sweep_interval" validate:"required"`
+	}
+
+	Pricing struct {
+		// currency that exchange rates are quoted against
+		BaseCurrency string `yaml:"base_currency" validate:"required,len=3"`
+	}
+
+	Media struct {
+		// local keeps uploads on disk and serves them from /media, s3 stores
+		// them in an S3 compatible bucket
+		Driver        string `yaml:"driver" validate:"required,oneof=local s3"`
+		PublicURL     string `yaml:"public_url" validate:"required"`
+		MaxUploadSize int64  `yaml:"max_upload_size" validate:"required"`
+		ThumbnailSize int    `yaml:"thumbnail_size" validate:"required"`
+		LocalDir      string `yaml:"local_dir"`
+		S3            S3     `yaml:"s3"`
+	}
+
+	S3 struct {
+		Endpoint        string `yaml:"endpoint"`
+		Region          string `yaml:"region"`
+		Bucket          string `yaml:"bucket"`
+		AccessKeyID     string `yaml:"access_key_id"`
+		SecretAccessKey string `yaml:"secret_access_key"`
+	}
 )
 
 func (c Config) Validate() error {
@@ -0,0 +1,212 @@
+package attribute
+
+import (
+	"regexp"
+	customErrors "shared/errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidAttributeCode = customErrors.NewIncorrectInputError("attributes/invalid_code", "Attribute codes are lowercase letters, digits and underscores")
+var ErrInvalidAttributeName = customErrors.NewIncorrectInputError("attributes/invalid_name", "Invalid attribute name")
+var ErrInvalidAttributeType = customErrors.NewIncorrectInputError("attributes/invalid_type", "Invalid attribute type")
+var ErrInvalidAttributeOptions = customErrors.NewIncorrectInputError("attributes/invalid_options", "Only enum attributes have options and they must not be empty")
+var ErrAttributeCodeTaken = customErrors.NewIncorrectInputError("attributes/code_taken", "An attribute with this code already exists")
+var ErrAttributeInUse = customErrors.NewIncorrectInputError("attributes/in_use", "Attribute is still used by products")
+var ErrAttributeNotFound = customErrors.NewNotFoundError("attributes/not_found", "Attribute not found")
+var ErrUnknownAttribute = customErrors.NewIncorrectInputError("attributes/unknown", "Unknown attribute")
+var ErrInvalidAttributeValue = customErrors.NewIncorrectInputError("attributes/invalid_value", "Invalid attribute value")
+
+// Attribute types. Values are always stored as strings, the type decides
+// which strings are accepted.
+const (
+	TypeText    = "text"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeEnum    = "enum"
+)
+
+var codePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Attribute describes a property that products and variants can have, like
+// size, color or material. Products refer to attributes by code.
+type Attribute struct {
+	id            string
+	code          string
+	name          string
+	attributeType string
+	options       []string
+	createdAt     time.Time
+	updatedAt     time.Time
+}
+
+type CreateAttributeParams struct {
+	Code    string
+	Name    string
+	Type    string
+	Options []string
+}
+
+// The code and the type of an attribute cannot change, values of existing
+// products depend on them.
+type UpdateAttributeParams struct {
+	Name    *string
+	Options []string
+}
+
+func NewAttribute(params CreateAttributeParams) (Attribute, error) {
+	if !codePattern.MatchString(params.Code) {
+		return Attribute{}, ErrInvalidAttributeCode
+	}
+	if params.Name == "" {
+		return Attribute{}, ErrInvalidAttributeName
+	}
+	switch params.Type {
+	case TypeText, TypeNumber, TypeBoolean, TypeEnum:
+	default:
+		return Attribute{}, ErrInvalidAttributeType
+	}
+	if !validOptions(params.Type, params.Options) {
+		return Attribute{}, ErrInvalidAttributeOptions
+	}
+	now := time.Now().UTC()
+	return Attribute{
+		id:            uuid.New().String(),
+		code:          params.Code,
+		name:          params.Name,
+		attributeType: params.Type,
+		options:       params.Options,
+		createdAt:     now,
+		updatedAt:     now,
+	}, nil
+}
+
+func NewAttributeFromDatabase(
+	id string,
+	code string,
+	name string,
+	attributeType string,
+	options []string,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Attribute {
+	return Attribute{
+		id:            id,
+		code:          code,
+		name:          name,
+		attributeType: attributeType,
+		options:       options,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
+	}
+}
+
+func (a Attribute) Update(params UpdateAttributeParams) (Attribute, error) {
+	if params.Name != nil {
+		if *params.Name == "" {
+			return Attribute{}, ErrInvalidAttributeName
+		}
+		a.name = *params.Name
+	}
+	if params.Options != nil {
+		if !validOptions(a.attributeType, params.Options) {
+			return Attribute{}, ErrInvalidAttributeOptions
+		}
+		a.options = params.Options
+	}
+	a.updatedAt = time.Now().UTC()
+	return a, nil
+}
+
+// ValidateValue checks that value is a valid value of the attribute.
+func (a Attribute) ValidateValue(value string) error {
+	var valid bool
+	switch a.attributeType {
+	case TypeText:
+		valid = value != ""
+	case TypeNumber:
+		_, err := strconv.ParseFloat(value, 64)
+		valid = err == nil
+	case TypeBoolean:
+		valid = value == "true" || value == "false"
+	case TypeEnum:
+		for _, option := range a.options {
+			valid = valid || option == value
+		}
+	}
+	if !valid {
+		return ErrInvalidAttributeValue
+	}
+	return nil
+}
+
+// ValidateValues checks values, keyed by attribute code, against the known
+// attributes.
+func ValidateValues(attributes []Attribute, values map[string]string) error {
+	byCode := make(map[string]Attribute, len(attributes))
+	for _, attribute := range attributes {
+		byCode[attribute.code] = attribute
+	}
+	for code, value := range values {
+		attribute, ok := byCode[code]
+		if !ok {
+			return ErrUnknownAttribute
+		}
+		err := attribute.ValidateValue(value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validOptions(attributeType string, options []string) bool {
+	if attributeType != TypeEnum {
+		return len(options) == 0
+	}
+	if len(options) == 0 {
+		return false
+	}
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		if option == "" || seen[option] {
+			return false
+		}
+		seen[option] = true
+	}
+	return true
+}
+
+func (a Attribute) ID() string {
+	return a.id
+}
+
+func (a Attribute) Code() string {
+	return a.code
+}
+
+func (a Attribute) Name() string {
+	return a.name
+}
+
+func (a Attribute) Type() string {
+	return a.attributeType
+}
+
+func (a Attribute) Options() []string {
+	return a.options
+}
+
+func (a Attribute) CreatedAt() time.Time {
+	return a.createdAt
+}
+
+func (a Attribute) UpdatedAt() time.Time {
+	return a.updatedAt
+}
+
+func (a Attribute) IsZero() bool {
+	return a.id == ""
+}
@@ -0,0 +1,135 @@
+package attribute_test
+
+import (
+	"catalog/internal/domain/entities/attribute"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAttribute(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		args   attribute.CreateAttributeParams
+		expErr error
+	}{
+		{
+			name: "Enum_ReturnsAttribute",
+			args: attribute.CreateAttributeParams{Code: "size", Name: "Size", Type: attribute.TypeEnum, Options: []string{"S", "M", "L"}},
+		},
+		{
+			name: "Text_ReturnsAttribute",
+			args: attribute.CreateAttributeParams{Code: "material", Name: "Material", Type: attribute.TypeText},
+		},
+		{
+			name:   "InvalidCode_ReturnsError",
+			args:   attribute.CreateAttributeParams{Code: "Size", Name: "Size", Type: attribute.TypeText},
+			expErr: attribute.ErrInvalidAttributeCode,
+		},
+		{
+			name:   "UnknownType_ReturnsError",
+			args:   attribute.CreateAttributeParams{Code: "size", Name: "Size", Type: "date"},
+			expErr: attribute.ErrInvalidAttributeType,
+		},
+		{
+			name:   "EnumWithoutOptions_ReturnsError",
+			args:   attribute.CreateAttributeParams{Code: "size", Name: "Size", Type: attribute.TypeEnum},
+			expErr: attribute.ErrInvalidAttributeOptions,
+		},
+		{
+			name:   "DuplicateOptions_ReturnsError",
+			args:   attribute.CreateAttributeParams{Code: "size", Name: "Size", Type: attribute.TypeEnum, Options: []string{"S", "S"}},
+			expErr: attribute.ErrInvalidAttributeOptions,
+		},
+		{
+			name:   "OptionsOfText_ReturnsError",
+			args:   attribute.CreateAttributeParams{Code: "material", Name: "Material", Type: attribute.TypeText, Options: []string{"cotton"}},
+			expErr: attribute.ErrInvalidAttributeOptions,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a, err := attribute.NewAttribute(tc.args)
+			if tc.expErr != nil {
+				assert.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, a.ID())
+			assert.Equal(t, tc.args.Code, a.Code())
+			assert.Equal(t, tc.args.Type, a.Type())
+			assert.Equal(t, tc.args.Options, a.Options())
+		})
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	t.Parallel()
+	attributes := []attribute.Attribute{
+		mustAttribute(t, attribute.CreateAttributeParams{Code: "size", Name: "Size", Type: attribute.TypeEnum, Options: []string{"S", "M"}}),
+		mustAttribute(t, attribute.CreateAttributeParams{Code: "weight", Name: "Weight", Type: attribute.TypeNumber}),
+		mustAttribute(t, attribute.CreateAttributeParams{Code: "organic", Name: "Organic", Type: attribute.TypeBoolean}),
+		mustAttribute(t, attribute.CreateAttributeParams{Code: "material", Name: "Material", Type: attribute.TypeText}),
+	}
+	testCases := []struct {
+		name   string
+		values map[string]string
+		expErr error
+	}{
+		{
+			name:   "ValidValues_ReturnsNil",
+			values: map[string]string{"size": "M", "weight": "0.25", "organic": "true", "material": "cotton"},
+		},
+		{
+			name: "NoValues_ReturnsNil",
+		},
+		{
+			name:   "UnknownCode_ReturnsError",
+			values: map[string]string{"color": "red"},
+			expErr: attribute.ErrUnknownAttribute,
+		},
+		{
+			name:   "UnknownOption_ReturnsError",
+			values: map[string]string{"size": "XL"},
+			expErr: attribute.ErrInvalidAttributeValue,
+		},
+		{
+			name:   "NotANumber_ReturnsError",
+			values: map[string]string{"weight": "heavy"},
+			expErr: attribute.ErrInvalidAttributeValue,
+		},
+		{
+			name:   "NotABoolean_ReturnsError",
+			values: map[string]string{"organic": "yes"},
+			expErr: attribute.ErrInvalidAttributeValue,
+		},
+		{
+			name:   "EmptyText_ReturnsError",
+			values: map[string]string{"material": ""},
+			expErr: attribute.ErrInvalidAttributeValue,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := attribute.ValidateValues(attributes, tc.values)
+			if tc.expErr != nil {
+				assert.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func mustAttribute(t *testing.T, params attribute.CreateAttributeParams) attribute.Attribute {
+	t.Helper()
+	a, err := attribute.NewAttribute(params)
+	require.NoError(t, err)
+	return a
+}
@@ -0,0 +1,177 @@
+package category
+
+import (
+	customErrors "shared/errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCategoryName = customErrors.NewIncorrectInputError("categories/invalid_name", "Invalid category name")
+var ErrInvalidParent = customErrors.NewIncorrectInputError("categories/invalid_parent", "A category cannot be moved under itself or its subcategories")
+var ErrParentNotFound = customErrors.NewNotFoundError("categories/parent_not_found", "Parent category not found")
+var ErrCategoryNotFound = customErrors.NewNotFoundError("categories/not_found", "Category not found")
+var ErrCategoryNotEmpty = customErrors.NewIncorrectInputError("categories/not_empty", "Category still has subcategories or products")
+
+// Category is a node of the category tree. Root categories have no parent.
+type Category struct {
+	id        string
+	name      string
+	parentID  string
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+type CreateCategoryParams struct {
+	Name     string
+	ParentID string
+}
+
+// UpdateCategoryParams moves the category to the root when ParentID points
+// to an empty string.
+type UpdateCategoryParams struct {
+	Name     *string
+	ParentID *string
+}
+
+// NewCategory creates a category under one of categories, the existing
+// categories of the tree.
+func NewCategory(params CreateCategoryParams, categories []Category) (Category, error) {
+	if params.Name == "" {
+		return Category{}, ErrInvalidCategoryName
+	}
+	if params.ParentID != "" && !contains(categories, params.ParentID) {
+		return Category{}, ErrParentNotFound
+	}
+	now := time.Now().UTC()
+	return Category{
+		id:        uuid.New().String(),
+		name:      params.Name,
+		parentID:  params.ParentID,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+func NewCategoryFromDatabase(id, name, parentID string, createdAt time.Time, updatedAt time.Time) Category {
+	return Category{
+		id:        id,
+		name:      name,
+		parentID:  parentID,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// Update renames or moves the category. categories is the whole tree, it is
+// needed to reject moves that would create a cycle.
+func (c Category) Update(params UpdateCategoryParams, categories []Category) (Category, error) {
+	if params.Name != nil {
+		if *params.Name == "" {
+			return Category{}, ErrInvalidCategoryName
+		}
+		c.name = *params.Name
+	}
+	if params.ParentID != nil {
+		parentID := *params.ParentID
+		if parentID != "" {
+			if !contains(categories, parentID) {
+				return Category{}, ErrParentNotFound
+			}
+			if parentID == c.id || isDescendant(categories, parentID, c.id) {
+				return Category{}, ErrInvalidParent
+			}
+		}
+		c.parentID = parentID
+	}
+	c.updatedAt = time.Now().UTC()
+	return c, nil
+}
+
+func (c Category) ID() string {
+	return c.id
+}
+
+func (c Category) Name() string {
+	return c.name
+}
+
+func (c Category) ParentID() string {
+	return c.parentID
+}
+
+func (c Category) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+func (c Category) UpdatedAt() time.Time {
+	return c.updatedAt
+}
+
+func (c Category) IsZero() bool {
+	return c == Category{}
+}
+
+// Node is a category with its subcategories.
+type Node struct {
+	Category Category
+	Children []Node
+}
+
+// BuildTree arranges categories into trees ordered by name. Categories whose
+// parent is missing are treated as roots.
+func BuildTree(categories []Category) []Node {
+	children := make(map[string][]Category, len(categories))
+	for _, category := range categories {
+		parentID := category.parentID
+		if parentID != "" && !contains(categories, parentID) {
+			parentID = ""
+		}
+		children[parentID] = append(children[parentID], category)
+	}
+	var build func(parentID string) []Node
+	build = func(parentID string) []Node {
+		level := children[parentID]
+		sort.Slice(level, func(a, b int) bool {
+			return level[a].name < level[b].name
+		})
+		nodes := make([]Node, 0, len(level))
+		for _, category := range level {
+			nodes = append(nodes, Node{Category: category, Children: build(category.id)})
+		}
+		return nodes
+	}
+	return build("")
+}
+
+// Subtree returns the ID of the category and of all its descendants.
+func Subtree(categories []Category, id string) []string {
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		for _, category := range categories {
+			if category.parentID == ids[i] {
+				ids = append(ids, category.id)
+			}
+		}
+	}
+	return ids
+}
+
+func isDescendant(categories []Category, id string, ancestorID string) bool {
+	for _, descendantID := range Subtree(categories, ancestorID)[1:] {
+		if descendantID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(categories []Category, id string) bool {
+	for _, category := range categories {
+		if category.id == id {
+			return true
+		}
+	}
+	return false
+}
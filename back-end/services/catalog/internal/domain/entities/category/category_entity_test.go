@@ -0,0 +1,149 @@
+package category_test
+
+import (
+	"catalog/internal/domain/entities/category"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clothing
+// ├── men
+// │   └── shirts
+// └── women
+// toys
+func testTree() []category.Category {
+	now := time.Now()
+	return []category.Category{
+		category.NewCategoryFromDatabase("clothing", "Clothing", "", now, now),
+		category.NewCategoryFromDatabase("women", "Women", "clothing", now, now),
+		category.NewCategoryFromDatabase("men", "Men", "clothing", now, now),
+		category.NewCategoryFromDatabase("shirts", "Shirts", "men", now, now),
+		category.NewCategoryFromDatabase("toys", "Toys", "", now, now),
+	}
+}
+
+func TestNewCategory(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		args   category.CreateCategoryParams
+		expErr error
+	}{
+		{
+			name: "Root_ReturnsCategory",
+			args: category.CreateCategoryParams{Name: "Books"},
+		},
+		{
+			name: "KnownParent_ReturnsCategory",
+			args: category.CreateCategoryParams{Name: "Socks", ParentID: "men"},
+		},
+		{
+			name:   "EmptyName_ReturnsError",
+			args:   category.CreateCategoryParams{ParentID: "men"},
+			expErr: category.ErrInvalidCategoryName,
+		},
+		{
+			name:   "UnknownParent_ReturnsError",
+			args:   category.CreateCategoryParams{Name: "Socks", ParentID: "shoes"},
+			expErr: category.ErrParentNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c, err := category.NewCategory(tc.args, testTree())
+			if tc.expErr != nil {
+				assert.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, c.ID())
+			assert.Equal(t, tc.args.Name, c.Name())
+			assert.Equal(t, tc.args.ParentID, c.ParentID())
+		})
+	}
+}
+
+func TestCategory_Update(t *testing.T) {
+	t.Parallel()
+	pointer := func(s string) *string { return &s }
+	testCases := []struct {
+		name       string
+		args       category.UpdateCategoryParams
+		wantParent string
+		expErr     error
+	}{
+		{
+			name:       "MoveUnderSibling_ReturnsCategory",
+			args:       category.UpdateCategoryParams{ParentID: pointer("women")},
+			wantParent: "women",
+		},
+		{
+			name:       "MoveToRoot_ReturnsCategory",
+			args:       category.UpdateCategoryParams{ParentID: pointer("")},
+			wantParent: "",
+		},
+		{
+			name:   "MoveUnderItself_ReturnsError",
+			args:   category.UpdateCategoryParams{ParentID: pointer("men")},
+			expErr: category.ErrInvalidParent,
+		},
+		{
+			name:   "MoveUnderDescendant_ReturnsError",
+			args:   category.UpdateCategoryParams{ParentID: pointer("shirts")},
+			expErr: category.ErrInvalidParent,
+		},
+		{
+			name:   "MoveUnderUnknown_ReturnsError",
+			args:   category.UpdateCategoryParams{ParentID: pointer("shoes")},
+			expErr: category.ErrParentNotFound,
+		},
+		{
+			name:   "EmptyName_ReturnsError",
+			args:   category.UpdateCategoryParams{Name: pointer("")},
+			expErr: category.ErrInvalidCategoryName,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tree := testTree()
+			men := tree[2]
+
+			updated, err := men.Update(tc.args, tree)
+			if tc.expErr != nil {
+				assert.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantParent, updated.ParentID())
+		})
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	t.Parallel()
+
+	roots := category.BuildTree(testTree())
+
+	require.Len(t, roots, 2)
+	assert.Equal(t, "clothing", roots[0].Category.ID())
+	assert.Equal(t, "toys", roots[1].Category.ID())
+	require.Len(t, roots[0].Children, 2)
+	assert.Equal(t, "men", roots[0].Children[0].Category.ID())
+	assert.Equal(t, "women", roots[0].Children[1].Category.ID())
+	require.Len(t, roots[0].Children[0].Children, 1)
+	assert.Equal(t, "shirts", roots[0].Children[0].Children[0].Category.ID())
+}
+
+func TestSubtree(t *testing.T) {
+	t.Parallel()
+
+	assert.ElementsMatch(t, []string{"clothing", "men", "women", "shirts"}, category.Subtree(testTree(), "clothing"))
+	assert.Equal(t, []string{"toys"}, category.Subtree(testTree(), "toys"))
+}
@@ -0,0 +1,68 @@
+package exchangerate
+
+import (
+	customErrors "shared/errors"
+	"shared/money"
+	"time"
+)
+
+var ErrInvalidCurrency = customErrors.NewIncorrectInputError("exchange_rates/invalid_currency", "Invalid currency")
+var ErrBaseCurrency = customErrors.NewIncorrectInputError("exchange_rates/base_currency", "The rate of the base currency is always 1")
+var ErrInvalidRate = customErrors.NewIncorrectInputError("exchange_rates/invalid_rate", "Invalid exchange rate")
+var ErrExchangeRateNotFound = customErrors.NewNotFoundError("exchange_rates/not_found", "Exchange rate not found")
+
+// ExchangeRate is the value of one unit of the base currency in currency.
+type ExchangeRate struct {
+	currency  money.Currency
+	rate      money.Rate
+	updatedAt time.Time
+}
+
+type CreateExchangeRateParams struct {
+	BaseCurrency money.Currency
+	Currency     string
+	Rate         string
+}
+
+func NewExchangeRate(params CreateExchangeRateParams) (ExchangeRate, error) {
+	currency, err := money.ParseCurrency(params.Currency)
+	if err != nil {
+		return ExchangeRate{}, ErrInvalidCurrency
+	}
+	if currency == params.BaseCurrency {
+		return ExchangeRate{}, ErrBaseCurrency
+	}
+	rate, err := money.ParseRate(params.Rate)
+	if err != nil {
+		return ExchangeRate{}, ErrInvalidRate
+	}
+	return ExchangeRate{
+		currency:  currency,
+		rate:      rate,
+		updatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func NewExchangeRateFromDatabase(currency money.Currency, rate money.Rate, updatedAt time.Time) ExchangeRate {
+	return ExchangeRate{
+		currency:  currency,
+		rate:      rate,
+		updatedAt: updatedAt,
+	}
+}
+
+func (e ExchangeRate) Currency() money.Currency {
+	return e.currency
+}
+
+func (e ExchangeRate) Rate() money.Rate {
+	return e.rate
+}
+
+func (e ExchangeRate) UpdatedAt() time.Time {
+	return e.updatedAt
+}
+
+func (e ExchangeRate) IsZero() bool {
+	return e == ExchangeRate{}
+}
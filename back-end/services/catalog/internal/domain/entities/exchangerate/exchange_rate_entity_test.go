@@ -0,0 +1,59 @@
+package exchangerate_test
+
+import (
+	"catalog/internal/domain/entities/exchangerate"
+	"shared/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExchangeRate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name         string
+		args         exchangerate.CreateExchangeRateParams
+		wantCurrency money.Currency
+		wantRate     string
+		expErr       error
+	}{
+		{
+			name:         "ValidParams_ReturnsRate",
+			args:         exchangerate.CreateExchangeRateParams{BaseCurrency: money.USD, Currency: "eur", Rate: "0.9200"},
+			wantCurrency: money.EUR,
+			wantRate:     "0.92",
+		},
+		{
+			name:   "UnknownCurrency_ReturnsError",
+			args:   exchangerate.CreateExchangeRateParams{BaseCurrency: money.USD, Currency: "XYZ", Rate: "1"},
+			expErr: exchangerate.ErrInvalidCurrency,
+		},
+		{
+			name:   "BaseCurrency_ReturnsError",
+			args:   exchangerate.CreateExchangeRateParams{BaseCurrency: money.USD, Currency: "USD", Rate: "1"},
+			expErr: exchangerate.ErrBaseCurrency,
+		},
+		{
+			name:   "ZeroRate_ReturnsError",
+			args:   exchangerate.CreateExchangeRateParams{BaseCurrency: money.USD, Currency: "EUR", Rate: "0"},
+			expErr: exchangerate.ErrInvalidRate,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rate, err := exchangerate.NewExchangeRate(tc.args)
+
+			require.Equal(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+			assert.Equal(t, tc.wantCurrency, rate.Currency())
+			assert.Equal(t, tc.wantRate, rate.Rate().String())
+			assert.False(t, rate.UpdatedAt().IsZero())
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package product
+
+import (
+	customErrors "shared/errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnsupportedImageType = customErrors.NewIncorrectInputError("products/unsupported_image_type", "Images must be JPEG, PNG or GIF")
+var ErrInvalidImage = customErrors.NewIncorrectInputError("products/invalid_image", "The file is not a valid image")
+var ErrImageTooLarge = customErrors.NewIncorrectInputError("products/image_too_large", "The image is too large")
+var ErrImageNotFound = customErrors.NewNotFoundError("products/image_not_found", "Image not found")
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Image is an uploaded picture of a product. The original and its thumbnail
+// live in the blob store under Key and ThumbnailKey.
+type Image struct {
+	id           string
+	productID    string
+	key          string
+	url          string
+	thumbnailKey string
+	thumbnailURL string
+	contentType  string
+	width        int
+	height       int
+	size         int64
+	createdAt    time.Time
+}
+
+type CreateImageParams struct {
+	ProductID   string
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+	// content type of the rendered thumbnail
+	ThumbnailContentType string
+}
+
+func NewImage(params CreateImageParams) (Image, error) {
+	extension, ok := imageExtensions[params.ContentType]
+	if !ok {
+		return Image{}, ErrUnsupportedImageType
+	}
+	thumbnailExtension, ok := imageExtensions[params.ThumbnailContentType]
+	if !ok {
+		return Image{}, ErrUnsupportedImageType
+	}
+	if params.Width <= 0 || params.Height <= 0 {
+		return Image{}, ErrInvalidImage
+	}
+	id := uuid.New().String()
+	prefix := "products/" + params.ProductID + "/" + id
+	return Image{
+		id:           id,
+		productID:    params.ProductID,
+		key:          prefix + extension,
+		thumbnailKey: prefix + "_thumb" + thumbnailExtension,
+		contentType:  params.ContentType,
+		width:        params.Width,
+		height:       params.Height,
+		size:         params.Size,
+		createdAt:    time.Now().UTC(),
+	}, nil
+}
+
+func NewImageFromDatabase(
+	id, productID, key, url, thumbnailKey, thumbnailURL, contentType string,
+	width, height int,
+	size int64,
+	createdAt time.Time,
+) Image {
+	return Image{
+		id:           id,
+		productID:    productID,
+		key:          key,
+		url:          url,
+		thumbnailKey: thumbnailKey,
+		thumbnailURL: thumbnailURL,
+		contentType:  contentType,
+		width:        width,
+		height:       height,
+		size:         size,
+		createdAt:    createdAt,
+	}
+}
+
+// WithURLs sets the URLs the blob store serves the image and thumbnail from.
+func (i Image) WithURLs(url, thumbnailURL string) Image {
+	i.url = url
+	i.thumbnailURL = thumbnailURL
+	return i
+}
+
+func (i Image) ID() string {
+	return i.id
+}
+
+func (i Image) ProductID() string {
+	return i.productID
+}
+
+func (i Image) Key() string {
+	return i.key
+}
+
+func (i Image) URL() string {
+	return i.url
+}
+
+func (i Image) ThumbnailKey() string {
+	return i.thumbnailKey
+}
+
+func (i Image) ThumbnailURL() string {
+	return i.thumbnailURL
+}
+
+func (i Image) ContentType() string {
+	return i.contentType
+}
+
+func (i Image) Width() int {
+	return i.width
+}
+
+func (i Image) Height() int {
+	return i.height
+}
+
+func (i Image) Size() int64 {
+	return i.size
+}
+
+func (i Image) CreatedAt() time.Time {
+	return i.createdAt
+}
+
+func (i Image) IsZero() bool {
+	return i.id == ""
+}
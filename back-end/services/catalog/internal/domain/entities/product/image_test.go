@@ -0,0 +1,88 @@
+package product_test
+
+import (
+	"catalog/internal/domain/entities/product"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewImage(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name             string
+		args             product.CreateImageParams
+		wantExtension    string
+		wantThumbnailExt string
+		expErr           error
+	}{
+		{
+			name: "Jpeg_ReturnsImage",
+			args: product.CreateImageParams{
+				ProductID:            "123",
+				ContentType:          "image/jpeg",
+				ThumbnailContentType: "image/jpeg",
+				Width:                800,
+				Height:               600,
+				Size:                 1024,
+			},
+			wantExtension:    ".jpg",
+			wantThumbnailExt: "_thumb.jpg",
+		},
+		{
+			name: "Gif_ReturnsImageWithPngThumbnail",
+			args: product.CreateImageParams{
+				ProductID:            "123",
+				ContentType:          "image/gif",
+				ThumbnailContentType: "image/png",
+				Width:                10,
+				Height:               10,
+			},
+			wantExtension:    ".gif",
+			wantThumbnailExt: "_thumb.png",
+		},
+		{
+			name: "Webp_ReturnsError",
+			args: product.CreateImageParams{
+				ProductID:            "123",
+				ContentType:          "image/webp",
+				ThumbnailContentType: "image/png",
+				Width:                10,
+				Height:               10,
+			},
+			expErr: product.ErrUnsupportedImageType,
+		},
+		{
+			name: "NoPixels_ReturnsError",
+			args: product.CreateImageParams{
+				ProductID:            "123",
+				ContentType:          "image/png",
+				ThumbnailContentType: "image/png",
+			},
+			expErr: product.ErrInvalidImage,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			image, err := product.NewImage(tc.args)
+
+			require.Equal(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+			assert.True(t, strings.HasPrefix(image.Key(), "products/123/"+image.ID()))
+			assert.True(t, strings.HasSuffix(image.Key(), tc.wantExtension))
+			assert.Equal(t, strings.TrimSuffix(image.Key(), tc.wantExtension)+tc.wantThumbnailExt, image.ThumbnailKey())
+			assert.Equal(t, "", image.URL())
+
+			image = image.WithURLs("http://media/original", "http://media/thumbnail")
+			assert.Equal(t, "http://media/original", image.URL())
+			assert.Equal(t, "http://media/thumbnail", image.ThumbnailURL())
+		})
+	}
+}
@@ -2,66 +2,114 @@ package product
 
 import (
 	customErrors "shared/errors"
+	"shared/money"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var ErrProductNotFound = customErrors.NewNotFoundError("products/not_found", "product not found")
 var ErrInvalidProductName = customErrors.NewIncorrectInputError("products/invalid_name", "Invalid product name")
 var ErrInvalidProductPrice = customErrors.NewIncorrectInputError("products/invalid_price", "Invalid product name")
 var ErrInvalidProductQuantity = customErrors.NewIncorrectInputError("products/invalid_quantity", "Invalid product quantity")
+var ErrInsufficientStock = customErrors.NewIncorrectInputError("products/insufficient_stock", "Not enough products in stock")
+var ErrSellerRequired = customErrors.NewIncorrectInputError("products/seller_required", "Products must belong to a seller")
+var ErrProductForbidden = customErrors.NewForbiddenError("products/forbidden", "Only the seller of the product can change it")
 
+// Product is sold as is or, when it has variants, through its variants.
+// Attributes are keyed by attribute code. Products created before sellers
+// existed have no seller and only admins can change them.
 type Product struct {
-	id        string
-	name      string
-	price     float64
-	quantity  int
-	createdAt time.Time
-	updatedAt time.Time
+	id          string
+	sellerID    string
+	name        string
+	description string
+	categoryID  string
+	attributes  map[string]string
+	price       money.Money
+	quantity    int
+	variants    []Variant
+	images      []Image
+	createdAt   time.Time
+	updatedAt   time.Time
 }
 
 type CreateProductParams struct {
-	Name     string
-	Price    float64
-	Quantity int
+	SellerID    string
+	Name        string
+	Description string
+	CategoryID  string
+	Attributes  map[string]string
+	Price       money.Money
+	Quantity    int
 }
 
+// UpdateProductParams removes the product from its category when CategoryID
+// points to an empty string. Nil Attributes leave the attributes unchanged.
 type UpdateProductParams struct {
-	Name     *string
-	Price    *float64
-	Quantity *int
+	Name        *string
+	Description *string
+	CategoryID  *string
+	Attributes  map[string]string
+	Price       *money.Money
+	Quantity    *int
+}
+
+// Actor is the user changing a product.
+type Actor struct {
+	UserID  string
+	IsAdmin bool
 }
 
 func NewProduct(createProductParams CreateProductParams) (Product, error) {
 	id := uuid.New().String()
 
+	if createProductParams.SellerID == "" {
+		return Product{}, ErrSellerRequired
+	}
+
 	if createProductParams.Name == "" {
 		return Product{}, ErrInvalidProductName
 	}
 
-	if createProductParams.Price == 0 {
+	if !isValidPrice(createProductParams.Price) {
 		return Product{}, ErrInvalidProductPrice
 	}
 
 	product := Product{
-		id:        id,
-		name:      createProductParams.Name,
-		price:     createProductParams.Price,
-		quantity:  createProductParams.Quantity,
-		createdAt: time.Now(),
+		id:          id,
+		sellerID:    createProductParams.SellerID,
+		name:        createProductParams.Name,
+		description: createProductParams.Description,
+		categoryID:  createProductParams.CategoryID,
+		attributes:  createProductParams.Attributes,
+		price:       createProductParams.Price,
+		quantity:    createProductParams.Quantity,
+		createdAt:   time.Now(),
 	}
 
 	return product, nil
 }
 
-func NewProductFromDatabase(id, name string, price float64, quantity int, createdAt time.Time, updatedAt time.Time) Product {
+func NewProductFromDatabase(
+	id, sellerID, name, description, categoryID string,
+	attributes map[string]string,
+	price money.Money,
+	quantity int,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Product {
 	product := Product{
-		id:        id,
-		name:      name,
-		price:     price,
-		quantity:  quantity,
-		createdAt: createdAt,
-		updatedAt: updatedAt,
+		id:          id,
+		sellerID:    sellerID,
+		name:        name,
+		description: description,
+		categoryID:  categoryID,
+		attributes:  attributes,
+		price:       price,
+		quantity:    quantity,
+		createdAt:   createdAt,
+		updatedAt:   updatedAt,
 	}
 
 	return product
@@ -78,8 +126,23 @@ func (p Product) Update(params UpdateProductParams) (Product, error) {
 
 	}
 
+	if params.Description != nil {
+		p.description = *params.Description
+		isUpdated = true
+	}
+
+	if params.CategoryID != nil {
+		p.categoryID = *params.CategoryID
+		isUpdated = true
+	}
+
+	if params.Attributes != nil {
+		p.attributes = params.Attributes
+		isUpdated = true
+	}
+
 	if params.Price != nil {
-		if *params.Price <= 0 {
+		if !isValidPrice(*params.Price) {
 			return Product{}, ErrInvalidProductPrice
 		}
 		p.price = *params.Price
@@ -103,15 +166,79 @@ func (p Product) Update(params UpdateProductParams) (Product, error) {
 	return p, nil
 }
 
+func isValidPrice(price money.Money) bool {
+	return price.IsPositive() && price.Currency().IsValid()
+}
+
+// ReserveStock takes quantity out of the available stock.
+func (p Product) ReserveStock(quantity int) (Product, error) {
+	if quantity <= 0 {
+		return Product{}, ErrInvalidProductQuantity
+	}
+	if p.quantity < quantity {
+		return Product{}, ErrInsufficientStock
+	}
+	p.quantity -= quantity
+	p.updatedAt = time.Now()
+	return p, nil
+}
+
+// ReleaseStock puts previously reserved quantity back into stock.
+func (p Product) ReleaseStock(quantity int) Product {
+	p.quantity += quantity
+	p.updatedAt = time.Now()
+	return p
+}
+
 func (p Product) ID() string {
 	return p.id
 }
 
+func (p Product) SellerID() string {
+	return p.sellerID
+}
+
+// CanBeManagedBy reports whether the actor may change or delete the product.
+func (p Product) CanBeManagedBy(actor Actor) bool {
+	return actor.IsAdmin || p.sellerID != "" && p.sellerID == actor.UserID
+}
+
 func (p Product) Name() string {
 	return p.name
 }
 
-func (p Product) Price() float64 {
+func (p Product) Description() string {
+	return p.description
+}
+
+func (p Product) CategoryID() string {
+	return p.categoryID
+}
+
+func (p Product) Attributes() map[string]string {
+	return p.attributes
+}
+
+// Variants are only loaded when a single product is fetched.
+func (p Product) Variants() []Variant {
+	return p.variants
+}
+
+func (p Product) WithVariants(variants []Variant) Product {
+	p.variants = variants
+	return p
+}
+
+func (p Product) Images() []Image {
+	return p.images
+}
+
+func (p Product) WithImages(images []Image) Product {
+	p.images = images
+	return p
+}
+
+func (p Product) Price() money.Money {
 	return p.price
 }
 
@@ -127,5 +254,5 @@ func (p Product) UpdatedAt() time.Time {
 }
 
 func (p Product) IsZero() bool {
-	return p == Product{}
+	return p.id == ""
 }
@@ -2,6 +2,7 @@ package product_test
 
 import (
 	"catalog/internal/domain/entities/product"
+	"shared/money"
 	"testing"
 	"time"
 
@@ -13,7 +14,7 @@ func TestNewProduct(t *testing.T) {
 	t.Parallel()
 	type want struct {
 		Name      string
-		Price     float64
+		Price     money.Money
 		Quantity  int
 		CreatedAt time.Time
 		UpdatedAt time.Time
@@ -27,23 +28,35 @@ func TestNewProduct(t *testing.T) {
 		{
 			name: "ValidParams_ReturnsProduct",
 			args: product.CreateProductParams{
+				SellerID: "seller-1",
 				Name:     "Test Product",
-				Price:    9.99,
+				Price:    money.MustParse("9.99", money.USD),
 				Quantity: 10,
 			},
 			want: want{
 				Name:      "Test Product",
-				Price:     9.99,
+				Price:     money.MustParse("9.99", money.USD),
 				Quantity:  10,
 				CreatedAt: time.Now(),
 			},
 			expErr: nil,
 		},
+		{
+			name: "MissingSeller_ReturnsError",
+			args: product.CreateProductParams{
+				Name:     "Test Product",
+				Price:    money.MustParse("9.99", money.USD),
+				Quantity: 10,
+			},
+			want:   want{},
+			expErr: product.ErrSellerRequired,
+		},
 		{
 			name: "InvalidName_ReturnsError",
 			args: product.CreateProductParams{
+				SellerID: "seller-1",
 				Name:     "",
-				Price:    0,
+				Price:    money.Zero(money.USD),
 				Quantity: 10,
 			},
 			want:   want{},
@@ -52,8 +65,31 @@ func TestNewProduct(t *testing.T) {
 		{
 			name: "InvalidPrice_ReturnsError",
 			args: product.CreateProductParams{
+				SellerID: "seller-1",
+				Name:     "Test Product",
+				Price:    money.Zero(money.USD),
+				Quantity: 10,
+			},
+			want:   want{},
+			expErr: product.ErrInvalidProductPrice,
+		},
+		{
+			name: "NegativePrice_ReturnsError",
+			args: product.CreateProductParams{
+				SellerID: "seller-1",
 				Name:     "Test Product",
-				Price:    0,
+				Price:    money.MustParse("-1", money.USD),
+				Quantity: 10,
+			},
+			want:   want{},
+			expErr: product.ErrInvalidProductPrice,
+		},
+		{
+			name: "UnknownCurrency_ReturnsError",
+			args: product.CreateProductParams{
+				SellerID: "seller-1",
+				Name:     "Test Product",
+				Price:    money.New(999, "XYZ"),
 				Quantity: 10,
 			},
 			want:   want{},
@@ -80,19 +116,125 @@ func TestNewProduct(t *testing.T) {
 func TestNewProductFromDatabase(t *testing.T) {
 	t.Parallel()
 	id := "123"
+	sellerID := "seller-1"
 	name := "Test Product"
-	price := 9.99
+	description := "A product used in tests"
+	categoryID := "456"
+	attributes := map[string]string{"material": "cotton"}
+	price := money.MustParse("9.99", money.USD)
 	quantity := 10
 	createdAt := time.Now()
 	updatedAt := time.Now().Add(1 * time.Hour)
 
-	p := product.NewProductFromDatabase(id, name, price, quantity, createdAt, updatedAt)
+	p := product.NewProductFromDatabase(id, sellerID, name, description, categoryID, attributes, price, quantity, createdAt, updatedAt)
 
 	assert.Equal(t, id, p.ID())
+	assert.Equal(t, sellerID, p.SellerID())
 	assert.Equal(t, name, p.Name())
+	assert.Equal(t, description, p.Description())
+	assert.Equal(t, categoryID, p.CategoryID())
+	assert.Equal(t, attributes, p.Attributes())
 	assert.Equal(t, price, p.Price())
 	assert.Equal(t, quantity, p.Quantity())
 	assert.Equal(t, createdAt, p.CreatedAt())
 	assert.Equal(t, updatedAt, p.UpdatedAt())
 	assert.False(t, p.IsZero())
 }
+
+func TestProduct_ReserveStock(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name         string
+		stock        int
+		quantity     int
+		wantQuantity int
+		expErr       error
+	}{
+		{
+			name:         "EnoughStock_DecrementsQuantity",
+			stock:        10,
+			quantity:     3,
+			wantQuantity: 7,
+		},
+		{
+			name:         "WholeStock_LeavesZero",
+			stock:        3,
+			quantity:     3,
+			wantQuantity: 0,
+		},
+		{
+			name:     "NotEnoughStock_ReturnsError",
+			stock:    2,
+			quantity: 3,
+			expErr:   product.ErrInsufficientStock,
+		},
+		{
+			name:     "NonPositiveQuantity_ReturnsError",
+			stock:    2,
+			quantity: 0,
+			expErr:   product.ErrInvalidProductQuantity,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := product.NewProductFromDatabase("123", "seller-1", "Test Product", "", "", nil, money.MustParse("9.99", money.USD), tc.stock, time.Now(), time.Now())
+
+			p, err := p.ReserveStock(tc.quantity)
+
+			require.Equal(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuantity, p.Quantity())
+			assert.Equal(t, tc.stock, p.ReleaseStock(tc.quantity).Quantity())
+		})
+	}
+}
+
+func TestProduct_CanBeManagedBy(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		sellerID string
+		actor    product.Actor
+		want     bool
+	}{
+		{
+			name:     "Seller_CanManage",
+			sellerID: "seller-1",
+			actor:    product.Actor{UserID: "seller-1"},
+			want:     true,
+		},
+		{
+			name:     "OtherUser_CannotManage",
+			sellerID: "seller-1",
+			actor:    product.Actor{UserID: "seller-2"},
+			want:     false,
+		},
+		{
+			name:     "Admin_CanManage",
+			sellerID: "seller-1",
+			actor:    product.Actor{UserID: "admin", IsAdmin: true},
+			want:     true,
+		},
+		{
+			name:     "NoSeller_OnlyAdminCanManage",
+			sellerID: "",
+			actor:    product.Actor{UserID: ""},
+			want:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := product.NewProductFromDatabase("123", tc.sellerID, "Test Product", "", "", nil, money.MustParse("9.99", money.USD), 1, time.Now(), time.Now())
+
+			assert.Equal(t, tc.want, p.CanBeManagedBy(tc.actor))
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package product
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	customErrors "shared/errors"
+	"shared/money"
+	"time"
+)
+
+var ErrInvalidCursor = customErrors.NewIncorrectInputError("products/invalid_cursor", "Invalid cursor")
+var ErrInvalidSort = customErrors.NewIncorrectInputError("products/invalid_sort", "Invalid sort option")
+var ErrInvalidPageSize = customErrors.NewIncorrectInputError("products/invalid_limit", "Invalid page size")
+var ErrInvalidPriceRange = customErrors.NewIncorrectInputError("products/invalid_price_range", "Invalid price range")
+var ErrInvalidCreatedRange = customErrors.NewIncorrectInputError("products/invalid_created_range", "Invalid created range")
+var ErrTooManyIDs = customErrors.NewIncorrectInputError("products/too_many_ids", "Too many product IDs")
+var ErrCurrencyRequired = customErrors.NewIncorrectInputError("products/currency_required", "Sorting by price requires a currency")
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Sort options, a leading "-" sorts in descending order.
+const (
+	SortNewest    = "-created_at"
+	SortOldest    = "created_at"
+	SortPriceAsc  = "price"
+	SortPriceDesc = "-price"
+	SortNameAsc   = "name"
+	SortNameDesc  = "-name"
+	// SortRelevance orders full-text search results by rank.
+	SortRelevance = "relevance"
+)
+
+var sortOptions = map[string]bool{
+	SortNewest:    true,
+	SortOldest:    true,
+	SortPriceAsc:  true,
+	SortPriceDesc: true,
+	SortNameAsc:   true,
+	SortNameDesc:  true,
+	SortRelevance: true,
+}
+
+// Cursor points after the last product of a page. Value is the sort key of
+// that product, the ID breaks ties so that pages never overlap.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(cursor string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	err = json.Unmarshal(data, &c)
+	if err != nil || c.ID == "" || !sortOptions[c.Sort] {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// ListProductsParams filters the listing. CategoryID matches the category and
+// all of its subcategories, IDs lets clients fetch several products at once.
+// Amounts only compare within one currency, so sorting by price requires
+// Currency and lists the products priced in it only.
+type ListProductsParams struct {
+	IDs         []string
+	Search      string
+	SellerID    string
+	CategoryID  string
+	MinPrice    *money.Money
+	MaxPrice    *money.Money
+	Currency    money.Currency
+	InStock     bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Sort        string
+	Limit       int
+	Cursor      string
+}
+
+// ListProductsQuery is a validated ListProductsParams.
+type ListProductsQuery struct {
+	IDs         []string
+	Search      string
+	SellerID    string
+	CategoryID  string
+	MinPrice    *money.Money
+	MaxPrice    *money.Money
+	Currency    money.Currency
+	InStock     bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Sort        string
+	Limit       int
+	After       *Cursor
+}
+
+// SortsByPrice reports whether the products are ordered by price.
+func (q ListProductsQuery) SortsByPrice() bool {
+	return q.Sort == SortPriceAsc || q.Sort == SortPriceDesc
+}
+
+func NewListProductsQuery(params ListProductsParams) (ListProductsQuery, error) {
+	query := ListProductsQuery{
+		IDs:         params.IDs,
+		Search:      params.Search,
+		SellerID:    params.SellerID,
+		CategoryID:  params.CategoryID,
+		MinPrice:    params.MinPrice,
+		MaxPrice:    params.MaxPrice,
+		Currency:    params.Currency,
+		InStock:     params.InStock,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		Sort:        params.Sort,
+		Limit:       params.Limit,
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultPageSize
+	}
+	if query.Limit < 0 || query.Limit > MaxPageSize {
+		return ListProductsQuery{}, ErrInvalidPageSize
+	}
+
+	if len(query.IDs) > MaxPageSize {
+		return ListProductsQuery{}, ErrTooManyIDs
+	}
+
+	if query.Sort == "" {
+		query.Sort = SortNewest
+		if query.Search != "" {
+			query.Sort = SortRelevance
+		}
+	}
+	if !sortOptions[query.Sort] || query.Sort == SortRelevance && query.Search == "" {
+		return ListProductsQuery{}, ErrInvalidSort
+	}
+	if query.SortsByPrice() && query.Currency == "" {
+		return ListProductsQuery{}, ErrCurrencyRequired
+	}
+
+	if query.MinPrice != nil && query.MaxPrice != nil {
+		if query.MinPrice.Currency() != query.MaxPrice.Currency() {
+			return ListProductsQuery{}, ErrInvalidPriceRange
+		}
+		if query.MinPrice.MinorUnits() > query.MaxPrice.MinorUnits() {
+			return ListProductsQuery{}, ErrInvalidPriceRange
+		}
+	}
+	if query.CreatedFrom != nil && query.CreatedTo != nil && query.CreatedFrom.After(*query.CreatedTo) {
+		return ListProductsQuery{}, ErrInvalidCreatedRange
+	}
+
+	if params.Cursor != "" {
+		cursor, err := DecodeCursor(params.Cursor)
+		if err != nil {
+			return ListProductsQuery{}, err
+		}
+		// a cursor cannot be reused with another sort order
+		if cursor.Sort != query.Sort {
+			return ListProductsQuery{}, ErrInvalidCursor
+		}
+		query.After = &cursor
+	}
+	return query, nil
+}
+
+// Page is one page of a product listing. NextCursor is empty on the last page.
+type Page struct {
+	Products   []Product
+	Total      int
+	NextCursor string
+}
@@ -0,0 +1,127 @@
+package product_test
+
+import (
+	"catalog/internal/domain/entities/product"
+	"shared/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewListProductsQuery(t *testing.T) {
+	t.Parallel()
+	minPrice := money.MustParse("10", money.USD)
+	maxPrice := money.MustParse("5", money.USD)
+	eurPrice := money.MustParse("20", money.EUR)
+	from := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	priceCursor := product.Cursor{Sort: product.SortPriceAsc, Value: "1000", ID: "id"}.Encode()
+
+	testCases := []struct {
+		name     string
+		args     product.ListProductsParams
+		wantSort string
+		wantSize int
+		expErr   error
+	}{
+		{
+			name:     "NoParams_DefaultsToNewest",
+			args:     product.ListProductsParams{},
+			wantSort: product.SortNewest,
+			wantSize: product.DefaultPageSize,
+		},
+		{
+			name:     "Search_DefaultsToRelevance",
+			args:     product.ListProductsParams{Search: "phone", Limit: 5},
+			wantSort: product.SortRelevance,
+			wantSize: 5,
+		},
+		{
+			name:   "RelevanceWithoutSearch_ReturnsError",
+			args:   product.ListProductsParams{Sort: product.SortRelevance},
+			expErr: product.ErrInvalidSort,
+		},
+		{
+			name:   "UnknownSort_ReturnsError",
+			args:   product.ListProductsParams{Sort: "quantity"},
+			expErr: product.ErrInvalidSort,
+		},
+		{
+			name:   "LimitAboveMax_ReturnsError",
+			args:   product.ListProductsParams{Limit: product.MaxPageSize + 1},
+			expErr: product.ErrInvalidPageSize,
+		},
+		{
+			name:   "TooManyIDs_ReturnsError",
+			args:   product.ListProductsParams{IDs: make([]string, product.MaxPageSize+1)},
+			expErr: product.ErrTooManyIDs,
+		},
+		{
+			name:   "PriceSortWithoutCurrency_ReturnsError",
+			args:   product.ListProductsParams{Sort: product.SortPriceDesc},
+			expErr: product.ErrCurrencyRequired,
+		},
+		{
+			name:   "MinPriceAboveMax_ReturnsError",
+			args:   product.ListProductsParams{MinPrice: &minPrice, MaxPrice: &maxPrice},
+			expErr: product.ErrInvalidPriceRange,
+		},
+		{
+			name:   "PriceRangeInDifferentCurrencies_ReturnsError",
+			args:   product.ListProductsParams{MinPrice: &minPrice, MaxPrice: &eurPrice},
+			expErr: product.ErrInvalidPriceRange,
+		},
+		{
+			name:   "CreatedFromAfterTo_ReturnsError",
+			args:   product.ListProductsParams{CreatedFrom: &from, CreatedTo: &to},
+			expErr: product.ErrInvalidCreatedRange,
+		},
+		{
+			name:   "MalformedCursor_ReturnsError",
+			args:   product.ListProductsParams{Cursor: "not a cursor"},
+			expErr: product.ErrInvalidCursor,
+		},
+		{
+			name:   "CursorOfAnotherSort_ReturnsError",
+			args:   product.ListProductsParams{Cursor: priceCursor},
+			expErr: product.ErrInvalidCursor,
+		},
+		{
+			name:     "CursorOfSameSort_IsDecoded",
+			args:     product.ListProductsParams{Sort: product.SortPriceAsc, Currency: money.USD, Cursor: priceCursor},
+			wantSort: product.SortPriceAsc,
+			wantSize: product.DefaultPageSize,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			query, err := product.NewListProductsQuery(tc.args)
+			if tc.expErr != nil {
+				assert.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSort, query.Sort)
+			assert.Equal(t, tc.wantSize, query.Limit)
+			if tc.args.Cursor != "" {
+				require.NotNil(t, query.After)
+				assert.Equal(t, "1000", query.After.Value)
+				assert.Equal(t, "id", query.After.ID)
+			}
+		})
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	t.Parallel()
+	cursor := product.Cursor{Sort: product.SortNameDesc, Value: "Phone", ID: "42"}
+
+	decoded, err := product.DecodeCursor(cursor.Encode())
+
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
@@ -0,0 +1,167 @@
+package product
+
+import (
+	customErrors "shared/errors"
+	"shared/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidSKU = customErrors.NewIncorrectInputError("products/invalid_sku", "Invalid SKU")
+var ErrSKUTaken = customErrors.NewIncorrectInputError("products/sku_taken", "A variant with this SKU already exists")
+var ErrVariantNotFound = customErrors.NewNotFoundError("products/variant_not_found", "Variant not found")
+
+// Variant is a sellable version of a product, e.g. a size and color, with
+// its own SKU, price and stock. Attributes are keyed by attribute code.
+type Variant struct {
+	id         string
+	productID  string
+	sku        string
+	attributes map[string]string
+	price      money.Money
+	quantity   int
+	createdAt  time.Time
+	updatedAt  time.Time
+}
+
+type CreateVariantParams struct {
+	ProductID  string
+	SKU        string
+	Attributes map[string]string
+	Price      money.Money
+	Quantity   int
+}
+
+// Nil Attributes leave the attributes unchanged.
+type UpdateVariantParams struct {
+	SKU        *string
+	Attributes map[string]string
+	Price      *money.Money
+	Quantity   *int
+}
+
+func NewVariant(params CreateVariantParams) (Variant, error) {
+	if params.SKU == "" {
+		return Variant{}, ErrInvalidSKU
+	}
+	if !isValidPrice(params.Price) {
+		return Variant{}, ErrInvalidProductPrice
+	}
+	if params.Quantity < 0 {
+		return Variant{}, ErrInvalidProductQuantity
+	}
+	now := time.Now().UTC()
+	return Variant{
+		id:         uuid.New().String(),
+		productID:  params.ProductID,
+		sku:        params.SKU,
+		attributes: params.Attributes,
+		price:      params.Price,
+		quantity:   params.Quantity,
+		createdAt:  now,
+		updatedAt:  now,
+	}, nil
+}
+
+func NewVariantFromDatabase(
+	id, productID, sku string,
+	attributes map[string]string,
+	price money.Money,
+	quantity int,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Variant {
+	return Variant{
+		id:         id,
+		productID:  productID,
+		sku:        sku,
+		attributes: attributes,
+		price:      price,
+		quantity:   quantity,
+		createdAt:  createdAt,
+		updatedAt:  updatedAt,
+	}
+}
+
+func (v Variant) Update(params UpdateVariantParams) (Variant, error) {
+	if params.SKU != nil {
+		if *params.SKU == "" {
+			return Variant{}, ErrInvalidSKU
+		}
+		v.sku = *params.SKU
+	}
+	if params.Attributes != nil {
+		v.attributes = params.Attributes
+	}
+	if params.Price != nil {
+		if !isValidPrice(*params.Price) {
+			return Variant{}, ErrInvalidProductPrice
+		}
+		v.price = *params.Price
+	}
+	if params.Quantity != nil {
+		if *params.Quantity < 0 {
+			return Variant{}, ErrInvalidProductQuantity
+		}
+		v.quantity = *params.Quantity
+	}
+	v.updatedAt = time.Now().UTC()
+	return v, nil
+}
+
+// ReserveStock takes quantity out of the available stock of the variant.
+func (v Variant) ReserveStock(quantity int) (Variant, error) {
+	if quantity <= 0 {
+		return Variant{}, ErrInvalidProductQuantity
+	}
+	if v.quantity < quantity {
+		return Variant{}, ErrInsufficientStock
+	}
+	v.quantity -= quantity
+	v.updatedAt = time.Now().UTC()
+	return v, nil
+}
+
+// ReleaseStock puts previously reserved quantity back into stock.
+func (v Variant) ReleaseStock(quantity int) Variant {
+	v.quantity += quantity
+	v.updatedAt = time.Now().UTC()
+	return v
+}
+
+func (v Variant) ID() string {
+	return v.id
+}
+
+func (v Variant) ProductID() string {
+	return v.productID
+}
+
+func (v Variant) SKU() string {
+	return v.sku
+}
+
+func (v Variant) Attributes() map[string]string {
+	return v.attributes
+}
+
+func (v Variant) Price() money.Money {
+	return v.price
+}
+
+func (v Variant) Quantity() int {
+	return v.quantity
+}
+
+func (v Variant) CreatedAt() time.Time {
+	return v.createdAt
+}
+
+func (v Variant) UpdatedAt() time.Time {
+	return v.updatedAt
+}
+
+func (v Variant) IsZero() bool {
+	return v.id == ""
+}
@@ -0,0 +1,96 @@
+package product_test
+
+import (
+	"catalog/internal/domain/entities/product"
+	"shared/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVariant(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		args   product.CreateVariantParams
+		expErr error
+	}{
+		{
+			name: "ValidParams_ReturnsVariant",
+			args: product.CreateVariantParams{
+				ProductID:  "123",
+				SKU:        "TSHIRT-M-RED",
+				Attributes: map[string]string{"size": "M", "color": "red"},
+				Price:      money.MustParse("19.99", money.USD),
+				Quantity:   3,
+			},
+		},
+		{
+			name: "EmptySKU_ReturnsError",
+			args: product.CreateVariantParams{
+				ProductID: "123",
+				Price:     money.MustParse("19.99", money.USD),
+			},
+			expErr: product.ErrInvalidSKU,
+		},
+		{
+			name: "ZeroPrice_ReturnsError",
+			args: product.CreateVariantParams{
+				ProductID: "123",
+				SKU:       "TSHIRT-M-RED",
+				Price:     money.Zero(money.USD),
+			},
+			expErr: product.ErrInvalidProductPrice,
+		},
+		{
+			name: "NegativeQuantity_ReturnsError",
+			args: product.CreateVariantParams{
+				ProductID: "123",
+				SKU:       "TSHIRT-M-RED",
+				Price:     money.MustParse("19.99", money.USD),
+				Quantity:  -1,
+			},
+			expErr: product.ErrInvalidProductQuantity,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := product.NewVariant(tc.args)
+			if tc.expErr != nil {
+				assert.ErrorIs(t, err, tc.expErr)
+				assert.True(t, v.IsZero())
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, v.ID())
+			assert.Equal(t, tc.args.ProductID, v.ProductID())
+			assert.Equal(t, tc.args.SKU, v.SKU())
+			assert.Equal(t, tc.args.Attributes, v.Attributes())
+			assert.Equal(t, tc.args.Price, v.Price())
+			assert.Equal(t, tc.args.Quantity, v.Quantity())
+		})
+	}
+}
+
+func TestVariant_ReserveStock(t *testing.T) {
+	t.Parallel()
+	v, err := product.NewVariant(product.CreateVariantParams{
+		ProductID: "123",
+		SKU:       "TSHIRT-M-RED",
+		Price:     money.MustParse("19.99", money.USD),
+		Quantity:  3,
+	})
+	require.NoError(t, err)
+
+	reserved, err := v.ReserveStock(2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reserved.Quantity())
+
+	_, err = reserved.ReserveStock(2)
+	assert.ErrorIs(t, err, product.ErrInsufficientStock)
+
+	assert.Equal(t, 3, reserved.ReleaseStock(2).Quantity())
+}
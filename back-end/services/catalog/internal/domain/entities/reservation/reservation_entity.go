@@ -0,0 +1,142 @@
+package reservation
+
+import (
+	customErrors "shared/errors"
+	"time"
+)
+
+var ErrInvalidOrderID = customErrors.NewIncorrectInputError("reservations/invalid_order_id", "Invalid order ID")
+var ErrInvalidQuantity = customErrors.NewIncorrectInputError("reservations/invalid_quantity", "Invalid reservation quantity")
+var ErrReservationNotActive = customErrors.NewIncorrectInputError("reservations/not_active", "Reservation is already confirmed or released")
+
+const (
+	StatusReserved  = "reserved"
+	StatusConfirmed = "confirmed"
+	StatusReleased  = "released"
+)
+
+// Reservation holds stock of one product, or of one variant of a product,
+// for an order until the order is confirmed, cancelled or the reservation
+// expires. The variant ID is empty for products without variants.
+type Reservation struct {
+	orderID   string
+	productID string
+	variantID string
+	quantity  int
+	status    string
+	expiresAt time.Time
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+type Item struct {
+	ProductID string
+	VariantID string
+	Quantity  int
+}
+
+type CreateReservationParams struct {
+	OrderID   string
+	ProductID string
+	VariantID string
+	Quantity  int
+	TTL       time.Duration
+}
+
+func NewReservation(params CreateReservationParams) (Reservation, error) {
+	if params.OrderID == "" {
+		return Reservation{}, ErrInvalidOrderID
+	}
+	if params.Quantity <= 0 {
+		return Reservation{}, ErrInvalidQuantity
+	}
+	now := time.Now().UTC()
+	return Reservation{
+		orderID:   params.OrderID,
+		productID: params.ProductID,
+		variantID: params.VariantID,
+		quantity:  params.Quantity,
+		status:    StatusReserved,
+		expiresAt: now.Add(params.TTL),
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+func NewReservationFromDatabase(
+	orderID string,
+	productID string,
+	variantID string,
+	quantity int,
+	status string,
+	expiresAt time.Time,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Reservation {
+	return Reservation{
+		orderID:   orderID,
+		productID: productID,
+		variantID: variantID,
+		quantity:  quantity,
+		status:    status,
+		expiresAt: expiresAt,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// Confirm makes the reservation permanent, the stock stays taken.
+func (r Reservation) Confirm() (Reservation, error) {
+	if !r.IsActive() {
+		return Reservation{}, ErrReservationNotActive
+	}
+	r.status = StatusConfirmed
+	r.updatedAt = time.Now().UTC()
+	return r, nil
+}
+
+// Release marks the reservation as released, the caller returns the stock.
+func (r Reservation) Release() (Reservation, error) {
+	if !r.IsActive() {
+		return Reservation{}, ErrReservationNotActive
+	}
+	r.status = StatusReleased
+	r.updatedAt = time.Now().UTC()
+	return r, nil
+}
+
+func (r Reservation) IsActive() bool {
+	return r.status == StatusReserved
+}
+
+func (r Reservation) OrderID() string {
+	return r.orderID
+}
+
+func (r Reservation) ProductID() string {
+	return r.productID
+}
+
+func (r Reservation) VariantID() string {
+	return r.variantID
+}
+
+func (r Reservation) Quantity() int {
+	return r.quantity
+}
+
+func (r Reservation) Status() string {
+	return r.status
+}
+
+func (r Reservation) ExpiresAt() time.Time {
+	return r.expiresAt
+}
+
+func (r Reservation) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+func (r Reservation) UpdatedAt() time.Time {
+	return r.updatedAt
+}
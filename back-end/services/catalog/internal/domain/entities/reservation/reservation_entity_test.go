@@ -0,0 +1,89 @@
+package reservation_test
+
+import (
+	"catalog/internal/domain/entities/reservation"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReservation(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		args   reservation.CreateReservationParams
+		expErr error
+	}{
+		{
+			name: "ValidParams_ReturnsReservation",
+			args: reservation.CreateReservationParams{
+				OrderID:   "order_id",
+				ProductID: "product_id",
+				Quantity:  2,
+				TTL:       time.Minute,
+			},
+		},
+		{
+			name: "InvalidOrderID_ReturnsError",
+			args: reservation.CreateReservationParams{
+				ProductID: "product_id",
+				Quantity:  2,
+			},
+			expErr: reservation.ErrInvalidOrderID,
+		},
+		{
+			name: "InvalidQuantity_ReturnsError",
+			args: reservation.CreateReservationParams{
+				OrderID:   "order_id",
+				ProductID: "product_id",
+				Quantity:  0,
+			},
+			expErr: reservation.ErrInvalidQuantity,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := reservation.NewReservation(tc.args)
+
+			require.Equal(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+			assert.Equal(t, tc.args.OrderID, r.OrderID())
+			assert.Equal(t, tc.args.ProductID, r.ProductID())
+			assert.Equal(t, tc.args.Quantity, r.Quantity())
+			assert.Equal(t, reservation.StatusReserved, r.Status())
+			assert.Equal(t, r.CreatedAt().Add(tc.args.TTL), r.ExpiresAt())
+		})
+	}
+}
+
+func TestReservation_Transitions(t *testing.T) {
+	t.Parallel()
+	active, err := reservation.NewReservation(reservation.CreateReservationParams{
+		OrderID:   "order_id",
+		ProductID: "product_id",
+		Quantity:  1,
+		TTL:       time.Minute,
+	})
+	require.NoError(t, err)
+
+	confirmed, err := active.Confirm()
+	require.NoError(t, err)
+	assert.Equal(t, reservation.StatusConfirmed, confirmed.Status())
+	assert.False(t, confirmed.IsActive())
+
+	released, err := active.Release()
+	require.NoError(t, err)
+	assert.Equal(t, reservation.StatusReleased, released.Status())
+
+	_, err = confirmed.Release()
+	assert.Equal(t, reservation.ErrReservationNotActive, err)
+	_, err = released.Confirm()
+	assert.Equal(t, reservation.ErrReservationNotActive, err)
+}
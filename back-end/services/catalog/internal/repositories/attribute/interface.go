@@ -0,0 +1,20 @@
+package repository
+
+import (
+	attributeEntity "catalog/internal/domain/entities/attribute"
+	"context"
+)
+
+type AttributeRepository interface {
+	SaveAttribute(ctx context.Context, attribute attributeEntity.Attribute) error
+	UpdateAttribute(ctx context.Context, attribute attributeEntity.Attribute) error
+	GetAttributes(ctx context.Context) ([]attributeEntity.Attribute, error)
+	// GetAttributeByID and GetAttributeByCode return a zero attribute when
+	// there is none.
+	GetAttributeByID(ctx context.Context, id string) (attributeEntity.Attribute, error)
+	GetAttributeByCode(ctx context.Context, code string) (attributeEntity.Attribute, error)
+	DeleteAttribute(ctx context.Context, id string) error
+	// CountUsages counts the products and variants that have a value for the
+	// attribute.
+	CountUsages(ctx context.Context, code string) (int, error)
+}
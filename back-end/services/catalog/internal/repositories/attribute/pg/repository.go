@@ -0,0 +1,139 @@
+package repository
+
+import (
+	attributeEntity "catalog/internal/domain/entities/attribute"
+	repository "catalog/internal/repositories/attribute"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+
+	pgStorage "shared/storage/pg"
+)
+
+var _ repository.AttributeRepository = (*attributePGRepository)(nil)
+
+type AttributeModel struct {
+	bun.BaseModel `bun:"table:attributes,alias:a"`
+
+	ID        string    `bun:"id,pk"`
+	Code      string    `bun:"code"`
+	Name      string    `bun:"name"`
+	Type      string    `bun:"type"`
+	Options   []string  `bun:"options,array"`
+	CreatedAt time.Time `bun:"created_at"`
+	UpdatedAt time.Time `bun:"updated_at"`
+}
+
+type attributePGRepository struct {
+	db     *bun.DB
+	logger zerolog.Logger
+}
+
+func (m AttributeModel) toEntity() attributeEntity.Attribute {
+	return attributeEntity.NewAttributeFromDatabase(m.ID, m.Code, m.Name, m.Type, m.Options, m.CreatedAt, m.UpdatedAt)
+}
+
+func toDB(a attributeEntity.Attribute) AttributeModel {
+	return AttributeModel{
+		ID:        a.ID(),
+		Code:      a.Code(),
+		Name:      a.Name(),
+		Type:      a.Type(),
+		Options:   a.Options(),
+		CreatedAt: a.CreatedAt(),
+		UpdatedAt: a.UpdatedAt(),
+	}
+}
+
+func NewAttributeRepository(sql *bun.DB, logger zerolog.Logger) *attributePGRepository {
+	return &attributePGRepository{sql, logger}
+}
+
+func (r *attributePGRepository) SaveAttribute(ctx context.Context, attribute attributeEntity.Attribute) error {
+	model := toDB(attribute)
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().Model(&model).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("attributePGRepository SaveAttribute -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *attributePGRepository) UpdateAttribute(ctx context.Context, attribute attributeEntity.Attribute) error {
+	model := toDB(attribute)
+	_, err := pgStorage.Conn(ctx, r.db).NewUpdate().
+		Model(&model).
+		Column("name", "options", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("attributePGRepository UpdateAttribute -> r.db.NewUpdate(): %w", err)
+	}
+	return nil
+}
+
+func (r *attributePGRepository) GetAttributes(ctx context.Context) ([]attributeEntity.Attribute, error) {
+	var models []AttributeModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&models).
+		OrderExpr("a.code").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("attributePGRepository GetAttributes -> r.db.NewSelect(): %w", err)
+	}
+	attributes := make([]attributeEntity.Attribute, 0, len(models))
+	for _, model := range models {
+		attributes = append(attributes, model.toEntity())
+	}
+	return attributes, nil
+}
+
+func (r *attributePGRepository) GetAttributeByID(ctx context.Context, id string) (attributeEntity.Attribute, error) {
+	return r.getAttribute(ctx, "a.id = ?", id)
+}
+
+func (r *attributePGRepository) GetAttributeByCode(ctx context.Context, code string) (attributeEntity.Attribute, error) {
+	return r.getAttribute(ctx, "a.code = ?", code)
+}
+
+func (r *attributePGRepository) getAttribute(ctx context.Context, where string, arg string) (attributeEntity.Attribute, error) {
+	var model AttributeModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&model).
+		Where(where, arg).
+		Scan(ctx)
+	if err == sql.ErrNoRows {
+		return attributeEntity.Attribute{}, nil
+	}
+	if err != nil {
+		return attributeEntity.Attribute{}, fmt.Errorf("attributePGRepository getAttribute -> r.db.NewSelect(): %w", err)
+	}
+	return model.toEntity(), nil
+}
+
+func (r *attributePGRepository) DeleteAttribute(ctx context.Context, id string) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().
+		Model((*AttributeModel)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("attributePGRepository DeleteAttribute -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
+
+func (r *attributePGRepository) CountUsages(ctx context.Context, code string) (int, error) {
+	var count int
+	err := pgStorage.Conn(ctx, r.db).NewRaw(
+		"SELECT (SELECT count(*) FROM products WHERE jsonb_exists(attributes, ?0)) + "+
+			"(SELECT count(*) FROM product_variants WHERE jsonb_exists(attributes, ?0))",
+		code,
+	).Scan(ctx, &count)
+	if err != nil {
+		return 0, fmt.Errorf("attributePGRepository CountUsages -> r.db.NewRaw(): %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	categoryEntity "catalog/internal/domain/entities/category"
+	"context"
+)
+
+type CategoryRepository interface {
+	SaveCategory(ctx context.Context, category categoryEntity.Category) error
+	UpdateCategory(ctx context.Context, category categoryEntity.Category) error
+	// GetCategories returns the whole category tree.
+	GetCategories(ctx context.Context) ([]categoryEntity.Category, error)
+	DeleteCategory(ctx context.Context, id string) error
+	CountProducts(ctx context.Context, categoryID string) (int, error)
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	categoryEntity "catalog/internal/domain/entities/category"
+	repository "catalog/internal/repositories/category"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+
+	pgStorage "shared/storage/pg"
+)
+
+var _ repository.CategoryRepository = (*categoryPGRepository)(nil)
+
+type CategoryModel struct {
+	bun.BaseModel `bun:"table:categories,alias:c"`
+
+	ID        string    `bun:"id,pk"`
+	Name      string    `bun:"name"`
+	ParentID  string    `bun:"parent_id,nullzero"`
+	CreatedAt time.Time `bun:"created_at"`
+	UpdatedAt time.Time `bun:"updated_at"`
+}
+
+type categoryPGRepository struct {
+	db     *bun.DB
+	logger zerolog.Logger
+}
+
+func (m CategoryModel) toEntity() categoryEntity.Category {
+	return categoryEntity.NewCategoryFromDatabase(m.ID, m.Name, m.ParentID, m.CreatedAt, m.UpdatedAt)
+}
+
+func toDB(c categoryEntity.Category) CategoryModel {
+	return CategoryModel{
+		ID:        c.ID(),
+		Name:      c.Name(),
+		ParentID:  c.ParentID(),
+		CreatedAt: c.CreatedAt(),
+		UpdatedAt: c.UpdatedAt(),
+	}
+}
+
+func NewCategoryRepository(sql *bun.DB, logger zerolog.Logger) *categoryPGRepository {
+	return &categoryPGRepository{sql, logger}
+}
+
+func (r *categoryPGRepository) SaveCategory(ctx context.Context, category categoryEntity.Category) error {
+	model := toDB(category)
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().Model(&model).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("categoryPGRepository SaveCategory -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *categoryPGRepository) UpdateCategory(ctx context.Context, category categoryEntity.Category) error {
+	model := toDB(category)
+	_, err := pgStorage.Conn(ctx, r.db).NewUpdate().
+		Model(&model).
+		Column("name", "parent_id", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("categoryPGRepository UpdateCategory -> r.db.NewUpdate(): %w", err)
+	}
+	return nil
+}
+
+func (r *categoryPGRepository) GetCategories(ctx context.Context) ([]categoryEntity.Category, error) {
+	var models []CategoryModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&models).
+		OrderExpr("c.name, c.id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("categoryPGRepository GetCategories -> r.db.NewSelect(): %w", err)
+	}
+	categories := make([]categoryEntity.Category, 0, len(models))
+	for _, model := range models {
+		categories = append(categories, model.toEntity())
+	}
+	return categories, nil
+}
+
+func (r *categoryPGRepository) DeleteCategory(ctx context.Context, id string) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().
+		Model((*CategoryModel)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("categoryPGRepository DeleteCategory -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
+
+func (r *categoryPGRepository) CountProducts(ctx context.Context, categoryID string) (int, error) {
+	count, err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Table("products").
+		Where("category_id = ?", categoryID).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("categoryPGRepository CountProducts -> r.db.NewSelect(): %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	exchangeRateEntity "catalog/internal/domain/entities/exchangerate"
+	"context"
+	"shared/money"
+)
+
+type ExchangeRateRepository interface {
+	SaveExchangeRate(ctx context.Context, rate exchangeRateEntity.ExchangeRate) error
+	GetExchangeRates(ctx context.Context) ([]exchangeRateEntity.ExchangeRate, error)
+	// DeleteExchangeRate reports whether a rate was deleted.
+	DeleteExchangeRate(ctx context.Context, currency money.Currency) (bool, error)
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	exchangeRateEntity "catalog/internal/domain/entities/exchangerate"
+	repository "catalog/internal/repositories/exchangerate"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+
+	"shared/money"
+	pgStorage "shared/storage/pg"
+)
+
+var _ repository.ExchangeRateRepository = (*exchangeRatePGRepository)(nil)
+
+type ExchangeRateModel struct {
+	bun.BaseModel `bun:"table:exchange_rates,alias:er"`
+
+	Currency  string    `bun:"currency,pk"`
+	Rate      string    `bun:"rate"`
+	UpdatedAt time.Time `bun:"updated_at"`
+}
+
+type exchangeRatePGRepository struct {
+	db     *bun.DB
+	logger zerolog.Logger
+}
+
+func (m ExchangeRateModel) toEntity() (exchangeRateEntity.ExchangeRate, error) {
+	rate, err := money.ParseRate(m.Rate)
+	if err != nil {
+		return exchangeRateEntity.ExchangeRate{}, err
+	}
+	return exchangeRateEntity.NewExchangeRateFromDatabase(money.Currency(m.Currency), rate, m.UpdatedAt), nil
+}
+
+func NewExchangeRateRepository(sql *bun.DB, logger zerolog.Logger) *exchangeRatePGRepository {
+	return &exchangeRatePGRepository{sql, logger}
+}
+
+func (r *exchangeRatePGRepository) SaveExchangeRate(ctx context.Context, rate exchangeRateEntity.ExchangeRate) error {
+	model := ExchangeRateModel{
+		Currency:  string(rate.Currency()),
+		Rate:      rate.Rate().String(),
+		UpdatedAt: rate.UpdatedAt(),
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().
+		Model(&model).
+		On("CONFLICT (currency) DO UPDATE").
+		Set("rate = EXCLUDED.rate").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("exchangeRatePGRepository SaveExchangeRate -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *exchangeRatePGRepository) GetExchangeRates(ctx context.Context) ([]exchangeRateEntity.ExchangeRate, error) {
+	var models []ExchangeRateModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&models).
+		OrderExpr("er.currency").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("exchangeRatePGRepository GetExchangeRates -> r.db.NewSelect(): %w", err)
+	}
+
+	rates := make([]exchangeRateEntity.ExchangeRate, 0, len(models))
+	for _, model := range models {
+		rate, err := model.toEntity()
+		if err != nil {
+			return nil, fmt.Errorf("exchangeRatePGRepository GetExchangeRates -> model.toEntity(): %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (r *exchangeRatePGRepository) DeleteExchangeRate(ctx context.Context, currency money.Currency) (bool, error) {
+	result, err := pgStorage.Conn(ctx, r.db).NewDelete().
+		Model((*ExchangeRateModel)(nil)).
+		Where("currency = ?", string(currency)).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("exchangeRatePGRepository DeleteExchangeRate -> r.db.NewDelete(): %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("exchangeRatePGRepository DeleteExchangeRate -> result.RowsAffected(): %w", err)
+	}
+	return deleted > 0, nil
+}
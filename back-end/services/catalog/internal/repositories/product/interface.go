@@ -7,8 +7,24 @@ import (
 
 type ProductRepository interface {
 	SaveProduct(ctx context.Context, product productEntity.Product) error
-	GetProducts(ctx context.Context) ([]productEntity.Product, error)
+	GetProducts(ctx context.Context, query productEntity.ListProductsQuery) (productEntity.Page, error)
 	GetProductByID(ctx context.Context, productID string) (productEntity.Product, error)
+	GetProductByIDForUpdate(ctx context.Context, productID string) (productEntity.Product, error)
 	DeleteProductByID(ctx context.Context, productID string) error
 	UpdateProductByID(ctx context.Context, product productEntity.Product) error
+
+	SaveVariant(ctx context.Context, variant productEntity.Variant) error
+	UpdateVariant(ctx context.Context, variant productEntity.Variant) error
+	GetVariantsByProductID(ctx context.Context, productID string) ([]productEntity.Variant, error)
+	// The variant getters return a zero variant when there is none.
+	GetVariantByID(ctx context.Context, variantID string) (productEntity.Variant, error)
+	GetVariantByIDForUpdate(ctx context.Context, variantID string) (productEntity.Variant, error)
+	GetVariantBySKU(ctx context.Context, sku string) (productEntity.Variant, error)
+	DeleteVariant(ctx context.Context, variantID string) error
+
+	SaveImage(ctx context.Context, image productEntity.Image) error
+	GetImagesByProductID(ctx context.Context, productID string) ([]productEntity.Image, error)
+	// GetImageByID returns a zero image when there is none.
+	GetImageByID(ctx context.Context, imageID string) (productEntity.Image, error)
+	DeleteImage(ctx context.Context, imageID string) error
 }
@@ -6,10 +6,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/uptrace/bun"
+
+	"shared/money"
+	pgStorage "shared/storage/pg"
 )
 
 var _ repository.ProductRepository = (*productPGRepository)(nil)
@@ -17,12 +21,48 @@ var _ repository.ProductRepository = (*productPGRepository)(nil)
 type ProductModel struct {
 	bun.BaseModel `bun:"table:products,alias:p"`
 
-	ID        string    `bun:"id"`
-	Name      string    `bun:"name"`
-	Price     float64   `bun:"price"`
-	Quantity  int       `bun:"quantity"`
-	CreatedAt time.Time `bson:"createdAt,omitempty"`
-	UpdatedAt time.Time `bson:"updated,omitempty"`
+	ID            string            `bun:"id"`
+	SellerID      string            `bun:"seller_id,nullzero"`
+	Name          string            `bun:"name"`
+	Description   string            `bun:"description"`
+	CategoryID    string            `bun:"category_id,nullzero"`
+	Attributes    map[string]string `bun:"attributes,type:jsonb"`
+	PriceAmount   int64             `bun:"price_amount"`
+	PriceCurrency string            `bun:"price_currency"`
+	Quantity      int               `bun:"quantity"`
+	CreatedAt     time.Time         `bson:"createdAt,omitempty"`
+	UpdatedAt     time.Time         `bson:"updated,omitempty"`
+	Rank          float32           `bun:"rank,scanonly"`
+}
+
+type VariantModel struct {
+	bun.BaseModel `bun:"table:product_variants,alias:v"`
+
+	ID            string            `bun:"id,pk"`
+	ProductID     string            `bun:"product_id"`
+	SKU           string            `bun:"sku"`
+	Attributes    map[string]string `bun:"attributes,type:jsonb"`
+	PriceAmount   int64             `bun:"price_amount"`
+	PriceCurrency string            `bun:"price_currency"`
+	Quantity      int               `bun:"quantity"`
+	CreatedAt     time.Time         `bun:"created_at"`
+	UpdatedAt     time.Time         `bun:"updated_at"`
+}
+
+type ImageModel struct {
+	bun.BaseModel `bun:"table:product_images,alias:i"`
+
+	ID           string    `bun:"id,pk"`
+	ProductID    string    `bun:"product_id"`
+	Key          string    `bun:"key"`
+	URL          string    `bun:"url"`
+	ThumbnailKey string    `bun:"thumbnail_key"`
+	ThumbnailURL string    `bun:"thumbnail_url"`
+	ContentType  string    `bun:"content_type"`
+	Width        int       `bun:"width"`
+	Height       int       `bun:"height"`
+	Size         int64     `bun:"size"`
+	CreatedAt    time.Time `bun:"created_at"`
 }
 
 type productPGRepository struct {
@@ -34,8 +74,12 @@ func (p ProductModel) toEntity() productEntity.Product {
 
 	product := productEntity.NewProductFromDatabase(
 		p.ID,
+		p.SellerID,
 		p.Name,
-		p.Price,
+		p.Description,
+		p.CategoryID,
+		p.Attributes,
+		money.New(p.PriceAmount, money.Currency(p.PriceCurrency)),
 		p.Quantity,
 		p.CreatedAt,
 		p.UpdatedAt,
@@ -46,12 +90,17 @@ func (p ProductModel) toEntity() productEntity.Product {
 
 func toDB(p productEntity.Product) ProductModel {
 	return ProductModel{
-		ID:        p.ID(),
-		Name:      p.Name(),
-		Price:     p.Price(),
-		Quantity:  p.Quantity(),
-		CreatedAt: p.CreatedAt(),
-		UpdatedAt: p.UpdatedAt(),
+		ID:            p.ID(),
+		SellerID:      p.SellerID(),
+		Name:          p.Name(),
+		Description:   p.Description(),
+		CategoryID:    p.CategoryID(),
+		Attributes:    attributesToDB(p.Attributes()),
+		PriceAmount:   p.Price().MinorUnits(),
+		PriceCurrency: string(p.Price().Currency()),
+		Quantity:      p.Quantity(),
+		CreatedAt:     p.CreatedAt(),
+		UpdatedAt:     p.UpdatedAt(),
 	}
 }
 
@@ -62,39 +111,178 @@ func NewProductRepository(sql *bun.DB, logger zerolog.Logger) *productPGReposito
 func (r *productPGRepository) SaveProduct(ctx context.Context, u productEntity.Product) error {
 	dbProduct := toDB(u)
 
-	_, err := r.db.NewInsert().Model(&dbProduct).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().Model(&dbProduct).Exec(ctx)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *productPGRepository) GetProducts(ctx context.Context) ([]productEntity.Product, error) {
-	productModels := make([]ProductModel, 0)
-	err := r.db.NewSelect().
+// GetProducts returns one page of products that match the query, ordered by
+// the sort key and the ID so that cursors stay stable while products are
+// added or removed.
+func (r *productPGRepository) GetProducts(ctx context.Context, query productEntity.ListProductsQuery) (productEntity.Page, error) {
+	productModels := make([]ProductModel, 0, query.Limit+1)
+	q := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&productModels).
-		Scan(ctx)
-	if err == sql.ErrNoRows {
-		return nil, nil
+		ColumnExpr("?TableColumns")
+
+	if len(query.IDs) > 0 {
+		q = q.Where("p.id IN (?)", bun.In(query.IDs))
+	}
+	if query.Search != "" {
+		q = q.ColumnExpr(rankExpr+" AS rank", query.Search).
+			Where("p.search_vector @@ websearch_to_tsquery('english', ?)", query.Search)
+	}
+	if query.SellerID != "" {
+		q = q.Where("p.seller_id = ?", query.SellerID)
+	}
+	if query.CategoryID != "" {
+		q = q.Where("p.category_id IN ("+categorySubtreeQuery+")", query.CategoryID)
+	}
+	if query.MinPrice != nil {
+		q = q.Where("p.price_currency = ? AND p.price_amount >= ?", string(query.MinPrice.Currency()), query.MinPrice.MinorUnits())
+	}
+	if query.MaxPrice != nil {
+		q = q.Where("p.price_currency = ? AND p.price_amount <= ?", string(query.MaxPrice.Currency()), query.MaxPrice.MinorUnits())
+	}
+	if query.SortsByPrice() {
+		q = q.Where("p.price_currency = ?", string(query.Currency))
+	}
+	if query.InStock {
+		q = q.Where("p.quantity > 0")
+	}
+	if query.CreatedFrom != nil {
+		q = q.Where("p.created_at >= ?", query.CreatedFrom.UTC())
+	}
+	if query.CreatedTo != nil {
+		q = q.Where("p.created_at <= ?", query.CreatedTo.UTC())
 	}
 
+	total, err := q.Count(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("productRepo -> GetProducts -> r.db.NewSelect(): %w", err)
+		return productEntity.Page{}, fmt.Errorf("productRepo -> GetProducts -> q.Count(): %w", err)
 	}
 
-	products := make([]productEntity.Product, 0, len(productModels))
+	key := sortKeys[query.Sort]
+	column := bun.Safe(key.column)
+	args := []interface{}{}
+	if query.Sort == productEntity.SortRelevance {
+		column = bun.Safe(rankExpr)
+		args = append(args, query.Search)
+	}
+	if query.After != nil {
+		value, err := key.parse(query.After.Value)
+		if err != nil {
+			return productEntity.Page{}, productEntity.ErrInvalidCursor
+		}
+		operator := ">"
+		if key.desc {
+			operator = "<"
+		}
+		whereArgs := append(append([]interface{}{}, args...), value, query.After.ID)
+		q = q.Where("("+string(column)+", p.id) "+operator+" (?, ?)", whereArgs...)
+	}
+	direction := " ASC"
+	if key.desc {
+		direction = " DESC"
+	}
+	err = q.OrderExpr(string(column)+direction, args...).
+		OrderExpr("p.id" + direction).
+		Limit(query.Limit + 1).
+		Scan(ctx)
+	if err != nil && err != sql.ErrNoRows {
+		return productEntity.Page{}, fmt.Errorf("productRepo -> GetProducts -> r.db.NewSelect(): %w", err)
+	}
+
+	page := productEntity.Page{Total: total}
+	if len(productModels) > query.Limit {
+		productModels = productModels[:query.Limit]
+		last := productModels[len(productModels)-1]
+		page.NextCursor = productEntity.Cursor{
+			Sort:  query.Sort,
+			Value: key.format(last),
+			ID:    last.ID,
+		}.Encode()
+	}
+	productIDs := make([]string, 0, len(productModels))
 	for _, productModel := range productModels {
-		productEntity := productModel.toEntity()
+		productIDs = append(productIDs, productModel.ID)
+	}
+	images, err := r.getImagesByProductIDs(ctx, productIDs)
+	if err != nil {
+		return productEntity.Page{}, fmt.Errorf("productRepo -> GetProducts -> r.getImagesByProductIDs: %w", err)
+	}
+	page.Products = make([]productEntity.Product, 0, len(productModels))
+	for _, productModel := range productModels {
+		page.Products = append(page.Products, productModel.toEntity().WithImages(images[productModel.ID]))
+	}
+	return page, nil
+}
+
+// categorySubtreeQuery selects the IDs of a category and of its descendants.
+const categorySubtreeQuery = "WITH RECURSIVE subtree AS (" +
+	"SELECT id FROM categories WHERE id = ? " +
+	"UNION ALL SELECT c.id FROM categories AS c JOIN subtree AS s ON c.parent_id = s.id" +
+	") SELECT id FROM subtree"
+
+const rankExpr = "ts_rank(p.search_vector, websearch_to_tsquery('english', ?))"
 
-		products = append(products, productEntity)
+type sortKey struct {
+	column string
+	desc   bool
+	format func(ProductModel) string
+	parse  func(string) (interface{}, error)
+}
+
+var (
+	createdAtKey = sortKey{
+		column: "p.created_at",
+		format: func(m ProductModel) string { return m.CreatedAt.UTC().Format(time.RFC3339Nano) },
+		parse: func(value string) (interface{}, error) {
+			return time.Parse(time.RFC3339Nano, value)
+		},
+	}
+	priceKey = sortKey{
+		column: "p.price_amount",
+		format: func(m ProductModel) string { return strconv.FormatInt(m.PriceAmount, 10) },
+		parse: func(value string) (interface{}, error) {
+			return strconv.ParseInt(value, 10, 64)
+		},
+	}
+	nameKey = sortKey{
+		column: "p.name",
+		format: func(m ProductModel) string { return m.Name },
+		parse:  func(value string) (interface{}, error) { return value, nil },
+	}
+	rankKey = sortKey{
+		desc:   true,
+		format: func(m ProductModel) string { return strconv.FormatFloat(float64(m.Rank), 'g', -1, 32) },
+		parse: func(value string) (interface{}, error) {
+			rank, err := strconv.ParseFloat(value, 32)
+			return bun.Safe("CAST(" + strconv.FormatFloat(rank, 'g', -1, 32) + " AS real)"), err
+		},
 	}
+)
+
+var sortKeys = map[string]sortKey{
+	productEntity.SortNewest:    descending(createdAtKey),
+	productEntity.SortOldest:    createdAtKey,
+	productEntity.SortPriceAsc:  priceKey,
+	productEntity.SortPriceDesc: descending(priceKey),
+	productEntity.SortNameAsc:   nameKey,
+	productEntity.SortNameDesc:  descending(nameKey),
+	productEntity.SortRelevance: rankKey,
+}
 
-	return products, nil
+func descending(key sortKey) sortKey {
+	key.desc = true
+	return key
 }
 
 func (r *productPGRepository) GetProductByID(ctx context.Context, productID string) (productEntity.Product, error) {
 	var productDB ProductModel
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&productDB).
 		Where("id IN (?)", productID).
 		Scan(ctx)
@@ -107,16 +295,46 @@ func (r *productPGRepository) GetProductByID(ctx context.Context, productID stri
 		return productEntity.Product{}, fmt.Errorf("productPGRepository -> GetProductByID -> r.db.NewSelect(): %w", err)
 	}
 
-	product := productDB.toEntity()
+	variants, err := r.GetVariantsByProductID(ctx, productID)
 	if err != nil {
-		return product, fmt.Errorf("productPGRepository -> GetByID -> toEntity: %w", err)
+		return productEntity.Product{}, fmt.Errorf("productPGRepository -> GetProductByID -> r.GetVariantsByProductID: %w", err)
 	}
-	return product, nil
+	images, err := r.GetImagesByProductID(ctx, productID)
+	if err != nil {
+		return productEntity.Product{}, fmt.Errorf("productPGRepository -> GetProductByID -> r.GetImagesByProductID: %w", err)
+	}
+	return productDB.toEntity().WithVariants(variants).WithImages(images), nil
+}
+
+// GetProductByIDForUpdate locks the product row until the surrounding
+// transaction ends.
+func (r *productPGRepository) GetProductByIDForUpdate(ctx context.Context, productID string) (productEntity.Product, error) {
+	var productDB ProductModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&productDB).
+		Where("id = ?", productID).
+		For("UPDATE").
+		Scan(ctx)
+
+	if err == sql.ErrNoRows {
+		return productEntity.Product{}, nil
+	}
+
+	if err != nil {
+		return productEntity.Product{}, fmt.Errorf("productPGRepository -> GetProductByIDForUpdate -> r.db.NewSelect(): %w", err)
+	}
+
+	// product events carry the images
+	images, err := r.GetImagesByProductID(ctx, productID)
+	if err != nil {
+		return productEntity.Product{}, fmt.Errorf("productPGRepository -> GetProductByIDForUpdate -> r.GetImagesByProductID: %w", err)
+	}
+	return productDB.toEntity().WithImages(images), nil
 }
 
 func (r *productPGRepository) DeleteProductByID(ctx context.Context, productID string) error {
 	var product ProductModel
-	_, err := r.db.NewDelete().Model(&product).Where("id = ?", productID).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().Model(&product).Where("id = ?", productID).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("productPGRepository DeleteProductByID -> NewDelete: %w", err)
 	}
@@ -130,10 +348,218 @@ func (r *productPGRepository) UpdateProductByID(
 ) error {
 	productModel := toDB(updatedProduct)
 
-	_, err := r.db.NewUpdate().Model(&productModel).Where("id = ?", updatedProduct.ID()).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewUpdate().Model(&productModel).Where("id = ?", updatedProduct.ID()).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("productPGRepository UpdateProductByID -> r.db.NewUpdate(): %w", err)
 	}
 
 	return nil
 }
+
+func (v VariantModel) toEntity() productEntity.Variant {
+	return productEntity.NewVariantFromDatabase(
+		v.ID,
+		v.ProductID,
+		v.SKU,
+		v.Attributes,
+		money.New(v.PriceAmount, money.Currency(v.PriceCurrency)),
+		v.Quantity,
+		v.CreatedAt,
+		v.UpdatedAt,
+	)
+}
+
+func variantToDB(v productEntity.Variant) VariantModel {
+	return VariantModel{
+		ID:            v.ID(),
+		ProductID:     v.ProductID(),
+		SKU:           v.SKU(),
+		Attributes:    attributesToDB(v.Attributes()),
+		PriceAmount:   v.Price().MinorUnits(),
+		PriceCurrency: string(v.Price().Currency()),
+		Quantity:      v.Quantity(),
+		CreatedAt:     v.CreatedAt(),
+		UpdatedAt:     v.UpdatedAt(),
+	}
+}
+
+// attributes are stored as a JSON object, never as null
+func attributesToDB(attributes map[string]string) map[string]string {
+	if attributes == nil {
+		return map[string]string{}
+	}
+	return attributes
+}
+
+func (r *productPGRepository) SaveVariant(ctx context.Context, variant productEntity.Variant) error {
+	model := variantToDB(variant)
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().Model(&model).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository SaveVariant -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *productPGRepository) UpdateVariant(ctx context.Context, variant productEntity.Variant) error {
+	model := variantToDB(variant)
+	_, err := pgStorage.Conn(ctx, r.db).NewUpdate().
+		Model(&model).
+		Column("sku", "attributes", "price_amount", "price_currency", "quantity", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository UpdateVariant -> r.db.NewUpdate(): %w", err)
+	}
+	return nil
+}
+
+func (r *productPGRepository) GetVariantsByProductID(ctx context.Context, productID string) ([]productEntity.Variant, error) {
+	var models []VariantModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&models).
+		Where("v.product_id = ?", productID).
+		OrderExpr("v.sku").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("productPGRepository GetVariantsByProductID -> r.db.NewSelect(): %w", err)
+	}
+	variants := make([]productEntity.Variant, 0, len(models))
+	for _, model := range models {
+		variants = append(variants, model.toEntity())
+	}
+	return variants, nil
+}
+
+func (r *productPGRepository) GetVariantByID(ctx context.Context, variantID string) (productEntity.Variant, error) {
+	return r.getVariant(ctx, "v.id = ?", variantID, false)
+}
+
+// GetVariantByIDForUpdate locks the variant row until the surrounding
+// transaction ends.
+func (r *productPGRepository) GetVariantByIDForUpdate(ctx context.Context, variantID string) (productEntity.Variant, error) {
+	return r.getVariant(ctx, "v.id = ?", variantID, true)
+}
+
+func (r *productPGRepository) GetVariantBySKU(ctx context.Context, sku string) (productEntity.Variant, error) {
+	return r.getVariant(ctx, "v.sku = ?", sku, false)
+}
+
+func (r *productPGRepository) getVariant(ctx context.Context, where string, arg string, forUpdate bool) (productEntity.Variant, error) {
+	var model VariantModel
+	q := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&model).
+		Where(where, arg)
+	if forUpdate {
+		q = q.For("UPDATE")
+	}
+	err := q.Scan(ctx)
+	if err == sql.ErrNoRows {
+		return productEntity.Variant{}, nil
+	}
+	if err != nil {
+		return productEntity.Variant{}, fmt.Errorf("productPGRepository getVariant -> r.db.NewSelect(): %w", err)
+	}
+	return model.toEntity(), nil
+}
+
+func (r *productPGRepository) DeleteVariant(ctx context.Context, variantID string) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().
+		Model((*VariantModel)(nil)).
+		Where("id = ?", variantID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository DeleteVariant -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
+
+func (i ImageModel) toEntity() productEntity.Image {
+	return productEntity.NewImageFromDatabase(
+		i.ID,
+		i.ProductID,
+		i.Key,
+		i.URL,
+		i.ThumbnailKey,
+		i.ThumbnailURL,
+		i.ContentType,
+		i.Width,
+		i.Height,
+		i.Size,
+		i.CreatedAt,
+	)
+}
+
+func (r *productPGRepository) SaveImage(ctx context.Context, image productEntity.Image) error {
+	model := ImageModel{
+		ID:           image.ID(),
+		ProductID:    image.ProductID(),
+		Key:          image.Key(),
+		URL:          image.URL(),
+		ThumbnailKey: image.ThumbnailKey(),
+		ThumbnailURL: image.ThumbnailURL(),
+		ContentType:  image.ContentType(),
+		Width:        image.Width(),
+		Height:       image.Height(),
+		Size:         image.Size(),
+		CreatedAt:    image.CreatedAt(),
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().Model(&model).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository SaveImage -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *productPGRepository) GetImagesByProductID(ctx context.Context, productID string) ([]productEntity.Image, error) {
+	images, err := r.getImagesByProductIDs(ctx, []string{productID})
+	if err != nil {
+		return nil, fmt.Errorf("productPGRepository GetImagesByProductID -> r.getImagesByProductIDs: %w", err)
+	}
+	return images[productID], nil
+}
+
+func (r *productPGRepository) getImagesByProductIDs(ctx context.Context, productIDs []string) (map[string][]productEntity.Image, error) {
+	images := make(map[string][]productEntity.Image, len(productIDs))
+	if len(productIDs) == 0 {
+		return images, nil
+	}
+	var models []ImageModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&models).
+		Where("i.product_id IN (?)", bun.In(productIDs)).
+		Order("i.created_at", "i.id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("productPGRepository getImagesByProductIDs -> r.db.NewSelect(): %w", err)
+	}
+	for _, model := range models {
+		images[model.ProductID] = append(images[model.ProductID], model.toEntity())
+	}
+	return images, nil
+}
+
+func (r *productPGRepository) GetImageByID(ctx context.Context, imageID string) (productEntity.Image, error) {
+	var model ImageModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&model).
+		Where("i.id = ?", imageID).
+		Scan(ctx)
+	if err == sql.ErrNoRows {
+		return productEntity.Image{}, nil
+	}
+	if err != nil {
+		return productEntity.Image{}, fmt.Errorf("productPGRepository GetImageByID -> r.db.NewSelect(): %w", err)
+	}
+	return model.toEntity(), nil
+}
+
+func (r *productPGRepository) DeleteImage(ctx context.Context, imageID string) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().
+		Model((*ImageModel)(nil)).
+		Where("id = ?", imageID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository DeleteImage -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	reservationEntity "catalog/internal/domain/entities/reservation"
+	"context"
+	"time"
+)
+
+type ReservationRepository interface {
+	SaveReservations(ctx context.Context, reservations []reservationEntity.Reservation) error
+	UpdateReservations(ctx context.Context, reservations []reservationEntity.Reservation) error
+	GetByOrderIDForUpdate(ctx context.Context, orderID string) ([]reservationEntity.Reservation, error)
+	GetExpiredOrderIDs(ctx context.Context, now time.Time, limit int) ([]string, error)
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	reservationEntity "catalog/internal/domain/entities/reservation"
+	repository "catalog/internal/repositories/reservation"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+
+	pgStorage "shared/storage/pg"
+)
+
+var _ repository.ReservationRepository = (*reservationPGRepository)(nil)
+
+type ReservationModel struct {
+	bun.BaseModel `bun:"table:stock_reservations,alias:sr"`
+
+	OrderID   string    `bun:"order_id,pk"`
+	ProductID string    `bun:"product_id,pk"`
+	VariantID string    `bun:"variant_id,pk"`
+	Quantity  int       `bun:"quantity"`
+	Status    string    `bun:"status"`
+	ExpiresAt time.Time `bun:"expires_at"`
+	CreatedAt time.Time `bun:"created_at"`
+	UpdatedAt time.Time `bun:"updated_at"`
+}
+
+type reservationPGRepository struct {
+	db     *bun.DB
+	logger zerolog.Logger
+}
+
+func (m ReservationModel) toEntity() reservationEntity.Reservation {
+	return reservationEntity.NewReservationFromDatabase(
+		m.OrderID,
+		m.ProductID,
+		m.VariantID,
+		m.Quantity,
+		m.Status,
+		m.ExpiresAt,
+		m.CreatedAt,
+		m.UpdatedAt,
+	)
+}
+
+func toDB(r reservationEntity.Reservation) ReservationModel {
+	return ReservationModel{
+		OrderID:   r.OrderID(),
+		ProductID: r.ProductID(),
+		VariantID: r.VariantID(),
+		Quantity:  r.Quantity(),
+		Status:    r.Status(),
+		ExpiresAt: r.ExpiresAt(),
+		CreatedAt: r.CreatedAt(),
+		UpdatedAt: r.UpdatedAt(),
+	}
+}
+
+func NewReservationRepository(sql *bun.DB, logger zerolog.Logger) *reservationPGRepository {
+	return &reservationPGRepository{sql, logger}
+}
+
+func (r *reservationPGRepository) SaveReservations(ctx context.Context, reservations []reservationEntity.Reservation) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+	models := make([]ReservationModel, 0, len(reservations))
+	for _, reservation := range reservations {
+		models = append(models, toDB(reservation))
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().Model(&models).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("reservationPGRepository SaveReservations -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *reservationPGRepository) UpdateReservations(ctx context.Context, reservations []reservationEntity.Reservation) error {
+	db := pgStorage.Conn(ctx, r.db)
+	for _, reservation := range reservations {
+		model := toDB(reservation)
+		_, err := db.NewUpdate().
+			Model(&model).
+			Column("status", "updated_at").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("reservationPGRepository UpdateReservations -> r.db.NewUpdate(): %w", err)
+		}
+	}
+	return nil
+}
+
+// GetByOrderIDForUpdate locks the order's reservations until the surrounding
+// transaction ends. Rows are returned in product and variant order so that
+// concurrent callers lock products and variants in the same order.
+func (r *reservationPGRepository) GetByOrderIDForUpdate(ctx context.Context, orderID string) ([]reservationEntity.Reservation, error) {
+	var models []ReservationModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&models).
+		Where("order_id = ?", orderID).
+		Order("product_id", "variant_id").
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reservationPGRepository GetByOrderIDForUpdate -> r.db.NewSelect(): %w", err)
+	}
+	reservations := make([]reservationEntity.Reservation, 0, len(models))
+	for _, model := range models {
+		reservations = append(reservations, model.toEntity())
+	}
+	return reservations, nil
+}
+
+func (r *reservationPGRepository) GetExpiredOrderIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	var orderIDs []string
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model((*ReservationModel)(nil)).
+		ColumnExpr("DISTINCT order_id").
+		Where("status = ?", reservationEntity.StatusReserved).
+		Where("expires_at < ?", now).
+		Limit(limit).
+		Scan(ctx, &orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("reservationPGRepository GetExpiredOrderIDs -> r.db.NewSelect(): %w", err)
+	}
+	return orderIDs, nil
+}
@@ -0,0 +1,126 @@
+package applicationservices
+
+import (
+	attributeEntity "catalog/internal/domain/entities/attribute"
+	repository "catalog/internal/repositories/attribute"
+	"context"
+	"fmt"
+	pgStorage "shared/storage/pg"
+
+	"github.com/rs/zerolog"
+)
+
+var _ AttributeApplicationService = (*attributeApplicationService)(nil)
+
+// AttributeApplicationService manages the attributes that product and
+// variant attribute values are validated against.
+type AttributeApplicationService interface {
+	GetAttributes(ctx context.Context) ([]attributeEntity.Attribute, error)
+	CreateAttribute(ctx context.Context, params attributeEntity.CreateAttributeParams) (attributeEntity.Attribute, error)
+	UpdateAttribute(ctx context.Context, attributeID string, params attributeEntity.UpdateAttributeParams) (attributeEntity.Attribute, error)
+	DeleteAttribute(ctx context.Context, attributeID string) error
+}
+
+type attributeApplicationService struct {
+	attributeRepository repository.AttributeRepository
+	logger              zerolog.Logger
+	transactor          pgStorage.Transactor
+}
+
+func NewAttributeApplicationService(
+	attributeRepository repository.AttributeRepository,
+	logger zerolog.Logger,
+	transactor pgStorage.Transactor,
+) *attributeApplicationService {
+	return &attributeApplicationService{
+		attributeRepository: attributeRepository,
+		logger:              logger,
+		transactor:          transactor,
+	}
+}
+
+func (a attributeApplicationService) GetAttributes(ctx context.Context) ([]attributeEntity.Attribute, error) {
+	attributes, err := a.attributeRepository.GetAttributes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("attributeApplicationService -> GetAttributes - a.attributeRepository.GetAttributes: %w", err)
+	}
+	return attributes, nil
+}
+
+func (a attributeApplicationService) CreateAttribute(
+	ctx context.Context,
+	params attributeEntity.CreateAttributeParams,
+) (attributeEntity.Attribute, error) {
+	attribute, err := attributeEntity.NewAttribute(params)
+	if err != nil {
+		return attributeEntity.Attribute{}, fmt.Errorf("attributeApplicationService -> CreateAttribute - attributeEntity.NewAttribute: %w", err)
+	}
+
+	err = a.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		existing, err := a.attributeRepository.GetAttributeByCode(ctx, attribute.Code())
+		if err != nil {
+			return fmt.Errorf("attributeApplicationService -> CreateAttribute - a.attributeRepository.GetAttributeByCode: %w", err)
+		}
+		if !existing.IsZero() {
+			return attributeEntity.ErrAttributeCodeTaken
+		}
+		err = a.attributeRepository.SaveAttribute(ctx, attribute)
+		if err != nil {
+			return fmt.Errorf("attributeApplicationService -> CreateAttribute - a.attributeRepository.SaveAttribute: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return attributeEntity.Attribute{}, err
+	}
+	return attribute, nil
+}
+
+func (a attributeApplicationService) UpdateAttribute(
+	ctx context.Context,
+	attributeID string,
+	params attributeEntity.UpdateAttributeParams,
+) (attributeEntity.Attribute, error) {
+	attribute, err := a.attributeRepository.GetAttributeByID(ctx, attributeID)
+	if err != nil {
+		return attributeEntity.Attribute{}, fmt.Errorf("attributeApplicationService -> UpdateAttribute - a.attributeRepository.GetAttributeByID: %w", err)
+	}
+	if attribute.IsZero() {
+		return attributeEntity.Attribute{}, attributeEntity.ErrAttributeNotFound
+	}
+	attribute, err = attribute.Update(params)
+	if err != nil {
+		return attributeEntity.Attribute{}, fmt.Errorf("attributeApplicationService -> UpdateAttribute - attribute.Update: %w", err)
+	}
+	err = a.attributeRepository.UpdateAttribute(ctx, attribute)
+	if err != nil {
+		return attributeEntity.Attribute{}, fmt.Errorf("attributeApplicationService -> UpdateAttribute - a.attributeRepository.UpdateAttribute: %w", err)
+	}
+	return attribute, nil
+}
+
+// Attributes that products or variants still have a value for cannot be
+// deleted.
+func (a attributeApplicationService) DeleteAttribute(ctx context.Context, attributeID string) error {
+	return a.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		attribute, err := a.attributeRepository.GetAttributeByID(ctx, attributeID)
+		if err != nil {
+			return fmt.Errorf("attributeApplicationService -> DeleteAttribute - a.attributeRepository.GetAttributeByID: %w", err)
+		}
+		if attribute.IsZero() {
+			return attributeEntity.ErrAttributeNotFound
+		}
+		usages, err := a.attributeRepository.CountUsages(ctx, attribute.Code())
+		if err != nil {
+			return fmt.Errorf("attributeApplicationService -> DeleteAttribute - a.attributeRepository.CountUsages: %w", err)
+		}
+		if usages > 0 {
+			return attributeEntity.ErrAttributeInUse
+		}
+		err = a.attributeRepository.DeleteAttribute(ctx, attributeID)
+		if err != nil {
+			return fmt.Errorf("attributeApplicationService -> DeleteAttribute - a.attributeRepository.DeleteAttribute: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,142 @@
+package applicationservices
+
+import (
+	categoryEntity "catalog/internal/domain/entities/category"
+	repository "catalog/internal/repositories/category"
+	"context"
+	"fmt"
+	pgStorage "shared/storage/pg"
+
+	"github.com/rs/zerolog"
+)
+
+var _ CategoryApplicationService = (*categoryApplicationService)(nil)
+
+type CategoryApplicationService interface {
+	GetCategoryTree(ctx context.Context) ([]categoryEntity.Node, error)
+	CreateCategory(ctx context.Context, params categoryEntity.CreateCategoryParams) (categoryEntity.Category, error)
+	UpdateCategory(ctx context.Context, categoryID string, params categoryEntity.UpdateCategoryParams) (categoryEntity.Category, error)
+	DeleteCategory(ctx context.Context, categoryID string) error
+}
+
+type categoryApplicationService struct {
+	categoryRepository repository.CategoryRepository
+	logger             zerolog.Logger
+	transactor         pgStorage.Transactor
+}
+
+func NewCategoryApplicationService(
+	categoryRepository repository.CategoryRepository,
+	logger zerolog.Logger,
+	transactor pgStorage.Transactor,
+) *categoryApplicationService {
+	return &categoryApplicationService{
+		categoryRepository: categoryRepository,
+		logger:             logger,
+		transactor:         transactor,
+	}
+}
+
+func (c categoryApplicationService) GetCategoryTree(ctx context.Context) ([]categoryEntity.Node, error) {
+	categories, err := c.categoryRepository.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("categoryApplicationService -> GetCategoryTree - c.categoryRepository.GetCategories: %w", err)
+	}
+	return categoryEntity.BuildTree(categories), nil
+}
+
+func (c categoryApplicationService) CreateCategory(
+	ctx context.Context,
+	params categoryEntity.CreateCategoryParams,
+) (categoryEntity.Category, error) {
+	var category categoryEntity.Category
+	err := c.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		categories, err := c.categoryRepository.GetCategories(ctx)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> CreateCategory - c.categoryRepository.GetCategories: %w", err)
+		}
+		category, err = categoryEntity.NewCategory(params, categories)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> CreateCategory - categoryEntity.NewCategory: %w", err)
+		}
+		err = c.categoryRepository.SaveCategory(ctx, category)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> CreateCategory - c.categoryRepository.SaveCategory: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return categoryEntity.Category{}, err
+	}
+	return category, nil
+}
+
+// Renames the category or moves it, together with its subcategories, under
+// another parent.
+func (c categoryApplicationService) UpdateCategory(
+	ctx context.Context,
+	categoryID string,
+	params categoryEntity.UpdateCategoryParams,
+) (categoryEntity.Category, error) {
+	var category categoryEntity.Category
+	err := c.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		categories, err := c.categoryRepository.GetCategories(ctx)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> UpdateCategory - c.categoryRepository.GetCategories: %w", err)
+		}
+		category = findCategory(categories, categoryID)
+		if category.IsZero() {
+			return categoryEntity.ErrCategoryNotFound
+		}
+		category, err = category.Update(params, categories)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> UpdateCategory - category.Update: %w", err)
+		}
+		err = c.categoryRepository.UpdateCategory(ctx, category)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> UpdateCategory - c.categoryRepository.UpdateCategory: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return categoryEntity.Category{}, err
+	}
+	return category, nil
+}
+
+// Only categories without subcategories and products can be deleted.
+func (c categoryApplicationService) DeleteCategory(ctx context.Context, categoryID string) error {
+	return c.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		categories, err := c.categoryRepository.GetCategories(ctx)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> DeleteCategory - c.categoryRepository.GetCategories: %w", err)
+		}
+		if findCategory(categories, categoryID).IsZero() {
+			return categoryEntity.ErrCategoryNotFound
+		}
+		if len(categoryEntity.Subtree(categories, categoryID)) > 1 {
+			return categoryEntity.ErrCategoryNotEmpty
+		}
+		products, err := c.categoryRepository.CountProducts(ctx, categoryID)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> DeleteCategory - c.categoryRepository.CountProducts: %w", err)
+		}
+		if products > 0 {
+			return categoryEntity.ErrCategoryNotEmpty
+		}
+		err = c.categoryRepository.DeleteCategory(ctx, categoryID)
+		if err != nil {
+			return fmt.Errorf("categoryApplicationService -> DeleteCategory - c.categoryRepository.DeleteCategory: %w", err)
+		}
+		return nil
+	})
+}
+
+func findCategory(categories []categoryEntity.Category, categoryID string) categoryEntity.Category {
+	for _, category := range categories {
+		if category.ID() == categoryID {
+			return category
+		}
+	}
+	return categoryEntity.Category{}
+}
@@ -0,0 +1,132 @@
+package applicationservices
+
+import (
+	exchangeRateEntity "catalog/internal/domain/entities/exchangerate"
+	repository "catalog/internal/repositories/exchangerate"
+	"context"
+	"fmt"
+	"shared/events"
+	"shared/money"
+	"shared/outbox"
+	pgStorage "shared/storage/pg"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var _ ExchangeRateApplicationService = (*exchangeRateApplicationService)(nil)
+
+// ExchangeRateApplicationService manages the rates of the base currency that
+// other services use to show prices in the shopper's currency.
+type ExchangeRateApplicationService interface {
+	BaseCurrency() money.Currency
+	GetExchangeRates(ctx context.Context) ([]exchangeRateEntity.ExchangeRate, error)
+	SetExchangeRate(ctx context.Context, currency string, rate string) (exchangeRateEntity.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, currency string) error
+}
+
+type exchangeRateApplicationService struct {
+	exchangeRateRepository repository.ExchangeRateRepository
+	logger                 zerolog.Logger
+	transactor             pgStorage.Transactor
+	outbox                 outbox.Writer
+	baseCurrency           money.Currency
+}
+
+func NewExchangeRateApplicationService(
+	exchangeRateRepository repository.ExchangeRateRepository,
+	logger zerolog.Logger,
+	transactor pgStorage.Transactor,
+	outboxWriter outbox.Writer,
+	baseCurrency money.Currency,
+) *exchangeRateApplicationService {
+	return &exchangeRateApplicationService{
+		exchangeRateRepository: exchangeRateRepository,
+		logger:                 logger,
+		transactor:             transactor,
+		outbox:                 outboxWriter,
+		baseCurrency:           baseCurrency,
+	}
+}
+
+func (e exchangeRateApplicationService) BaseCurrency() money.Currency {
+	return e.baseCurrency
+}
+
+func (e exchangeRateApplicationService) GetExchangeRates(ctx context.Context) ([]exchangeRateEntity.ExchangeRate, error) {
+	rates, err := e.exchangeRateRepository.GetExchangeRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("exchangeRateApplicationService -> GetExchangeRates - e.exchangeRateRepository.GetExchangeRates: %w", err)
+	}
+	return rates, nil
+}
+
+// Creates or replaces the rate of currency and publishes it.
+func (e exchangeRateApplicationService) SetExchangeRate(
+	ctx context.Context,
+	currency string,
+	rate string,
+) (exchangeRateEntity.ExchangeRate, error) {
+	exchangeRate, err := exchangeRateEntity.NewExchangeRate(exchangeRateEntity.CreateExchangeRateParams{
+		BaseCurrency: e.baseCurrency,
+		Currency:     currency,
+		Rate:         rate,
+	})
+	if err != nil {
+		return exchangeRateEntity.ExchangeRate{}, fmt.Errorf("exchangeRateApplicationService -> SetExchangeRate - exchangeRateEntity.NewExchangeRate: %w", err)
+	}
+
+	err = e.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := e.exchangeRateRepository.SaveExchangeRate(ctx, exchangeRate)
+		if err != nil {
+			return fmt.Errorf("exchangeRateApplicationService -> SetExchangeRate - e.exchangeRateRepository.SaveExchangeRate: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, events.ExchangeRateUpdated{
+			BaseCurrency: e.baseCurrency,
+			Currency:     exchangeRate.Currency(),
+			Rate:         exchangeRate.Rate(),
+			UpdatedAt:    exchangeRate.UpdatedAt(),
+		})
+		if err != nil {
+			return err
+		}
+		err = e.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("exchangeRateApplicationService -> SetExchangeRate - e.outbox.Save: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return exchangeRateEntity.ExchangeRate{}, err
+	}
+	return exchangeRate, nil
+}
+
+func (e exchangeRateApplicationService) DeleteExchangeRate(ctx context.Context, currency string) error {
+	parsedCurrency, err := money.ParseCurrency(currency)
+	if err != nil {
+		return exchangeRateEntity.ErrInvalidCurrency
+	}
+
+	return e.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := e.exchangeRateRepository.DeleteExchangeRate(ctx, parsedCurrency)
+		if err != nil {
+			return fmt.Errorf("exchangeRateApplicationService -> DeleteExchangeRate - e.exchangeRateRepository.DeleteExchangeRate: %w", err)
+		}
+		if !deleted {
+			return exchangeRateEntity.ErrExchangeRateNotFound
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, events.ExchangeRateDeleted{
+			Currency:  parsedCurrency,
+			UpdatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		err = e.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("exchangeRateApplicationService -> DeleteExchangeRate - e.outbox.Save: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,174 @@
+package applicationservices
+
+import (
+	"bytes"
+	productEntity "catalog/internal/domain/entities/product"
+	"catalog/pkg/imaging"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"shared/events"
+)
+
+// Uploads an image of a product and renders its thumbnail
+func (u productApplicationService) UploadImage(ctx context.Context, actor productEntity.Actor, productID string, data []byte) (productEntity.Image, error) {
+	img, format, err := imaging.Decode(data)
+	if errors.Is(err, image.ErrFormat) {
+		return productEntity.Image{}, productEntity.ErrUnsupportedImageType
+	}
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return productEntity.Image{}, productEntity.ErrImageTooLarge
+	}
+	if err != nil {
+		return productEntity.Image{}, productEntity.ErrInvalidImage
+	}
+	var thumbnail bytes.Buffer
+	thumbnailContentType, err := imaging.Encode(&thumbnail, imaging.Thumbnail(img, u.thumbnailSize), format)
+	if err != nil {
+		return productEntity.Image{}, fmt.Errorf("productApplicationService -> UploadImage - imaging.Encode: %w", err)
+	}
+	productImage, err := productEntity.NewImage(productEntity.CreateImageParams{
+		ProductID:            productID,
+		ContentType:          "image/" + format,
+		Width:                img.Bounds().Dx(),
+		Height:               img.Bounds().Dy(),
+		Size:                 int64(len(data)),
+		ThumbnailContentType: thumbnailContentType,
+	})
+	if err != nil {
+		return productEntity.Image{}, fmt.Errorf("productApplicationService -> UploadImage - productEntity.NewImage: %w", err)
+	}
+
+	product, err := u.productRepository.GetProductByID(ctx, productID)
+	if err != nil {
+		return productEntity.Image{}, fmt.Errorf("productApplicationService -> UploadImage - u.productRepository.GetProductByID: %w", err)
+	}
+	err = authorize(product, actor)
+	if err != nil {
+		return productEntity.Image{}, err
+	}
+
+	err = u.store.Put(ctx, productImage.Key(), bytes.NewReader(data), productImage.ContentType())
+	if err != nil {
+		return productEntity.Image{}, fmt.Errorf("productApplicationService -> UploadImage - u.store.Put: %w", err)
+	}
+	err = u.store.Put(ctx, productImage.ThumbnailKey(), &thumbnail, thumbnailContentType)
+	if err != nil {
+		u.deleteBlobs(ctx, productImage.Key())
+		return productEntity.Image{}, fmt.Errorf("productApplicationService -> UploadImage - u.store.Put: %w", err)
+	}
+	productImage = productImage.WithURLs(u.store.URL(productImage.Key()), u.store.URL(productImage.ThumbnailKey()))
+
+	err = u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		product, err := u.productRepository.GetProductByIDForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UploadImage - u.productRepository.GetProductByIDForUpdate: %w", err)
+		}
+		err = authorize(product, actor)
+		if err != nil {
+			return err
+		}
+		err = u.productRepository.SaveImage(ctx, productImage)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UploadImage - u.productRepository.SaveImage: %w", err)
+		}
+		product = product.WithImages(append(product.Images(), productImage))
+		message, err := events.NewOutboxMessage(ctx, producerName, productUpdatedEvent(product))
+		if err != nil {
+			return err
+		}
+		err = u.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UploadImage - u.outbox.Save: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		u.deleteBlobs(ctx, productImage.Key(), productImage.ThumbnailKey())
+		return productEntity.Image{}, err
+	}
+	return productImage, nil
+}
+
+// Fetches the images of a product
+func (u productApplicationService) GetImages(ctx context.Context, productID string) ([]productEntity.Image, error) {
+	product, err := u.productRepository.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("productApplicationService -> GetImages - u.productRepository.GetProductByID: %w", err)
+	}
+	if product.IsZero() {
+		return nil, productEntity.ErrProductNotFound
+	}
+	return product.Images(), nil
+}
+
+// Deletes an image of a product
+func (u productApplicationService) DeleteImage(ctx context.Context, actor productEntity.Actor, productID string, imageID string) error {
+	var image productEntity.Image
+	err := u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		product, err := u.productRepository.GetProductByIDForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteImage - u.productRepository.GetProductByIDForUpdate: %w", err)
+		}
+		err = authorize(product, actor)
+		if err != nil {
+			return err
+		}
+		images := make([]productEntity.Image, 0, len(product.Images()))
+		for _, i := range product.Images() {
+			if i.ID() == imageID {
+				image = i
+				continue
+			}
+			images = append(images, i)
+		}
+		if image.IsZero() {
+			return productEntity.ErrImageNotFound
+		}
+		err = u.productRepository.DeleteImage(ctx, imageID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteImage - u.productRepository.DeleteImage: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, productUpdatedEvent(product.WithImages(images)))
+		if err != nil {
+			return err
+		}
+		err = u.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteImage - u.outbox.Save: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	u.deleteBlobs(ctx, image.Key(), image.ThumbnailKey())
+	return nil
+}
+
+// deleteBlobs removes stored objects once their rows are gone. Failures are
+// only logged, an orphaned object does no harm.
+func (u productApplicationService) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		err := u.store.Delete(ctx, key)
+		if err != nil {
+			u.logger.Error().Err(err).Str("key", key).Msg("failed to delete blob")
+		}
+	}
+}
+
+func imagesEvent(images []productEntity.Image) []events.ProductImage {
+	if len(images) == 0 {
+		return nil
+	}
+	result := make([]events.ProductImage, 0, len(images))
+	for _, image := range images {
+		result = append(result, events.ProductImage{
+			ID:           image.ID(),
+			URL:          image.URL(),
+			ThumbnailURL: image.ThumbnailURL(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,320 @@
+package applicationservices
+
+import (
+	productEntity "catalog/internal/domain/entities/product"
+	reservationEntity "catalog/internal/domain/entities/reservation"
+	productRepository "catalog/internal/repositories/product"
+	reservationRepository "catalog/internal/repositories/reservation"
+	"context"
+	"errors"
+	"fmt"
+	"shared/events"
+	"shared/outbox"
+	pgStorage "shared/storage/pg"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const expiredReservationsBatchSize = 100
+
+var _ InventoryApplicationService = (*inventoryApplicationService)(nil)
+
+type InventoryApplicationService interface {
+	ReserveStock(ctx context.Context, orderID string, items []reservationEntity.Item) error
+	ConfirmReservation(ctx context.Context, orderID string) error
+	ReleaseReservation(ctx context.Context, orderID string, reason string) error
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+}
+
+type inventoryApplicationService struct {
+	productRepository     productRepository.ProductRepository
+	reservationRepository reservationRepository.ReservationRepository
+	logger                zerolog.Logger
+	transactor            pgStorage.Transactor
+	outbox                outbox.Writer
+	reservationTTL        time.Duration
+}
+
+func NewInventoryApplicationService(
+	productRepository productRepository.ProductRepository,
+	reservationRepository reservationRepository.ReservationRepository,
+	logger zerolog.Logger,
+	transactor pgStorage.Transactor,
+	outboxWriter outbox.Writer,
+	reservationTTL time.Duration,
+) *inventoryApplicationService {
+	return &inventoryApplicationService{
+		productRepository:     productRepository,
+		reservationRepository: reservationRepository,
+		logger:                logger,
+		transactor:            transactor,
+		outbox:                outboxWriter,
+		reservationTTL:        reservationTTL,
+	}
+}
+
+// Reserves stock for every item of the order or for none of them. A failed
+// reservation is not an error: it is reported to the orders service with
+// inventory.stock_reservation_failed.
+func (i inventoryApplicationService) ReserveStock(
+	ctx context.Context,
+	orderID string,
+	items []reservationEntity.Item,
+) error {
+	items = mergeItems(items)
+
+	return i.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		existing, err := i.reservationRepository.GetByOrderIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("inventoryApplicationService -> ReserveStock - i.reservationRepository.GetByOrderIDForUpdate: %w", err)
+		}
+		if len(existing) > 0 {
+			i.logger.Info().Str("orderID", orderID).Msg("inventoryApplicationService -> ReserveStock - stock already reserved")
+			return nil
+		}
+
+		products := make([]productEntity.Product, 0, len(items))
+		variants := make([]productEntity.Variant, 0, len(items))
+		reservations := make([]reservationEntity.Reservation, 0, len(items))
+		for _, item := range items {
+			failed := events.StockReservationFailed{
+				OrderID:   orderID,
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+			}
+			if item.VariantID != "" {
+				variant, err := i.productRepository.GetVariantByIDForUpdate(ctx, item.VariantID)
+				if err != nil {
+					return fmt.Errorf("inventoryApplicationService -> ReserveStock - i.productRepository.GetVariantByIDForUpdate: %w", err)
+				}
+				if variant.IsZero() || variant.ProductID() != item.ProductID {
+					failed.Reason = events.ReasonProductNotFound
+					return i.saveEvents(ctx, failed)
+				}
+				variant, err = variant.ReserveStock(item.Quantity)
+				if errors.Is(err, productEntity.ErrInsufficientStock) {
+					failed.Reason = events.ReasonInsufficientStock
+					return i.saveEvents(ctx, failed)
+				}
+				if err != nil {
+					return fmt.Errorf("inventoryApplicationService -> ReserveStock - variant.ReserveStock: %w", err)
+				}
+				variants = append(variants, variant)
+			} else {
+				product, err := i.productRepository.GetProductByIDForUpdate(ctx, item.ProductID)
+				if err != nil {
+					return fmt.Errorf("inventoryApplicationService -> ReserveStock - i.productRepository.GetProductByIDForUpdate: %w", err)
+				}
+				if product.IsZero() {
+					failed.Reason = events.ReasonProductNotFound
+					return i.saveEvents(ctx, failed)
+				}
+				product, err = product.ReserveStock(item.Quantity)
+				if errors.Is(err, productEntity.ErrInsufficientStock) {
+					failed.Reason = events.ReasonInsufficientStock
+					return i.saveEvents(ctx, failed)
+				}
+				if err != nil {
+					return fmt.Errorf("inventoryApplicationService -> ReserveStock - product.ReserveStock: %w", err)
+				}
+				products = append(products, product)
+			}
+			reservation, err := reservationEntity.NewReservation(reservationEntity.CreateReservationParams{
+				OrderID:   orderID,
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  item.Quantity,
+				TTL:       i.reservationTTL,
+			})
+			if err != nil {
+				return fmt.Errorf("inventoryApplicationService -> ReserveStock - reservationEntity.NewReservation: %w", err)
+			}
+			reservations = append(reservations, reservation)
+		}
+
+		for _, product := range products {
+			err := i.productRepository.UpdateProductByID(ctx, product)
+			if err != nil {
+				return fmt.Errorf("inventoryApplicationService -> ReserveStock - i.productRepository.UpdateProductByID: %w", err)
+			}
+		}
+		for _, variant := range variants {
+			err := i.productRepository.UpdateVariant(ctx, variant)
+			if err != nil {
+				return fmt.Errorf("inventoryApplicationService -> ReserveStock - i.productRepository.UpdateVariant: %w", err)
+			}
+		}
+		err = i.reservationRepository.SaveReservations(ctx, reservations)
+		if err != nil {
+			return fmt.Errorf("inventoryApplicationService -> ReserveStock - i.reservationRepository.SaveReservations: %w", err)
+		}
+
+		stockEvents := make([]events.Event, 0, len(products)+len(variants)+1)
+		for _, product := range products {
+			stockEvents = append(stockEvents, productUpdatedEvent(product))
+		}
+		for _, variant := range variants {
+			stockEvents = append(stockEvents, variantUpdatedEvent(variant))
+		}
+		var expiresAt time.Time
+		if len(reservations) > 0 {
+			expiresAt = reservations[0].ExpiresAt()
+		}
+		stockEvents = append(stockEvents, events.StockReserved{OrderID: orderID, ExpiresAt: expiresAt})
+		return i.saveEvents(ctx, stockEvents...)
+	})
+}
+
+// Makes the order's active reservations permanent. Reservations that were
+// already released, e.g. because they expired, stay released.
+func (i inventoryApplicationService) ConfirmReservation(ctx context.Context, orderID string) error {
+	return i.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		reservations, err := i.reservationRepository.GetByOrderIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("inventoryApplicationService -> ConfirmReservation - i.reservationRepository.GetByOrderIDForUpdate: %w", err)
+		}
+
+		confirmed := make([]reservationEntity.Reservation, 0, len(reservations))
+		for _, reservation := range reservations {
+			if !reservation.IsActive() {
+				continue
+			}
+			reservation, err = reservation.Confirm()
+			if err != nil {
+				return fmt.Errorf("inventoryApplicationService -> ConfirmReservation - reservation.Confirm: %w", err)
+			}
+			confirmed = append(confirmed, reservation)
+		}
+		if len(confirmed) == 0 {
+			i.logger.Warn().Str("orderID", orderID).Msg("inventoryApplicationService -> ConfirmReservation - no active reservations")
+			return nil
+		}
+
+		err = i.reservationRepository.UpdateReservations(ctx, confirmed)
+		if err != nil {
+			return fmt.Errorf("inventoryApplicationService -> ConfirmReservation - i.reservationRepository.UpdateReservations: %w", err)
+		}
+		return nil
+	})
+}
+
+// Returns the stock of the order's active reservations.
+func (i inventoryApplicationService) ReleaseReservation(ctx context.Context, orderID string, reason string) error {
+	return i.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		reservations, err := i.reservationRepository.GetByOrderIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("inventoryApplicationService -> ReleaseReservation - i.reservationRepository.GetByOrderIDForUpdate: %w", err)
+		}
+
+		released := make([]reservationEntity.Reservation, 0, len(reservations))
+		stockEvents := make([]events.Event, 0, len(reservations)+1)
+		for _, reservation := range reservations {
+			if !reservation.IsActive() {
+				continue
+			}
+			reservation, err = reservation.Release()
+			if err != nil {
+				return fmt.Errorf("inventoryApplicationService -> ReleaseReservation - reservation.Release: %w", err)
+			}
+			released = append(released, reservation)
+
+			if reservation.VariantID() != "" {
+				variant, err := i.productRepository.GetVariantByIDForUpdate(ctx, reservation.VariantID())
+				if err != nil {
+					return fmt.Errorf("inventoryApplicationService -> ReleaseReservation - i.productRepository.GetVariantByIDForUpdate: %w", err)
+				}
+				if variant.IsZero() {
+					continue
+				}
+				variant = variant.ReleaseStock(reservation.Quantity())
+				err = i.productRepository.UpdateVariant(ctx, variant)
+				if err != nil {
+					return fmt.Errorf("inventoryApplicationService -> ReleaseReservation - i.productRepository.UpdateVariant: %w", err)
+				}
+				stockEvents = append(stockEvents, variantUpdatedEvent(variant))
+				continue
+			}
+
+			product, err := i.productRepository.GetProductByIDForUpdate(ctx, reservation.ProductID())
+			if err != nil {
+				return fmt.Errorf("inventoryApplicationService -> ReleaseReservation - i.productRepository.GetProductByIDForUpdate: %w", err)
+			}
+			// the product was deleted in the meantime, there is nothing to return the stock to
+			if product.IsZero() {
+				continue
+			}
+			product = product.ReleaseStock(reservation.Quantity())
+			err = i.productRepository.UpdateProductByID(ctx, product)
+			if err != nil {
+				return fmt.Errorf("inventoryApplicationService -> ReleaseReservation - i.productRepository.UpdateProductByID: %w", err)
+			}
+			stockEvents = append(stockEvents, productUpdatedEvent(product))
+		}
+		if len(released) == 0 {
+			return nil
+		}
+
+		err = i.reservationRepository.UpdateReservations(ctx, released)
+		if err != nil {
+			return fmt.Errorf("inventoryApplicationService -> ReleaseReservation - i.reservationRepository.UpdateReservations: %w", err)
+		}
+		stockEvents = append(stockEvents, events.StockReleased{OrderID: orderID, Reason: reason})
+		return i.saveEvents(ctx, stockEvents...)
+	})
+}
+
+// Releases a batch of reservations whose orders were neither confirmed nor
+// cancelled in time and returns the number of affected orders.
+func (i inventoryApplicationService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	orderIDs, err := i.reservationRepository.GetExpiredOrderIDs(ctx, time.Now().UTC(), expiredReservationsBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("inventoryApplicationService -> ReleaseExpiredReservations - i.reservationRepository.GetExpiredOrderIDs: %w", err)
+	}
+	for n, orderID := range orderIDs {
+		err := i.ReleaseReservation(ctx, orderID, events.ReasonReservationExpired)
+		if err != nil {
+			return n, fmt.Errorf("inventoryApplicationService -> ReleaseExpiredReservations - i.ReleaseReservation: %w", err)
+		}
+	}
+	return len(orderIDs), nil
+}
+
+func (i inventoryApplicationService) saveEvents(ctx context.Context, stockEvents ...events.Event) error {
+	messages := make([]outbox.Message, 0, len(stockEvents))
+	for _, event := range stockEvents {
+		message, err := events.NewOutboxMessage(ctx, producerName, event)
+		if err != nil {
+			return fmt.Errorf("inventoryApplicationService -> saveEvents - events.NewOutboxMessage: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	err := i.outbox.Save(ctx, messages...)
+	if err != nil {
+		return fmt.Errorf("inventoryApplicationService -> saveEvents - i.outbox.Save: %w", err)
+	}
+	return nil
+}
+
+// mergeItems sums quantities of the same product and variant and sorts items
+// by product and variant so that concurrent reservations lock rows in the
+// same order.
+func mergeItems(items []reservationEntity.Item) []reservationEntity.Item {
+	type key struct{ productID, variantID string }
+	quantities := make(map[key]int, len(items))
+	for _, item := range items {
+		quantities[key{item.ProductID, item.VariantID}] += item.Quantity
+	}
+	merged := make([]reservationEntity.Item, 0, len(quantities))
+	for k, quantity := range quantities {
+		merged = append(merged, reservationEntity.Item{ProductID: k.productID, VariantID: k.variantID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(a, b int) bool {
+		if merged[a].ProductID != merged[b].ProductID {
+			return merged[a].ProductID < merged[b].ProductID
+		}
+		return merged[a].VariantID < merged[b].VariantID
+	})
+	return merged
+}
@@ -1,15 +1,18 @@
 package applicationservices
 
 import (
+	attributeEntity "catalog/internal/domain/entities/attribute"
+	categoryEntity "catalog/internal/domain/entities/category"
 	productEntity "catalog/internal/domain/entities/product"
+	attributeRepository "catalog/internal/repositories/attribute"
+	categoryRepository "catalog/internal/repositories/category"
 	repository "catalog/internal/repositories/product"
 	"context"
-	"encoding/json"
 	"fmt"
-	natsClient "shared/messaging/nats"
-	"time"
-
-	customErrors "shared/errors"
+	"shared/events"
+	"shared/outbox"
+	"shared/storage/blob"
+	pgStorage "shared/storage/pg"
 
 	"github.com/rs/zerolog"
 )
@@ -17,55 +20,52 @@ import (
 var _ ProductApplicationService = (*productApplicationService)(nil)
 
 type productApplicationService struct {
-	productRepository repository.ProductRepository
-	logger            zerolog.Logger
-	natsClient        natsClient.NatsClient
-}
-
-type ProductCreatedEvent struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Price     float64   `json:"price"`
-	Quantity  int       `json:"quantity"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
-
-type ProductDeletedEvent struct {
-	ID string `json:"id"`
+	productRepository   repository.ProductRepository
+	categoryRepository  categoryRepository.CategoryRepository
+	attributeRepository attributeRepository.AttributeRepository
+	logger              zerolog.Logger
+	transactor          pgStorage.Transactor
+	outbox              outbox.Writer
+	store               blob.Store
+	thumbnailSize       int
 }
 
-type ProductUpdatedEvent struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Price     float64   `json:"price"`
-	Quantity  int       `json:"quantity"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
-
-const (
-	productCreatedSubject             = "products.created"
-	productDeletedSubject             = "products.deleted"
-	productUpdatedSubject             = "products.updated"
-	productCreatedDurableConsumerName = "cart-product-created"
-	productStream                     = "products"
-)
+const producerName = "catalog"
 
 type ProductApplicationService interface {
 	CreateProduct(ctx context.Context, createProductParams productEntity.CreateProductParams) error
-	GetProducts(ctx context.Context) ([]productEntity.Product, error)
+	GetProducts(ctx context.Context, params productEntity.ListProductsParams) (productEntity.Page, error)
 	GetProductByID(ctx context.Context, productID string) (productEntity.Product, error)
-	DeleteProductByID(ctx context.Context, productID string) error
-	UpdateProductByID(ctx context.Context, productID string, productParams productEntity.UpdateProductParams) error
+	DeleteProductByID(ctx context.Context, actor productEntity.Actor, productID string) error
+	UpdateProductByID(ctx context.Context, actor productEntity.Actor, productID string, productParams productEntity.UpdateProductParams) error
+	CreateVariant(ctx context.Context, actor productEntity.Actor, variantParams productEntity.CreateVariantParams) (productEntity.Variant, error)
+	UpdateVariant(ctx context.Context, actor productEntity.Actor, productID string, variantID string, variantParams productEntity.UpdateVariantParams) (productEntity.Variant, error)
+	DeleteVariant(ctx context.Context, actor productEntity.Actor, productID string, variantID string) error
+	UploadImage(ctx context.Context, actor productEntity.Actor, productID string, data []byte) (productEntity.Image, error)
+	GetImages(ctx context.Context, productID string) ([]productEntity.Image, error)
+	DeleteImage(ctx context.Context, actor productEntity.Actor, productID string, imageID string) error
 }
 
 func NewProductApplicationService(
 	productRepository repository.ProductRepository,
+	categoryRepository categoryRepository.CategoryRepository,
+	attributeRepository attributeRepository.AttributeRepository,
 	logger zerolog.Logger,
-	nats natsClient.NatsClient,
+	transactor pgStorage.Transactor,
+	outboxWriter outbox.Writer,
+	store blob.Store,
+	thumbnailSize int,
 ) *productApplicationService {
-	return &productApplicationService{productRepository: productRepository, logger: logger, natsClient: nats}
+	return &productApplicationService{
+		productRepository:   productRepository,
+		categoryRepository:  categoryRepository,
+		attributeRepository: attributeRepository,
+		logger:              logger,
+		transactor:          transactor,
+		outbox:              outboxWriter,
+		store:               store,
+		thumbnailSize:       thumbnailSize,
+	}
 }
 
 // Creates a product
@@ -77,38 +77,52 @@ func (u productApplicationService) CreateProduct(
 	if err != nil {
 		return fmt.Errorf("productApplicationService -> CreateProduct - productEntity.NewProduct: %w", err)
 	}
-
-	err = u.productRepository.SaveProduct(ctx, product)
+	err = u.validateReferences(ctx, product.CategoryID(), product.Attributes())
 	if err != nil {
-		return fmt.Errorf("productApplicationService -> CreateProduct -  u.productRepository.Sav: %w", err)
+		return fmt.Errorf("productApplicationService -> CreateProduct - u.validateReferences: %w", err)
 	}
 
-	bytes, err := json.Marshal(ProductCreatedEvent{
-		ID:        product.ID(),
-		Name:      product.Name(),
-		Price:     product.Price(),
-		Quantity:  product.Quantity(),
-		CreatedAt: product.CreatedAt(),
-		UpdatedAt: product.UpdatedAt(),
-	})
-	if err != nil {
-		return err
-	}
-	u.natsClient.PublishMessage(productCreatedSubject, string(bytes))
+	return u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := u.productRepository.SaveProduct(ctx, product)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> CreateProduct -  u.productRepository.Sav: %w", err)
+		}
 
-	return nil
+		message, err := events.NewOutboxMessage(ctx, producerName, events.ProductCreated{
+			ID:        product.ID(),
+			Name:      product.Name(),
+			SellerID:  product.SellerID(),
+			Price:     product.Price(),
+			Quantity:  product.Quantity(),
+			CreatedAt: product.CreatedAt(),
+			UpdatedAt: product.UpdatedAt(),
+		})
+		if err != nil {
+			return err
+		}
+		err = u.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> CreateProduct - u.outbox.Save: %w", err)
+		}
+		return nil
+	})
 }
 
-// Fetches a list of products
+// Fetches a page of products
 func (u productApplicationService) GetProducts(
 	ctx context.Context,
-) ([]productEntity.Product, error) {
-	products, err := u.productRepository.GetProducts(ctx)
+	params productEntity.ListProductsParams,
+) (productEntity.Page, error) {
+	query, err := productEntity.NewListProductsQuery(params)
 	if err != nil {
-		return nil, fmt.Errorf("productApplicationService -> GetProducts - u.productRepository.GetProducts: %w", err)
+		return productEntity.Page{}, fmt.Errorf("productApplicationService -> GetProducts - productEntity.NewListProductsQuery: %w", err)
+	}
+	page, err := u.productRepository.GetProducts(ctx, query)
+	if err != nil {
+		return productEntity.Page{}, fmt.Errorf("productApplicationService -> GetProducts - u.productRepository.GetProducts: %w", err)
 	}
 
-	return products, nil
+	return page, nil
 }
 
 // Fetches a product by ID
@@ -118,7 +132,7 @@ func (u productApplicationService) GetProductByID(
 ) (productEntity.Product, error) {
 	product, err := u.productRepository.GetProductByID(ctx, productID)
 	if product.IsZero() {
-		return productEntity.Product{}, customErrors.NewNotFoundError("products/not_found", "product not found")
+		return productEntity.Product{}, productEntity.ErrProductNotFound
 	}
 	if err != nil {
 		return productEntity.Product{}, fmt.Errorf("productApplicationService -> GetProducts - u.productRepository.GetProducts: %w", err)
@@ -130,51 +144,302 @@ func (u productApplicationService) GetProductByID(
 // Deletes product by ID
 func (u productApplicationService) DeleteProductByID(
 	ctx context.Context,
+	actor productEntity.Actor,
 	productID string,
 ) error {
-	err := u.productRepository.DeleteProductByID(ctx, productID)
-	if err != nil {
-		return fmt.Errorf("productApplicationService -> DeleteProductByID - u.productRepository.DeleteProductByID: %w", err)
-	}
-	bytes, err := json.Marshal(ProductDeletedEvent{
-		ID: productID,
+	var images []productEntity.Image
+	err := u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		product, err := u.productRepository.GetProductByIDForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteProductByID - u.productRepository.GetProductByIDForUpdate: %w", err)
+		}
+		err = authorize(product, actor)
+		if err != nil {
+			return err
+		}
+		images = product.Images()
+		err = u.productRepository.DeleteProductByID(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteProductByID - u.productRepository.DeleteProductByID: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, events.ProductDeleted{
+			ID: productID,
+		})
+		if err != nil {
+			return err
+		}
+		err = u.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteProductByID - u.outbox.Save: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
 		return err
 	}
-	u.natsClient.PublishMessage(productDeletedSubject, string(bytes))
+	for _, image := range images {
+		u.deleteBlobs(ctx, image.Key(), image.ThumbnailKey())
+	}
 	return nil
 }
 
-// Updates product by ID
-func (u productApplicationService) UpdateProductByID(ctx context.Context, productID string, productParams productEntity.UpdateProductParams) error {
-	product, err := u.productRepository.GetProductByID(ctx, productID)
-	if product.IsZero() {
-		return customErrors.NewNotFoundError("products/not_found", "product not found")
+// Updates product by ID. The row is locked while it is rewritten so that
+// stock reserved in the meantime is not overwritten.
+func (u productApplicationService) UpdateProductByID(ctx context.Context, actor productEntity.Actor, productID string, productParams productEntity.UpdateProductParams) error {
+	return u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		product, err := u.productRepository.GetProductByIDForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateProductByID - u.productRepository.GetProductByIDForUpdate: %w", err)
+		}
+		err = authorize(product, actor)
+		if err != nil {
+			return err
+		}
+		product, err = product.Update(productParams)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateProductByID - product.Update: %w", err)
+		}
+		err = u.validateReferences(ctx, product.CategoryID(), productParams.Attributes)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateProductByID - u.validateReferences: %w", err)
+		}
+		err = u.productRepository.UpdateProductByID(ctx, product)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateProductByID - u.productRepository.UpdateProductByID: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, productUpdatedEvent(product))
+		if err != nil {
+			return err
+		}
+		err = u.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateProductByID - u.outbox.Save: %w", err)
+		}
+		return nil
+	})
+}
+
+func productUpdatedEvent(product productEntity.Product) events.ProductUpdated {
+	return events.ProductUpdated{
+		ID:        product.ID(),
+		Name:      product.Name(),
+		SellerID:  product.SellerID(),
+		Price:     product.Price(),
+		Quantity:  product.Quantity(),
+		CreatedAt: product.CreatedAt(),
+		UpdatedAt: product.UpdatedAt(),
+		Images:    imagesEvent(product.Images()),
 	}
+}
+
+// Creates a variant of an existing product
+func (u productApplicationService) CreateVariant(
+	ctx context.Context,
+	actor productEntity.Actor,
+	variantParams productEntity.CreateVariantParams,
+) (productEntity.Variant, error) {
+	variant, err := productEntity.NewVariant(variantParams)
 	if err != nil {
-		return fmt.Errorf("productApplicationService -> UpdateProductByID - u.productRepository.GetProductByID: %w", err)
+		return productEntity.Variant{}, fmt.Errorf("productApplicationService -> CreateVariant - productEntity.NewVariant: %w", err)
 	}
-	product, err = product.Update(productParams)
+	err = u.validateReferences(ctx, "", variant.Attributes())
 	if err != nil {
-		return fmt.Errorf("productApplicationService -> UpdateProductByID - product.Update: %w", err)
+		return productEntity.Variant{}, fmt.Errorf("productApplicationService -> CreateVariant - u.validateReferences: %w", err)
 	}
 
-	err = u.productRepository.UpdateProductByID(ctx, product)
+	err = u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		product, err := u.productRepository.GetProductByIDForUpdate(ctx, variant.ProductID())
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> CreateVariant - u.productRepository.GetProductByIDForUpdate: %w", err)
+		}
+		err = authorize(product, actor)
+		if err != nil {
+			return err
+		}
+		err = u.checkSKU(ctx, variant)
+		if err != nil {
+			return err
+		}
+		err = u.productRepository.SaveVariant(ctx, variant)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> CreateVariant - u.productRepository.SaveVariant: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, events.ProductVariantCreated{
+			ID:         variant.ID(),
+			ProductID:  variant.ProductID(),
+			SKU:        variant.SKU(),
+			Attributes: variant.Attributes(),
+			Price:      variant.Price(),
+			Quantity:   variant.Quantity(),
+			CreatedAt:  variant.CreatedAt(),
+			UpdatedAt:  variant.UpdatedAt(),
+		})
+		if err != nil {
+			return err
+		}
+		err = u.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> CreateVariant - u.outbox.Save: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("productApplicationService -> UpdateProductByID - u.productRepository.UpdateProductByID: %w", err)
+		return productEntity.Variant{}, err
 	}
-	bytes, err := json.Marshal(ProductUpdatedEvent{
-		ID:        productID,
-		Name:      product.Name(),
-		Price:     product.Price(),
-		Quantity:  product.Quantity(),
-		CreatedAt: product.CreatedAt(),
-		UpdatedAt: product.UpdatedAt(),
+	return variant, nil
+}
+
+// Updates a variant of a product
+func (u productApplicationService) UpdateVariant(
+	ctx context.Context,
+	actor productEntity.Actor,
+	productID string,
+	variantID string,
+	variantParams productEntity.UpdateVariantParams,
+) (productEntity.Variant, error) {
+	err := u.validateReferences(ctx, "", variantParams.Attributes)
+	if err != nil {
+		return productEntity.Variant{}, fmt.Errorf("productApplicationService -> UpdateVariant - u.validateReferences: %w", err)
+	}
+
+	var variant productEntity.Variant
+	err = u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		product, err := u.productRepository.GetProductByIDForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateVariant - u.productRepository.GetProductByIDForUpdate: %w", err)
+		}
+		err = authorize(product, actor)
+		if err != nil {
+			return err
+		}
+		variant, err = u.productRepository.GetVariantByIDForUpdate(ctx, variantID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateVariant - u.productRepository.GetVariantByIDForUpdate: %w", err)
+		}
+		if variant.IsZero() || variant.ProductID() != productID {
+			return productEntity.ErrVariantNotFound
+		}
+		variant, err = variant.Update(variantParams)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateVariant - variant.Update: %w", err)
+		}
+		err = u.checkSKU(ctx, variant)
+		if err != nil {
+			return err
+		}
+		err = u.productRepository.UpdateVariant(ctx, variant)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateVariant - u.productRepository.UpdateVariant: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, variantUpdatedEvent(variant))
+		if err != nil {
+			return err
+		}
+		err = u.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> UpdateVariant - u.outbox.Save: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
-		return err
+		return productEntity.Variant{}, err
+	}
+	return variant, nil
+}
+
+// Deletes a variant of a product
+func (u productApplicationService) DeleteVariant(ctx context.Context, actor productEntity.Actor, productID string, variantID string) error {
+	return u.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		product, err := u.productRepository.GetProductByIDForUpdate(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteVariant - u.productRepository.GetProductByIDForUpdate: %w", err)
+		}
+		err = authorize(product, actor)
+		if err != nil {
+			return err
+		}
+		variant, err := u.productRepository.GetVariantByIDForUpdate(ctx, variantID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteVariant - u.productRepository.GetVariantByIDForUpdate: %w", err)
+		}
+		if variant.IsZero() || variant.ProductID() != productID {
+			return productEntity.ErrVariantNotFound
+		}
+		err = u.productRepository.DeleteVariant(ctx, variantID)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteVariant - u.productRepository.DeleteVariant: %w", err)
+		}
+		message, err := events.NewOutboxMessage(ctx, producerName, events.ProductVariantDeleted{
+			ID:        variantID,
+			ProductID: productID,
+		})
+		if err != nil {
+			return err
+		}
+		err = u.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> DeleteVariant - u.outbox.Save: %w", err)
+		}
+		return nil
+	})
+}
+
+// authorize checks that the product exists and that the actor may change it.
+func authorize(product productEntity.Product, actor productEntity.Actor) error {
+	if product.IsZero() {
+		return productEntity.ErrProductNotFound
+	}
+	if !product.CanBeManagedBy(actor) {
+		return productEntity.ErrProductForbidden
+	}
+	return nil
+}
+
+// validateReferences checks that the category exists and that the attribute
+// values are valid. An empty category ID and nil attributes are not checked.
+func (u productApplicationService) validateReferences(ctx context.Context, categoryID string, attributes map[string]string) error {
+	if categoryID != "" {
+		categories, err := u.categoryRepository.GetCategories(ctx)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> validateReferences - u.categoryRepository.GetCategories: %w", err)
+		}
+		if findCategory(categories, categoryID).IsZero() {
+			return categoryEntity.ErrCategoryNotFound
+		}
+	}
+	if len(attributes) > 0 {
+		definitions, err := u.attributeRepository.GetAttributes(ctx)
+		if err != nil {
+			return fmt.Errorf("productApplicationService -> validateReferences - u.attributeRepository.GetAttributes: %w", err)
+		}
+		err = attributeEntity.ValidateValues(definitions, attributes)
+		if err != nil {
+			return err
+		}
 	}
-	u.natsClient.PublishMessage(productUpdatedSubject, string(bytes))
 	return nil
 }
+
+func (u productApplicationService) checkSKU(ctx context.Context, variant productEntity.Variant) error {
+	existing, err := u.productRepository.GetVariantBySKU(ctx, variant.SKU())
+	if err != nil {
+		return fmt.Errorf("productApplicationService -> checkSKU - u.productRepository.GetVariantBySKU: %w", err)
+	}
+	if !existing.IsZero() && existing.ID() != variant.ID() {
+		return productEntity.ErrSKUTaken
+	}
+	return nil
+}
+
+func variantUpdatedEvent(variant productEntity.Variant) events.ProductVariantUpdated {
+	return events.ProductVariantUpdated{
+		ID:         variant.ID(),
+		ProductID:  variant.ProductID(),
+		SKU:        variant.SKU(),
+		Attributes: variant.Attributes(),
+		Price:      variant.Price(),
+		Quantity:   variant.Quantity(),
+		CreatedAt:  variant.CreatedAt(),
+		UpdatedAt:  variant.UpdatedAt(),
+	}
+}
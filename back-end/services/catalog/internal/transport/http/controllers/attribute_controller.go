@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	attributeEntity "catalog/internal/domain/entities/attribute"
+	applicationServices "catalog/internal/services"
+	dto "catalog/internal/transport/http/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"shared/authz"
+	httpErrors "shared/errors/http"
+)
+
+type AttributeController struct {
+	ApplicationService applicationServices.AttributeApplicationService
+	Logger             zerolog.Logger
+}
+
+func NewAttributeController(
+	appService applicationServices.AttributeApplicationService,
+	logger zerolog.Logger,
+) *AttributeController {
+	return &AttributeController{
+		ApplicationService: appService,
+		Logger:             logger,
+	}
+}
+
+type attributeParams struct {
+	AttributeID string `uri:"attributeID" binding:"required,uuid"`
+}
+
+// GetAttributes lists all attributes
+func (h *AttributeController) GetAttributes(c *gin.Context) {
+	attributes, err := h.ApplicationService.GetAttributes(c.Request.Context())
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewAttributeListOutputFromEntities(attributes))
+}
+
+// CreateAttribute creates an attribute
+func (h *AttributeController) CreateAttribute(c *gin.Context) {
+	if !permitted(c, authz.PermissionManageCatalog) {
+		return
+	}
+	var input dto.CreateAttributeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	attribute, err := h.ApplicationService.CreateAttribute(c.Request.Context(), attributeEntity.CreateAttributeParams{
+		Code:    input.Code,
+		Name:    input.Name,
+		Type:    input.Type,
+		Options: input.Options,
+	})
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewAttributeOutputFromEntity(attribute))
+}
+
+// UpdateAttribute renames an attribute or replaces its options
+func (h *AttributeController) UpdateAttribute(c *gin.Context) {
+	if !permitted(c, authz.PermissionManageCatalog) {
+		return
+	}
+	var params attributeParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	var input dto.UpdateAttributeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	attribute, err := h.ApplicationService.UpdateAttribute(c.Request.Context(), params.AttributeID, attributeEntity.UpdateAttributeParams{
+		Name:    input.Name,
+		Options: input.Options,
+	})
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewAttributeOutputFromEntity(attribute))
+}
+
+// DeleteAttribute deletes an attribute that no product uses
+func (h *AttributeController) DeleteAttribute(c *gin.Context) {
+	if !permitted(c, authz.PermissionManageCatalog) {
+		return
+	}
+	var params attributeParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	err := h.ApplicationService.DeleteAttribute(c.Request.Context(), params.AttributeID)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleOkResponse(c)
+}
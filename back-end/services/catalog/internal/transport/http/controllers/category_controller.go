@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	categoryEntity "catalog/internal/domain/entities/category"
+	applicationServices "catalog/internal/services"
+	dto "catalog/internal/transport/http/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"shared/authz"
+	httpErrors "shared/errors/http"
+)
+
+type CategoryController struct {
+	ApplicationService applicationServices.CategoryApplicationService
+	Logger             zerolog.Logger
+}
+
+func NewCategoryController(
+	appService applicationServices.CategoryApplicationService,
+	logger zerolog.Logger,
+) *CategoryController {
+	return &CategoryController{
+		ApplicationService: appService,
+		Logger:             logger,
+	}
+}
+
+type categoryParams struct {
+	CategoryID string `uri:"categoryID" binding:"required,uuid"`
+}
+
+// GetCategoryTree fetches all categories as a tree
+func (h *CategoryController) GetCategoryTree(c *gin.Context) {
+	nodes, err := h.ApplicationService.GetCategoryTree(c.Request.Context())
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewCategoryTreeOutputFromNodes(nodes))
+}
+
+// CreateCategory creates a category
+func (h *CategoryController) CreateCategory(c *gin.Context) {
+	if !permitted(c, authz.PermissionManageCatalog) {
+		return
+	}
+	var input dto.CreateCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	category, err := h.ApplicationService.CreateCategory(c.Request.Context(), categoryEntity.CreateCategoryParams{
+		Name:     input.Name,
+		ParentID: input.ParentID,
+	})
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewCategoryOutputFromEntity(category))
+}
+
+// UpdateCategory renames or moves a category
+func (h *CategoryController) UpdateCategory(c *gin.Context) {
+	if !permitted(c, authz.PermissionManageCatalog) {
+		return
+	}
+	var params categoryParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	var input dto.UpdateCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	category, err := h.ApplicationService.UpdateCategory(c.Request.Context(), params.CategoryID, categoryEntity.UpdateCategoryParams{
+		Name:     input.Name,
+		ParentID: input.ParentID,
+	})
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewCategoryOutputFromEntity(category))
+}
+
+// DeleteCategory deletes an empty category
+func (h *CategoryController) DeleteCategory(c *gin.Context) {
+	if !permitted(c, authz.PermissionManageCatalog) {
+		return
+	}
+	var params categoryParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	err := h.ApplicationService.DeleteCategory(c.Request.Context(), params.CategoryID)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleOkResponse(c)
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	applicationServices "catalog/internal/services"
+	dto "catalog/internal/transport/http/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"shared/authz"
+	httpErrors "shared/errors/http"
+)
+
+type ExchangeRateController struct {
+	ApplicationService applicationServices.ExchangeRateApplicationService
+	Logger             zerolog.Logger
+}
+
+func NewExchangeRateController(
+	appService applicationServices.ExchangeRateApplicationService,
+	logger zerolog.Logger,
+) *ExchangeRateController {
+	return &ExchangeRateController{
+		ApplicationService: appService,
+		Logger:             logger,
+	}
+}
+
+type exchangeRateParams struct {
+	Currency string `uri:"currency" binding:"required,len=3"`
+}
+
+// GetExchangeRates lists the rates of the base currency
+func (h *ExchangeRateController) GetExchangeRates(c *gin.Context) {
+	rates, err := h.ApplicationService.GetExchangeRates(c.Request.Context())
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewExchangeRateListOutputFromEntities(h.ApplicationService.BaseCurrency(), rates))
+}
+
+// SetExchangeRate creates or replaces the rate of a currency
+func (h *ExchangeRateController) SetExchangeRate(c *gin.Context) {
+	if !permitted(c, authz.PermissionManageExchangeRates) {
+		return
+	}
+	var params exchangeRateParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	var input dto.SetExchangeRateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	rate, err := h.ApplicationService.SetExchangeRate(c.Request.Context(), params.Currency, input.Rate)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewExchangeRateOutputFromEntity(rate))
+}
+
+// DeleteExchangeRate stops converting prices into a currency
+func (h *ExchangeRateController) DeleteExchangeRate(c *gin.Context) {
+	if !permitted(c, authz.PermissionManageExchangeRates) {
+		return
+	}
+	var params exchangeRateParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	err := h.ApplicationService.DeleteExchangeRate(c.Request.Context(), params.Currency)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleOkResponse(c)
+}
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"shared/authz"
+	httpErrors "shared/errors/http"
+)
+
+// permitted answers 403 unless the caller has permission. The gateway checks
+// the permission of the route as well, this guards requests that reach the
+// service some other way.
+func permitted(c *gin.Context, permission authz.Permission) bool {
+	if authz.FromRequest(c.Request).Can(permission) {
+		return true
+	}
+	httpErrors.Forbidden(c, "You are not allowed to do this")
+	return false
+}
@@ -0,0 +1,66 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"catalog/internal/transport/http/controllers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"shared/authz"
+)
+
+func TestControllers_RequirePermission(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	handler := gin.New()
+	// the requests never reach the application services
+	categories := controllers.NewCategoryController(nil, zerolog.Nop())
+	attributes := controllers.NewAttributeController(nil, zerolog.Nop())
+	exchangeRates := controllers.NewExchangeRateController(nil, zerolog.Nop())
+	products := controllers.NewProductController(nil, zerolog.Nop(), nil)
+	handler.POST("/v1/categories", categories.CreateCategory)
+	handler.DELETE("/v1/attributes/:attributeID", attributes.DeleteAttribute)
+	handler.PUT("/v1/exchange-rates/:currency", exchangeRates.SetExchangeRate)
+	handler.POST("/v1/products", products.CreateProduct)
+
+	customer := authz.Identity{UserID: "user_id", Roles: []authz.Role{authz.RoleCustomer}}
+	seller := authz.Identity{UserID: "user_id", Roles: []authz.Role{authz.RoleSeller}}
+	admin := authz.Identity{UserID: "user_id", Roles: []authz.Role{authz.RoleAdmin}}
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		identity authz.Identity
+		want     int
+	}{
+		{name: "anonymous_create_category", method: http.MethodPost, path: "/v1/categories", want: http.StatusForbidden},
+		{name: "customer_create_category", method: http.MethodPost, path: "/v1/categories", identity: customer, want: http.StatusForbidden},
+		{name: "admin_create_category", method: http.MethodPost, path: "/v1/categories", identity: admin, want: http.StatusBadRequest},
+		{name: "seller_delete_attribute", method: http.MethodDelete, path: "/v1/attributes/not-a-uuid", identity: seller, want: http.StatusForbidden},
+		{name: "admin_delete_attribute", method: http.MethodDelete, path: "/v1/attributes/not-a-uuid", identity: admin, want: http.StatusBadRequest},
+		{name: "customer_set_exchange_rate", method: http.MethodPut, path: "/v1/exchange-rates/EUR", identity: customer, want: http.StatusForbidden},
+		{name: "admin_set_exchange_rate", method: http.MethodPut, path: "/v1/exchange-rates/EUR", identity: admin, want: http.StatusBadRequest},
+		{name: "customer_create_product", method: http.MethodPost, path: "/v1/products", identity: customer, want: http.StatusForbidden},
+		{name: "seller_create_product", method: http.MethodPost, path: "/v1/products", identity: seller, want: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader("{"))
+			req = req.WithContext(authz.NewContext(req.Context(), tc.identity))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"catalog/config"
 	applicationServices "catalog/internal/services"
 	dto "catalog/internal/transport/http/dto"
+	"errors"
+	"io"
+	"net/http"
 
 	"github.com/rs/zerolog"
 
 	"github.com/gin-gonic/gin"
 
+	"shared/authz"
 	httpErrors "shared/errors/http"
+	"shared/money"
 
 	productEntity "catalog/internal/domain/entities/product"
 )
@@ -32,8 +37,20 @@ func NewProductController(
 	}
 }
 
+// actor reads the user the gateway authenticated
+func (h *ProductController) actor(c *gin.Context) productEntity.Actor {
+	identity := authz.FromRequest(c.Request)
+	return productEntity.Actor{
+		UserID:  identity.UserID,
+		IsAdmin: identity.Can(authz.PermissionManageAnyProduct),
+	}
+}
+
 // CreateProduct creates a product
 func (h *ProductController) CreateProduct(c *gin.Context) {
+	if !permitted(c, authz.PermissionSellProducts) {
+		return
+	}
 	var createProductInput dto.CreateProductInput
 	if err := c.ShouldBindJSON(&createProductInput); err != nil {
 		httpErrors.BadRequest(c, err.Error())
@@ -43,9 +60,13 @@ func (h *ProductController) CreateProduct(c *gin.Context) {
 	err := h.ApplicationService.CreateProduct(
 		c.Request.Context(),
 		productEntity.CreateProductParams{
-			Name:     createProductInput.Name,
-			Price:    createProductInput.Price,
-			Quantity: createProductInput.Quantity,
+			SellerID:    h.actor(c).UserID,
+			Name:        createProductInput.Name,
+			Description: createProductInput.Description,
+			CategoryID:  createProductInput.CategoryID,
+			Attributes:  createProductInput.Attributes,
+			Price:       createProductInput.Price,
+			Quantity:    createProductInput.Quantity,
 		},
 	)
 
@@ -57,15 +78,54 @@ func (h *ProductController) CreateProduct(c *gin.Context) {
 	dto.HandleOkResponse(c)
 }
 
-// GetProducts fetches a list of products
+// GetProducts fetches a page of products
 func (h *ProductController) GetProducts(c *gin.Context) {
-	products, err := h.ApplicationService.GetProducts(c.Request.Context())
+	var listProductsInput dto.ListProductsInput
+	if err := c.ShouldBindQuery(&listProductsInput); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	params, err := listProductsInput.ToParams(money.Currency(h.Config.Pricing.BaseCurrency))
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+
+	page, err := h.ApplicationService.GetProducts(c.Request.Context(), params)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewProductListOutputFromPage(page))
+}
+
+// GetSellerProducts fetches a page of the products of a seller
+func (h *ProductController) GetSellerProducts(c *gin.Context) {
+	var uri struct {
+		SellerID string `uri:"sellerID" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	var listProductsInput dto.ListProductsInput
+	if err := c.ShouldBindQuery(&listProductsInput); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	params, err := listProductsInput.ToParams(money.Currency(h.Config.Pricing.BaseCurrency))
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	params.SellerID = uri.SellerID
 
+	page, err := h.ApplicationService.GetProducts(c.Request.Context(), params)
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
 		return
 	}
-	dto.HandleResponseWithBody(c, dto.NewProductListOutputFromEntities(products))
+	dto.HandleResponseWithBody(c, dto.NewProductListOutputFromPage(page))
 }
 
 // GetProductByID Fetches a product"
@@ -95,7 +155,7 @@ func (h *ProductController) DeleteProductByID(c *gin.Context) {
 		httpErrors.BadRequest(c, err.Error())
 		return
 	}
-	err := h.ApplicationService.DeleteProductByID(c.Request.Context(), params.ProductID)
+	err := h.ApplicationService.DeleteProductByID(c.Request.Context(), h.actor(c), params.ProductID)
 
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
@@ -121,11 +181,15 @@ func (h *ProductController) UpdateProductByID(c *gin.Context) {
 
 	err := h.ApplicationService.UpdateProductByID(
 		c.Request.Context(),
+		h.actor(c),
 		params.ProductID,
 		productEntity.UpdateProductParams{
-			Name:     updateProductInput.Name,
-			Price:    updateProductInput.Price,
-			Quantity: updateProductInput.Quantity,
+			Name:        updateProductInput.Name,
+			Description: updateProductInput.Description,
+			CategoryID:  updateProductInput.CategoryID,
+			Attributes:  updateProductInput.Attributes,
+			Price:       updateProductInput.Price,
+			Quantity:    updateProductInput.Quantity,
 		},
 	)
 
@@ -135,3 +199,153 @@ func (h *ProductController) UpdateProductByID(c *gin.Context) {
 	}
 	dto.HandleOkResponse(c)
 }
+
+type variantParams struct {
+	ProductID string `uri:"productID" binding:"required,uuid"`
+	VariantID string `uri:"variantID" binding:"required,uuid"`
+}
+
+// CreateVariant adds a variant to a product
+func (h *ProductController) CreateVariant(c *gin.Context) {
+	var params struct {
+		ProductID string `uri:"productID" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	var input dto.CreateVariantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	variant, err := h.ApplicationService.CreateVariant(c.Request.Context(), h.actor(c), productEntity.CreateVariantParams{
+		ProductID:  params.ProductID,
+		SKU:        input.SKU,
+		Attributes: input.Attributes,
+		Price:      input.Price,
+		Quantity:   input.Quantity,
+	})
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewVariantOutputFromEntity(variant))
+}
+
+// UpdateVariant updates a variant of a product
+func (h *ProductController) UpdateVariant(c *gin.Context) {
+	var params variantParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	var input dto.UpdateVariantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	variant, err := h.ApplicationService.UpdateVariant(c.Request.Context(), h.actor(c), params.ProductID, params.VariantID, productEntity.UpdateVariantParams{
+		SKU:        input.SKU,
+		Attributes: input.Attributes,
+		Price:      input.Price,
+		Quantity:   input.Quantity,
+	})
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewVariantOutputFromEntity(variant))
+}
+
+// DeleteVariant deletes a variant of a product
+func (h *ProductController) DeleteVariant(c *gin.Context) {
+	var params variantParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	err := h.ApplicationService.DeleteVariant(c.Request.Context(), h.actor(c), params.ProductID, params.VariantID)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleOkResponse(c)
+}
+
+type imageParams struct {
+	ProductID string `uri:"productID" binding:"required,uuid"`
+	ImageID   string `uri:"imageID" binding:"required,uuid"`
+}
+
+// UploadImage uploads an image of a product
+func (h *ProductController) UploadImage(c *gin.Context) {
+	var params struct {
+		ProductID string `uri:"productID" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.Config.Media.MaxUploadSize)
+	file, _, err := c.Request.FormFile("file")
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		httpErrors.RespondWithError(c, productEntity.ErrImageTooLarge)
+		return
+	}
+	if err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	image, err := h.ApplicationService.UploadImage(c.Request.Context(), h.actor(c), params.ProductID, data)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewImageOutputFromEntity(image))
+}
+
+// GetImages lists the images of a product
+func (h *ProductController) GetImages(c *gin.Context) {
+	var params struct {
+		ProductID string `uri:"productID" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	images, err := h.ApplicationService.GetImages(c.Request.Context(), params.ProductID)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleResponseWithBody(c, dto.NewImageListOutputFromEntities(images))
+}
+
+// DeleteImage deletes an image of a product
+func (h *ProductController) DeleteImage(c *gin.Context) {
+	var params imageParams
+	if err := c.ShouldBindUri(&params); err != nil {
+		httpErrors.BadRequest(c, err.Error())
+		return
+	}
+
+	err := h.ApplicationService.DeleteImage(c.Request.Context(), h.actor(c), params.ProductID, params.ImageID)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	dto.HandleOkResponse(c)
+}
@@ -0,0 +1,13 @@
+package dto
+
+type CreateAttributeInput struct {
+	Code    string   `json:"code" binding:"required"`
+	Name    string   `json:"name" binding:"required"`
+	Type    string   `json:"type" binding:"required,oneof=text number boolean enum"`
+	Options []string `json:"options"`
+}
+
+type UpdateAttributeInput struct {
+	Name    *string  `json:"name" binding:"omitempty,min=1"`
+	Options []string `json:"options"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"catalog/internal/domain/entities/attribute"
+	"time"
+)
+
+type AttributeOutput struct {
+	Type          string    `json:"type"`
+	ID            string    `json:"id"`
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`
+	AttributeType string    `json:"attributeType"`
+	Options       []string  `json:"options,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type AttributeListOutput struct {
+	Type string            `json:"type"`
+	Data []AttributeOutput `json:"data"`
+}
+
+func NewAttributeOutputFromEntity(attribute attribute.Attribute) AttributeOutput {
+	return AttributeOutput{
+		Type:          "attribute",
+		ID:            attribute.ID(),
+		Code:          attribute.Code(),
+		Name:          attribute.Name(),
+		AttributeType: attribute.Type(),
+		Options:       attribute.Options(),
+		CreatedAt:     attribute.CreatedAt(),
+		UpdatedAt:     attribute.UpdatedAt(),
+	}
+}
+
+func NewAttributeListOutputFromEntities(attributes []attribute.Attribute) AttributeListOutput {
+	outputs := make([]AttributeOutput, 0, len(attributes))
+	for _, attribute := range attributes {
+		outputs = append(outputs, NewAttributeOutputFromEntity(attribute))
+	}
+	return AttributeListOutput{Type: "list", Data: outputs}
+}
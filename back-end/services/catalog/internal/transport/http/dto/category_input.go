@@ -0,0 +1,11 @@
+package dto
+
+type CreateCategoryInput struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID string `json:"parentId" binding:"omitempty,uuid"`
+}
+
+type UpdateCategoryInput struct {
+	Name     *string `json:"name" binding:"omitempty,min=1"`
+	ParentID *string `json:"parentId" binding:"omitempty,len=0|uuid"`
+}
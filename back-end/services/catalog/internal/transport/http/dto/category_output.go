@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"catalog/internal/domain/entities/category"
+	"time"
+)
+
+type CategoryOutput struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ParentID  string    `json:"parentId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CategoryNodeOutput struct {
+	CategoryOutput
+	Children []CategoryNodeOutput `json:"children"`
+}
+
+type CategoryTreeOutput struct {
+	Type string               `json:"type"`
+	Data []CategoryNodeOutput `json:"data"`
+}
+
+func NewCategoryOutputFromEntity(category category.Category) CategoryOutput {
+	return CategoryOutput{
+		Type:      "category",
+		ID:        category.ID(),
+		Name:      category.Name(),
+		ParentID:  category.ParentID(),
+		CreatedAt: category.CreatedAt(),
+		UpdatedAt: category.UpdatedAt(),
+	}
+}
+
+func NewCategoryTreeOutputFromNodes(nodes []category.Node) CategoryTreeOutput {
+	return CategoryTreeOutput{Type: "list", Data: newCategoryNodeOutputs(nodes)}
+}
+
+func newCategoryNodeOutputs(nodes []category.Node) []CategoryNodeOutput {
+	outputs := make([]CategoryNodeOutput, 0, len(nodes))
+	for _, node := range nodes {
+		outputs = append(outputs, CategoryNodeOutput{
+			CategoryOutput: NewCategoryOutputFromEntity(node.Category),
+			Children:       newCategoryNodeOutputs(node.Children),
+		})
+	}
+	return outputs
+}
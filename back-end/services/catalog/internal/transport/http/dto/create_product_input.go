@@ -1,7 +1,12 @@
 package dto
 
+import "shared/money"
+
 type CreateProductInput struct {
-	Name     string  `json:"name" binding:"required"`
-	Price    float64 `json:"price" binding:"required,gte=0"`
-	Quantity int     `json:"quantity" binding:"gte=0"`
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	CategoryID  string            `json:"categoryId" binding:"omitempty,uuid"`
+	Attributes  map[string]string `json:"attributes"`
+	Price       money.Money       `json:"price"`
+	Quantity    int               `json:"quantity" binding:"gte=0"`
 }
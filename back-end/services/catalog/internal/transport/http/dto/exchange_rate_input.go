@@ -0,0 +1,6 @@
+package dto
+
+type SetExchangeRateInput struct {
+	// value of one unit of the base currency, e.g. "0.92"
+	Rate string `json:"rate" binding:"required"`
+}
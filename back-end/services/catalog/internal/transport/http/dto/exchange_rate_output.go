@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"catalog/internal/domain/entities/exchangerate"
+	"shared/money"
+	"time"
+)
+
+type ExchangeRateOutput struct {
+	Type      string         `json:"type"`
+	Currency  money.Currency `json:"currency"`
+	Rate      money.Rate     `json:"rate"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+type ExchangeRateListOutput struct {
+	Type         string               `json:"type"`
+	BaseCurrency money.Currency       `json:"baseCurrency"`
+	Data         []ExchangeRateOutput `json:"data"`
+}
+
+func NewExchangeRateOutputFromEntity(rate exchangerate.ExchangeRate) ExchangeRateOutput {
+	return ExchangeRateOutput{
+		Type:      "exchange_rate",
+		Currency:  rate.Currency(),
+		Rate:      rate.Rate(),
+		UpdatedAt: rate.UpdatedAt(),
+	}
+}
+
+func NewExchangeRateListOutputFromEntities(baseCurrency money.Currency, rates []exchangerate.ExchangeRate) ExchangeRateListOutput {
+	outputs := make([]ExchangeRateOutput, 0, len(rates))
+	for _, rate := range rates {
+		outputs = append(outputs, NewExchangeRateOutputFromEntity(rate))
+	}
+	return ExchangeRateListOutput{Type: "list", BaseCurrency: baseCurrency, Data: outputs}
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"catalog/internal/domain/entities/product"
+	"time"
+)
+
+type ImageOutput struct {
+	Type         string    `json:"type"`
+	ID           string    `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnailUrl"`
+	ContentType  string    `json:"contentType"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type ImageListOutput struct {
+	Type string        `json:"type"`
+	Data []ImageOutput `json:"data"`
+}
+
+func NewImageOutputFromEntity(image product.Image) ImageOutput {
+	return ImageOutput{
+		Type:         "image",
+		ID:           image.ID(),
+		URL:          image.URL(),
+		ThumbnailURL: image.ThumbnailURL(),
+		ContentType:  image.ContentType(),
+		Width:        image.Width(),
+		Height:       image.Height(),
+		Size:         image.Size(),
+		CreatedAt:    image.CreatedAt(),
+	}
+}
+
+func NewImageListOutputFromEntities(images []product.Image) ImageListOutput {
+	return ImageListOutput{Type: "list", Data: imagesOutput(images)}
+}
+
+func imagesOutput(images []product.Image) []ImageOutput {
+	outputs := make([]ImageOutput, 0, len(images))
+	for _, image := range images {
+		outputs = append(outputs, NewImageOutputFromEntity(image))
+	}
+	return outputs
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"catalog/internal/domain/entities/product"
+	"shared/money"
+	"time"
+)
+
+type ListProductsInput struct {
+	IDs         []string   `form:"ids" binding:"omitempty,dive,uuid"`
+	Query       string     `form:"q"`
+	CategoryID  string     `form:"category_id" binding:"omitempty,uuid"`
+	MinPrice    string     `form:"min_price"`
+	MaxPrice    string     `form:"max_price"`
+	Currency    string     `form:"currency" binding:"omitempty,len=3"`
+	InStock     bool       `form:"in_stock"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort        string     `form:"sort"`
+	Limit       int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor      string     `form:"cursor"`
+}
+
+// ToParams reads the price range in currency, or in defaultCurrency when the
+// request does not name one. Sorting by price needs the request to name it.
+func (i ListProductsInput) ToParams(defaultCurrency money.Currency) (product.ListProductsParams, error) {
+	params := product.ListProductsParams{
+		IDs:         i.IDs,
+		Search:      i.Query,
+		CategoryID:  i.CategoryID,
+		InStock:     i.InStock,
+		CreatedFrom: i.CreatedFrom,
+		CreatedTo:   i.CreatedTo,
+		Sort:        i.Sort,
+		Limit:       i.Limit,
+		Cursor:      i.Cursor,
+	}
+
+	currency := defaultCurrency
+	if i.Currency != "" {
+		parsed, err := money.ParseCurrency(i.Currency)
+		if err != nil {
+			return product.ListProductsParams{}, product.ErrInvalidPriceRange
+		}
+		currency = parsed
+		params.Currency = parsed
+	}
+	if i.MinPrice != "" {
+		minPrice, err := money.Parse(i.MinPrice, currency)
+		if err != nil {
+			return product.ListProductsParams{}, product.ErrInvalidPriceRange
+		}
+		params.MinPrice = &minPrice
+	}
+	if i.MaxPrice != "" {
+		maxPrice, err := money.Parse(i.MaxPrice, currency)
+		if err != nil {
+			return product.ListProductsParams{}, product.ErrInvalidPriceRange
+		}
+		params.MaxPrice = &maxPrice
+	}
+	return params, nil
+}
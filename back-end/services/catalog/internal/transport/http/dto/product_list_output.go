@@ -5,14 +5,21 @@ import (
 )
 
 type ProductListOutput struct {
-	Type string          `json:"type"`
-	Data []ProductOutput `json:"data"`
+	Type       string          `json:"type"`
+	Data       []ProductOutput `json:"data"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"nextCursor,omitempty"`
 }
 
-func NewProductListOutputFromEntities(products []product.Product) ProductListOutput {
-	productOutputList := make([]ProductOutput, 0, len(products))
-	for _, product := range products {
+func NewProductListOutputFromPage(page product.Page) ProductListOutput {
+	productOutputList := make([]ProductOutput, 0, len(page.Products))
+	for _, product := range page.Products {
 		productOutputList = append(productOutputList, NewProductOutputFromEntity(product))
 	}
-	return ProductListOutput{Type: "list", Data: productOutputList}
+	return ProductListOutput{
+		Type:       "list",
+		Data:       productOutputList,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+	}
 }
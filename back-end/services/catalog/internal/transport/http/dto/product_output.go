@@ -1,19 +1,51 @@
 package dto
 
-import "catalog/internal/domain/entities/product"
+import (
+	"catalog/internal/domain/entities/product"
+	"shared/money"
+	"time"
+)
 
 type ProductOutput struct {
-	Type  string  `json:"type"`
-	ID    string  `json:"id"`
-	Name  string  `json:"name"`
-	Price float64 `json:"price"`
+	Type        string            `json:"type"`
+	ID          string            `json:"id"`
+	SellerID    string            `json:"sellerId,omitempty"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	CategoryID  string            `json:"categoryId,omitempty"`
+	Attributes  map[string]string `json:"attributes"`
+	Price       money.Money       `json:"price"`
+	Quantity    int               `json:"quantity"`
+	Variants    []VariantOutput   `json:"variants,omitempty"`
+	Images      []ImageOutput     `json:"images"`
+	CreatedAt   time.Time         `json:"createdAt"`
 }
 
 func NewProductOutputFromEntity(product product.Product) ProductOutput {
+	var variants []VariantOutput
+	for _, variant := range product.Variants() {
+		variants = append(variants, NewVariantOutputFromEntity(variant))
+	}
 	return ProductOutput{
-		Type:  "product",
-		ID:    product.ID(),
-		Name:  product.Name(),
-		Price: product.Price(),
+		Type:        "product",
+		ID:          product.ID(),
+		SellerID:    product.SellerID(),
+		Name:        product.Name(),
+		Description: product.Description(),
+		CategoryID:  product.CategoryID(),
+		Attributes:  attributesOutput(product.Attributes()),
+		Price:       product.Price(),
+		Quantity:    product.Quantity(),
+		Variants:    variants,
+		Images:      imagesOutput(product.Images()),
+		CreatedAt:   product.CreatedAt(),
+	}
+}
+
+// attributesOutput renders missing attributes as an empty object
+func attributesOutput(attributes map[string]string) map[string]string {
+	if attributes == nil {
+		return map[string]string{}
 	}
+	return attributes
 }
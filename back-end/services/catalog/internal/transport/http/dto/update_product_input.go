@@ -1,7 +1,12 @@
 package dto
 
+import "shared/money"
+
 type UpdateProductInput struct {
-	Name     *string  `json:"name" binding:"omitempty,min=1"`
-	Price    *float64 `json:"price" binding:"omitempty,gt=0"`
-	Quantity *int     `json:"quantity" binding:"omitempty,gte=0"`
+	Name        *string           `json:"name" binding:"omitempty,min=1"`
+	Description *string           `json:"description"`
+	CategoryID  *string           `json:"categoryId" binding:"omitempty,len=0|uuid"`
+	Attributes  map[string]string `json:"attributes"`
+	Price       *money.Money      `json:"price"`
+	Quantity    *int              `json:"quantity" binding:"omitempty,gte=0"`
 }
@@ -0,0 +1,17 @@
+package dto
+
+import "shared/money"
+
+type CreateVariantInput struct {
+	SKU        string            `json:"sku" binding:"required"`
+	Attributes map[string]string `json:"attributes"`
+	Price      money.Money       `json:"price"`
+	Quantity   int               `json:"quantity" binding:"gte=0"`
+}
+
+type UpdateVariantInput struct {
+	SKU        *string           `json:"sku" binding:"omitempty,min=1"`
+	Attributes map[string]string `json:"attributes"`
+	Price      *money.Money      `json:"price"`
+	Quantity   *int              `json:"quantity" binding:"omitempty,gte=0"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"catalog/internal/domain/entities/product"
+	"shared/money"
+)
+
+type VariantOutput struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	ProductID  string            `json:"productId"`
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      money.Money       `json:"price"`
+	Quantity   int               `json:"quantity"`
+}
+
+func NewVariantOutputFromEntity(variant product.Variant) VariantOutput {
+	return VariantOutput{
+		Type:       "variant",
+		ID:         variant.ID(),
+		ProductID:  variant.ProductID(),
+		SKU:        variant.SKU(),
+		Attributes: attributesOutput(variant.Attributes()),
+		Price:      variant.Price(),
+		Quantity:   variant.Quantity(),
+	}
+}
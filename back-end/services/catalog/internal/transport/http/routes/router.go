@@ -4,7 +4,8 @@ import (
 	"catalog/config"
 	applicationServices "catalog/internal/services"
 	controllers "catalog/internal/transport/http/controllers"
-	"net/http"
+	"shared/authz"
+	"shared/health"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
@@ -13,16 +14,29 @@ import (
 func NewRouter(
 	handler *gin.Engine,
 	u applicationServices.ProductApplicationService,
+	exchangeRates applicationServices.ExchangeRateApplicationService,
+	categories applicationServices.CategoryApplicationService,
+	attributes applicationServices.AttributeApplicationService,
 	logger zerolog.Logger,
 	config *config.Config,
+	checks *health.Health,
+	verifier authz.Verifier,
 ) {
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
-	handler.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	checks.Register(handler)
 
 	r := controllers.NewProductController(u, logger, config)
+	e := controllers.NewExchangeRateController(exchangeRates, logger)
+	cat := controllers.NewCategoryController(categories, logger)
+	a := controllers.NewAttributeController(attributes, logger)
 
-	v1 := handler.Group("/v1")
+	// uploads kept on disk are served by the service itself
+	if config.Media.Driver == "local" {
+		handler.Static("/media", config.Media.LocalDir)
+	}
+
+	v1 := handler.Group("/v1", authz.Middleware(verifier))
 
 	// products
 	v1.POST("/products", r.CreateProduct)
@@ -30,4 +44,28 @@ func NewRouter(
 	v1.GET("/products/:productID", r.GetProductByID)
 	v1.DELETE("/products/:productID", r.DeleteProductByID)
 	v1.PATCH("/products/:productID", r.UpdateProductByID)
+	v1.POST("/products/:productID/variants", r.CreateVariant)
+	v1.PATCH("/products/:productID/variants/:variantID", r.UpdateVariant)
+	v1.DELETE("/products/:productID/variants/:variantID", r.DeleteVariant)
+	v1.POST("/products/:productID/images", r.UploadImage)
+	v1.GET("/products/:productID/images", r.GetImages)
+	v1.DELETE("/products/:productID/images/:imageID", r.DeleteImage)
+	v1.GET("/sellers/:sellerID/products", r.GetSellerProducts)
+
+	// categories
+	v1.GET("/categories", cat.GetCategoryTree)
+	v1.POST("/categories", cat.CreateCategory)
+	v1.PATCH("/categories/:categoryID", cat.UpdateCategory)
+	v1.DELETE("/categories/:categoryID", cat.DeleteCategory)
+
+	// attributes
+	v1.GET("/attributes", a.GetAttributes)
+	v1.POST("/attributes", a.CreateAttribute)
+	v1.PATCH("/attributes/:attributeID", a.UpdateAttribute)
+	v1.DELETE("/attributes/:attributeID", a.DeleteAttribute)
+
+	// exchange rates
+	v1.GET("/exchange-rates", e.GetExchangeRates)
+	v1.PUT("/exchange-rates/:currency", e.SetExchangeRate)
+	v1.DELETE("/exchange-rates/:currency", e.DeleteExchangeRate)
 }
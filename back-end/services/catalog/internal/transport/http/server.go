@@ -9,6 +9,8 @@ import (
 
 	"catalog/config"
 	"catalog/pkg/httpserver"
+	"shared/authz"
+	"shared/health"
 
 	applicationServices "catalog/internal/services"
 	routes "catalog/internal/transport/http/routes"
@@ -16,11 +18,26 @@ import (
 
 func NewHTTPServer(
 	productApplicationService applicationServices.ProductApplicationService,
+	exchangeRateApplicationService applicationServices.ExchangeRateApplicationService,
+	categoryApplicationService applicationServices.CategoryApplicationService,
+	attributeApplicationService applicationServices.AttributeApplicationService,
 	handler *gin.Engine,
 	logger zerolog.Logger,
 	config *config.Config,
+	checks *health.Health,
+	verifier authz.Verifier,
 ) *httpserver.Server {
-	routes.NewRouter(handler, productApplicationService, logger, config)
+	routes.NewRouter(
+		handler,
+		productApplicationService,
+		exchangeRateApplicationService,
+		categoryApplicationService,
+		attributeApplicationService,
+		logger,
+		config,
+		checks,
+		verifier,
+	)
 	logger.Info().Msg(fmt.Sprintf("Listening on %s port", config.HTTP.Port))
 	return httpserver.New(http.Handler(handler), httpserver.Port(config.HTTP.Port))
 }
@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	applicationServices "catalog/internal/services"
+
+	"github.com/rs/zerolog"
+)
+
+// ReservationExpiryJob periodically releases stock held by orders that were
+// abandoned, i.e. neither confirmed nor cancelled before their reservation
+// expired.
+type ReservationExpiryJob struct {
+	appService applicationServices.InventoryApplicationService
+	logger     zerolog.Logger
+	interval   time.Duration
+}
+
+func NewReservationExpiryJob(
+	appService applicationServices.InventoryApplicationService,
+	logger zerolog.Logger,
+	interval time.Duration,
+) *ReservationExpiryJob {
+	return &ReservationExpiryJob{appService: appService, logger: logger, interval: interval}
+}
+
+func (j *ReservationExpiryJob) Run(ctx context.Context) error {
+	j.logger.Info().Msg("reservation expiry job started")
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info().Msg("reservation expiry job stopped")
+			return nil
+		case <-ticker.C:
+		}
+		released, err := j.appService.ReleaseExpiredReservations(ctx)
+		if err != nil && ctx.Err() == nil {
+			j.logger.Error().Err(err).Msg("ReservationExpiryJob -> Run - j.appService.ReleaseExpiredReservations")
+		}
+		if released > 0 {
+			j.logger.Info().Int("orders", released).Msg("released expired reservations")
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package messaging
+
+import (
+	"context"
+	"shared/events"
+	"shared/inbox"
+	natsClient "shared/messaging/nats"
+
+	reservationEntity "catalog/internal/domain/entities/reservation"
+	applicationServices "catalog/internal/services"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	orderCreatedDurableConsumerName   = "catalog-order-created"
+	orderConfirmedDurableConsumerName = "catalog-order-confirmed"
+	orderCancelledDurableConsumerName = "catalog-order-cancelled"
+	orderStream                       = "orders"
+)
+
+type OrderMessagingHandlers interface {
+	OrderCreatedListener()
+	OrderConfirmedListener()
+	OrderCancelledListener()
+	Init()
+}
+
+var _ OrderMessagingHandlers = (*orderMessagingHandlers)(nil)
+
+type orderMessagingHandlers struct {
+	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
+	logger     zerolog.Logger
+	appService applicationServices.InventoryApplicationService
+}
+
+func NewOrderMessagingHandlers(
+	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
+	appService applicationServices.InventoryApplicationService,
+	logger zerolog.Logger,
+) *orderMessagingHandlers {
+	o := orderMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
+	return &o
+}
+
+func (o *orderMessagingHandlers) Init() {
+	o.logger.Info().Msg("initializing OrderMessagingHandlers")
+
+	err := o.natsClient.CreateStream(orderStream, "orders.*")
+	if err != nil {
+		log.Error().Err(err).Msg("orderMessagingHandlers Init -> o.natsClient.CreateStream")
+	}
+
+	o.OrderCreatedListener()
+	o.OrderConfirmedListener()
+	o.OrderCancelledListener()
+}
+
+func (o *orderMessagingHandlers) OrderCreatedListener() {
+	o.logger.Info().Msg("OrderCreatedListener initialized")
+	handler := func(ctx context.Context, orderEvent events.OrderCreated, envelope events.Envelope) error {
+		log.Info().Msg("OrderCreatedListener -> Received a message: " + string(envelope.Payload))
+
+		items := make([]reservationEntity.Item, 0, len(orderEvent.Items))
+		for _, item := range orderEvent.Items {
+			items = append(items, reservationEntity.Item{
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  item.Quantity,
+			})
+		}
+		err := o.appService.ReserveStock(ctx, orderEvent.ID, items)
+		if err != nil {
+			log.Error().Err(err).Msg("OrderCreatedListener -> o.appService.ReserveStock")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(o.natsClient, orderStream, orderCreatedDurableConsumerName, inbox.Idempotent(o.inbox, orderCreatedDurableConsumerName, handler))
+}
+
+func (o *orderMessagingHandlers) OrderConfirmedListener() {
+	o.logger.Info().Msg("OrderConfirmedListener initialized")
+	handler := func(ctx context.Context, orderEvent events.OrderConfirmed, envelope events.Envelope) error {
+		log.Info().Msg("OrderConfirmedListener -> Received a message: " + string(envelope.Payload))
+
+		err := o.appService.ConfirmReservation(ctx, orderEvent.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("OrderConfirmedListener -> o.appService.ConfirmReservation")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(o.natsClient, orderStream, orderConfirmedDurableConsumerName, inbox.Idempotent(o.inbox, orderConfirmedDurableConsumerName, handler))
+}
+
+func (o *orderMessagingHandlers) OrderCancelledListener() {
+	o.logger.Info().Msg("OrderCancelledListener initialized")
+	handler := func(ctx context.Context, orderEvent events.OrderCancelled, envelope events.Envelope) error {
+		log.Info().Msg("OrderCancelledListener -> Received a message: " + string(envelope.Payload))
+
+		err := o.appService.ReleaseReservation(ctx, orderEvent.ID, events.ReasonOrderCancelled)
+		if err != nil {
+			log.Error().Err(err).Msg("OrderCancelledListener -> o.appService.ReleaseReservation")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(o.natsClient, orderStream, orderCancelledDurableConsumerName, inbox.Idempotent(o.inbox, orderCancelledDurableConsumerName, handler))
+}
@@ -0,0 +1,3 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_quantity_non_negative;
+
+DROP TABLE IF EXISTS stock_reservations;
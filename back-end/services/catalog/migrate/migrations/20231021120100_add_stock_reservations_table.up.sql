@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    order_id uuid NOT NULL,
+    product_id uuid NOT NULL,
+    quantity int NOT NULL,
+    status varchar NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    CONSTRAINT stock_reservations_pk PRIMARY KEY (order_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS stock_reservations_expires_at_idx
+    ON stock_reservations (expires_at)
+    WHERE status = 'reserved';
+
+ALTER TABLE products ADD CONSTRAINT products_quantity_non_negative CHECK (quantity >= 0) NOT VALID;
@@ -0,0 +1,7 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price numeric NULL;
+
+UPDATE products SET price = price_amount / 100.0 WHERE price IS NULL;
+
+ALTER TABLE products ALTER COLUMN price SET NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE products DROP COLUMN IF EXISTS price_amount;
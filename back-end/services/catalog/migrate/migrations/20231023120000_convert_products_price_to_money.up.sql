@@ -0,0 +1,8 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_amount bigint NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_currency char(3) NOT NULL DEFAULT 'USD';
+
+-- prices were stored as decimal USD amounts
+UPDATE products SET price_amount = ROUND(price * 100) WHERE price_amount IS NULL;
+
+ALTER TABLE products ALTER COLUMN price_amount SET NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS price;
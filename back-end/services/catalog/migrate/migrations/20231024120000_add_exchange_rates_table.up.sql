@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency char(3) NOT NULL,
+    rate numeric NOT NULL,
+    updated_at timestamptz NOT NULL,
+    CONSTRAINT exchange_rates_pk PRIMARY KEY (currency),
+    CONSTRAINT exchange_rates_rate_positive CHECK (rate > 0)
+);
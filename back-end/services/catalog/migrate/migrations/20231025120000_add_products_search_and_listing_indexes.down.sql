@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS products_name_id_idx;
+DROP INDEX IF EXISTS products_price_amount_id_idx;
+DROP INDEX IF EXISTS products_created_at_id_idx;
+DROP INDEX IF EXISTS products_search_vector_idx;
+
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS description;
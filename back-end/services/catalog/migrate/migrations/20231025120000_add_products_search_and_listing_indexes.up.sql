@@ -0,0 +1,10 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS description varchar NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', coalesce(name, '') || ' ' || coalesce(description, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS products_search_vector_idx ON products USING gin (search_vector);
+
+-- keyset pagination orders by the sort key and breaks ties by id
+CREATE INDEX IF NOT EXISTS products_created_at_id_idx ON products (created_at, id);
+CREATE INDEX IF NOT EXISTS products_price_amount_id_idx ON products (price_amount, id);
+CREATE INDEX IF NOT EXISTS products_name_id_idx ON products (name, id);
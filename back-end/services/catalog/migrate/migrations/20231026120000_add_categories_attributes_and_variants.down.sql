@@ -0,0 +1,13 @@
+ALTER TABLE stock_reservations DROP CONSTRAINT IF EXISTS stock_reservations_pk;
+DELETE FROM stock_reservations WHERE variant_id <> '';
+ALTER TABLE stock_reservations DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE stock_reservations ADD CONSTRAINT stock_reservations_pk PRIMARY KEY (order_id, product_id);
+
+DROP TABLE IF EXISTS product_variants;
+
+DROP INDEX IF EXISTS products_category_id_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS attributes;
+DROP TABLE IF EXISTS categories;
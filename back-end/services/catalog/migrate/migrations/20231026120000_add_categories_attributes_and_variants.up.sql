@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id uuid NOT NULL,
+    name varchar NOT NULL,
+    parent_id uuid NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    CONSTRAINT categories_pk PRIMARY KEY (id),
+    CONSTRAINT categories_parent_fk FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
+CREATE TABLE IF NOT EXISTS attributes (
+    id uuid NOT NULL,
+    code varchar NOT NULL,
+    name varchar NOT NULL,
+    type varchar NOT NULL,
+    options text[] NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    CONSTRAINT attributes_pk PRIMARY KEY (id),
+    CONSTRAINT attributes_code_unique UNIQUE (code)
+);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id uuid NULL
+    CONSTRAINT products_category_fk REFERENCES categories (id);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes jsonb NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS products_category_id_idx ON products (category_id);
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    id uuid NOT NULL,
+    product_id uuid NOT NULL,
+    sku varchar NOT NULL,
+    attributes jsonb NOT NULL DEFAULT '{}',
+    price_amount bigint NOT NULL,
+    price_currency char(3) NOT NULL,
+    quantity int NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    CONSTRAINT product_variants_pk PRIMARY KEY (id),
+    CONSTRAINT product_variants_product_fk FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE,
+    CONSTRAINT product_variants_sku_unique UNIQUE (sku),
+    CONSTRAINT product_variants_quantity_non_negative CHECK (quantity >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS product_variants_product_id_idx ON product_variants (product_id);
+
+-- an empty variant_id reserves the product itself
+ALTER TABLE stock_reservations ADD COLUMN IF NOT EXISTS variant_id varchar NOT NULL DEFAULT '';
+ALTER TABLE stock_reservations DROP CONSTRAINT IF EXISTS stock_reservations_pk;
+ALTER TABLE stock_reservations ADD CONSTRAINT stock_reservations_pk PRIMARY KEY (order_id, product_id, variant_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS product_images (
+    id uuid NOT NULL,
+    product_id uuid NOT NULL,
+    key varchar NOT NULL,
+    url varchar NOT NULL,
+    thumbnail_key varchar NOT NULL,
+    thumbnail_url varchar NOT NULL,
+    content_type varchar NOT NULL,
+    width int NOT NULL,
+    height int NOT NULL,
+    size bigint NOT NULL,
+    created_at timestamptz NOT NULL,
+    CONSTRAINT product_images_pk PRIMARY KEY (id),
+    CONSTRAINT product_images_product_fk FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS product_images_product_id_idx ON product_images (product_id);
@@ -0,0 +1,4 @@
+-- products created before sellers existed keep a NULL seller
+ALTER TABLE products ADD COLUMN IF NOT EXISTS seller_id uuid NULL;
+
+CREATE INDEX IF NOT EXISTS products_seller_id_idx ON products (seller_id);
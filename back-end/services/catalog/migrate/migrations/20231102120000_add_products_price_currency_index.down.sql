@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS products_price_amount_id_idx ON products (price_amount, id);
+DROP INDEX IF EXISTS products_price_currency_amount_id_idx;
@@ -0,0 +1,3 @@
+-- price sorts list the products of one currency
+CREATE INDEX IF NOT EXISTS products_price_currency_amount_id_idx ON products (price_currency, price_amount, id);
+DROP INDEX IF EXISTS products_price_amount_id_idx;
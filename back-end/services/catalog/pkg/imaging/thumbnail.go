@@ -0,0 +1,116 @@
+// Package imaging decodes uploaded images and renders their thumbnails.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+var ErrUnsupportedFormat = errors.New("imaging: unsupported format")
+
+// MaxPixels bounds the size of a decoded image, a small file can declare
+// huge dimensions.
+const MaxPixels = 50_000_000
+
+var ErrTooManyPixels = errors.New("imaging: image has too many pixels")
+
+// Decode reads a JPEG, PNG or GIF image and reports its format.
+func Decode(data []byte) (image.Image, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	return img, format, nil
+}
+
+// Thumbnail scales img down to fit in a size x size square, keeping its
+// aspect ratio. Smaller images keep their size.
+func Thumbnail(img image.Image, size int) *image.NRGBA {
+	bounds := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	width, height := fit(bounds.Dx(), bounds.Dy(), size)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return src
+	}
+	return boxResize(src, width, height)
+}
+
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, maxInt(1, height*size/width)
+	}
+	return maxInt(1, width*size/height), size
+}
+
+// boxResize averages the source pixels covered by each destination pixel,
+// which is accurate for downscaling.
+func boxResize(src *image.NRGBA, width, height int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcHeight/height, maxInt((y+1)*srcHeight/height, y*srcHeight/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcWidth/width, maxInt((x+1)*srcWidth/width, x*srcWidth/width+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					pixel := row[sx*4 : sx*4+4]
+					// weigh colors by alpha so transparent pixels do not darken edges
+					alpha := uint64(pixel[3])
+					r += uint64(pixel[0]) * alpha
+					g += uint64(pixel[1]) * alpha
+					b += uint64(pixel[2]) * alpha
+					a += alpha
+					n++
+				}
+			}
+			offset := y*dst.Stride + x*4
+			if a > 0 {
+				dst.Pix[offset] = uint8(r / a)
+				dst.Pix[offset+1] = uint8(g / a)
+				dst.Pix[offset+2] = uint8(b / a)
+			}
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// Encode writes img as JPEG when the original was a JPEG and as PNG
+// otherwise, so transparency survives. It returns the content type written.
+func Encode(w io.Writer, img image.Image, format string) (string, error) {
+	switch format {
+	case "jpeg":
+		return "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	case "png", "gif":
+		return "image/png", png.Encode(w, img)
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
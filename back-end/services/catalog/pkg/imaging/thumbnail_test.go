@@ -0,0 +1,74 @@
+package imaging_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"catalog/pkg/imaging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThumbnail(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		width      int
+		height     int
+		wantWidth  int
+		wantHeight int
+	}{
+		{name: "Landscape_FitsWidth", width: 800, height: 400, wantWidth: 200, wantHeight: 100},
+		{name: "Portrait_FitsHeight", width: 300, height: 600, wantWidth: 100, wantHeight: 200},
+		{name: "Small_KeepsSize", width: 50, height: 20, wantWidth: 50, wantHeight: 20},
+		{name: "Thin_KeepsOnePixel", width: 1000, height: 2, wantWidth: 200, wantHeight: 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			img := image.NewNRGBA(image.Rect(0, 0, tc.width, tc.height))
+
+			thumbnail := imaging.Thumbnail(img, 200)
+
+			assert.Equal(t, tc.wantWidth, thumbnail.Bounds().Dx())
+			assert.Equal(t, tc.wantHeight, thumbnail.Bounds().Dy())
+		})
+	}
+}
+
+func TestThumbnail_AveragesPixels(t *testing.T) {
+	t.Parallel()
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.NRGBA{R: 200, A: 255})
+		img.Set(x, 1, color.NRGBA{B: 100, A: 255})
+	}
+
+	thumbnail := imaging.Thumbnail(img, 2)
+
+	assert.Equal(t, color.NRGBA{R: 100, B: 50, A: 255}, thumbnail.NRGBAAt(0, 0))
+}
+
+func TestDecodeAndEncode(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 3, 2))))
+
+	img, format, err := imaging.Decode(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, image.Rect(0, 0, 3, 2), img.Bounds())
+
+	contentType, err := imaging.Encode(&bytes.Buffer{}, img, format)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	_, _, err = imaging.Decode([]byte("not an image"))
+	assert.Error(t, err)
+}
@@ -1,18 +1,20 @@
 package main
 
 import (
-	"os"
+	"context"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
 	"customer/config"
+	"shared/health"
+	inboxStore "shared/inbox/pg"
+	"shared/lifecycle"
 	pgStorage "shared/storage/pg"
 
 	httpServ "customer/internal/transport/http"
 
 	applicationServices "customer/internal/services"
-	"customer/pkg/httpserver"
 
 	customerRepo "customer/internal/repositories/customer/pg"
 	nats "shared/messaging/nats"
@@ -20,24 +22,38 @@ import (
 	messaging "customer/internal/transport/messaging"
 )
 
-func buildDependencies() (messaging.UserMessagingHandlers, *httpserver.Server, error) {
+const maxConsumerLag = 1000
 
-	logger := zerolog.New(os.Stdout)
+func buildDependencies(runner *lifecycle.Runner, logger zerolog.Logger) (messaging.UserMessagingHandlers, error) {
 	config, err := config.NewConfig()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	checks := health.NewHealth(runner)
 
 	pg := pgStorage.NewClient(logger, pgStorage.Config{DSN: config.PgSDN})
+	runner.OnShutdown("postgres", lifecycle.Closer(pg))
+	checks.AddReadinessCheck("postgres", health.Ping(pg))
+	transactor := pgStorage.NewTransactor(pg)
+	inbox := inboxStore.NewInbox(pg, transactor)
+	inboxCleaner := inboxStore.NewCleaner(pg, logger)
+	runner.Go("inbox cleaner", func(ctx context.Context) error {
+		inboxCleaner.Run(ctx)
+		return nil
+	})
 
 	customerRepo := customerRepo.NewCustomerRepository(pg, logger)
 	nats := nats.NewNatsClient()
+	runner.OnShutdown("nats", nats.Drain)
+	checks.AddReadinessCheck("nats", health.Connection(nats))
+	checks.AddDegradedCheck("consumer lag", health.ConsumerLag(nats, maxConsumerLag))
 
 	customerAppService := applicationServices.NewCustomerApplicationService(customerRepo, logger)
 
-	userMessagingHandlers := messaging.NewCustomerMessagingHandlers(nats, customerAppService, logger)
+	userMessagingHandlers := messaging.NewCustomerMessagingHandlers(nats, inbox, customerAppService, logger)
 
-	httpServer := httpServ.NewHTTPServer(customerAppService, gin.New(), logger, config, pg)
+	httpServer := httpServ.NewHTTPServer(customerAppService, gin.New(), logger, config, pg, checks)
+	runner.AddServer("http server", httpServer)
 
-	return userMessagingHandlers, httpServer, nil
+	return userMessagingHandlers, nil
 }
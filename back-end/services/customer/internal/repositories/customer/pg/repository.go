@@ -10,6 +10,8 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/uptrace/bun"
+
+	pgStorage "shared/storage/pg"
 )
 
 var _ repository.CustomerRepository = (*customerPGRepository)(nil)
@@ -61,7 +63,7 @@ func NewCustomerRepository(sql *bun.DB, logger zerolog.Logger) *customerPGReposi
 func (r *customerPGRepository) GetByID(ctx context.Context, id string) (*customerEntity.Customer, error) {
 
 	var customer CustomerModel
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&customer).
 		Where("id IN (?)", id).
 		Scan(ctx)
@@ -84,7 +86,7 @@ func (r *customerPGRepository) GetByID(ctx context.Context, id string) (*custome
 func (r *customerPGRepository) GetByEmail(ctx context.Context, email string) (*customerEntity.Customer, error) {
 	var customer CustomerModel
 	// err := r.customersCollection.FindOne(ctx, bson.M{"email": email}).Decode(&customer)
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&customer).
 		Where("email IN (?)", email).
 		Scan(ctx)
@@ -107,7 +109,7 @@ func (r *customerPGRepository) Create(ctx context.Context, u customerEntity.Cust
 	if err != nil {
 		return err
 	}
-	_, err = r.db.NewInsert().Model(&dbUser).Exec(ctx)
+	_, err = pgStorage.Conn(ctx, r.db).NewInsert().Model(&dbUser).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -120,7 +122,7 @@ func (r *customerPGRepository) Update(ctx context.Context, customer customerEnti
 		return fmt.Errorf("customerPGRepository Update -> toDB(customer): %w", err)
 	}
 
-	_, err = r.db.NewUpdate().Model(&sqlCustomer).Where("id = ?", sqlCustomer.ID).Exec(ctx)
+	_, err = pgStorage.Conn(ctx, r.db).NewUpdate().Model(&sqlCustomer).Where("id = ?", sqlCustomer.ID).Exec(ctx)
 
 	if err != nil {
 		return fmt.Errorf("customerPGRepository Update -> r.db.NewUpdate: %w", err)
@@ -131,7 +133,7 @@ func (r *customerPGRepository) Update(ctx context.Context, customer customerEnti
 
 func (r *customerPGRepository) Delete(ctx context.Context, ID string) error {
 	var customer CustomerModel
-	_, err := r.db.NewDelete().Model(&customer).Where("id = ?", ID).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().Model(&customer).Where("id = ?", ID).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("customerPGRepository Delete -> NewDelete: %w", err)
 	}
@@ -3,7 +3,7 @@ package routes
 import (
 	"customer/config"
 	applicationServices "customer/internal/services"
-	"net/http"
+	"shared/health"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
@@ -14,9 +14,10 @@ func NewRouter(
 	u applicationServices.CustomerApplicationService,
 	logger zerolog.Logger,
 	config *config.Config,
+	checks *health.Health,
 ) {
 
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
-	handler.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	checks.Register(handler)
 }
@@ -10,6 +10,7 @@ import (
 
 	"customer/config"
 	"customer/pkg/httpserver"
+	"shared/health"
 
 	applicationServices "customer/internal/services"
 	routes "customer/internal/transport/http/routes"
@@ -21,8 +22,9 @@ func NewHTTPServer(
 	logger zerolog.Logger,
 	config *config.Config,
 	db *bun.DB,
+	checks *health.Health,
 ) *httpserver.Server {
-	routes.NewRouter(handler, CustomerApplicationService, logger, config)
+	routes.NewRouter(handler, CustomerApplicationService, logger, config, checks)
 	logger.Info().Msg(fmt.Sprintf("Listening on %s port", config.HTTP.Port))
 	return httpserver.New(http.Handler(handler), httpserver.Port(config.HTTP.Port))
 }
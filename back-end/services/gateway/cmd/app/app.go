@@ -1,63 +1,122 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"gateway/config"
 	"gateway/pkg/httpserver"
+	"gateway/pkg/proxy"
+	"shared/authz"
+	"shared/health"
+	"shared/lifecycle"
+	nats "shared/messaging/nats"
 
 	applicationServices "gateway/internal/domain/application-services"
 	middlewares "gateway/internal/transport/http/middlewares"
 	routes "gateway/internal/transport/http/routes"
+	messaging "gateway/internal/transport/messaging"
+
+	sessionCache "gateway/internal/repositories/session/redis"
 
 	redisPool "gateway/pkg/storage/redis"
 )
 
 func NewHTTPServer(
-	userApplicationService applicationServices.UserApplicationService,
-	handler *gin.Engine,
-	m middlewares.Middlewares,
+	router *routes.Router,
 	logger zerolog.Logger,
 	config *config.Config,
 ) *httpserver.Server {
-	routes.NewRouter(handler, userApplicationService, m, logger, config)
 	port := config.HTTP.Port
 	logger.Info().Msg(fmt.Sprintf("Listening on %s port", port))
-	return httpserver.New(http.Handler(handler), httpserver.Port(port))
+	return httpserver.New(http.Handler(router), httpserver.Port(port))
 }
 
-func buildDependencies() (*httpserver.Server, error) {
-
-	logger := zerolog.New(os.Stdout)
-	config, err := config.NewConfig()
+func buildDependencies(runner *lifecycle.Runner, logger zerolog.Logger) (messaging.UserMessagingHandlers, error) {
+	conf, err := config.NewConfig()
 	if err != nil {
 		return nil, err
 	}
-	redis := redisPool.NewRedisPool(config.RedisAddress)
+	checks := health.NewHealth(runner)
+	redis := redisPool.NewRedisPool(conf.RedisAddress)
+	runner.OnShutdown("redis", lifecycle.Closer(redis))
+	checks.AddReadinessCheck("redis", redisPool.NewHealthChecker(redis))
 
-	rateLimiter := middlewares.NewRateLimiter(redis)
+	rateLimiter := middlewares.NewRateLimiter(logger, redis)
+	runner.OnShutdown("rate limiter", lifecycle.Closer(rateLimiter))
 	requireAuthentication := middlewares.NewRequireAuthentication(logger)
-	userApplicationService := applicationServices.NewUserApplicationService(logger, config)
+	requirePermission := middlewares.NewRequirePermission(logger)
+	requireRole := middlewares.NewRequireRole(logger)
+	nats := nats.NewNatsClient()
+	runner.OnShutdown("nats", nats.Drain)
+	checks.AddReadinessCheck("nats", health.Connection(nats))
+
+	// shared by every lookup so connections to the authentication service
+	// are reused
+	authenticationClient := &http.Client{
+		Timeout: conf.Session.LookupTimeout,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	sessions := sessionCache.NewSessionCache(redis, conf.Session.CacheTTL)
+	userApplicationService := applicationServices.NewUserApplicationService(logger, conf, authenticationClient, sessions)
+	userMessageHandlers := messaging.NewUserMessagingHandlers(nats, userApplicationService, logger)
 
-	getAuthenticationInfo := middlewares.NewGetAuthenticationInfo(logger, userApplicationService)
+	getAuthenticationInfo := middlewares.NewGetAuthenticationInfo(logger, userApplicationService, conf.Session.FailOpen)
 
 	middlewaresContainer := middlewares.Middlewares{
 		RequireAuthentication: requireAuthentication,
+		RequirePermission:     requirePermission,
+		RequireRole:           requireRole,
 		GetAuthenticationInfo: getAuthenticationInfo,
 		RateLimiter:           rateLimiter,
 	}
 
-	httpServer := NewHTTPServer(userApplicationService, gin.New(), middlewaresContainer, logger, config)
+	keys, err := authz.NewKeySet([]byte(conf.Identity.SigningSecret), conf.Identity.KeyRotationInterval)
+	if err != nil {
+		return nil, err
+	}
+	signer := authz.NewSigner(keys, conf.Identity.Issuer, conf.Identity.TokenTTL)
+
+	proxies := proxy.NewPool(
+		logger,
+		proxy.DialTimeout(conf.Proxy.DialTimeout),
+		proxy.IdleConnTimeout(conf.Proxy.IdleConnTimeout),
+		proxy.MaxIdleConnsPerHost(conf.Proxy.MaxIdleConnsPerHost),
+		proxy.Retries(conf.Proxy.Retries),
+		proxy.RetryBackoff(conf.Proxy.RetryBackoff),
+		proxy.Breaker(conf.Proxy.BreakerThreshold, conf.Proxy.BreakerOpenTimeout),
+		proxy.HealthCheck(conf.Proxy.HealthCheckInterval, conf.Proxy.HealthCheckTimeout),
+	)
+	runner.OnShutdown("upstream connections", lifecycle.Closer(proxies))
+	runner.Go("upstream health checks", proxies.Run)
+
+	router := routes.NewRouter(middlewaresContainer, logger, conf, checks, keys, signer, proxies)
+	routeTable, err := config.LoadRoutes(conf.Routes.File)
+	if err != nil {
+		return nil, err
+	}
+	err = router.Load(routeTable)
+	if err != nil {
+		return nil, err
+	}
+	runner.Go("route table reload", func(ctx context.Context) error {
+		return router.Watch(ctx, conf.Routes.File, conf.Routes.ReloadInterval)
+	})
 
-	return httpServer, err
+	httpServer := NewHTTPServer(router, logger, conf)
+	runner.AddServer("http server", httpServer)
+
+	return userMessageHandlers, nil
 }
 
 // Run creates objects via constructors.
@@ -68,26 +127,19 @@ func run() {
 	}
 
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	logger := zerolog.New(os.Stdout)
+	runner := lifecycle.NewRunner(logger)
 
-	httpServer, err := buildDependencies()
+	userMessageHandlers, err := buildDependencies(runner, logger)
 	if err != nil {
 		log.Panic().Err(err).Msg("c.Invoke")
 	}
-	// Waiting signal
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
-
-	select {
-	case s := <-interrupt:
-		log.Info().Msg("app - Run - signal: " + s.String())
-	case err := <-httpServer.Notify():
-		log.Error().Err(err).Msg("app - Run - httpServer.Notify")
-	}
 
-	// Shutdown
-	err = httpServer.Shutdown()
+	userMessageHandlers.Init()
+
+	err = runner.Run()
 	if err != nil {
-		log.Error().Err(err).Msg("app - Run - httpServer.Shutdown")
+		log.Error().Err(err).Msg("app - Run - runner.Run")
 	}
 
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
@@ -14,14 +15,15 @@ type (
 	Config struct {
 		App                      `yaml:"app"`
 		HTTP                     `yaml:"http"`
+		Identity                 `yaml:"identity"`
+		Session                  `yaml:"session"`
+		Routes                   `yaml:"routes"`
+		Proxy                    `yaml:"proxy"`
+		Dashboard                `yaml:"dashboard"`
+		GraphQL                  `yaml:"graphql"`
 		AccountsAppURL           string `yaml:"accounts_app_url"  validate:"required"`
 		MarketplaceAppUrl        string `yaml:"marketplace_app_url"  validate:"required"`
-		CatalogServiceURL        string `yaml:"catalog_service_url" validate:"required"`
-		CartServiceURL           string `yaml:"cart_service_url" validate:"required"`
-		ChatsServiceWebsocketURL string `yaml:"chat_service_websocket_url" validate:"required"`
-		ChatsServiceURL          string `yaml:"chat_service_url" validate:"required"`
 		AuthenticationServiceURL string `yaml:"authentication_service_url" validate:"required"`
-		NotificationServiceURL   string `yaml:"notification_service_url" validate:"required"`
 		SwaggerUIDomain          string `yaml:"swagger_ui_domain"`
 		SwaggerEditorDomain      string `yaml:"swagger_editor_domain"`
 		RedisAddress             string `yaml:"redis_address" validate:"required"`
@@ -33,6 +35,72 @@ type (
 
 	HTTP struct {
 		Port string `yaml:"port" env:"PORT" validate:"required"`
+		// addresses of the load balancers in front of the gateway, only their
+		// X-Forwarded-For is used for the client IP. Without them the client
+		// IP is the address of the connection.
+		TrustedProxies []string `yaml:"trusted_proxies" validate:"dive,ip|cidr"`
+	}
+
+	Identity struct {
+		// services only accept tokens of this issuer
+		Issuer   string        `yaml:"issuer" validate:"required"`
+		TokenTTL time.Duration `yaml:"token_ttl" validate:"required"`
+		// must be longer than token_ttl
+		KeyRotationInterval time.Duration `yaml:"key_rotation_interval" validate:"required,gtfield=TokenTTL"`
+		// the signing keys are derived from it, every gateway must share it
+		SigningSecret string `yaml:"signing_secret" validate:"required,min=32"`
+	}
+
+	Session struct {
+		// cookie of the authentication service, requests without it are
+		// anonymous and skip the lookup
+		CookieName    string        `yaml:"cookie_name" validate:"required"`
+		LookupTimeout time.Duration `yaml:"lookup_timeout" validate:"required"`
+		CacheTTL      time.Duration `yaml:"cache_ttl" validate:"required"`
+		// when the authentication service cannot be reached, true lets the
+		// request through as anonymous, false rejects it with 503
+		FailOpen bool `yaml:"fail_open"`
+	}
+
+	Routes struct {
+		// see RouteTable
+		File           string        `yaml:"file" validate:"required"`
+		ReloadInterval time.Duration `yaml:"reload_interval" validate:"required"`
+	}
+
+	Proxy struct {
+		// timeout of the routes that do not set their own
+		Timeout             time.Duration `yaml:"timeout" validate:"required"`
+		DialTimeout         time.Duration `yaml:"dial_timeout" validate:"required"`
+		IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout" validate:"required"`
+		MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host" validate:"required"`
+		// extra attempts of GET and HEAD requests the upstream failed
+		Retries      int           `yaml:"retries" validate:"gte=0"`
+		RetryBackoff time.Duration `yaml:"retry_backoff" validate:"required"`
+		// consecutive failures that open the circuit of an upstream, requests
+		// to it fail with 503 until a probe after breaker_open_timeout succeeds
+		BreakerThreshold   int           `yaml:"breaker_threshold" validate:"required"`
+		BreakerOpenTimeout time.Duration `yaml:"breaker_open_timeout" validate:"required"`
+		// instances of upstreams with a health_path are probed this often
+		HealthCheckInterval time.Duration `yaml:"health_check_interval" validate:"required"`
+		HealthCheckTimeout  time.Duration `yaml:"health_check_timeout" validate:"required"`
+	}
+
+	Dashboard struct {
+		// timeout of each call to a service, a section whose call fails is
+		// left out of the dashboard
+		CallTimeout time.Duration `yaml:"call_timeout" validate:"required"`
+	}
+
+	GraphQL struct {
+		// timeout of each call a resolver makes to a service
+		CallTimeout time.Duration `yaml:"call_timeout" validate:"required"`
+		// how long product lookups are collected into one catalog call
+		BatchWait time.Duration `yaml:"batch_wait" validate:"required"`
+		// queries nesting deeper are rejected
+		MaxDepth int `yaml:"max_depth" validate:"required"`
+		// resolvers of one request running at the same time
+		MaxConcurrency int `yaml:"max_concurrency" validate:"required"`
 	}
 )
 
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"shared/authz"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v2"
+)
+
+var ErrInvalidRoute = errors.New("invalid route")
+
+// Handlers of the routes the gateway answers itself.
+const (
+	// the user, cart and notifications of the logged in user
+	HandlerDashboard = "dashboard"
+	// the GraphQL API, see gateway/internal/transport/graphql
+	HandlerGraphQL = "graphql"
+	// the GraphQL schema in the schema definition language
+	HandlerGraphQLSchema = "graphql_schema"
+)
+
+type (
+	// RouteTable maps the public endpoints of the gateway to the services.
+	RouteTable struct {
+		Upstreams  map[string]Upstream `yaml:"upstreams" validate:"required,dive"`
+		RateLimits RateLimits          `yaml:"rate_limits"`
+		Routes     []Route             `yaml:"routes" validate:"required,dive"`
+	}
+
+	// RateLimits are the request budgets of clients. A client is the logged
+	// in user, or the IP address of anonymous callers.
+	RateLimits struct {
+		// applies to every request per IP address, requests with an API key
+		// count against the quota of the key instead
+		Global RateLimit `yaml:"global"`
+		// named policies routes refer to
+		Policies map[string]RateLimitPolicy `yaml:"policies" validate:"dive"`
+		// IP addresses and CIDR ranges which are never limited
+		Allowlist []string `yaml:"allowlist" validate:"dive,ip|cidr"`
+		APIKeys   []APIKey `yaml:"api_keys" validate:"dive"`
+	}
+
+	// RateLimit allows Burst requests per second and Sustained requests per
+	// Interval, 0 disables either.
+	RateLimit struct {
+		Burst     int `yaml:"burst" validate:"gte=0"`
+		Sustained int `yaml:"sustained" validate:"gte=0"`
+		// a minute when empty
+		Interval time.Duration `yaml:"interval" validate:"gte=0"`
+	}
+
+	RateLimitPolicy struct {
+		RateLimit `yaml:",inline"`
+		// limits of users with the role, the most generous one of the roles
+		// of a user applies
+		Roles map[string]RateLimit `yaml:"roles" validate:"dive"`
+	}
+
+	// APIKey is a key partners send in the X-API-Key header, its requests
+	// count against Quota instead of the global limit.
+	APIKey struct {
+		Name  string    `yaml:"name" validate:"required"`
+		Key   string    `yaml:"key" validate:"required"`
+		Quota RateLimit `yaml:"quota"`
+	}
+
+	// Upstream is a service running on one or more instances. A string is
+	// read as a comma separated list of instances.
+	Upstream struct {
+		Instances []string `yaml:"instances" validate:"required,dive,url"`
+		// round_robin when empty, consistent_hash keeps the requests of a user
+		// on one instance
+		Strategy string `yaml:"strategy" validate:"omitempty,oneof=round_robin least_connections consistent_hash"`
+		// probed on every instance, failing instances get no requests
+		HealthPath string `yaml:"health_path" validate:"omitempty,startswith=/"`
+	}
+
+	Route struct {
+		Path     string   `yaml:"path" validate:"required,startswith=/"`
+		Methods  []string `yaml:"methods" validate:"required,dive,oneof=GET POST PUT PATCH DELETE HEAD OPTIONS"`
+		Upstream string   `yaml:"upstream" validate:"required_without=Handler"`
+		// answers the route in the gateway instead of an upstream
+		Handler string `yaml:"handler" validate:"omitempty,oneof=dashboard graphql graphql_schema"`
+		// requires a logged in user
+		Auth       bool   `yaml:"auth"`
+		Permission string `yaml:"permission"`
+		// the user needs any of the roles
+		Roles []string `yaml:"roles"`
+		// name of a policy of RateLimits
+		RateLimit string `yaml:"rate_limit"`
+		// 0 uses the timeout of Proxy
+		Timeout time.Duration `yaml:"timeout" validate:"gte=0"`
+		Rewrite []Rewrite     `yaml:"rewrite" validate:"dive"`
+		// websocket upgrades are registered before the CORS middleware
+		SkipCORS bool `yaml:"skip_cors"`
+	}
+
+	// Rewrite replaces the matches of Pattern in the path sent upstream.
+	Rewrite struct {
+		Pattern     string `yaml:"pattern" validate:"required"`
+		Replacement string `yaml:"replacement"`
+	}
+)
+
+func (u *Upstream) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var instances string
+	if unmarshal(&instances) == nil {
+		*u = Upstream{}
+		for _, instance := range strings.Split(instances, ",") {
+			if instance = strings.TrimSpace(instance); instance != "" {
+				u.Instances = append(u.Instances, instance)
+			}
+		}
+		return nil
+	}
+	type plain Upstream
+	return unmarshal((*plain)(u))
+}
+
+// LoadRoutes reads and validates the route table, ${ENV} references are
+// expanded.
+func LoadRoutes(path string) (*RouteTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("routes error: %w", err)
+	}
+	return ParseRoutes(data)
+}
+
+func ParseRoutes(data []byte) (*RouteTable, error) {
+	table := &RouteTable{}
+	err := yaml.UnmarshalStrict([]byte(os.ExpandEnv(string(data))), table)
+	if err != nil {
+		return nil, fmt.Errorf("routes error: %w", err)
+	}
+	err = table.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("routes.Validate %w", err)
+	}
+	return table, nil
+}
+
+func (t RouteTable) Validate() error {
+	err := validator.New().Struct(t)
+	if err != nil {
+		return err
+	}
+
+	for name, upstream := range t.Upstreams {
+		// the proxy joins the request path to the path of the instances
+		path := ""
+		for i, instance := range upstream.Instances {
+			instanceURL, err := url.Parse(instance)
+			if err != nil {
+				return fmt.Errorf("%w: upstream %s: %s", ErrInvalidRoute, name, err)
+			}
+			if i > 0 && strings.TrimSuffix(instanceURL.Path, "/") != path {
+				return fmt.Errorf("%w: upstream %s: instances with different paths", ErrInvalidRoute, name)
+			}
+			path = strings.TrimSuffix(instanceURL.Path, "/")
+		}
+	}
+
+	for name, policy := range t.RateLimits.Policies {
+		for role := range policy.Roles {
+			_, err := authz.ParseRoles([]string{role})
+			if err != nil {
+				return fmt.Errorf("%w: rate limit policy %s: %s", ErrInvalidRoute, name, err)
+			}
+		}
+	}
+	keys := map[string]bool{}
+	for _, key := range t.RateLimits.APIKeys {
+		if keys[key.Key] {
+			return fmt.Errorf("%w: API key %s is declared twice", ErrInvalidRoute, key.Name)
+		}
+		keys[key.Key] = true
+	}
+
+	registered := map[string]bool{}
+	for _, route := range t.Routes {
+		if route.Handler != "" {
+			err := validateHandler(route)
+			if err != nil {
+				return err
+			}
+		} else if _, ok := t.Upstreams[route.Upstream]; !ok {
+			return fmt.Errorf("%w: %s: unknown upstream %q", ErrInvalidRoute, route.Path, route.Upstream)
+		}
+		for _, method := range route.Methods {
+			key := method + " " + route.Path
+			if registered[key] {
+				return fmt.Errorf("%w: %s declared twice", ErrInvalidRoute, key)
+			}
+			registered[key] = true
+		}
+		if _, ok := t.RateLimits.Policies[route.RateLimit]; route.RateLimit != "" && !ok {
+			return fmt.Errorf("%w: %s: unknown rate limit policy %q", ErrInvalidRoute, route.Path, route.RateLimit)
+		}
+		if (route.Permission != "" || len(route.Roles) > 0) && !route.Auth {
+			return fmt.Errorf("%w: %s: permission and roles require auth", ErrInvalidRoute, route.Path)
+		}
+		if route.Permission != "" {
+			_, err := authz.ParsePermission(route.Permission)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %s", ErrInvalidRoute, route.Path, err)
+			}
+		}
+		_, err := authz.ParseRoles(route.Roles)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidRoute, route.Path, err)
+		}
+		for _, rewrite := range route.Rewrite {
+			_, err := regexp.Compile(rewrite.Pattern)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %s", ErrInvalidRoute, route.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateHandler(route Route) error {
+	if route.Upstream != "" {
+		return fmt.Errorf("%w: %s: a route has either an upstream or a handler", ErrInvalidRoute, route.Path)
+	}
+	if route.Timeout > 0 || len(route.Rewrite) > 0 {
+		return fmt.Errorf("%w: %s: timeout and rewrite only apply to upstreams", ErrInvalidRoute, route.Path)
+	}
+	// both call the services as the logged in user
+	if (route.Handler == HandlerDashboard || route.Handler == HandlerGraphQL) && !route.Auth {
+		return fmt.Errorf("%w: %s: the %s handler requires auth", ErrInvalidRoute, route.Path, route.Handler)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package config_test
+
+import (
+	"gateway/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRoutes_ShippedTable(t *testing.T) {
+	for _, name := range []string{
+		"AUTHENTICATION_SERVICE_URL",
+		"CATALOG_SERVICE_URL",
+		"CART_SERVICE_URL",
+		"ORDERS_SERVICE_URL",
+		"NOTIFICATION_SERVICE_URL",
+		"CHAT_SERVICE_URL",
+		"CHAT_SERVICE_WEBSOCKET_URL",
+	} {
+		t.Setenv(name, "http://service.test")
+	}
+
+	table, err := config.LoadRoutes("routes.yml")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, table.RateLimits.Global)
+	handlers := map[string]bool{}
+	for _, route := range table.Routes {
+		if route.Handler != "" {
+			handlers[route.Handler] = true
+			// the aggregating handlers call several services per request
+			if route.Handler != config.HandlerGraphQLSchema {
+				assert.NotEmpty(t, route.RateLimit, route.Path)
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{
+		config.HandlerDashboard:     true,
+		config.HandlerGraphQL:       true,
+		config.HandlerGraphQLSchema: true,
+	}, handlers)
+}
+
+func TestParseRoutes_Handlers(t *testing.T) {
+	t.Parallel()
+	const upstreams = `
+upstreams:
+  cart: http://cart.test
+routes:
+`
+	testCases := []struct {
+		name   string
+		route  string
+		expErr bool
+	}{
+		{
+			name:  "Handler",
+			route: "{path: /v1/graphql, methods: [POST], handler: graphql, auth: true}",
+		},
+		{
+			name:  "SchemaWithoutAuth",
+			route: "{path: /v1/graphql/schema, methods: [GET], handler: graphql_schema}",
+		},
+		{
+			name:   "UnknownHandler_ReturnsError",
+			route:  "{path: /v1/orders, methods: [GET], handler: orders, auth: true}",
+			expErr: true,
+		},
+		{
+			name:   "HandlerAndUpstream_ReturnsError",
+			route:  "{path: /v1/graphql, methods: [POST], handler: graphql, upstream: cart, auth: true}",
+			expErr: true,
+		},
+		{
+			name:   "NeitherHandlerNorUpstream_ReturnsError",
+			route:  "{path: /v1/graphql, methods: [POST], auth: true}",
+			expErr: true,
+		},
+		{
+			name:   "DashboardWithoutAuth_ReturnsError",
+			route:  "{path: /v1/me/dashboard, methods: [GET], handler: dashboard}",
+			expErr: true,
+		},
+		{
+			name:   "HandlerWithTimeout_ReturnsError",
+			route:  "{path: /v1/graphql, methods: [POST], handler: graphql, auth: true, timeout: 5s}",
+			expErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := config.ParseRoutes([]byte(upstreams + "  - " + tc.route + "\n"))
+
+			if tc.expErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package applicationServices
+
+import (
+	"context"
+	"encoding/json"
+	"gateway/config"
+	"net/url"
+	"shared/money"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// catalog requests sent at once for the products of a cart
+const _maxConcurrentProductCalls = 8
+
+// Dashboard sections the services could not deliver are listed in
+// Unavailable and left empty.
+type Dashboard struct {
+	User                User           `json:"user"`
+	Cart                *DashboardCart `json:"cart"`
+	UnreadNotifications *int           `json:"unreadNotifications"`
+	Unavailable         []string       `json:"unavailable,omitempty"`
+}
+
+type DashboardCart struct {
+	Products     []DashboardCartProduct `json:"products"`
+	TotalPrice   money.Money            `json:"totalPrice"`
+	PriceChanged bool                   `json:"priceChanged"`
+}
+
+type DashboardCartProduct struct {
+	ProductID    string      `json:"productId"`
+	VariantID    string      `json:"variantId,omitempty"`
+	SKU          string      `json:"sku,omitempty"`
+	Name         string      `json:"name"`
+	Quantity     int         `json:"quantity"`
+	Price        money.Money `json:"price"`
+	AddedPrice   money.Money `json:"addedPrice"`
+	PriceChanged bool        `json:"priceChanged"`
+	// the catalog product, missing when the catalog did not answer
+	Product json.RawMessage `json:"product,omitempty"`
+}
+
+type notificationList struct {
+	Notifications []struct {
+		ViewedAt *string `json:"viewedAt"`
+	} `json:"notifications"`
+}
+
+type DashboardApplicationService interface {
+	// GetDashboard calls the services as the user, token is the identity
+	// token of the user.
+	GetDashboard(ctx context.Context, user User, token string, currency string) Dashboard
+}
+
+type dashboardApplicationService struct {
+	logger       zerolog.Logger
+	config       *config.Config
+	cart         Upstream
+	catalog      Upstream
+	notification Upstream
+}
+
+func NewDashboardApplicationService(
+	logger zerolog.Logger,
+	config *config.Config,
+	cart Upstream,
+	catalog Upstream,
+	notification Upstream,
+) dashboardApplicationService {
+	return dashboardApplicationService{logger, config, cart, catalog, notification}
+}
+
+func (d dashboardApplicationService) GetDashboard(ctx context.Context, user User, token string, currency string) Dashboard {
+	dashboard := Dashboard{User: user}
+	var cartErr, notificationsErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		var cart *DashboardCart
+		cart, cartErr = d.getCart(ctx, token, currency)
+		dashboard.Cart = cart
+	}()
+	go func() {
+		defer wg.Done()
+		var unread int
+		unread, notificationsErr = d.countUnreadNotifications(ctx, token)
+		if notificationsErr == nil {
+			dashboard.UnreadNotifications = &unread
+		}
+	}()
+	wg.Wait()
+
+	if cartErr != nil {
+		d.logger.Warn().Err(cartErr).Msg("GetDashboard -> d.getCart")
+		dashboard.Unavailable = append(dashboard.Unavailable, "cart")
+	}
+	if notificationsErr != nil {
+		d.logger.Warn().Err(notificationsErr).Msg("GetDashboard -> d.countUnreadNotifications")
+		dashboard.Unavailable = append(dashboard.Unavailable, "notifications")
+	}
+	return dashboard
+}
+
+func (d dashboardApplicationService) getCart(ctx context.Context, token string, currency string) (*DashboardCart, error) {
+	path := "/v1/cart"
+	if currency != "" {
+		path += "?currency=" + url.QueryEscape(currency)
+	}
+	var cart DashboardCart
+	err := d.get(ctx, d.cart, path, token, &cart)
+	if err != nil {
+		return nil, err
+	}
+
+	// a product the catalog cannot deliver keeps the details the cart has
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, _maxConcurrentProductCalls)
+	for i := range cart.Products {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(product *DashboardCartProduct) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			var details json.RawMessage
+			err := d.get(ctx, d.catalog, "/v1/products/"+url.PathEscape(product.ProductID), token, &details)
+			if err != nil {
+				d.logger.Warn().Err(err).Str("productId", product.ProductID).Msg("getCart -> d.get")
+				return
+			}
+			product.Product = details
+		}(&cart.Products[i])
+	}
+	wg.Wait()
+	return &cart, nil
+}
+
+func (d dashboardApplicationService) countUnreadNotifications(ctx context.Context, token string) (int, error) {
+	var list notificationList
+	err := d.get(ctx, d.notification, "/v1/users/me/notifications", token, &list)
+	if err != nil {
+		return 0, err
+	}
+	unread := 0
+	for _, notification := range list.Notifications {
+		if notification.ViewedAt == nil {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+// get gives each call its own timeout so a slow service only costs its own
+// section.
+func (d dashboardApplicationService) get(ctx context.Context, upstream Upstream, path string, token string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Dashboard.CallTimeout)
+	defer cancel()
+	return upstream.Get(ctx, path, token, v)
+}
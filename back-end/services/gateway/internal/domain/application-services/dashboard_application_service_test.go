@@ -0,0 +1,175 @@
+package applicationServices_test
+
+import (
+	"context"
+	"encoding/json"
+	"gateway/config"
+	applicationServices "gateway/internal/domain/application-services"
+	"net/http"
+	"net/http/httptest"
+	"shared/authz"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _token = "identity-token"
+
+const _cart = `{
+	"products": [
+		{"productId": "p1", "name": "Phone", "quantity": 1, "price": {"amount": "100.00", "currency": "USD"}, "addedPrice": {"amount": "90.00", "currency": "USD"}, "priceChanged": true},
+		{"productId": "p2", "name": "Case", "quantity": 2, "price": {"amount": "5.00", "currency": "USD"}, "addedPrice": {"amount": "5.00", "currency": "USD"}, "priceChanged": false}
+	],
+	"totalPrice": {"amount": "110.00", "currency": "USD"},
+	"priceChanged": true
+}`
+
+const _notifications = `{"notifications": [{"viewedAt": null}, {"viewedAt": "2023-10-01T12:00:00Z"}, {"viewedAt": null}]}`
+
+// services are the upstreams of the dashboard, every handler checks the
+// identity token.
+type services struct {
+	cart, catalog, notification http.HandlerFunc
+}
+
+func newServices() *services {
+	return &services{
+		cart: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(_cart))
+		},
+		catalog: func(w http.ResponseWriter, r *http.Request) {
+			// the catalog does not know the case
+			if r.URL.Path != "/v1/products/p1" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": "p1", "stock": 3}`))
+		},
+		notification: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(_notifications))
+		},
+	}
+}
+
+func (s *services) upstream(t *testing.T, handler *http.HandlerFunc) applicationServices.Upstream {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authz.Header) != _token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		(*handler)(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return applicationServices.Upstream{URL: server.URL, Client: server.Client()}
+}
+
+func (s *services) dashboardService(t *testing.T) applicationServices.DashboardApplicationService {
+	conf := &config.Config{}
+	conf.Dashboard.CallTimeout = 100 * time.Millisecond
+	return applicationServices.NewDashboardApplicationService(
+		zerolog.Nop(),
+		conf,
+		s.upstream(t, &s.cart),
+		s.upstream(t, &s.catalog),
+		s.upstream(t, &s.notification),
+	)
+}
+
+func down(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusServiceUnavailable)
+}
+
+func TestDashboardApplicationService_GetDashboard(t *testing.T) {
+	t.Parallel()
+	const cart = `{
+		"products": [
+			{"productId": "p1", "name": "Phone", "quantity": 1, "price": {"amount": "100.00", "currency": "USD"}, "addedPrice": {"amount": "90.00", "currency": "USD"}, "priceChanged": true, "product": {"id": "p1", "stock": 3}},
+			{"productId": "p2", "name": "Case", "quantity": 2, "price": {"amount": "5.00", "currency": "USD"}, "addedPrice": {"amount": "5.00", "currency": "USD"}, "priceChanged": false}
+		],
+		"totalPrice": {"amount": "110.00", "currency": "USD"},
+		"priceChanged": true
+	}`
+	const user = `{"id": "u1", "name": "Jane", "email": "jane@example.com", "roles": ["customer"], "session_id": "s1"}`
+
+	testCases := []struct {
+		name  string
+		setup func(s *services)
+		want  string
+	}{
+		{
+			name: "AllServicesUp",
+			want: `{"user": ` + user + `, "cart": ` + cart + `, "unreadNotifications": 2}`,
+		},
+		{
+			name:  "CartDown",
+			setup: func(s *services) { s.cart = down },
+			want:  `{"user": ` + user + `, "cart": null, "unreadNotifications": 2, "unavailable": ["cart"]}`,
+		},
+		{
+			name:  "NotificationDown",
+			setup: func(s *services) { s.notification = down },
+			want:  `{"user": ` + user + `, "cart": ` + cart + `, "unreadNotifications": null, "unavailable": ["notifications"]}`,
+		},
+		{
+			name: "NotificationTimesOut",
+			setup: func(s *services) {
+				s.notification = func(w http.ResponseWriter, r *http.Request) {
+					<-r.Context().Done()
+				}
+			},
+			want: `{"user": ` + user + `, "cart": ` + cart + `, "unreadNotifications": null, "unavailable": ["notifications"]}`,
+		},
+		{
+			// the products keep the details the cart has
+			name:  "CatalogDown",
+			setup: func(s *services) { s.catalog = down },
+			want:  `{"user": ` + user + `, "cart": ` + _cart + `, "unreadNotifications": 2}`,
+		},
+		{
+			name: "AllServicesDown",
+			setup: func(s *services) {
+				s.cart, s.catalog, s.notification = down, down, down
+			},
+			want: `{"user": ` + user + `, "cart": null, "unreadNotifications": null, "unavailable": ["cart", "notifications"]}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := newServices()
+			if tc.setup != nil {
+				tc.setup(s)
+			}
+			service := s.dashboardService(t)
+			var u applicationServices.User
+			require.NoError(t, json.Unmarshal([]byte(user), &u))
+
+			dashboard := service.GetDashboard(context.Background(), u, _token, "")
+
+			body, err := json.Marshal(dashboard)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(body))
+		})
+	}
+}
+
+func TestDashboardApplicationService_GetDashboard_Currency(t *testing.T) {
+	t.Parallel()
+	s := newServices()
+	var currency string
+	cart := s.cart
+	s.cart = func(w http.ResponseWriter, r *http.Request) {
+		currency = r.URL.Query().Get("currency")
+		cart(w, r)
+	}
+	service := s.dashboardService(t)
+
+	dashboard := service.GetDashboard(context.Background(), applicationServices.User{ID: "u1"}, _token, "EUR")
+
+	assert.Equal(t, "EUR", currency)
+	assert.Empty(t, dashboard.Unavailable)
+}
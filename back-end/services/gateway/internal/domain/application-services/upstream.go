@@ -0,0 +1,41 @@
+package applicationServices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"shared/authz"
+)
+
+// Upstream is a service the gateway calls itself.
+type Upstream struct {
+	URL    string
+	Client *http.Client
+}
+
+// Get decodes the response of the service into v, token is the identity
+// token of the caller.
+func (u Upstream) Get(ctx context.Context, path string, token string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.URL+path, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set(authz.Header, token)
+	}
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", path, resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", path, err)
+	}
+	return nil
+}
@@ -1,25 +1,39 @@
 package applicationServices
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"gateway/config"
 
 	"net/http"
+	"shared/authz"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
+var ErrAuthenticationUnavailable = errors.New("authentication service is unavailable")
+
 type userApplicationService struct {
 	logger zerolog.Logger
 	config *config.Config
+	client *http.Client
+	cache  SessionCache
 }
 
 type User struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	SessionID string `json:"session_id"`
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Email     string       `json:"email"`
+	Roles     []authz.Role `json:"roles"`
+	SessionID string       `json:"session_id"`
+}
+
+func (u User) Identity() authz.Identity {
+	return authz.Identity{UserID: u.ID, Roles: u.Roles, SessionID: u.SessionID}
 }
 
 type UserResponse struct {
@@ -27,39 +41,109 @@ type UserResponse struct {
 	SessionID string `json:"session_id"`
 }
 
+// SessionCache keeps the users of sessions, keyed by a hash of the session
+// cookie.
+type SessionCache interface {
+	Get(ctx context.Context, key string) (UserResponse, bool, error)
+	// Generation grows with every DeleteUserSessions. Set drops the session
+	// when its user was deleted after generation was read, so a lookup
+	// racing an invalidation cannot cache the user it replaced.
+	Generation(ctx context.Context) (int64, error)
+	Set(ctx context.Context, key string, session UserResponse, generation int64) error
+	DeleteUserSessions(ctx context.Context, userID string) error
+}
+
 type UserApplicationService interface {
-	GetCurrentUser(http.Header) (User, string, error)
+	GetCurrentUser(ctx context.Context, headers http.Header) (User, string, error)
+	InvalidateUserSessions(ctx context.Context, userID string) error
 }
 
 func NewUserApplicationService(
 	logger zerolog.Logger,
 	config *config.Config,
+	client *http.Client,
+	cache SessionCache,
 ) userApplicationService {
-	return userApplicationService{logger, config}
+	return userApplicationService{logger, config, client, cache}
 }
 
-func (u userApplicationService) GetCurrentUser(headers http.Header) (User, string, error) {
-	req, err := http.NewRequest("GET", u.config.AuthenticationServiceURL+"/v1/users/me/internal", nil)
+func (u userApplicationService) GetCurrentUser(ctx context.Context, headers http.Header) (User, string, error) {
+	cookie := sessionCookie(headers, u.config.Session.CookieName)
+	if cookie == "" {
+		return User{}, "", nil
+	}
+	key := cacheKey(cookie)
+	cached, found, err := u.cache.Get(ctx, key)
+	if err != nil {
+		u.logger.Warn().Err(err).Msg("GetCurrentUser -> u.cache.Get")
+	}
+	if found {
+		return cached.User, cached.SessionID, nil
+	}
+
+	// read before the lookup, the user may change while it runs
+	generation, generationErr := u.cache.Generation(ctx)
+	if generationErr != nil {
+		u.logger.Warn().Err(generationErr).Msg("GetCurrentUser -> u.cache.Generation")
+	}
+	userResponse, err := u.fetchCurrentUser(ctx, headers)
 	if err != nil {
 		return User{}, "", err
 	}
+	// anonymous sessions are not cached, a login changes the cookie anyway
+	if userResponse.User.ID != "" && generationErr == nil {
+		err = u.cache.Set(ctx, key, userResponse, generation)
+		if err != nil {
+			u.logger.Warn().Err(err).Msg("GetCurrentUser -> u.cache.Set")
+		}
+	}
+	return userResponse.User, userResponse.SessionID, nil
+}
 
-	req.Header = headers
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// InvalidateUserSessions drops the cached sessions of a user, the next
+// request of each session looks the user up again.
+func (u userApplicationService) InvalidateUserSessions(ctx context.Context, userID string) error {
+	err := u.cache.DeleteUserSessions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("userApplicationService -> InvalidateUserSessions - u.cache.DeleteUserSessions: %w", err)
+	}
+	return nil
+}
 
+func (u userApplicationService) fetchCurrentUser(ctx context.Context, headers http.Header) (UserResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u.config.AuthenticationServiceURL+"/v1/users/me/internal", nil)
 	if err != nil {
-		return User{}, "", err
+		return UserResponse{}, err
+	}
+	req.Header = headers.Clone()
+	req.Header.Del(authz.Header)
 
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return UserResponse{}, fmt.Errorf("%w: %s", ErrAuthenticationUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return UserResponse{}, fmt.Errorf("%w: status %d", ErrAuthenticationUnavailable, resp.StatusCode)
 	}
 
 	var userResponse UserResponse
-	decoder := json.NewDecoder(resp.Body)
-	err = decoder.Decode(&userResponse)
+	err = json.NewDecoder(resp.Body).Decode(&userResponse)
+	if err != nil {
+		return UserResponse{}, errors.Wrap(err, "GetCurrentUser -> decoding user error")
+	}
+	return userResponse, nil
+}
 
+func sessionCookie(headers http.Header, name string) string {
+	cookie, err := (&http.Request{Header: headers}).Cookie(name)
 	if err != nil {
-		return User{}, "", errors.Wrap(err, "GetCurrentUser -> decoding user error")
+		return ""
 	}
+	return cookie.Value
+}
 
-	return userResponse.User, userResponse.SessionID, nil
+func cacheKey(cookie string) string {
+	sum := sha256.Sum256([]byte(cookie))
+	return hex.EncodeToString(sum[:])
 }
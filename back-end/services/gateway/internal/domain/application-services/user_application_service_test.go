@@ -0,0 +1,198 @@
+package applicationServices_test
+
+import (
+	"context"
+	"errors"
+	"gateway/config"
+	applicationServices "gateway/internal/domain/application-services"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCache follows the contract of the Redis session cache.
+type memoryCache struct {
+	mu          sync.Mutex
+	sessions    map[string]applicationServices.UserResponse
+	generation  int64
+	invalidated map[string]int64
+	err         error
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{sessions: map[string]applicationServices.UserResponse{}, invalidated: map[string]int64{}}
+}
+
+func (m *memoryCache) Get(_ context.Context, key string) (applicationServices.UserResponse, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return applicationServices.UserResponse{}, false, m.err
+	}
+	session, ok := m.sessions[key]
+	return session, ok, nil
+}
+
+func (m *memoryCache) Generation(context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.generation, m.err
+}
+
+func (m *memoryCache) Set(_ context.Context, key string, session applicationServices.UserResponse, generation int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if m.invalidated[session.User.ID] > generation {
+		return nil
+	}
+	m.sessions[key] = session
+	return nil
+}
+
+func (m *memoryCache) DeleteUserSessions(_ context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generation++
+	m.invalidated[userID] = m.generation
+	for key, session := range m.sessions {
+		if session.User.ID == userID {
+			delete(m.sessions, key)
+		}
+	}
+	return nil
+}
+
+// authentication answers the lookups with the current name of the user,
+// during is called while a lookup runs.
+type authentication struct {
+	name    atomic.Value
+	lookups atomic.Int64
+	during  func()
+}
+
+func newUserService(t *testing.T, cache applicationServices.SessionCache, auth *authentication) applicationServices.UserApplicationService {
+	auth.name.Store("Jane")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.lookups.Add(1)
+		if cookie, _ := r.Cookie("session"); cookie.Value == "expired" {
+			_, _ = w.Write([]byte(`{"user": {}}`))
+			return
+		}
+		name := auth.name.Load().(string)
+		if auth.during != nil {
+			auth.during()
+		}
+		_, _ = w.Write([]byte(`{"user": {"id": "u1", "name": "` + name + `"}, "session_id": "s1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	conf := &config.Config{AuthenticationServiceURL: server.URL}
+	conf.Session.CookieName = "session"
+	return applicationServices.NewUserApplicationService(zerolog.Nop(), conf, server.Client(), cache)
+}
+
+func sessionHeaders() http.Header {
+	return http.Header{"Cookie": []string{"session=abc"}}
+}
+
+func TestUserApplicationService_GetCurrentUser_CachesSessions(t *testing.T) {
+	t.Parallel()
+	auth := &authentication{}
+	service := newUserService(t, newMemoryCache(), auth)
+
+	for i := 0; i < 3; i++ {
+		user, sessionID, err := service.GetCurrentUser(context.Background(), sessionHeaders())
+
+		require.NoError(t, err)
+		assert.Equal(t, "Jane", user.Name)
+		assert.Equal(t, "s1", sessionID)
+	}
+	assert.Equal(t, int64(1), auth.lookups.Load())
+}
+
+func TestUserApplicationService_InvalidateUserSessions(t *testing.T) {
+	t.Parallel()
+	auth := &authentication{}
+	service := newUserService(t, newMemoryCache(), auth)
+	_, _, err := service.GetCurrentUser(context.Background(), sessionHeaders())
+	require.NoError(t, err)
+
+	auth.name.Store("Joan")
+	require.NoError(t, service.InvalidateUserSessions(context.Background(), "u1"))
+	user, _, err := service.GetCurrentUser(context.Background(), sessionHeaders())
+
+	require.NoError(t, err)
+	assert.Equal(t, "Joan", user.Name)
+	assert.Equal(t, int64(2), auth.lookups.Load())
+}
+
+// The user changes while the authentication service answers with the old
+// one, the answer must not outlive the invalidation in the cache.
+func TestUserApplicationService_GetCurrentUser_InvalidatedDuringLookup(t *testing.T) {
+	t.Parallel()
+	auth := &authentication{}
+	service := newUserService(t, newMemoryCache(), auth)
+	auth.during = func() {
+		auth.during = nil
+		auth.name.Store("Joan")
+		require.NoError(t, service.InvalidateUserSessions(context.Background(), "u1"))
+	}
+
+	user, _, err := service.GetCurrentUser(context.Background(), sessionHeaders())
+	require.NoError(t, err)
+	assert.Equal(t, "Jane", user.Name)
+
+	user, _, err = service.GetCurrentUser(context.Background(), sessionHeaders())
+	require.NoError(t, err)
+	assert.Equal(t, "Joan", user.Name)
+	assert.Equal(t, int64(2), auth.lookups.Load())
+
+	// the fresh user is cached again
+	_, _, err = service.GetCurrentUser(context.Background(), sessionHeaders())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), auth.lookups.Load())
+}
+
+func TestUserApplicationService_GetCurrentUser_CacheDown(t *testing.T) {
+	t.Parallel()
+	cache := newMemoryCache()
+	cache.err = errors.New("redis is down")
+	auth := &authentication{}
+	service := newUserService(t, cache, auth)
+
+	for i := 0; i < 2; i++ {
+		user, _, err := service.GetCurrentUser(context.Background(), sessionHeaders())
+
+		require.NoError(t, err)
+		assert.Equal(t, "u1", user.ID)
+	}
+	assert.Equal(t, int64(2), auth.lookups.Load())
+}
+
+func TestUserApplicationService_GetCurrentUser_Anonymous(t *testing.T) {
+	t.Parallel()
+	auth := &authentication{}
+	cache := newMemoryCache()
+	service := newUserService(t, cache, auth)
+
+	user, _, err := service.GetCurrentUser(context.Background(), http.Header{})
+	require.NoError(t, err)
+	assert.Empty(t, user.ID)
+	assert.Equal(t, int64(0), auth.lookups.Load(), "no cookie, no lookup")
+
+	// the session is not known to the authentication service
+	user, _, err = service.GetCurrentUser(context.Background(), http.Header{"Cookie": []string{"session=expired"}})
+	require.NoError(t, err)
+	assert.Empty(t, user.ID)
+	assert.Equal(t, int64(1), auth.lookups.Load())
+	assert.Empty(t, cache.sessions)
+}
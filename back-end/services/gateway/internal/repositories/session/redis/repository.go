@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	applicationServices "gateway/internal/domain/application-services"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	sessionKeyPrefix        = "gateway:session:"
+	userSessionsKeyPrefix   = "gateway:user-sessions:"
+	generationKey           = "gateway:session-generation"
+	userGenerationKeyPrefix = "gateway:user-generation:"
+)
+
+// setScript stores the session unless its user was invalidated after the
+// generation the lookup started at.
+var setScript = redis.NewScript(3, `
+local invalidated = tonumber(redis.call("GET", KEYS[3]) or "0")
+if invalidated > tonumber(ARGV[3]) then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "EX", ARGV[2])
+redis.call("SADD", KEYS[2], KEYS[1])
+redis.call("EXPIRE", KEYS[2], ARGV[2])
+return 1
+`)
+
+// invalidateScript bumps the generation and records it as the one the user
+// was invalidated at.
+var invalidateScript = redis.NewScript(2, `
+local generation = redis.call("INCR", KEYS[1])
+redis.call("SET", KEYS[2], generation, "EX", ARGV[1])
+return generation
+`)
+
+var _ applicationServices.SessionCache = (*sessionCache)(nil)
+
+type sessionCache struct {
+	pool *redis.Pool
+	ttl  time.Duration
+}
+
+func NewSessionCache(pool *redis.Pool, ttl time.Duration) *sessionCache {
+	return &sessionCache{pool: pool, ttl: ttl}
+}
+
+func (s *sessionCache) Get(ctx context.Context, key string) (applicationServices.UserResponse, bool, error) {
+	conn, err := s.pool.GetContext(ctx)
+	if err != nil {
+		return applicationServices.UserResponse{}, false, fmt.Errorf("sessionCache -> Get - s.pool.GetContext: %w", err)
+	}
+	defer conn.Close()
+
+	data, err := redis.Bytes(conn.Do("GET", sessionKeyPrefix+key))
+	if errors.Is(err, redis.ErrNil) {
+		return applicationServices.UserResponse{}, false, nil
+	}
+	if err != nil {
+		return applicationServices.UserResponse{}, false, fmt.Errorf("sessionCache -> Get - GET: %w", err)
+	}
+	var session applicationServices.UserResponse
+	err = json.Unmarshal(data, &session)
+	if err != nil {
+		return applicationServices.UserResponse{}, false, fmt.Errorf("sessionCache -> Get - json.Unmarshal: %w", err)
+	}
+	return session, true, nil
+}
+
+func (s *sessionCache) Generation(ctx context.Context) (int64, error) {
+	conn, err := s.pool.GetContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("sessionCache -> Generation - s.pool.GetContext: %w", err)
+	}
+	defer conn.Close()
+
+	generation, err := redis.Int64(conn.Do("GET", generationKey))
+	if errors.Is(err, redis.ErrNil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("sessionCache -> Generation - GET: %w", err)
+	}
+	return generation, nil
+}
+
+// Set stores the session and indexes it by its user so that it can be
+// dropped when the user changes. A session whose user was invalidated after
+// generation is dropped instead.
+func (s *sessionCache) Set(ctx context.Context, key string, session applicationServices.UserResponse, generation int64) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("sessionCache -> Set - json.Marshal: %w", err)
+	}
+	conn, err := s.pool.GetContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sessionCache -> Set - s.pool.GetContext: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = setScript.Do(conn,
+		sessionKeyPrefix+key,
+		userSessionsKeyPrefix+session.User.ID,
+		userGenerationKeyPrefix+session.User.ID,
+		data,
+		int(s.ttl.Seconds()),
+		generation,
+	)
+	if err != nil {
+		return fmt.Errorf("sessionCache -> Set - setScript.Do: %w", err)
+	}
+	return nil
+}
+
+func (s *sessionCache) DeleteUserSessions(ctx context.Context, userID string) error {
+	conn, err := s.pool.GetContext(ctx)
+	if err != nil {
+		return fmt.Errorf("sessionCache -> DeleteUserSessions - s.pool.GetContext: %w", err)
+	}
+	defer conn.Close()
+
+	// first, lookups running now must not cache the sessions deleted below.
+	// The generation only has to outlive them, the session TTL is plenty.
+	_, err = invalidateScript.Do(conn, generationKey, userGenerationKeyPrefix+userID, int(s.ttl.Seconds()))
+	if err != nil {
+		return fmt.Errorf("sessionCache -> DeleteUserSessions - invalidateScript.Do: %w", err)
+	}
+	userKey := userSessionsKeyPrefix + userID
+	keys, err := redis.Strings(conn.Do("SMEMBERS", userKey))
+	if err != nil {
+		return fmt.Errorf("sessionCache -> DeleteUserSessions - SMEMBERS: %w", err)
+	}
+	_, err = conn.Do("DEL", redis.Args{}.Add(userKey).AddFlat(keys)...)
+	if err != nil {
+		return fmt.Errorf("sessionCache -> DeleteUserSessions - DEL: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Package graphql serves the services of the marketplace as one GraphQL
+// schema, the resolvers call the services as the logged in user.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"gateway/config"
+	applicationServices "gateway/internal/domain/application-services"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/graph-gophers/dataloader"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/rs/zerolog"
+)
+
+// the most products the catalog returns in one call
+const _maxProductBatch = 100
+
+// SDL is the schema in the schema definition language.
+//
+//go:embed schema.graphql
+var SDL string
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type requestKey struct{}
+
+// request is what the resolvers of one request share.
+type request struct {
+	user     applicationServices.User
+	token    string
+	products *dataloader.Loader
+}
+
+// Resolver resolves the fields of Query.
+type Resolver struct {
+	logger       zerolog.Logger
+	config       *config.Config
+	cart         applicationServices.Upstream
+	catalog      applicationServices.Upstream
+	notification applicationServices.Upstream
+}
+
+func NewResolver(
+	logger zerolog.Logger,
+	config *config.Config,
+	cart applicationServices.Upstream,
+	catalog applicationServices.Upstream,
+	notification applicationServices.Upstream,
+) *Resolver {
+	return &Resolver{logger, config, cart, catalog, notification}
+}
+
+// NewContext returns the context to execute a request of user with, token is
+// the identity token of the user. Product lookups made while the request is
+// executed are batched into catalog calls.
+func (r *Resolver) NewContext(ctx context.Context, user applicationServices.User, token string) context.Context {
+	req := &request{user: user, token: token}
+	req.products = dataloader.NewBatchedLoader(
+		func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+			return r.getProducts(ctx, token, keys.Keys())
+		},
+		dataloader.WithWait(r.config.GraphQL.BatchWait),
+		dataloader.WithBatchCapacity(_maxProductBatch),
+	)
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+func fromContext(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// NewSchema -.
+func NewSchema(r *Resolver) (*graphql.Schema, error) {
+	return graphql.ParseSchema(
+		SDL,
+		r,
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(r.config.GraphQL.MaxDepth),
+		graphql.MaxParallelism(r.config.GraphQL.MaxConcurrency),
+	)
+}
+
+func (r *Resolver) Me(ctx context.Context) *userResolver {
+	return &userResolver{fromContext(ctx).user}
+}
+
+func (r *Resolver) Product(ctx context.Context, args struct{ ID graphql.ID }) (*productResolver, error) {
+	if !uuidPattern.MatchString(string(args.ID)) {
+		return nil, nil
+	}
+	return r.loadProduct(ctx, string(args.ID))
+}
+
+type productsArgs struct {
+	Q          *string
+	CategoryID *graphql.ID
+	InStock    bool
+	Sort       *string
+	Currency   *string
+	First      int32
+	After      *string
+}
+
+func (r *Resolver) Products(ctx context.Context, args productsArgs) (*productPageResolver, error) {
+	first := int(args.First)
+	if first < 1 || first > _maxProductBatch {
+		return nil, fmt.Errorf("first must be between 1 and %d", _maxProductBatch)
+	}
+	query := url.Values{"limit": {strconv.Itoa(first)}}
+	for param, value := range map[string]*string{"q": args.Q, "category_id": (*string)(args.CategoryID), "sort": args.Sort, "currency": args.Currency, "cursor": args.After} {
+		if value != nil && *value != "" {
+			query.Set(param, *value)
+		}
+	}
+	if args.InStock {
+		query.Set("in_stock", "true")
+	}
+
+	var list productList
+	err := r.get(ctx, r.catalog, "/v1/products?"+query.Encode(), &list)
+	if err != nil {
+		r.logger.Warn().Err(err).Msg("Resolver -> Products - r.get")
+		return nil, errors.New("the catalog is unavailable")
+	}
+	return &productPageResolver{&list}, nil
+}
+
+func (r *Resolver) Cart(ctx context.Context, args struct{ Currency *string }) (*cartResolver, error) {
+	path := "/v1/cart"
+	if args.Currency != nil && *args.Currency != "" {
+		path += "?currency=" + url.QueryEscape(*args.Currency)
+	}
+	var c cart
+	err := r.get(ctx, r.cart, path, &c)
+	if err != nil {
+		r.logger.Warn().Err(err).Msg("Resolver -> Cart - r.get")
+		return nil, errors.New("the cart is unavailable")
+	}
+	return &cartResolver{resolver: r, cart: &c}, nil
+}
+
+func (r *Resolver) Notifications(ctx context.Context, args struct{ Unread bool }) (*[]*notificationResolver, error) {
+	var list notificationList
+	err := r.get(ctx, r.notification, "/v1/users/me/notifications", &list)
+	if err != nil {
+		r.logger.Warn().Err(err).Msg("Resolver -> Notifications - r.get")
+		return nil, errors.New("the notifications are unavailable")
+	}
+	notifications := make([]*notificationResolver, 0, len(list.Notifications))
+	for i := range list.Notifications {
+		if args.Unread && list.Notifications[i].ViewedAt != nil {
+			continue
+		}
+		notifications = append(notifications, &notificationResolver{&list.Notifications[i]})
+	}
+	return &notifications, nil
+}
+
+func (r *Resolver) loadProduct(ctx context.Context, id string) (*productResolver, error) {
+	found, err := fromContext(ctx).products.Load(ctx, dataloader.StringKey(id))()
+	if err != nil {
+		r.logger.Warn().Err(err).Str("productId", id).Msg("Resolver -> loadProduct - products.Load")
+		return nil, errors.New("the catalog is unavailable")
+	}
+	if found == nil {
+		return nil, nil
+	}
+	return &productResolver{found.(*product)}, nil
+}
+
+// getProducts is the batch function of the product loader, one catalog call
+// fetches the products of all the ids. Unknown ids resolve to nil.
+func (r *Resolver) getProducts(ctx context.Context, token string, ids []string) []*dataloader.Result {
+	results := make([]*dataloader.Result, len(ids))
+	query := url.Values{"ids": ids, "limit": {strconv.Itoa(len(ids))}}
+	var list productList
+	err := r.getAs(ctx, token, r.catalog, "/v1/products?"+query.Encode(), &list)
+	if err != nil {
+		err = fmt.Errorf("Resolver -> getProducts - r.getAs: %w", err)
+		for i := range results {
+			results[i] = &dataloader.Result{Error: err}
+		}
+		return results
+	}
+	products := make(map[string]*product, len(list.Data))
+	for i := range list.Data {
+		products[list.Data[i].ID] = &list.Data[i]
+	}
+	for i, id := range ids {
+		// a nil *product would not compare equal to nil once boxed
+		result := &dataloader.Result{}
+		if found, ok := products[id]; ok {
+			result.Data = found
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// get calls the service as the user of the request.
+func (r *Resolver) get(ctx context.Context, upstream applicationServices.Upstream, path string, v interface{}) error {
+	return r.getAs(ctx, fromContext(ctx).token, upstream, path, v)
+}
+
+func (r *Resolver) getAs(ctx context.Context, token string, upstream applicationServices.Upstream, path string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, r.config.GraphQL.CallTimeout)
+	defer cancel()
+	return upstream.Get(ctx, path, token, v)
+}
@@ -0,0 +1,136 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"gateway/config"
+	applicationServices "gateway/internal/domain/application-services"
+	"gateway/internal/transport/graphql"
+	"net/http"
+	"net/http/httptest"
+	"shared/authz"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _token = "identity-token"
+
+const _cart = `{
+	"products": [
+		{"productId": "p1", "name": "Phone", "quantity": 1, "price": {"amount": "100.00", "currency": "USD"}, "addedPrice": {"amount": "90.00", "currency": "USD"}, "priceChanged": true},
+		{"productId": "p2", "variantId": "v1", "name": "Case", "quantity": 2, "price": {"amount": "5.00", "currency": "USD"}, "addedPrice": {"amount": "5.00", "currency": "USD"}, "priceChanged": false}
+	],
+	"totalPrice": {"amount": "110.00", "currency": "USD"},
+	"priceChanged": true
+}`
+
+const _notifications = `{"notifications": [
+	{"id": "n1", "title": "Shipped", "message": "", "notificationTypeId": "t1", "viewedAt": null, "createdAt": "2023-10-01T12:00:00Z"},
+	{"id": "n2", "title": "Paid", "message": "", "notificationTypeId": "t1", "viewedAt": "2023-10-02T12:00:00Z", "createdAt": "2023-10-01T11:00:00Z"}
+]}`
+
+// catalog knows p1 only and records the ids of every call
+type catalog struct {
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (c *catalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	c.calls = append(c.calls, r.URL.Query()["ids"])
+	c.mu.Unlock()
+	_, _ = w.Write([]byte(`{"data": [{"id": "p1", "name": "Phone", "price": {"amount": "100.00", "currency": "USD"}, "createdAt": "2023-10-01T12:00:00Z"}], "total": 1}`))
+}
+
+func upstream(t *testing.T, handler http.Handler) applicationServices.Upstream {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authz.Header) != _token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return applicationServices.Upstream{URL: server.URL, Client: server.Client()}
+}
+
+func text(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func execute(t *testing.T, catalog *catalog, query string) (map[string]interface{}, []interface{}) {
+	t.Helper()
+	conf := &config.Config{}
+	conf.GraphQL.CallTimeout = time.Second
+	conf.GraphQL.BatchWait = 10 * time.Millisecond
+	conf.GraphQL.MaxDepth = 12
+	conf.GraphQL.MaxConcurrency = 8
+	resolver := graphql.NewResolver(zerolog.Nop(), conf, upstream(t, text(_cart)), upstream(t, catalog), upstream(t, text(_notifications)))
+	schema, err := graphql.NewSchema(resolver)
+	require.NoError(t, err)
+
+	ctx := resolver.NewContext(context.Background(), applicationServices.User{ID: "user_id", Roles: []authz.Role{authz.RoleCustomer}}, _token)
+	response := schema.Exec(ctx, query, "", nil)
+	body, err := json.Marshal(response)
+	require.NoError(t, err)
+	var result struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []interface{}          `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	return result.Data, result.Errors
+}
+
+func TestSchema_CartLineProductsAreBatched(t *testing.T) {
+	t.Parallel()
+	catalog := &catalog{}
+
+	data, errs := execute(t, catalog, `{ cart { lines { productId variantId addedPrice { amount } product { name createdAt } } } }`)
+
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"lines": []interface{}{
+			map[string]interface{}{
+				"productId":  "p1",
+				"variantId":  nil,
+				"addedPrice": map[string]interface{}{"amount": "90.00"},
+				"product":    map[string]interface{}{"name": "Phone", "createdAt": "2023-10-01T12:00:00Z"},
+			},
+			map[string]interface{}{
+				"productId":  "p2",
+				"variantId":  "v1",
+				"addedPrice": map[string]interface{}{"amount": "5.00"},
+				"product":    nil,
+			},
+		},
+	}, data["cart"])
+	require.Len(t, catalog.calls, 1)
+	assert.ElementsMatch(t, []string{"p1", "p2"}, catalog.calls[0])
+}
+
+func TestSchema_UnreadNotifications(t *testing.T) {
+	t.Parallel()
+
+	data, errs := execute(t, &catalog{}, `{ me { id roles } notifications(unread: true) { id viewedAt } }`)
+
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"id": "user_id", "roles": []interface{}{"customer"}}, data["me"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "n1", "viewedAt": nil}}, data["notifications"])
+}
+
+func TestSchema_Introspection(t *testing.T) {
+	t.Parallel()
+
+	data, errs := execute(t, &catalog{}, `{ __type(name: "CartLine") { fields { name } } }`)
+
+	require.Empty(t, errs)
+	fields := data["__type"].(map[string]interface{})["fields"].([]interface{})
+	assert.Contains(t, fields, map[string]interface{}{"name": "addedPrice"})
+}
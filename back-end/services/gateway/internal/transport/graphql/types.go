@@ -0,0 +1,352 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	applicationServices "gateway/internal/domain/application-services"
+	"shared/money"
+	"sort"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// the shapes the services answer with, decoded as much as the schema needs
+
+type product struct {
+	ID          string            `json:"id"`
+	SellerID    string            `json:"sellerId"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	CategoryID  string            `json:"categoryId"`
+	Attributes  map[string]string `json:"attributes"`
+	Price       money.Money       `json:"price"`
+	Quantity    int               `json:"quantity"`
+	Images      []image           `json:"images"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+type image struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+type productList struct {
+	Data       []product `json:"data"`
+	Total      int       `json:"total"`
+	NextCursor string    `json:"nextCursor"`
+}
+
+type cart struct {
+	Products     []cartLine  `json:"products"`
+	TotalPrice   money.Money `json:"totalPrice"`
+	PriceChanged bool        `json:"priceChanged"`
+}
+
+type cartLine struct {
+	ProductID    string      `json:"productId"`
+	VariantID    string      `json:"variantId"`
+	SKU          string      `json:"sku"`
+	Name         string      `json:"name"`
+	Quantity     int         `json:"quantity"`
+	Price        money.Money `json:"price"`
+	AddedPrice   money.Money `json:"addedPrice"`
+	PriceChanged bool        `json:"priceChanged"`
+}
+
+type notificationList struct {
+	Notifications []notification `json:"notifications"`
+}
+
+type notification struct {
+	ID                 string     `json:"id"`
+	Title              string     `json:"title"`
+	Message            string     `json:"message"`
+	NotificationTypeID string     `json:"notificationTypeId"`
+	ViewedAt           *time.Time `json:"viewedAt"`
+	CreatedAt          time.Time  `json:"createdAt"`
+}
+
+// dateTime is the DateTime scalar.
+type dateTime struct {
+	time.Time
+}
+
+func (dateTime) ImplementsGraphQLType(name string) bool {
+	return name == "DateTime"
+}
+
+func (t *dateTime) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("DateTime cannot represent %v", input)
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (t dateTime) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.Format(time.RFC3339) + `"`), nil
+}
+
+type moneyResolver struct {
+	money money.Money
+}
+
+func (m moneyResolver) Amount() string {
+	return m.money.Amount()
+}
+
+func (m moneyResolver) Currency() string {
+	return string(m.money.Currency())
+}
+
+type userResolver struct {
+	user applicationServices.User
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(u.user.ID)
+}
+
+func (u *userResolver) Name() string {
+	return u.user.Name
+}
+
+func (u *userResolver) Email() string {
+	return u.user.Email
+}
+
+func (u *userResolver) Roles() []string {
+	roles := make([]string, 0, len(u.user.Roles))
+	for _, role := range u.user.Roles {
+		roles = append(roles, string(role))
+	}
+	return roles
+}
+
+type imageResolver struct {
+	image image
+}
+
+func (i imageResolver) ID() graphql.ID {
+	return graphql.ID(i.image.ID)
+}
+
+func (i imageResolver) URL() string {
+	return i.image.URL
+}
+
+func (i imageResolver) ThumbnailURL() string {
+	return i.image.ThumbnailURL
+}
+
+func (i imageResolver) Width() int32 {
+	return int32(i.image.Width)
+}
+
+func (i imageResolver) Height() int32 {
+	return int32(i.image.Height)
+}
+
+type attribute struct {
+	code  string
+	value string
+}
+
+func (a attribute) Code() string {
+	return a.code
+}
+
+func (a attribute) Value() string {
+	return a.value
+}
+
+type productResolver struct {
+	product *product
+}
+
+func (p *productResolver) ID() graphql.ID {
+	return graphql.ID(p.product.ID)
+}
+
+func (p *productResolver) SellerID() *graphql.ID {
+	return optionalID(p.product.SellerID)
+}
+
+func (p *productResolver) Name() string {
+	return p.product.Name
+}
+
+func (p *productResolver) Description() string {
+	return p.product.Description
+}
+
+func (p *productResolver) CategoryID() *graphql.ID {
+	return optionalID(p.product.CategoryID)
+}
+
+func (p *productResolver) Attributes() []attribute {
+	list := make([]attribute, 0, len(p.product.Attributes))
+	for code, value := range p.product.Attributes {
+		list = append(list, attribute{code: code, value: value})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].code < list[j].code })
+	return list
+}
+
+func (p *productResolver) Price() moneyResolver {
+	return moneyResolver{p.product.Price}
+}
+
+func (p *productResolver) Quantity() int32 {
+	return int32(p.product.Quantity)
+}
+
+func (p *productResolver) Images() []imageResolver {
+	images := make([]imageResolver, 0, len(p.product.Images))
+	for _, image := range p.product.Images {
+		images = append(images, imageResolver{image})
+	}
+	return images
+}
+
+func (p *productResolver) CreatedAt() dateTime {
+	return dateTime{p.product.CreatedAt}
+}
+
+type productPageResolver struct {
+	list *productList
+}
+
+func (p *productPageResolver) Nodes() []*productResolver {
+	nodes := make([]*productResolver, 0, len(p.list.Data))
+	for i := range p.list.Data {
+		nodes = append(nodes, &productResolver{&p.list.Data[i]})
+	}
+	return nodes
+}
+
+func (p *productPageResolver) Total() int32 {
+	return int32(p.list.Total)
+}
+
+func (p *productPageResolver) NextCursor() *string {
+	return optional(p.list.NextCursor)
+}
+
+type cartResolver struct {
+	resolver *Resolver
+	cart     *cart
+}
+
+func (c *cartResolver) Lines() []*cartLineResolver {
+	lines := make([]*cartLineResolver, 0, len(c.cart.Products))
+	for i := range c.cart.Products {
+		lines = append(lines, &cartLineResolver{resolver: c.resolver, line: &c.cart.Products[i]})
+	}
+	return lines
+}
+
+func (c *cartResolver) TotalPrice() moneyResolver {
+	return moneyResolver{c.cart.TotalPrice}
+}
+
+func (c *cartResolver) PriceChanged() bool {
+	return c.cart.PriceChanged
+}
+
+type cartLineResolver struct {
+	resolver *Resolver
+	line     *cartLine
+}
+
+func (l *cartLineResolver) ProductID() graphql.ID {
+	return graphql.ID(l.line.ProductID)
+}
+
+func (l *cartLineResolver) VariantID() *graphql.ID {
+	return optionalID(l.line.VariantID)
+}
+
+func (l *cartLineResolver) SKU() *string {
+	return optional(l.line.SKU)
+}
+
+func (l *cartLineResolver) Name() string {
+	return l.line.Name
+}
+
+func (l *cartLineResolver) Quantity() int32 {
+	return int32(l.line.Quantity)
+}
+
+func (l *cartLineResolver) Price() moneyResolver {
+	return moneyResolver{l.line.Price}
+}
+
+func (l *cartLineResolver) AddedPrice() moneyResolver {
+	return moneyResolver{l.line.AddedPrice}
+}
+
+func (l *cartLineResolver) PriceChanged() bool {
+	return l.line.PriceChanged
+}
+
+func (l *cartLineResolver) Product(ctx context.Context) (*productResolver, error) {
+	return l.resolver.loadProduct(ctx, l.line.ProductID)
+}
+
+type notificationResolver struct {
+	notification *notification
+}
+
+func (n *notificationResolver) ID() graphql.ID {
+	return graphql.ID(n.notification.ID)
+}
+
+func (n *notificationResolver) Title() string {
+	return n.notification.Title
+}
+
+func (n *notificationResolver) Message() string {
+	return n.notification.Message
+}
+
+func (n *notificationResolver) NotificationTypeID() graphql.ID {
+	return graphql.ID(n.notification.NotificationTypeID)
+}
+
+func (n *notificationResolver) ViewedAt() *dateTime {
+	if n.notification.ViewedAt == nil {
+		return nil
+	}
+	return &dateTime{*n.notification.ViewedAt}
+}
+
+func (n *notificationResolver) CreatedAt() dateTime {
+	return dateTime{n.notification.CreatedAt}
+}
+
+// optional resolves the empty strings the services leave out to null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalID(s string) *graphql.ID {
+	if s == "" {
+		return nil
+	}
+	id := graphql.ID(s)
+	return &id
+}
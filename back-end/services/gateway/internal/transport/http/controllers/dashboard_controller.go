@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	applicationServices "gateway/internal/domain/application-services"
+	"gateway/pkg/proxy"
+	"net/http"
+	"shared/authz"
+	httpErrors "shared/errors/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDashboard combines the user, the cart with the catalog details of its
+// products and the unread notification count. The route requires a logged in
+// user.
+func GetDashboard(service applicationServices.DashboardApplicationService, signer *authz.Signer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(applicationServices.User)
+		token, err := signer.Sign(user.Identity())
+		if err != nil {
+			httpErrors.InternalError(c, "Something went wrong")
+			return
+		}
+		ctx := proxy.WithHashKey(c.Request.Context(), user.ID)
+		c.JSON(http.StatusOK, service.GetDashboard(ctx, user, token, c.Query("currency")))
+	}
+}
@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	applicationServices "gateway/internal/domain/application-services"
+	"gateway/internal/transport/http/controllers"
+	"net/http"
+	"net/http/httptest"
+	"shared/authz"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// dashboardService returns the dashboard of the user and records the call.
+type dashboardService struct {
+	user     applicationServices.User
+	token    string
+	currency string
+}
+
+func (d *dashboardService) GetDashboard(_ context.Context, user applicationServices.User, token string, currency string) applicationServices.Dashboard {
+	d.user, d.token, d.currency = user, token, currency
+	unread := 1
+	return applicationServices.Dashboard{User: user, UnreadNotifications: &unread, Unavailable: []string{"cart"}}
+}
+
+func TestGetDashboard(t *testing.T) {
+	t.Parallel()
+	keys, err := authz.NewKeySet([]byte("a secret of at least thirty-two bytes"), time.Hour)
+	require.NoError(t, err)
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(keys.JWKS())
+	}))
+	t.Cleanup(jwks.Close)
+	user := applicationServices.User{ID: "u1", Name: "Jane", Roles: []authz.Role{authz.RoleCustomer}, SessionID: "s1"}
+	service := &dashboardService{}
+
+	engine := gin.New()
+	engine.GET("/v1/me/dashboard", func(c *gin.Context) {
+		c.Set("user", user)
+	}, controllers.GetDashboard(service, authz.NewSigner(keys, "gateway", time.Minute)))
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/me/dashboard?currency=EUR", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"user": {"id": "u1", "name": "Jane", "email": "", "roles": ["customer"], "session_id": "s1"},
+		"cart": null,
+		"unreadNotifications": 1,
+		"unavailable": ["cart"]
+	}`, w.Body.String())
+	assert.Equal(t, user, service.user)
+	assert.Equal(t, "EUR", service.currency)
+
+	// the services are called as the user
+	identity, err := authz.NewJWKSVerifier(jwks.URL, "gateway", jwks.Client()).Verify(context.Background(), service.token)
+	require.NoError(t, err)
+	assert.Equal(t, user.Identity(), identity)
+}
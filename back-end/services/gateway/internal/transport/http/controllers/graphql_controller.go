@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"encoding/json"
+	applicationServices "gateway/internal/domain/application-services"
+	"gateway/internal/transport/graphql"
+	"gateway/pkg/proxy"
+	"net/http"
+	"shared/authz"
+	httpErrors "shared/errors/http"
+
+	"github.com/gin-gonic/gin"
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+// largest GraphQL request body accepted
+const _maxGraphQLBodySize = 1 << 20
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQL executes a query sent as a JSON body or, for GET requests, in the
+// query, operationName and variables parameters. The route requires a logged
+// in user, resolvers call the services as that user.
+func GraphQL(schema *gql.Schema, resolver *graphql.Resolver, signer *authz.Signer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request graphQLRequest
+		if c.Request.Method == http.MethodGet {
+			request.Query = c.Query("query")
+			request.OperationName = c.Query("operationName")
+			if variables := c.Query("variables"); variables != "" {
+				err := json.Unmarshal([]byte(variables), &request.Variables)
+				if err != nil {
+					httpErrors.BadRequest(c, "variables must be a JSON object")
+					return
+				}
+			}
+		} else {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, _maxGraphQLBodySize)
+			err := json.NewDecoder(c.Request.Body).Decode(&request)
+			if err != nil {
+				httpErrors.BadRequest(c, "Invalid GraphQL request")
+				return
+			}
+		}
+		if request.Query == "" {
+			httpErrors.BadRequest(c, "query is required")
+			return
+		}
+
+		user := c.MustGet("user").(applicationServices.User)
+		token, err := signer.Sign(user.Identity())
+		if err != nil {
+			httpErrors.InternalError(c, "Something went wrong")
+			return
+		}
+		ctx := proxy.WithHashKey(c.Request.Context(), user.ID)
+		ctx = resolver.NewContext(ctx, user, token)
+		c.JSON(http.StatusOK, schema.Exec(ctx, request.Query, request.OperationName, request.Variables))
+	}
+}
+
+// GraphQLSchema serves the schema in the schema definition language.
+func GraphQLSchema() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.String(http.StatusOK, graphql.SDL)
+	}
+}
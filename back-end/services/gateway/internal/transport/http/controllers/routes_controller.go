@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"gateway/config"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type routeOutput struct {
+	Path       string   `json:"path"`
+	Methods    []string `json:"methods"`
+	Upstream   string   `json:"upstream,omitempty"`
+	Handler    string   `json:"handler,omitempty"`
+	Auth       bool     `json:"auth"`
+	Permission string   `json:"permission,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+	RateLimit  string   `json:"rateLimit,omitempty"`
+	Timeout    string   `json:"timeout,omitempty"`
+}
+
+type routeListOutput struct {
+	Routes []routeOutput `json:"routes"`
+}
+
+// ListRoutes lists the route table the gateway serves. Upstream URLs are not
+// exposed.
+func ListRoutes(table *config.RouteTable) gin.HandlerFunc {
+	routes := make([]routeOutput, 0, len(table.Routes))
+	for _, route := range table.Routes {
+		output := routeOutput{
+			Path:       route.Path,
+			Methods:    route.Methods,
+			Upstream:   route.Upstream,
+			Handler:    route.Handler,
+			Auth:       route.Auth,
+			Permission: route.Permission,
+			Roles:      route.Roles,
+			RateLimit:  route.RateLimit,
+		}
+		if route.Timeout > 0 {
+			output.Timeout = route.Timeout.String()
+		}
+		routes = append(routes, output)
+	}
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, routeListOutput{Routes: routes})
+	}
+}
@@ -1,11 +1,15 @@
 package controllers
 
 import (
-	"encoding/json"
+	"context"
 	applicationServices "gateway/internal/domain/application-services"
+	"gateway/pkg/proxy"
 	"net/http"
 	"net/http/httputil"
-	"net/url"
+	"regexp"
+	"shared/authz"
+	httpErrors "shared/errors/http"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -21,43 +25,65 @@ type dataHandlers struct {
 	logger zerolog.Logger
 }
 
-type AuthenticationInfo struct {
-	UserID    string `json:"user_id"`
-	IP        string `json:"ip"`
-	UserAgent string `json:"user_agent"`
-	SessionID string `json:"session_id"`
+type ProxyOptions struct {
+	// 0 disables the timeout, websocket upgrades are never timed out
+	Timeout  time.Duration
+	Rewrites []Rewrite
 }
 
-func ReverseProxy(target string) gin.HandlerFunc {
+// Rewrite replaces the matches of Pattern in the path sent upstream.
+type Rewrite struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// ReverseProxy forwards the request to the service. The identity of a logged
+// in caller is sent as a token signed by the gateway, services verify it with
+// authz.Middleware.
+func ReverseProxy(upstream *httputil.ReverseProxy, signer *authz.Signer, options ProxyOptions) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		target, err := url.Parse(target)
+		var token string
+		// consistent hashing keeps the requests of a user, e.g. the polling
+		// of a socket.io session, on one instance
+		hashKey := c.ClientIP()
 		userFromContext, exists := c.Get("user")
-		var authenticationInfo AuthenticationInfo
 		if exists {
 			user := userFromContext.(applicationServices.User)
-			userID := user.ID
-			ip := c.ClientIP()
-			userAgent := c.Request.UserAgent()
-			authenticationInfo = AuthenticationInfo{
-				UserID:    userID,
-				IP:        ip,
-				UserAgent: userAgent,
-				SessionID: user.SessionID,
+			if user.ID != "" {
+				var err error
+				token, err = signer.Sign(user.Identity())
+				if err != nil {
+					httpErrors.InternalError(c, "Something went wrong")
+					return
+				}
+				hashKey = user.ID
 			}
 		}
-		if err != nil {
-			panic(err)
+
+		ctx := proxy.WithHashKey(c.Request.Context(), hashKey)
+		if options.Timeout > 0 && c.GetHeader("Upgrade") == "" {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+			defer cancel()
 		}
-		proxy := httputil.NewSingleHostReverseProxy(target)
-		originalDirector := proxy.Director
-		proxy.Director = func(req *http.Request) {
-			originalDirector(req)
-			authInfoJSON, err := json.Marshal(authenticationInfo)
-			if err != nil {
-				panic(err)
-			}
-			req.Header.Add("X-Authentication-Info", string(authInfoJSON))
+		req := c.Request.Clone(ctx)
+		for _, rewrite := range options.Rewrites {
+			req.URL.Path = rewrite.Pattern.ReplaceAllString(req.URL.Path, rewrite.Replacement)
+			req.URL.RawPath = ""
+		}
+		// never forward an identity the client sent itself
+		req.Header.Del(authz.Header)
+		if token != "" {
+			req.Header.Set(authz.Header, token)
 		}
-		proxy.ServeHTTP(c.Writer, c.Request)
+		upstream.ServeHTTP(c.Writer, req)
+	}
+}
+
+// JWKS publishes the public keys services verify identity tokens with.
+func JWKS(keys *authz.KeySet) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=60")
+		c.JSON(http.StatusOK, keys.JWKS())
 	}
 }
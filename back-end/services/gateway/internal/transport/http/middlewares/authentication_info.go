@@ -11,17 +11,21 @@ import (
 type GetAuthenticationInfo struct {
 	logger                 zerolog.Logger
 	userApplicationService applicationServices.UserApplicationService
+	// let requests through as anonymous when the lookup fails
+	failOpen bool
 }
 
 func (r GetAuthenticationInfo) Apply(c *gin.Context) {
-	headers := c.Request.Header
-	user, sessionID, err := r.userApplicationService.GetCurrentUser(headers)
-	r.logger.Info().Interface("user", user).Str("sessionID", sessionID).Msg("GetAuthenticationInfo -> user")
+	user, sessionID, err := r.userApplicationService.GetCurrentUser(c.Request.Context(), c.Request.Header)
 	if err != nil {
-		r.logger.Error().Err(err).Msg("r.userApplicationService.GetCurrentUser -> user")
-		httpErrors.BadRequest(c, err.Error())
-		return
+		r.logger.Error().Err(err).Bool("failOpen", r.failOpen).Msg("r.userApplicationService.GetCurrentUser -> user")
+		if !r.failOpen {
+			httpErrors.ServiceUnavailable(c, "Authentication is unavailable, try again later")
+			return
+		}
+		user, sessionID = applicationServices.User{}, ""
 	}
+	r.logger.Debug().Str("userID", user.ID).Str("sessionID", sessionID).Msg("GetAuthenticationInfo -> user")
 
 	user.SessionID = sessionID
 	c.Set("user", user)
@@ -32,6 +36,7 @@ func (r GetAuthenticationInfo) Apply(c *gin.Context) {
 func NewGetAuthenticationInfo(
 	logger zerolog.Logger,
 	userApplicationService applicationServices.UserApplicationService,
+	failOpen bool,
 ) *GetAuthenticationInfo {
-	return &GetAuthenticationInfo{logger, userApplicationService}
+	return &GetAuthenticationInfo{logger, userApplicationService, failOpen}
 }
@@ -3,5 +3,7 @@ package middlewares
 type Middlewares struct {
 	GetAuthenticationInfo *GetAuthenticationInfo
 	RequireAuthentication *RequireAuthentication
+	RequirePermission     *RequirePermission
+	RequireRole           *RequireRole
 	RateLimiter           RateLimiter
 }
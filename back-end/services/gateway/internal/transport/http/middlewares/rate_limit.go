@@ -2,86 +2,269 @@ package middlewares
 
 import (
 	"context"
-	"errors"
+	"crypto/sha256"
 	"fmt"
+	"gateway/config"
 	applicationServices "gateway/internal/domain/application-services"
-	"log"
 	"math"
+	"net"
 	httpErrors "shared/errors/http"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gomodule/redigo/redis"
+	"github.com/rs/zerolog"
 	"github.com/sethvargo/go-limiter"
 	"github.com/sethvargo/go-limiter/httplimit"
+	"github.com/sethvargo/go-limiter/memorystore"
 	"github.com/sethvargo/go-redisstore"
 )
 
+// APIKeyHeader carries the keys of config.RateLimits.APIKeys.
+const APIKeyHeader = "X-API-Key"
+
+const (
+	_defaultRateLimitInterval = time.Minute
+	// how long the memory stores stand in for Redis after it failed
+	_redisRetryInterval = 5 * time.Second
+	_rateLimitKeyPrefix = "gateway:rate-limit:"
+	// set by Global for allowlisted clients
+	_rateLimitExemptKey = "rateLimitExempt"
+)
+
 type RateLimiter interface {
-	Apply(tokensLimit int) func(c *gin.Context)
+	// Global limits every request per IP address, or per API key when the
+	// request has one.
+	Global(limits config.RateLimits) gin.HandlerFunc
+	// Apply limits the requests of a route per user, or per IP address of
+	// anonymous callers. It runs after Global.
+	Apply(route string, policy config.RateLimitPolicy) gin.HandlerFunc
 }
 
+// rateLimiter keeps its counters in Redis, while Redis is unavailable each
+// gateway instance counts in memory.
 type rateLimiter struct {
+	logger    zerolog.Logger
 	redisPool *redis.Pool
+
+	mu     sync.Mutex
+	stores map[bucket]*store
+	// unix nanoseconds until which the memory stores are used
+	redisDownUntil atomic.Int64
+}
+
+// bucket holds tokens which are refilled every interval.
+type bucket struct {
+	tokens   uint64
+	interval time.Duration
+}
+
+type store struct {
+	redis  limiter.Store
+	memory limiter.Store
 }
 
-func NewRateLimiter(pool *redis.Pool) *rateLimiter {
+func NewRateLimiter(logger zerolog.Logger, pool *redis.Pool) *rateLimiter {
 	return &rateLimiter{
+		logger:    logger,
 		redisPool: pool,
+		stores:    map[bucket]*store{},
 	}
 }
 
-func (r rateLimiter) Apply(tokensLimit int) func(*gin.Context) {
-	// create in-memory store
-	store, err := redisstore.NewWithPool(&redisstore.Config{
-		Tokens:   uint64(tokensLimit),
-		Interval: time.Minute,
-	}, r.redisPool)
+func (r *rateLimiter) Global(limits config.RateLimits) gin.HandlerFunc {
+	var allowlist []*net.IPNet
+	for _, entry := range limits.Allowlist {
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			ip := net.ParseIP(entry)
+			network = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+		}
+		allowlist = append(allowlist, network)
+	}
+	// keys are looked up by hash so they are not compared byte by byte
+	apiKeys := make(map[[sha256.Size]byte]config.APIKey, len(limits.APIKeys))
+	for _, key := range limits.APIKeys {
+		apiKeys[sha256.Sum256([]byte(key.Key))] = key
+	}
+
+	return func(c *gin.Context) {
+		ip := net.ParseIP(c.ClientIP())
+		for _, network := range allowlist {
+			if ip != nil && network.Contains(ip) {
+				c.Set(_rateLimitExemptKey, true)
+				c.Next()
+				return
+			}
+		}
+
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			apiKey, ok := apiKeys[sha256.Sum256([]byte(key))]
+			if !ok {
+				httpErrors.Unauthorized(c, "Invalid API key")
+				return
+			}
+			// the key is not sent to the services
+			c.Request.Header.Del(APIKeyHeader)
+			if !r.allow(c, "key:"+apiKey.Name, apiKey.Quota) {
+				httpErrors.TooManyRequests(c)
+				return
+			}
+			c.Next()
+			return
+		}
 
-	if err != nil {
-		panic(err)
+		if !r.allow(c, "ip:"+c.ClientIP(), limits.Global) {
+			httpErrors.TooManyRequests(c)
+			return
+		}
+		c.Next()
 	}
+}
 
+func (r *rateLimiter) Apply(route string, policy config.RateLimitPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userFromContext, exists := c.Get("user")
-		var userID string
-		if exists {
+		if c.GetBool(_rateLimitExemptKey) {
+			c.Next()
+			return
+		}
+		subject, limit := "ip:"+c.ClientIP(), policy.RateLimit
+		if userFromContext, exists := c.Get("user"); exists {
 			user := userFromContext.(applicationServices.User)
-			userID = user.ID
+			if user.ID != "" {
+				subject, limit = "user:"+user.ID, roleLimit(policy, user)
+			}
 		}
-		err := limit(store, userID, c)
-
-		if err != nil {
-			httpErrors.BadRequest(c, err.Error())
+		if !r.allow(c, "route:"+route+":"+subject, limit) {
+			httpErrors.TooManyRequests(c)
 			return
 		}
 		c.Next()
 	}
 }
 
-func limit(store limiter.Store, userID string, c *gin.Context) error {
-	path := c.Request.URL.EscapedPath()
-	clientIP := c.ClientIP()
-	key := fmt.Sprintf("%s/%s/%s", path, userID, clientIP)
-	// Take from the store.
-	limit, remaining, reset, ok, err := store.Take(context.Background(), key)
-	if err != nil {
-		return err
+// Close stops the memory stores, the Redis pool is closed by its owner.
+func (r *rateLimiter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.stores {
+		_ = s.memory.Close(context.Background())
+	}
+	return nil
+}
+
+// roleLimit is the most generous limit of the roles of user, or the default
+// limit of the policy when none of the roles has one.
+func roleLimit(policy config.RateLimitPolicy, user applicationServices.User) config.RateLimit {
+	var limit *config.RateLimit
+	for _, role := range user.Roles {
+		roleLimit, ok := policy.Roles[string(role)]
+		if ok && (limit == nil || moreGenerous(roleLimit, *limit)) {
+			limit = &roleLimit
+		}
+	}
+	if limit == nil {
+		return policy.RateLimit
+	}
+	return *limit
+}
+
+// moreGenerous compares the sustained rates first, 0 means unlimited.
+func moreGenerous(a config.RateLimit, b config.RateLimit) bool {
+	aRate, bRate := perSecond(a.Sustained, interval(a)), perSecond(b.Sustained, interval(b))
+	if aRate != bRate {
+		return aRate > bRate
 	}
+	return perSecond(a.Burst, time.Second) > perSecond(b.Burst, time.Second)
+}
 
-	resetTime := int(math.Floor(time.Unix(0, int64(reset)).UTC().Sub(time.Now().UTC()).Seconds()))
-	log.Println(resetTime)
+func perSecond(tokens int, interval time.Duration) float64 {
+	if tokens == 0 {
+		return math.Inf(1)
+	}
+	return float64(tokens) / interval.Seconds()
+}
 
-	// Set headers (we do this regardless of whether the request is permitted).
-	c.Writer.Header().Set(httplimit.HeaderRateLimitLimit, strconv.FormatUint(limit, 10))
-	c.Writer.Header().Set(httplimit.HeaderRateLimitRemaining, strconv.FormatUint(remaining, 10))
-	c.Writer.Header().Set(httplimit.HeaderRateLimitReset, strconv.Itoa(resetTime))
+func interval(limit config.RateLimit) time.Duration {
+	if limit.Interval == 0 {
+		return _defaultRateLimitInterval
+	}
+	return limit.Interval
+}
 
-	// Fail if there were no tokens remaining.
-	if !ok {
-		c.Writer.Header().Set(httplimit.HeaderRetryAfter, strconv.Itoa(resetTime))
-		return errors.New("too many attempts")
+func buckets(limit config.RateLimit) []bucket {
+	var list []bucket
+	if limit.Burst > 0 {
+		list = append(list, bucket{tokens: uint64(limit.Burst), interval: time.Second})
 	}
-	return nil
+	if limit.Sustained > 0 {
+		list = append(list, bucket{tokens: uint64(limit.Sustained), interval: interval(limit)})
+	}
+	return list
+}
+
+// allow takes a token from every bucket of limit for key. The rate limit
+// headers describe the bucket closest to running out, of this call or of an
+// earlier middleware.
+func (r *rateLimiter) allow(c *gin.Context, key string, limit config.RateLimit) bool {
+	for _, b := range buckets(limit) {
+		tokens, remaining, reset, ok := r.take(b, fmt.Sprintf("%s%s:%d/%s", _rateLimitKeyPrefix, key, b.tokens, b.interval))
+		resetIn := int((time.Duration(reset) - time.Duration(time.Now().UnixNano()) + time.Second - 1) / time.Second)
+		if resetIn < 1 {
+			resetIn = 1
+		}
+
+		header := c.Writer.Header()
+		current, err := strconv.ParseUint(header.Get(httplimit.HeaderRateLimitRemaining), 10, 64)
+		if err != nil || remaining < current {
+			header.Set(httplimit.HeaderRateLimitLimit, strconv.FormatUint(tokens, 10))
+			header.Set(httplimit.HeaderRateLimitRemaining, strconv.FormatUint(remaining, 10))
+			header.Set(httplimit.HeaderRateLimitReset, strconv.Itoa(resetIn))
+		}
+		if !ok {
+			header.Set(httplimit.HeaderRetryAfter, strconv.Itoa(resetIn))
+			return false
+		}
+	}
+	return true
+}
+
+func (r *rateLimiter) take(b bucket, key string) (tokens, remaining, reset uint64, ok bool) {
+	s := r.store(b)
+	// requests of clients which went away are counted too
+	ctx := context.Background()
+	if time.Now().UnixNano() >= r.redisDownUntil.Load() {
+		tokens, remaining, reset, ok, err := s.redis.Take(ctx, key)
+		if err == nil {
+			return tokens, remaining, reset, ok
+		}
+		r.redisDownUntil.Store(time.Now().Add(_redisRetryInterval).UnixNano())
+		r.logger.Warn().Err(err).Msg("rateLimiter -> take - counting in memory")
+	}
+	tokens, remaining, reset, ok, _ = s.memory.Take(ctx, key)
+	return tokens, remaining, reset, ok
+}
+
+// store is shared by the limits with the same bucket, also across reloads of
+// the route table.
+func (r *rateLimiter) store(b bucket) *store {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.stores[b]; ok {
+		return s
+	}
+	// neither constructor fails
+	redisStore, _ := redisstore.NewWithPool(&redisstore.Config{Tokens: b.tokens, Interval: b.interval}, r.redisPool)
+	memoryStore, _ := memorystore.New(&memorystore.Config{
+		Tokens:        b.tokens,
+		Interval:      b.interval,
+		SweepInterval: _defaultRateLimitInterval,
+		SweepMinTTL:   b.interval + _defaultRateLimitInterval,
+	})
+	s := &store{redis: redisStore, memory: memoryStore}
+	r.stores[b] = s
+	return s
 }
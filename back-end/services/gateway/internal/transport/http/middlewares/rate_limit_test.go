@@ -0,0 +1,224 @@
+package middlewares_test
+
+import (
+	"errors"
+	"gateway/config"
+	applicationServices "gateway/internal/domain/application-services"
+	"gateway/internal/transport/http/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"shared/authz"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gomodule/redigo/redis"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newRateLimiter counts in memory, its Redis is unreachable.
+func newRateLimiter(t *testing.T) middlewares.RateLimiter {
+	pool := &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return nil, errors.New("redis is down")
+		},
+	}
+	limiter := middlewares.NewRateLimiter(zerolog.Nop(), pool)
+	t.Cleanup(func() { _ = limiter.Close() })
+	return limiter
+}
+
+// newEngine serves GET /route limited by the global limits and policy, the
+// user of a request is taken from the X-User-ID and X-User-Role headers.
+func newEngine(limiter middlewares.RateLimiter, limits config.RateLimits, policy config.RateLimitPolicy) *gin.Engine {
+	engine := gin.New()
+	engine.Use(limiter.Global(limits))
+	engine.Use(func(c *gin.Context) {
+		user := applicationServices.User{ID: c.GetHeader("X-User-ID")}
+		if role := c.GetHeader("X-User-Role"); role != "" {
+			user.Roles = []authz.Role{authz.Role(role)}
+		}
+		c.Set("user", user)
+	})
+	engine.GET("/route", limiter.Apply("/route", policy), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetHeader(middlewares.APIKeyHeader))
+	})
+	return engine
+}
+
+func serve(engine *gin.Engine, ip string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/route", nil)
+	req.RemoteAddr = ip + ":4321"
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiter_Global(t *testing.T) {
+	t.Parallel()
+	limits := config.RateLimits{
+		Global:    config.RateLimit{Burst: 2},
+		Allowlist: []string{"10.0.0.0/8", "192.0.2.7"},
+		APIKeys: []config.APIKey{
+			{Name: "partner", Key: "secret", Quota: config.RateLimit{Burst: 3}},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		ip      string
+		headers map[string]string
+		// statuses of consecutive requests
+		want []int
+	}{
+		{
+			name: "ExceedingBurst_Returns429",
+			ip:   "192.0.2.1",
+			want: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "AllowlistedRange_IsNotLimited",
+			ip:   "10.1.2.3",
+			want: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name: "AllowlistedIP_IsNotLimited",
+			ip:   "192.0.2.7",
+			want: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:    "APIKey_CountsAgainstItsQuota",
+			ip:      "192.0.2.2",
+			headers: map[string]string{middlewares.APIKeyHeader: "secret"},
+			want:    []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "UnknownAPIKey_Returns401",
+			ip:      "192.0.2.3",
+			headers: map[string]string{middlewares.APIKeyHeader: "guess"},
+			want:    []int{http.StatusUnauthorized},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			engine := newEngine(newRateLimiter(t), limits, config.RateLimitPolicy{})
+
+			for i, want := range tc.want {
+				w := serve(engine, tc.ip, tc.headers)
+				require.Equal(t, want, w.Code, "request %d", i+1)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Global_LimitedResponse(t *testing.T) {
+	t.Parallel()
+	engine := newEngine(newRateLimiter(t), config.RateLimits{Global: config.RateLimit{Burst: 1}}, config.RateLimitPolicy{})
+
+	w := serve(engine, "192.0.2.1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = serve(engine, "192.0.2.1", nil)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"message":"Too Many requests","success":false}`, w.Body.String())
+
+	// other clients have their own budget
+	w = serve(engine, "192.0.2.2", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimiter_Global_APIKeyIsNotForwarded(t *testing.T) {
+	t.Parallel()
+	limits := config.RateLimits{APIKeys: []config.APIKey{{Name: "partner", Key: "secret"}}}
+	engine := newEngine(newRateLimiter(t), limits, config.RateLimitPolicy{})
+
+	w := serve(engine, "192.0.2.1", map[string]string{middlewares.APIKeyHeader: "secret"})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestRateLimiter_Apply(t *testing.T) {
+	t.Parallel()
+	policy := config.RateLimitPolicy{
+		RateLimit: config.RateLimit{Burst: 1},
+		Roles: map[string]config.RateLimit{
+			string(authz.RoleSupport): {Burst: 2},
+			string(authz.RoleAdmin):   {Burst: 3, Sustained: 100, Interval: time.Minute},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		ip      string
+		headers map[string]string
+		want    []int
+	}{
+		{
+			name: "Anonymous_DefaultLimitPerIP",
+			ip:   "192.0.2.1",
+			want: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "RoleWithoutTier_DefaultLimit",
+			ip:      "192.0.2.2",
+			headers: map[string]string{"X-User-ID": "customer", "X-User-Role": string(authz.RoleCustomer)},
+			want:    []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "Support_SupportTier",
+			ip:      "192.0.2.3",
+			headers: map[string]string{"X-User-ID": "support", "X-User-Role": string(authz.RoleSupport)},
+			want:    []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "Admin_AdminTier",
+			ip:      "192.0.2.4",
+			headers: map[string]string{"X-User-ID": "admin", "X-User-Role": string(authz.RoleAdmin)},
+			want:    []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name: "Allowlisted_IsNotLimited",
+			ip:   "10.0.0.1",
+			want: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			limits := config.RateLimits{Allowlist: []string{"10.0.0.0/8"}}
+			engine := newEngine(newRateLimiter(t), limits, policy)
+
+			for i, want := range tc.want {
+				w := serve(engine, tc.ip, tc.headers)
+				require.Equal(t, want, w.Code, "request %d", i+1)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Apply_LimitsUsersNotAddresses(t *testing.T) {
+	t.Parallel()
+	engine := newEngine(newRateLimiter(t), config.RateLimits{}, config.RateLimitPolicy{RateLimit: config.RateLimit{Burst: 1}})
+
+	// one user from two addresses
+	require.Equal(t, http.StatusOK, serve(engine, "192.0.2.1", map[string]string{"X-User-ID": "user"}).Code)
+	require.Equal(t, http.StatusTooManyRequests, serve(engine, "192.0.2.2", map[string]string{"X-User-ID": "user"}).Code)
+	// another user from the same address
+	require.Equal(t, http.StatusOK, serve(engine, "192.0.2.1", map[string]string{"X-User-ID": "other"}).Code)
+}
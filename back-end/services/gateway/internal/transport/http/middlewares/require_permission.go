@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	applicationServices "gateway/internal/domain/application-services"
+	"shared/authz"
+	httpErrors "shared/errors/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// RequirePermission rejects users whose roles do not grant a permission. It
+// runs after RequireAuthentication.
+type RequirePermission struct {
+	logger zerolog.Logger
+}
+
+func (r RequirePermission) Apply(permission authz.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user applicationServices.User
+		if userFromContext, exists := c.Get("user"); exists {
+			user = userFromContext.(applicationServices.User)
+		}
+		if !user.Identity().Can(permission) {
+			r.logger.Info().Str("userID", user.ID).Str("permission", string(permission)).Msg("RequirePermission -> forbidden")
+			httpErrors.Forbidden(c, "You are not allowed to do this")
+			return
+		}
+		c.Next()
+	}
+}
+
+func NewRequirePermission(
+	logger zerolog.Logger,
+) *RequirePermission {
+	return &RequirePermission{logger}
+}
@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	applicationServices "gateway/internal/domain/application-services"
+	"shared/authz"
+	httpErrors "shared/errors/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// RequireRole rejects users who have none of the roles. It runs after
+// RequireAuthentication.
+type RequireRole struct {
+	logger zerolog.Logger
+}
+
+func (r RequireRole) Apply(roles ...authz.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user applicationServices.User
+		if userFromContext, exists := c.Get("user"); exists {
+			user = userFromContext.(applicationServices.User)
+		}
+		identity := user.Identity()
+		for _, role := range roles {
+			if identity.UserID != "" && identity.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+		r.logger.Info().Str("userID", user.ID).Interface("roles", roles).Msg("RequireRole -> forbidden")
+		httpErrors.Forbidden(c, "You are not allowed to do this")
+	}
+}
+
+func NewRequireRole(
+	logger zerolog.Logger,
+) *RequireRole {
+	return &RequireRole{logger}
+}
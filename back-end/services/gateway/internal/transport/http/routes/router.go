@@ -2,10 +2,21 @@
 package routes
 
 import (
+	"context"
+	"fmt"
 	"gateway/config"
 	applicationServices "gateway/internal/domain/application-services"
+	"gateway/internal/transport/graphql"
 	middlewares "gateway/internal/transport/http/middlewares"
+	"gateway/pkg/proxy"
 	"net/http"
+	"os"
+	"regexp"
+	"shared/authz"
+	"shared/health"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/requestid"
@@ -15,99 +26,270 @@ import (
 	controllers "gateway/internal/transport/http/controllers"
 )
 
+// Router serves the route table from config.RouteTable. Loading a table
+// builds a new engine and swaps it in, requests in flight finish on the
+// engine they started on.
+type Router struct {
+	engine  atomic.Pointer[gin.Engine]
+	m       middlewares.Middlewares
+	logger  zerolog.Logger
+	config  *config.Config
+	checks  *health.Health
+	keys    *authz.KeySet
+	signer  *authz.Signer
+	proxies *proxy.Pool
+}
+
 func NewRouter(
-	handler *gin.Engine,
-	u applicationServices.UserApplicationService,
 	m middlewares.Middlewares,
 	logger zerolog.Logger,
 	config *config.Config,
-) {
-	handler.Use(gin.Logger())
-	handler.Use(gin.Recovery())
-	handler.Use(requestid.New())
-	handler.Use(m.GetAuthenticationInfo.Apply)
-	handler.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	checks *health.Health,
+	keys *authz.KeySet,
+	signer *authz.Signer,
+	proxies *proxy.Pool,
+) *Router {
+	return &Router{m: m, logger: logger, config: config, checks: checks, keys: keys, signer: signer, proxies: proxies}
+}
 
-	authenticate := m.RequireAuthentication.Apply
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.engine.Load().ServeHTTP(w, req)
+}
 
-	notificationServiceURL := config.NotificationServiceURL
-	notifProxy := controllers.ReverseProxy(notificationServiceURL)
+// Load replaces the served routes, the current ones are kept when the table
+// cannot be served.
+func (r *Router) Load(table *config.RouteTable) error {
+	engine, err := r.build(table)
+	if err != nil {
+		return err
+	}
+	r.engine.Store(engine)
+
+	upstreams := make([]proxy.Upstream, 0, len(table.Upstreams))
+	for _, upstream := range table.Upstreams {
+		upstreams = append(upstreams, proxyUpstream(upstream))
+	}
+	r.proxies.Retain(upstreams)
+	return nil
+}
 
-	authServiceURL := config.AuthenticationServiceURL
-	authServiceProxy := controllers.ReverseProxy(authServiceURL)
+// Watch reloads the route table when its file changes.
+func (r *Router) Watch(ctx context.Context, path string, interval time.Duration) error {
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("Router -> Watch - os.Stat")
+			continue
+		}
+		if info.ModTime().Equal(modTime) {
+			continue
+		}
+		modTime = info.ModTime()
+		table, err := config.LoadRoutes(path)
+		if err == nil {
+			err = r.Load(table)
+		}
+		if err != nil {
+			r.logger.Error().Err(err).Msg("Router -> Watch - keeping the current routes")
+			continue
+		}
+		r.logger.Info().Int("routes", len(table.Routes)).Msg("Router -> Watch - routes reloaded")
+	}
+}
 
-	catalogServiceURL := config.CatalogServiceURL
-	catalogServiceProxy := controllers.ReverseProxy(catalogServiceURL)
+func (r *Router) build(table *config.RouteTable) (engine *gin.Engine, err error) {
+	// gin panics on conflicting paths
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			engine, err = nil, fmt.Errorf("%w: %v", config.ErrInvalidRoute, recovered)
+		}
+	}()
 
-	cartServiceURL := config.CartServiceURL
-	cartServiceProxy := controllers.ReverseProxy(cartServiceURL)
+	// routes without a timeout get the default one, the listing shows it too
+	cloned := *table
+	cloned.Routes = append([]config.Route(nil), table.Routes...)
+	table = &cloned
+	for i := range table.Routes {
+		if table.Routes[i].Upstream != "" && table.Routes[i].Timeout == 0 {
+			table.Routes[i].Timeout = r.config.Proxy.Timeout
+		}
+	}
 
-	chatServiceWebsocketURL := config.ChatsServiceWebsocketURL
-	chatServiceWebsocketProxy := controllers.ReverseProxy(chatServiceWebsocketURL)
+	handlers, err := r.handlers(table)
+	if err != nil {
+		return nil, err
+	}
 
-	chatServiceURL := config.ChatsServiceURL
-	chatServiceProxy := controllers.ReverseProxy(chatServiceURL)
+	handler := gin.New()
+	// the rate limits and the balancers key anonymous clients by IP, a
+	// forwarded address is only believed from a trusted proxy
+	err = handler.SetTrustedProxies(r.config.HTTP.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("Router -> build - handler.SetTrustedProxies: %w", err)
+	}
+	handler.Use(gin.Logger())
+	handler.Use(gin.Recovery())
+	handler.Use(requestid.New())
+	// probes and the signing keys are registered before the authentication
+	// lookup so they do not depend on the authentication service
+	r.checks.Register(handler)
+	handler.GET("/.well-known/jwks.json", controllers.JWKS(r.keys))
+	// the global limit is per IP address, it runs first to also protect the
+	// authentication lookup
+	handler.Use(r.m.RateLimiter.Global(table.RateLimits))
+	handler.Use(r.m.GetAuthenticationInfo.Apply)
 
 	// declare before CORS
-	// notification service websocket
-	handler.GET("/socket.io/*any", authenticate, notifProxy)
-	handler.POST("/socket.io/*any", authenticate, notifProxy)
-
-	// chat service websocket
-	handler.GET("/chat/socket.io/*any", authenticate, chatServiceWebsocketProxy)
-	handler.POST("/chat/socket.io/*any", authenticate, chatServiceWebsocketProxy)
+	for _, route := range table.Routes {
+		if route.SkipCORS {
+			err := r.handle(handler, table, handlers, route)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{
-		config.MarketplaceAppUrl,
-		config.AccountsAppURL,
-		config.SwaggerEditorDomain,
-		config.SwaggerUIDomain,
+		r.config.MarketplaceAppUrl,
+		r.config.AccountsAppURL,
+		r.config.SwaggerEditorDomain,
+		r.config.SwaggerUIDomain,
 	}
 	corsConfig.AllowCredentials = true
 
 	handler.Use(cors.New(corsConfig))
-	v1 := handler.Group("/v1")
-
-	rateLimit := m.RateLimiter.Apply
-
-	// users
-	v1.POST("users", authServiceProxy)
-	v1.GET("/users/:userID", authenticate, authServiceProxy)
-	v1.PATCH("/users/:userID", authenticate, authServiceProxy)
-	v1.DELETE("/users/:userID", authenticate, authServiceProxy)
-	v1.GET("/users/me", authServiceProxy)
-
-	// chat
-	v1.GET("/chat/messages", authenticate, chatServiceProxy)
-
-	// auth
-	v1.POST("/auth/login", rateLimit(10), authServiceProxy)
-	v1.POST("/auth/login/mfa/totp", rateLimit(10), authServiceProxy)
-	v1.GET("/auth/logout", authServiceProxy)
-	v1.PATCH("/auth/me/change_password", rateLimit(5), authenticate, authServiceProxy)
-	v1.PUT("/auth/me/mfa/totp", rateLimit(5), authenticate, authServiceProxy)
-	v1.PATCH("/auth/me/mfa/totp/enable", rateLimit(10), authenticate, authServiceProxy)
-	v1.PATCH("/auth/me/mfa/totp/disable", rateLimit(10), authenticate, authServiceProxy)
-
-	// auth/social
-	v1.GET("/auth/social/:provider/callback", rateLimit(10), authServiceProxy)
-	v1.GET("/auth/social/:provider", rateLimit(10), authServiceProxy)
-
-	// user notifications
-	v1.GET("/users/me/notifications", authenticate, notifProxy)
-	v1.PATCH("/users/me/notifications/view", authenticate, notifProxy)
-	v1.DELETE("/users/me/notifications/:notificationId", authenticate, notifProxy)
-	v1.PATCH("/users/me/notifications/:notificationId/view", authenticate, notifProxy)
-
-	// products
-	v1.POST("/products", authenticate, catalogServiceProxy)
-	v1.GET("/products", authenticate, catalogServiceProxy)
-	v1.GET("/products/:productID", authenticate, catalogServiceProxy)
-	v1.DELETE("/products/:productID", authenticate, catalogServiceProxy)
-	v1.PATCH("/products/:productID", authenticate, catalogServiceProxy)
-
-	// cart
-	v1.PATCH("/cart/products", authenticate, cartServiceProxy)
-	v1.GET("/cart", authenticate, cartServiceProxy)
+
+	handler.GET(
+		"/v1/gateway/routes",
+		r.m.RequireAuthentication.Apply,
+		r.m.RequireRole.Apply(authz.RoleAdmin),
+		controllers.ListRoutes(table),
+	)
+	for _, route := range table.Routes {
+		if !route.SkipCORS {
+			err := r.handle(handler, table, handlers, route)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return handler, nil
+}
+
+func (r *Router) handle(handler *gin.Engine, table *config.RouteTable, handlers map[string]gin.HandlerFunc, route config.Route) error {
+	var chain gin.HandlersChain
+	if route.RateLimit != "" {
+		chain = append(chain, r.m.RateLimiter.Apply(route.Path, table.RateLimits.Policies[route.RateLimit]))
+	}
+	if route.Auth {
+		chain = append(chain, r.m.RequireAuthentication.Apply)
+	}
+	if route.Permission != "" {
+		chain = append(chain, r.m.RequirePermission.Apply(authz.Permission(route.Permission)))
+	}
+	if len(route.Roles) > 0 {
+		roles := make([]authz.Role, 0, len(route.Roles))
+		for _, role := range route.Roles {
+			roles = append(roles, authz.Role(role))
+		}
+		chain = append(chain, r.m.RequireRole.Apply(roles...))
+	}
+
+	if route.Handler != "" {
+		chain = append(chain, handlers[route.Handler])
+	} else {
+		reverseProxy, err := r.reverseProxy(table, route)
+		if err != nil {
+			return err
+		}
+		chain = append(chain, reverseProxy)
+	}
+
+	for _, method := range route.Methods {
+		handler.Handle(method, route.Path, chain...)
+	}
+	return nil
+}
+
+func (r *Router) reverseProxy(table *config.RouteTable, route config.Route) (gin.HandlerFunc, error) {
+	options := controllers.ProxyOptions{Timeout: route.Timeout}
+	for _, rewrite := range route.Rewrite {
+		options.Rewrites = append(options.Rewrites, controllers.Rewrite{
+			Pattern:     regexp.MustCompile(rewrite.Pattern),
+			Replacement: rewrite.Replacement,
+		})
+	}
+	upstream, err := r.proxies.Get(proxyUpstream(table.Upstreams[route.Upstream]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", config.ErrInvalidRoute, route.Path, err)
+	}
+	return controllers.ReverseProxy(upstream, r.signer, options), nil
+}
+
+// handlers answer the routes of the table with a handler, they are only
+// built when a route uses one.
+func (r *Router) handlers(table *config.RouteTable) (map[string]gin.HandlerFunc, error) {
+	used := false
+	for _, route := range table.Routes {
+		used = used || route.Handler != ""
+	}
+	if !used {
+		return nil, nil
+	}
+
+	// the dashboard and the GraphQL API call the services themselves
+	upstreams, err := r.upstreams(table, "cart", "catalog", "notification")
+	if err != nil {
+		return nil, err
+	}
+	dashboard := applicationServices.NewDashboardApplicationService(r.logger, r.config, upstreams[0], upstreams[1], upstreams[2])
+	resolver := graphql.NewResolver(r.logger, r.config, upstreams[0], upstreams[1], upstreams[2])
+	schema, err := graphql.NewSchema(resolver)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]gin.HandlerFunc{
+		config.HandlerDashboard:     controllers.GetDashboard(dashboard, r.signer),
+		config.HandlerGraphQL:       controllers.GraphQL(schema, resolver, r.signer),
+		config.HandlerGraphQLSchema: controllers.GraphQLSchema(),
+	}, nil
+}
+
+// upstreams are the services the gateway calls itself, through the proxies
+// of their upstreams so the calls are balanced and circuit broken like
+// proxied requests.
+func (r *Router) upstreams(table *config.RouteTable, names ...string) ([]applicationServices.Upstream, error) {
+	upstreams := make([]applicationServices.Upstream, 0, len(names))
+	for _, name := range names {
+		upstream, ok := table.Upstreams[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: the gateway needs the upstream %s", config.ErrInvalidRoute, name)
+		}
+		client, err := r.proxies.Client(proxyUpstream(upstream))
+		if err != nil {
+			return nil, fmt.Errorf("%w: upstream %s: %s", config.ErrInvalidRoute, name, err)
+		}
+		upstreams = append(upstreams, applicationServices.Upstream{URL: strings.TrimSuffix(upstream.Instances[0], "/"), Client: client})
+	}
+	return upstreams, nil
+}
+
+func proxyUpstream(upstream config.Upstream) proxy.Upstream {
+	return proxy.Upstream{
+		Instances:  upstream.Instances,
+		Strategy:   proxy.Strategy(upstream.Strategy),
+		HealthPath: upstream.HealthPath,
+	}
 }
@@ -0,0 +1,228 @@
+package routes_test
+
+import (
+	"context"
+	"errors"
+	"gateway/config"
+	applicationServices "gateway/internal/domain/application-services"
+	"gateway/internal/transport/http/middlewares"
+	"gateway/internal/transport/http/routes"
+	"gateway/pkg/proxy"
+	"net/http"
+	"net/http/httptest"
+	"shared/authz"
+	"shared/health"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gomodule/redigo/redis"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// anonymous answers every request as an anonymous user.
+type anonymous struct{}
+
+func (anonymous) GetCurrentUser(context.Context, http.Header) (applicationServices.User, string, error) {
+	return applicationServices.User{}, "", nil
+}
+
+func (anonymous) InvalidateUserSessions(context.Context, string) error {
+	return nil
+}
+
+type probe struct{}
+
+func (probe) Ready() bool { return true }
+func (probe) Live() bool  { return true }
+
+const _routeTable = `
+upstreams:
+  cart: ${UPSTREAM}
+  catalog: ${UPSTREAM}
+  notification: ${UPSTREAM}
+rate_limits:
+  global:
+    burst: 2
+  api_keys:
+    - name: partner
+      key: secret
+      quota:
+        burst: 1
+routes:
+  - path: /v1/products
+    methods: [GET]
+    upstream: catalog
+`
+
+const _handlerRouteTable = `
+upstreams:
+  cart: ${UPSTREAM}
+  catalog: ${UPSTREAM}
+  notification: ${UPSTREAM}
+rate_limits:
+  policies:
+    aggregation:
+      burst: 1
+routes:
+  - path: /v1/graphql
+    methods: [POST]
+    handler: graphql
+    auth: true
+    rate_limit: aggregation
+  - path: /v1/graphql/schema
+    methods: [GET]
+    handler: graphql_schema
+`
+
+func newRouter(t *testing.T, routeTable string, options ...func(*config.Config)) *routes.Router {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+	t.Setenv("UPSTREAM", upstream.URL)
+	table, err := config.ParseRoutes([]byte(routeTable))
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	conf := &config.Config{
+		MarketplaceAppUrl:   "http://marketplace.test",
+		AccountsAppURL:      "http://accounts.test",
+		SwaggerUIDomain:     "http://swagger.test",
+		SwaggerEditorDomain: "http://editor.test",
+	}
+	conf.Proxy.Timeout = time.Second
+	for _, option := range options {
+		option(conf)
+	}
+	keys, err := authz.NewKeySet([]byte("a secret of at least thirty-two bytes"), time.Hour)
+	require.NoError(t, err)
+	redisPool := &redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return nil, errors.New("redis is down")
+		},
+	}
+	rateLimiter := middlewares.NewRateLimiter(logger, redisPool)
+	t.Cleanup(func() { _ = rateLimiter.Close() })
+	proxies := proxy.NewPool(logger)
+	t.Cleanup(func() { _ = proxies.Close() })
+
+	m := middlewares.Middlewares{
+		GetAuthenticationInfo: middlewares.NewGetAuthenticationInfo(logger, anonymous{}, false),
+		RequireAuthentication: middlewares.NewRequireAuthentication(logger),
+		RequirePermission:     middlewares.NewRequirePermission(logger),
+		RequireRole:           middlewares.NewRequireRole(logger),
+		RateLimiter:           rateLimiter,
+	}
+	router := routes.NewRouter(m, logger, conf, health.NewHealth(probe{}), keys, authz.NewSigner(keys, "gateway", time.Minute), proxies)
+	require.NoError(t, router.Load(table))
+	return router
+}
+
+func TestRouter_Load_AppliesRateLimits(t *testing.T) {
+	testCases := []struct {
+		name    string
+		headers map[string]string
+		want    []int
+	}{
+		{
+			name: "GlobalLimit",
+			want: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "APIKeyQuota",
+			headers: map[string]string{middlewares.APIKeyHeader: "secret"},
+			want:    []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newRouter(t, _routeTable)
+
+			for i, want := range tc.want {
+				req := httptest.NewRequest(http.MethodGet, "/v1/products", nil)
+				for name, value := range tc.headers {
+					req.Header.Set(name, value)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				assert.Equal(t, want, w.Code, "request %d: %s", i+1, strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+}
+
+const _allowlistRouteTable = `
+upstreams:
+  cart: ${UPSTREAM}
+  catalog: ${UPSTREAM}
+  notification: ${UPSTREAM}
+rate_limits:
+  global:
+    burst: 1
+  allowlist:
+    - 192.0.2.7
+routes:
+  - path: /v1/products
+    methods: [GET]
+    upstream: catalog
+`
+
+func TestRouter_Load_TrustedProxies(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		want           []int
+	}{
+		{
+			// the client claims to be forwarded from the allowlisted address
+			name: "SpoofedForwardedFor",
+			want: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:           "TrustedProxy",
+			trustedProxies: []string{"203.0.113.0/24"},
+			want:           []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newRouter(t, _allowlistRouteTable, func(conf *config.Config) {
+				conf.HTTP.TrustedProxies = tc.trustedProxies
+			})
+
+			for i, want := range tc.want {
+				req := httptest.NewRequest(http.MethodGet, "/v1/products", nil)
+				req.RemoteAddr = "203.0.113.1:4321"
+				req.Header.Set("X-Forwarded-For", "192.0.2.7")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				assert.Equal(t, want, w.Code, "request %d: %s", i+1, strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+}
+
+func TestRouter_Load_HandlerRoutes(t *testing.T) {
+	router := newRouter(t, _handlerRouteTable)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/graphql/schema", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "type Query {")
+
+	// the policy of the table applies before the login is required
+	statuses := []int{http.StatusBadRequest, http.StatusTooManyRequests}
+	for i, want := range statuses {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(`{"query":"{ me { id } }"}`)))
+		assert.Equal(t, want, w.Code, "request %d: %s", i+1, strings.TrimSpace(w.Body.String()))
+	}
+}
@@ -0,0 +1,76 @@
+package messaging
+
+import (
+	"context"
+	"shared/events"
+	natsClient "shared/messaging/nats"
+
+	applicationServices "gateway/internal/domain/application-services"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	usersStreamName                       = "users"
+	userUpdateDurableConsumerName         = "gateway-user-update"
+	userDeleteDurableConsumerName         = "gateway-user-delete"
+	userPasswordChangeDurableConsumerName = "gateway-user-password-change"
+	userLogoutDurableConsumerName         = "gateway-user-logout"
+)
+
+// UserMessagingHandlers drop the cached sessions of users whose name, roles
+// or credentials changed, who logged out or who were deleted.
+type UserMessagingHandlers interface {
+	Init()
+}
+
+type userMessagingHandlers struct {
+	natsClient natsClient.NatsClient
+	logger     zerolog.Logger
+	appService applicationServices.UserApplicationService
+}
+
+func NewUserMessagingHandlers(
+	natsClient natsClient.NatsClient,
+	appService applicationServices.UserApplicationService,
+	logger zerolog.Logger,
+) *userMessagingHandlers {
+	return &userMessagingHandlers{natsClient: natsClient, appService: appService, logger: logger}
+}
+
+func (u *userMessagingHandlers) Init() {
+	u.logger.Info().Msg("initializing UserMessagingHandlers")
+	err := u.natsClient.CreateStream(usersStreamName, "users.*")
+	if err != nil {
+		log.Error().Err(err).Msg("Init -> u.natsClient.CreateStream")
+	}
+
+	events.Subscribe(u.natsClient, usersStreamName, userUpdateDurableConsumerName,
+		func(ctx context.Context, event events.UserUpdated, _ events.Envelope) error {
+			return u.invalidate(ctx, event.ID)
+		})
+	events.Subscribe(u.natsClient, usersStreamName, userDeleteDurableConsumerName,
+		func(ctx context.Context, event events.UserDeleted, _ events.Envelope) error {
+			return u.invalidate(ctx, event.ID)
+		})
+	events.Subscribe(u.natsClient, usersStreamName, userPasswordChangeDurableConsumerName,
+		func(ctx context.Context, event events.UserPasswordChanged, _ events.Envelope) error {
+			return u.invalidate(ctx, event.ID)
+		})
+	events.Subscribe(u.natsClient, usersStreamName, userLogoutDurableConsumerName,
+		func(ctx context.Context, event events.UserLoggedOut, _ events.Envelope) error {
+			return u.invalidate(ctx, event.ID)
+		})
+}
+
+// the cache is not keyed by session id, a logout drops every cached session
+// of the user
+func (u *userMessagingHandlers) invalidate(ctx context.Context, userID string) error {
+	err := u.appService.InvalidateUserSessions(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID).Msg("invalidate -> u.appService.InvalidateUserSessions")
+		return err
+	}
+	return nil
+}
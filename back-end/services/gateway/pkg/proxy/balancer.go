@@ -0,0 +1,129 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net/url"
+	"sort"
+	"sync/atomic"
+)
+
+// Strategy picks the instance of an upstream a request goes to.
+type Strategy string
+
+const (
+	RoundRobin       Strategy = "round_robin"
+	LeastConnections Strategy = "least_connections"
+	// ConsistentHash sends the requests of a hash key, see WithHashKey, to
+	// the same instance as long as it is healthy.
+	ConsistentHash Strategy = "consistent_hash"
+)
+
+// points of an instance on the hash ring, more points spread the keys more
+// evenly
+const _ringReplicas = 100
+
+var ErrNoHealthyInstance = errors.New("proxy: no healthy instance")
+
+// Upstream is a service running on one or more instances.
+type Upstream struct {
+	Instances []string
+	// defaults to RoundRobin
+	Strategy Strategy
+	// probed on every instance, instances failing it get no requests. Empty
+	// disables the probes.
+	HealthPath string
+}
+
+func (u Upstream) key() string {
+	return fmt.Sprintf("%s %s %v", u.Strategy, u.HealthPath, u.Instances)
+}
+
+type instance struct {
+	url     *url.URL
+	breaker *breaker
+	healthy atomic.Bool
+	// requests in flight
+	active     atomic.Int64
+	healthPath string
+}
+
+type hashKey struct{}
+
+// WithHashKey sets the key ConsistentHash balances the request by.
+func WithHashKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, hashKey{}, key)
+}
+
+type ringPoint struct {
+	hash     uint32
+	instance *instance
+}
+
+type balancer struct {
+	strategy  Strategy
+	instances []*instance
+	next      atomic.Uint64
+	ring      []ringPoint
+}
+
+func newBalancer(strategy Strategy, instances []*instance) *balancer {
+	b := &balancer{strategy: strategy, instances: instances}
+	if strategy == ConsistentHash {
+		for _, inst := range instances {
+			for i := 0; i < _ringReplicas; i++ {
+				b.ring = append(b.ring, ringPoint{
+					hash:     crc32.ChecksumIEEE([]byte(fmt.Sprintf("%s#%d", inst.url, i))),
+					instance: inst,
+				})
+			}
+		}
+		sort.Slice(b.ring, func(i, j int) bool { return b.ring[i].hash < b.ring[j].hash })
+	}
+	return b
+}
+
+// candidates returns the healthy instances in the order they should be
+// tried.
+func (b *balancer) candidates(ctx context.Context) []*instance {
+	healthy := make([]*instance, 0, len(b.instances))
+	for _, inst := range b.instances {
+		if inst.healthy.Load() {
+			healthy = append(healthy, inst)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	switch b.strategy {
+	case LeastConnections:
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return healthy[i].active.Load() < healthy[j].active.Load()
+		})
+		return healthy
+	case ConsistentHash:
+		// walk the ring from the key so a dead instance only moves its own
+		// keys
+		key, _ := ctx.Value(hashKey{}).(string)
+		hash := crc32.ChecksumIEEE([]byte(key))
+		start := sort.Search(len(b.ring), func(i int) bool { return b.ring[i].hash >= hash })
+		ordered := make([]*instance, 0, len(healthy))
+		seen := make(map[*instance]bool, len(b.instances))
+		for i := 0; i < len(b.ring) && len(ordered) < len(healthy); i++ {
+			inst := b.ring[(start+i)%len(b.ring)].instance
+			if !seen[inst] && inst.healthy.Load() {
+				ordered = append(ordered, inst)
+			}
+			seen[inst] = true
+		}
+		return ordered
+	default:
+		start := int(b.next.Add(1) % uint64(len(healthy)))
+		ordered := make([]*instance, 0, len(healthy))
+		ordered = append(ordered, healthy[start:]...)
+		return append(ordered, healthy[:start]...)
+	}
+}
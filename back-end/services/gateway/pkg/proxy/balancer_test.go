@@ -0,0 +1,112 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInstances(t *testing.T, n int) []*instance {
+	instances := make([]*instance, n)
+	for i := range instances {
+		u, err := url.Parse(fmt.Sprintf("http://instance-%d:8080", i))
+		require.NoError(t, err)
+		instances[i] = &instance{url: u, breaker: newBreaker(1, time.Minute)}
+		instances[i].healthy.Store(true)
+	}
+	return instances
+}
+
+func TestBalancer_RoundRobin(t *testing.T) {
+	t.Parallel()
+	instances := newInstances(t, 3)
+	b := newBalancer(RoundRobin, instances)
+
+	firsts := map[*instance]int{}
+	for i := 0; i < 6; i++ {
+		candidates := b.candidates(context.Background())
+		// the others are the fallbacks of the first
+		assert.ElementsMatch(t, instances, candidates)
+		firsts[candidates[0]]++
+	}
+
+	assert.Equal(t, map[*instance]int{instances[0]: 2, instances[1]: 2, instances[2]: 2}, firsts)
+}
+
+func TestBalancer_LeastConnections(t *testing.T) {
+	t.Parallel()
+	instances := newInstances(t, 3)
+	instances[0].active.Store(3)
+	instances[1].active.Store(1)
+	instances[2].active.Store(2)
+	b := newBalancer(LeastConnections, instances)
+
+	assert.Equal(t, []*instance{instances[1], instances[2], instances[0]}, b.candidates(context.Background()))
+
+	instances[1].active.Store(5)
+	assert.Equal(t, []*instance{instances[2], instances[0], instances[1]}, b.candidates(context.Background()))
+}
+
+func TestBalancer_ConsistentHash(t *testing.T) {
+	t.Parallel()
+	instances := newInstances(t, 3)
+	b := newBalancer(ConsistentHash, instances)
+	first := func(key string) *instance {
+		return b.candidates(WithHashKey(context.Background(), key))[0]
+	}
+
+	owners := map[string]*instance{}
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		owners[key] = first(key)
+		// the same key always goes to the same instance
+		assert.Same(t, owners[key], first(key))
+		assert.ElementsMatch(t, instances, b.candidates(WithHashKey(context.Background(), key)))
+	}
+	used := map[*instance]bool{}
+	for _, owner := range owners {
+		used[owner] = true
+	}
+	assert.Len(t, used, 3, "the keys are spread over the instances")
+
+	// only the keys of a dead instance move
+	instances[0].healthy.Store(false)
+	for key, owner := range owners {
+		if owner == instances[0] {
+			assert.NotSame(t, instances[0], first(key))
+		} else {
+			assert.Same(t, owner, first(key))
+		}
+	}
+}
+
+func TestBalancer_SkipsUnhealthyInstances(t *testing.T) {
+	t.Parallel()
+	for _, strategy := range []Strategy{RoundRobin, LeastConnections, ConsistentHash} {
+		strategy := strategy
+		t.Run(string(strategy), func(t *testing.T) {
+			t.Parallel()
+			instances := newInstances(t, 3)
+			b := newBalancer(strategy, instances)
+			ctx := WithHashKey(context.Background(), "user-1")
+
+			instances[1].healthy.Store(false)
+			for i := 0; i < 4; i++ {
+				assert.ElementsMatch(t, []*instance{instances[0], instances[2]}, b.candidates(ctx))
+			}
+
+			instances[0].healthy.Store(false)
+			instances[2].healthy.Store(false)
+			assert.Empty(t, b.candidates(ctx))
+
+			// instances passing their probe again get requests again
+			instances[1].healthy.Store(true)
+			assert.Equal(t, []*instance{instances[1]}, b.candidates(ctx))
+		})
+	}
+}
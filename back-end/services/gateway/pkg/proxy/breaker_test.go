@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is the time of a breaker under test.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(threshold int, openTimeout time.Duration) (*breaker, *clock) {
+	c := &clock{now: time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)}
+	b := newBreaker(threshold, openTimeout)
+	b.now = func() time.Time { return c.now }
+	return b, c
+}
+
+func TestBreaker_ClosedUntilThreshold(t *testing.T) {
+	t.Parallel()
+	b, _ := newTestBreaker(3, time.Minute)
+
+	b.failure()
+	b.failure()
+	assert.True(t, b.allow())
+	assert.Equal(t, breakerClosed, b.state)
+
+	// a success resets the consecutive failures
+	b.success()
+	b.failure()
+	b.failure()
+	assert.True(t, b.allow())
+
+	b.failure()
+	assert.Equal(t, breakerOpen, b.state)
+	assert.False(t, b.allow())
+}
+
+func TestBreaker_OpenUntilTimeout(t *testing.T) {
+	t.Parallel()
+	b, c := newTestBreaker(1, time.Minute)
+	b.failure()
+
+	c.advance(time.Minute - time.Second)
+	assert.False(t, b.allow())
+
+	c.advance(time.Second)
+	assert.True(t, b.allow(), "a probe is let through")
+	assert.Equal(t, breakerHalfOpen, b.state)
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		outcome func(b *breaker)
+		// state after the outcome of the probe
+		want      breakerState
+		wantAllow bool
+	}{
+		{
+			name:      "ProbeSucceeds_Closes",
+			outcome:   (*breaker).success,
+			want:      breakerClosed,
+			wantAllow: true,
+		},
+		{
+			name:      "ProbeFails_Reopens",
+			outcome:   (*breaker).failure,
+			want:      breakerOpen,
+			wantAllow: false,
+		},
+		{
+			name:      "ProbeReleased_AllowsAnotherProbe",
+			outcome:   (*breaker).release,
+			want:      breakerHalfOpen,
+			wantAllow: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			b, c := newTestBreaker(2, time.Minute)
+			b.failure()
+			b.failure()
+			c.advance(time.Minute)
+			assert.True(t, b.allow())
+			// one probe at a time
+			assert.False(t, b.allow())
+
+			tc.outcome(b)
+
+			assert.Equal(t, tc.want, b.state)
+			assert.Equal(t, tc.wantAllow, b.allow())
+		})
+	}
+}
+
+func TestBreaker_ReopenedCircuitWaitsAgain(t *testing.T) {
+	t.Parallel()
+	b, c := newTestBreaker(1, time.Minute)
+	b.failure()
+	c.advance(time.Minute)
+	assert.True(t, b.allow())
+
+	c.advance(30 * time.Second)
+	b.failure()
+
+	c.advance(59 * time.Second)
+	assert.False(t, b.allow(), "the open timeout starts when the probe failed")
+	c.advance(time.Second)
+	assert.True(t, b.allow())
+}
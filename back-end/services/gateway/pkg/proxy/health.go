@@ -0,0 +1,65 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Run probes the instances until ctx is done. An instance that fails its
+// probe gets no requests until it passes one again.
+func (p *Pool) Run(ctx context.Context) error {
+	client := &http.Client{Transport: p.transport, Timeout: p.healthTimeout}
+	ticker := time.NewTicker(p.healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		type probe struct {
+			inst *instance
+			url  string
+		}
+		var probes []probe
+		p.mu.Lock()
+		for _, inst := range p.instances {
+			if inst.healthPath != "" {
+				probes = append(probes, probe{inst: inst, url: inst.url.JoinPath(inst.healthPath).String()})
+			}
+		}
+		p.mu.Unlock()
+
+		var wg sync.WaitGroup
+		for _, pr := range probes {
+			wg.Add(1)
+			go func(pr probe) {
+				defer wg.Done()
+				healthy := p.check(ctx, client, pr.url)
+				if ctx.Err() != nil {
+					return
+				}
+				if pr.inst.healthy.Swap(healthy) != healthy {
+					p.logger.Warn().Str("instance", pr.inst.url.String()).Bool("healthy", healthy).Msg("proxy -> Run - instance health changed")
+				}
+			}(pr)
+		}
+		wg.Wait()
+	}
+}
+
+func (p *Pool) check(ctx context.Context, client *http.Client, url string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
@@ -0,0 +1,105 @@
+package proxy_test
+
+import (
+	"context"
+	"gateway/pkg/proxy"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// service passes the health probe on path while healthy is set.
+type service struct {
+	*httptest.Server
+	healthy atomic.Bool
+	hits    atomic.Int64
+}
+
+func newService(t *testing.T, path string) *service {
+	s := &service{}
+	s.healthy.Store(true)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == path {
+			if !s.healthy.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			return
+		}
+		s.hits.Add(1)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func runPool(t *testing.T) *proxy.Pool {
+	pool := proxy.NewPool(zerolog.Nop(), proxy.HealthCheck(5*time.Millisecond, time.Second), proxy.Retries(0))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = pool.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return pool
+}
+
+func TestPool_Run_RemovesUnhealthyInstances(t *testing.T) {
+	t.Parallel()
+	pool := runPool(t)
+	healthy, sick := newService(t, "/healthz"), newService(t, "/healthz")
+	upstream := proxy.Upstream{Instances: []string{healthy.URL, sick.URL}, HealthPath: "/healthz"}
+	serve(t, pool, upstream, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+
+	sick.healthy.Store(false)
+	assert.Eventually(t, func() bool {
+		before := sick.hits.Load()
+		for i := 0; i < 4; i++ {
+			serve(t, pool, upstream, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+		}
+		return sick.hits.Load() == before
+	}, time.Second, 10*time.Millisecond)
+
+	healthy.healthy.Store(false)
+	assert.Eventually(t, func() bool {
+		w := serve(t, pool, upstream, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+		return w.Code == http.StatusServiceUnavailable
+	}, time.Second, 10*time.Millisecond)
+
+	sick.healthy.Store(true)
+	assert.Eventually(t, func() bool {
+		w := serve(t, pool, upstream, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+		return w.Code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+}
+
+// Two upstreams running on the same instance are probed on their own health
+// path.
+func TestPool_Run_SharedInstanceWithOtherHealthPath(t *testing.T) {
+	t.Parallel()
+	pool := runPool(t)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ready" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(s.Close)
+	live := proxy.Upstream{Instances: []string{s.URL}, HealthPath: "/live"}
+	ready := proxy.Upstream{Instances: []string{s.URL}, HealthPath: "/ready"}
+	serve(t, pool, live, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+	serve(t, pool, ready, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+
+	assert.Eventually(t, func() bool {
+		w := serve(t, pool, ready, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+		return w.Code == http.StatusServiceUnavailable
+	}, time.Second, 10*time.Millisecond)
+	w := serve(t, pool, live, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
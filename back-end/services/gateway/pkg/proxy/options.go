@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"time"
+)
+
+// Option -.
+type Option func(*Pool)
+
+// DialTimeout -.
+func DialTimeout(timeout time.Duration) Option {
+	return func(p *Pool) {
+		p.dialTimeout = timeout
+	}
+}
+
+// IdleConnTimeout -.
+func IdleConnTimeout(timeout time.Duration) Option {
+	return func(p *Pool) {
+		p.idleConnTimeout = timeout
+	}
+}
+
+// MaxIdleConnsPerHost -.
+func MaxIdleConnsPerHost(n int) Option {
+	return func(p *Pool) {
+		p.maxIdleConnsPerHost = n
+	}
+}
+
+// Retries is the number of extra attempts of GET and HEAD requests.
+func Retries(n int) Option {
+	return func(p *Pool) {
+		p.retries = n
+	}
+}
+
+// RetryBackoff is the delay before the first retry, it doubles with every
+// attempt.
+func RetryBackoff(backoff time.Duration) Option {
+	return func(p *Pool) {
+		p.retryBackoff = backoff
+	}
+}
+
+// Breaker opens the circuit of an upstream after threshold consecutive
+// failures and probes it again after openTimeout.
+func Breaker(threshold int, openTimeout time.Duration) Option {
+	return func(p *Pool) {
+		p.breakerThreshold = threshold
+		p.breakerOpenTimeout = openTimeout
+	}
+}
+
+// HealthCheck probes the instances of the upstreams with a health path every
+// interval.
+func HealthCheck(interval, timeout time.Duration) Option {
+	return func(p *Pool) {
+		p.healthInterval = interval
+		p.healthTimeout = timeout
+	}
+}
@@ -0,0 +1,206 @@
+// Package proxy implements the reverse proxies of the gateway upstreams.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	httpErrors "shared/errors/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	_defaultDialTimeout         = 2 * time.Second
+	_defaultIdleConnTimeout     = 90 * time.Second
+	_defaultMaxIdleConnsPerHost = 100
+	_defaultRetries             = 2
+	_defaultRetryBackoff        = 50 * time.Millisecond
+	_defaultBreakerThreshold    = 5
+	_defaultBreakerOpenTimeout  = 30 * time.Second
+	_defaultHealthInterval      = 5 * time.Second
+	_defaultHealthTimeout       = time.Second
+)
+
+// Pool keeps one reverse proxy per upstream. The proxies share their
+// connections and outlive reloads of the route table, so does the state of
+// the circuit breakers and health checks of their instances.
+type Pool struct {
+	logger    zerolog.Logger
+	transport *http.Transport
+
+	dialTimeout         time.Duration
+	idleConnTimeout     time.Duration
+	maxIdleConnsPerHost int
+	retries             int
+	retryBackoff        time.Duration
+	breakerThreshold    int
+	breakerOpenTimeout  time.Duration
+	healthInterval      time.Duration
+	healthTimeout       time.Duration
+
+	mu        sync.Mutex
+	proxies   map[string]*httputil.ReverseProxy
+	instances map[string]*instance
+}
+
+// NewPool -.
+func NewPool(logger zerolog.Logger, opts ...Option) *Pool {
+	p := &Pool{
+		logger:              logger,
+		dialTimeout:         _defaultDialTimeout,
+		idleConnTimeout:     _defaultIdleConnTimeout,
+		maxIdleConnsPerHost: _defaultMaxIdleConnsPerHost,
+		retries:             _defaultRetries,
+		retryBackoff:        _defaultRetryBackoff,
+		breakerThreshold:    _defaultBreakerThreshold,
+		breakerOpenTimeout:  _defaultBreakerOpenTimeout,
+		healthInterval:      _defaultHealthInterval,
+		healthTimeout:       _defaultHealthTimeout,
+		proxies:             map[string]*httputil.ReverseProxy{},
+		instances:           map[string]*instance{},
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.transport = &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   p.dialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        p.maxIdleConnsPerHost * 10,
+		MaxIdleConnsPerHost: p.maxIdleConnsPerHost,
+		IdleConnTimeout:     p.idleConnTimeout,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	return p
+}
+
+// Get returns the proxy of the upstream. The instances must only differ in
+// scheme and host.
+func (p *Pool) Get(upstream Upstream) (*httputil.ReverseProxy, error) {
+	if len(upstream.Instances) == 0 {
+		return nil, errors.New("proxy -> Get - upstream without instances")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if proxy, ok := p.proxies[upstream.key()]; ok {
+		return proxy, nil
+	}
+
+	instances := make([]*instance, 0, len(upstream.Instances))
+	for _, target := range upstream.Instances {
+		inst, err := p.instance(target, upstream.HealthPath)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, inst)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(instances[0].url)
+	proxy.Transport = &transport{
+		next:     p.transport,
+		balancer: newBalancer(upstream.Strategy, instances),
+		retries:  p.retries,
+		backoff:  p.retryBackoff,
+	}
+	proxy.ErrorHandler = p.errorHandler(strings.Join(upstream.Instances, ","))
+	p.proxies[upstream.key()] = proxy
+	return proxy, nil
+}
+
+// Client returns a client balancing its requests over the instances of the
+// upstream, requests are sent to the first instance's URL.
+func (p *Pool) Client(upstream Upstream) (*http.Client, error) {
+	proxy, err := p.Get(upstream)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: proxy.Transport}, nil
+}
+
+// instance returns the instance of the target probed on healthPath. Upstreams
+// sharing a target with different health paths get an instance each, so one
+// does not overwrite the probe of the other.
+func (p *Pool) instance(target, healthPath string) (*instance, error) {
+	if inst, ok := p.instances[instanceKey(target, healthPath)]; ok {
+		return inst, nil
+	}
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("proxy -> Get - url.Parse: %w", err)
+	}
+	if targetURL.Scheme == "" || targetURL.Host == "" {
+		return nil, fmt.Errorf("proxy -> Get - %q is not an absolute URL", target)
+	}
+	inst := &instance{
+		url:        targetURL,
+		breaker:    newBreaker(p.breakerThreshold, p.breakerOpenTimeout),
+		healthPath: healthPath,
+	}
+	inst.healthy.Store(true)
+	p.instances[instanceKey(target, healthPath)] = inst
+	return inst, nil
+}
+
+func instanceKey(target, healthPath string) string {
+	return target + " " + healthPath
+}
+
+// Retain drops the proxies and instances of upstreams not in use anymore.
+func (p *Pool) Retain(upstreams []Upstream) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	proxies := make(map[string]*httputil.ReverseProxy, len(upstreams))
+	instances := map[string]*instance{}
+	for _, upstream := range upstreams {
+		if proxy, ok := p.proxies[upstream.key()]; ok {
+			proxies[upstream.key()] = proxy
+		}
+		for _, target := range upstream.Instances {
+			key := instanceKey(target, upstream.HealthPath)
+			if inst, ok := p.instances[key]; ok {
+				instances[key] = inst
+			}
+		}
+	}
+	p.proxies = proxies
+	p.instances = instances
+}
+
+// Close closes the idle upstream connections.
+func (p *Pool) Close() error {
+	p.transport.CloseIdleConnections()
+	return nil
+}
+
+func (p *Pool) errorHandler(target string) func(http.ResponseWriter, *http.Request, error) {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		if errors.Is(r.Context().Err(), context.Canceled) {
+			// the client went away, nobody reads the response
+			return
+		}
+		p.logger.Error().Err(err).Str("upstream", target).Str("path", r.URL.Path).Msg("proxy -> ErrorHandler")
+
+		var netErr net.Error
+		switch {
+		case errors.Is(err, ErrCircuitOpen), errors.Is(err, ErrNoHealthyInstance):
+			httpErrors.WriteError(w, "Service unavailable", http.StatusServiceUnavailable)
+		case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+			httpErrors.WriteError(w, "Service timed out", http.StatusGatewayTimeout)
+		default:
+			httpErrors.WriteError(w, "Bad gateway", http.StatusBadGateway)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("proxy: circuit open")
+
+// transport balances the requests of one upstream over its instances, each
+// behind its own breaker, and retries idempotent requests without a body on
+// the next instance.
+type transport struct {
+	next     http.RoundTripper
+	balancer *balancer
+	retries  int
+	backoff  time.Duration
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		inst, err := t.pick(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.send(inst, req)
+		if errors.Is(req.Context().Err(), context.Canceled) {
+			inst.breaker.release()
+			return resp, err
+		}
+		if !failed(resp, err) {
+			inst.breaker.success()
+			return resp, err
+		}
+		inst.breaker.failure()
+		if attempt >= t.retries || !retryable(req) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		// exponential backoff with jitter so retries of many clients do not
+		// hit the upstream at once
+		delay := t.backoff << attempt
+		delay += time.Duration(rand.Int63n(int64(delay)/2 + 1))
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pick returns the first candidate whose circuit lets the request through.
+func (t *transport) pick(ctx context.Context) (*instance, error) {
+	candidates := t.balancer.candidates(ctx)
+	if len(candidates) == 0 {
+		return nil, ErrNoHealthyInstance
+	}
+	for _, inst := range candidates {
+		if inst.breaker.allow() {
+			return inst, nil
+		}
+	}
+	return nil, ErrCircuitOpen
+}
+
+func (t *transport) send(inst *instance, req *http.Request) (*http.Response, error) {
+	outreq := *req
+	outURL := *req.URL
+	outURL.Scheme = inst.url.Scheme
+	outURL.Host = inst.url.Host
+	outreq.URL = &outURL
+
+	inst.active.Add(1)
+	resp, err := t.next.RoundTrip(&outreq)
+	if err != nil {
+		inst.active.Add(-1)
+		return nil, err
+	}
+	// the request is in flight until the proxy is done with the body
+	var once sync.Once
+	done := func() { once.Do(func() { inst.active.Add(-1) }) }
+	if conn, ok := resp.Body.(io.ReadWriteCloser); ok && resp.StatusCode == http.StatusSwitchingProtocols {
+		// the proxy needs to write to upgraded connections
+		resp.Body = &trackedConn{ReadWriteCloser: conn, done: done}
+	} else {
+		resp.Body = &trackedBody{ReadCloser: resp.Body, done: done}
+	}
+	return resp, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	done func()
+}
+
+func (b *trackedBody) Close() error {
+	b.done()
+	return b.ReadCloser.Close()
+}
+
+type trackedConn struct {
+	io.ReadWriteCloser
+	done func()
+}
+
+func (c *trackedConn) Close() error {
+	c.done()
+	return c.ReadWriteCloser.Close()
+}
+
+// failed tells whether the upstream could not handle the request, errors of
+// the service itself do not count.
+func failed(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody
+}
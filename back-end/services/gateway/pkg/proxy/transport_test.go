@@ -0,0 +1,194 @@
+package proxy_test
+
+import (
+	"context"
+	"gateway/pkg/proxy"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upstream answers every request with status and counts the requests.
+type upstream struct {
+	*httptest.Server
+	status int
+	hits   atomic.Int64
+}
+
+func newUpstream(t *testing.T, status int) *upstream {
+	u := &upstream{status: status}
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.hits.Add(1)
+		w.WriteHeader(u.status)
+		_, _ = w.Write([]byte("from upstream"))
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+func serve(t *testing.T, pool *proxy.Pool, upstream proxy.Upstream, req *http.Request) *httptest.ResponseRecorder {
+	reverseProxy, err := pool.Get(upstream)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	reverseProxy.ServeHTTP(w, req)
+	return w
+}
+
+func TestPool_Get_RetriesIdempotentRequests(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		method   string
+		body     string
+		wantHits int64
+	}{
+		{name: "Get", method: http.MethodGet, wantHits: 3},
+		{name: "Head", method: http.MethodHead, wantHits: 3},
+		{name: "Post", method: http.MethodPost, wantHits: 1},
+		{name: "Delete", method: http.MethodDelete, wantHits: 1},
+		{name: "GetWithBody", method: http.MethodGet, body: "{}", wantHits: 1},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			failing := newUpstream(t, http.StatusServiceUnavailable)
+			pool := proxy.NewPool(zerolog.Nop(), proxy.Retries(2), proxy.RetryBackoff(time.Millisecond), proxy.Breaker(10, time.Minute))
+
+			req := httptest.NewRequest(tc.method, "/v1/products", nil)
+			if tc.body != "" {
+				req = httptest.NewRequest(tc.method, "/v1/products", strings.NewReader(tc.body))
+			}
+			w := serve(t, pool, proxy.Upstream{Instances: []string{failing.URL}}, req)
+
+			// the response of the last attempt is passed on
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.Equal(t, tc.wantHits, failing.hits.Load())
+		})
+	}
+}
+
+func TestPool_Get_RetriesNextInstance(t *testing.T) {
+	t.Parallel()
+	failing := newUpstream(t, http.StatusBadGateway)
+	healthy := newUpstream(t, http.StatusOK)
+	pool := proxy.NewPool(zerolog.Nop(), proxy.Retries(1), proxy.RetryBackoff(time.Millisecond))
+	upstream := proxy.Upstream{Instances: []string{failing.URL, healthy.URL}}
+
+	for i := 0; i < 4; i++ {
+		w := serve(t, pool, upstream, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "from upstream", w.Body.String())
+	}
+	// every request that went to the failing instance was retried
+	assert.Equal(t, int64(4), healthy.hits.Load())
+	assert.Positive(t, failing.hits.Load())
+}
+
+func TestPool_Get_RetriesStopWhenCircuitOpens(t *testing.T) {
+	t.Parallel()
+	failing := newUpstream(t, http.StatusServiceUnavailable)
+	pool := proxy.NewPool(zerolog.Nop(), proxy.Retries(5), proxy.RetryBackoff(time.Millisecond), proxy.Breaker(2, time.Hour))
+	upstream := proxy.Upstream{Instances: []string{failing.URL}}
+
+	w := serve(t, pool, upstream, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+
+	assert.Equal(t, int64(2), failing.hits.Load())
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"message":"Service unavailable","success":false}`, w.Body.String())
+
+	// the open circuit rejects requests without reaching the upstream
+	w = serve(t, pool, upstream, httptest.NewRequest(http.MethodPost, "/v1/orders", nil))
+
+	assert.Equal(t, int64(2), failing.hits.Load())
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestPool_Get_Errors(t *testing.T) {
+	t.Parallel()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+
+	testCases := []struct {
+		name       string
+		target     string
+		timeout    time.Duration
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Unreachable",
+			target:     unreachable.URL,
+			wantStatus: http.StatusBadGateway,
+			wantBody:   `{"message":"Bad gateway","success":false}`,
+		},
+		{
+			name:       "Timeout",
+			target:     slow.URL,
+			timeout:    20 * time.Millisecond,
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   `{"message":"Service timed out","success":false}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			pool := proxy.NewPool(zerolog.Nop(), proxy.Retries(0))
+			req := httptest.NewRequest(http.MethodGet, "/v1/products", nil)
+			if tc.timeout > 0 {
+				ctx, cancel := context.WithTimeout(req.Context(), tc.timeout)
+				defer cancel()
+				req = req.WithContext(ctx)
+			}
+
+			w := serve(t, pool, proxy.Upstream{Instances: []string{tc.target}}, req)
+
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestPool_Get_ClientCanceled(t *testing.T) {
+	t.Parallel()
+	var hits atomic.Int64
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(slow.Close)
+	pool := proxy.NewPool(zerolog.Nop(), proxy.Breaker(1, time.Hour))
+	upstream := proxy.Upstream{Instances: []string{slow.URL}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	w := serve(t, pool, upstream, httptest.NewRequest(http.MethodGet, "/v1/products", nil).WithContext(ctx))
+
+	// nobody reads the response, and the instance is not to blame
+	assert.Empty(t, w.Body.String())
+	w = serve(t, pool, upstream, httptest.NewRequest(http.MethodGet, "/v1/products", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(2), hits.Load())
+}
@@ -0,0 +1,20 @@
+package redis
+
+import (
+	"context"
+	"shared/health"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+func NewHealthChecker(pool *redis.Pool) health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		conn, err := pool.GetContext(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = conn.Do("PING")
+		return err
+	})
+}
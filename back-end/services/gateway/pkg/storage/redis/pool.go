@@ -1,10 +1,24 @@
 package redis
 
-import "github.com/gomodule/redigo/redis"
+import (
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
 
 func NewRedisPool(redisAddress string) *redis.Pool {
-	pool := redis.Pool(redis.Pool{Dial: func() (redis.Conn, error) {
-		return redis.Dial("tcp", redisAddress)
-	}})
+	pool := redis.Pool(redis.Pool{
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial(
+				"tcp",
+				redisAddress,
+				redis.DialConnectTimeout(time.Second),
+				redis.DialReadTimeout(time.Second),
+				redis.DialWriteTimeout(time.Second),
+			)
+		},
+		MaxIdle:     16,
+		IdleTimeout: 5 * time.Minute,
+	})
 	return &pool
 }
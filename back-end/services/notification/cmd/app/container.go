@@ -1,13 +1,18 @@
 package main
 
 import (
-	"os"
+	"context"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
 	"notification/config"
-	"notification/pkg/httpserver"
+	"shared/authz"
+	"shared/health"
+	inboxStore "shared/inbox/pg"
+	"shared/lifecycle"
 	pgStorage "shared/storage/pg"
 
 	httpServ "notification/internal/transport/http"
@@ -22,20 +27,37 @@ import (
 	messaging "notification/internal/transport/messaging"
 )
 
-func buildDependencies() (
+const maxConsumerLag = 1000
+
+func buildDependencies(runner *lifecycle.Runner, logger zerolog.Logger) (
 	messaging.UserMessagingHandlers,
 	messaging.NotificationMessagingHandlers,
-	*socketServer.SocketIOServer,
-	*httpserver.Server,
 	error,
 ) {
-	logger := zerolog.New(os.Stdout)
 	config, err := config.NewConfig()
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, err
 	}
+	checks := health.NewHealth(runner)
 	pg := pgStorage.NewClient(logger, pgStorage.Config{DSN: config.PgSDN})
+	runner.OnShutdown("postgres", lifecycle.Closer(pg))
+	checks.AddReadinessCheck("postgres", health.Ping(pg))
+	transactor := pgStorage.NewTransactor(pg)
+	inbox := inboxStore.NewInbox(pg, transactor)
+	inboxCleaner := inboxStore.NewCleaner(pg, logger)
+	runner.Go("inbox cleaner", func(ctx context.Context) error {
+		inboxCleaner.Run(ctx)
+		return nil
+	})
+	// created before NATS so that in-flight notifications can still be
+	// emitted while the subscriptions drain
+	verifier := authz.NewJWKSVerifier(config.Identity.JWKSURL, config.Identity.Issuer, &http.Client{Timeout: 5 * time.Second})
+	socketServer := socketServer.NewSocketIOServer(logger, verifier)
+	runner.OnShutdown("socket.io server", lifecycle.Closer(socketServer.Server))
 	nats := nats.NewNatsClient()
+	runner.OnShutdown("nats", nats.Drain)
+	checks.AddReadinessCheck("nats", health.Connection(nats))
+	checks.AddDegradedCheck("consumer lag", health.ConsumerLag(nats, maxConsumerLag))
 	userRepo := userRepository.NewUserRepository(pg, logger)
 	notificationRepo := notificationRepository.NewNotificationRepository(pg, logger)
 
@@ -44,9 +66,9 @@ func buildDependencies() (
 	userAppService := applicationServices.NewUserApplicationService(userRepo, logger, userDomainService)
 	notificationAppService := applicationServices.NewNotificationApplicationService(notificationRepo, logger, nats)
 
-	userMessageHandlers := messaging.NewUserMessagingHandlers(nats, userAppService, logger)
-	socketServer := socketServer.NewSocketIOServer(logger)
-	notificationMessageHandlers := messaging.NewNotificationMessagingHandlers(nats, notificationAppService, logger, socketServer)
-	httpServer := httpServ.NewHTTPServer(notificationAppService, gin.New(), logger, config, pg, socketServer)
-	return userMessageHandlers, notificationMessageHandlers, socketServer, httpServer, nil
+	userMessageHandlers := messaging.NewUserMessagingHandlers(nats, inbox, userAppService, logger)
+	notificationMessageHandlers := messaging.NewNotificationMessagingHandlers(nats, inbox, notificationAppService, logger, socketServer)
+	httpServer := httpServ.NewHTTPServer(notificationAppService, gin.New(), logger, config, pg, socketServer, checks, verifier)
+	runner.AddServer("http server", httpServer)
+	return userMessageHandlers, notificationMessageHandlers, nil
 }
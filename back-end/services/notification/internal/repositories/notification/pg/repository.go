@@ -10,6 +10,8 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/uptrace/bun"
+
+	pgStorage "shared/storage/pg"
 )
 
 type UserNotificationModel struct {
@@ -71,7 +73,7 @@ func (r *notificationPGRepository) CreateUserNotification(ctx context.Context, u
 	var dbNotification UserNotificationModel
 
 	dbNotification = dbNotification.toDB(userNotification)
-	_, err := r.db.NewInsert().Model(&dbNotification).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().Model(&dbNotification).Exec(ctx)
 	if err != nil {
 		return err
 	}
@@ -80,7 +82,7 @@ func (r *notificationPGRepository) CreateUserNotification(ctx context.Context, u
 
 func (r *notificationPGRepository) GetByUserID(ctx context.Context, userID string) ([]notificationEntity.UserNotification, error) {
 	notificationModels := make([]UserNotificationModel, 0)
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&notificationModels).
 		Where("user_id = (?)", userID).
 		OrderExpr("created_at DESC").
@@ -109,7 +111,7 @@ func (r *notificationPGRepository) GetByUserIDAndUserNotificationID(
 	userNotificationID string,
 ) (notificationEntity.UserNotification, error) {
 	var userNotificationModel UserNotificationModel
-	err := r.db.NewSelect().
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
 		Model(&userNotificationModel).
 		Where("user_id = (?)", userID).
 		Where("id = (?)", userNotificationID).
@@ -132,7 +134,7 @@ func (r *notificationPGRepository) MarkUserNotificationViewed(
 	var userNotification UserNotificationModel
 	userNotification.ViewedAt = time.Now()
 	userNotification.UpdatedAt = time.Now()
-	res, err := r.db.NewUpdate().
+	res, err := pgStorage.Conn(ctx, r.db).NewUpdate().
 		Model(&userNotification).
 		Column("viewed_at").
 		Column("updated_at").
@@ -156,7 +158,7 @@ func (r *notificationPGRepository) MarkAllUserNotificationViewed(
 		"viewed_at":  time.Now(),
 		"updated_at": time.Now(),
 	}
-	_, err := r.db.NewUpdate().
+	_, err := pgStorage.Conn(ctx, r.db).NewUpdate().
 		Model(&updates).
 		Table("notifications").
 		Where("user_id = (?)", userID).
@@ -172,7 +174,7 @@ func (r *notificationPGRepository) DeleteUserNotification(
 	userNotificationID string,
 ) error {
 	var user UserNotificationModel
-	_, err := r.db.NewDelete().Model(&user).Where("id = ?", userNotificationID).Exec(ctx)
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().Model(&user).Where("id = ?", userNotificationID).Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("notificationPGRepository DeleteUserNotification -> NewDelete: %w", err)
 	}
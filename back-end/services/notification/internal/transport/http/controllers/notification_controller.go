@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"encoding/json"
 	"notification/config"
 	"notification/internal/domain/entities/notification"
 	applicationServices "notification/internal/services"
@@ -11,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	httpDto "notification/internal/transport/http/dto"
+	"shared/authz"
 	httpErrors "shared/errors/http"
 )
 
@@ -32,16 +32,10 @@ func NewNotificationController(
 	}
 }
 
-type AuthInfo struct {
-	UserID string `json:"user_id"`
-}
-
 func (r *NotificationControllers) GetNotificationsByNotificationID(c *gin.Context) {
-	var authInfo AuthInfo
-	authValue := c.Request.Header.Get("X-Authentication-Info")
-	json.Unmarshal([]byte(authValue), &authInfo)
+	identity := authz.FromRequest(c.Request)
 
-	notifications, err := r.ApplicationService.GetNotificationsByUserID(c.Request.Context(), authInfo.UserID)
+	notifications, err := r.ApplicationService.GetNotificationsByUserID(c.Request.Context(), identity.UserID)
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
 		return
@@ -73,11 +67,9 @@ func (r *NotificationControllers) ViewNotification(c *gin.Context) {
 
 	notificationId, _ := c.Params.Get("notificationId")
 
-	var authInfo AuthInfo
-	authValue := c.Request.Header.Get("X-Authentication-Info")
-	json.Unmarshal([]byte(authValue), &authInfo)
+	identity := authz.FromRequest(c.Request)
 
-	err := r.ApplicationService.ViewNotification(c.Request.Context(), authInfo.UserID, notificationId)
+	err := r.ApplicationService.ViewNotification(c.Request.Context(), identity.UserID, notificationId)
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
 		return
@@ -89,11 +81,9 @@ func (r *NotificationControllers) DeleteUserNotification(c *gin.Context) {
 
 	notificationId, _ := c.Params.Get("notificationId")
 
-	var authInfo AuthInfo
-	authValue := c.Request.Header.Get("X-Authentication-Info")
-	json.Unmarshal([]byte(authValue), &authInfo)
+	identity := authz.FromRequest(c.Request)
 
-	err := r.ApplicationService.DeleteUserNotification(c.Request.Context(), authInfo.UserID, notificationId)
+	err := r.ApplicationService.DeleteUserNotification(c.Request.Context(), identity.UserID, notificationId)
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
 		return
@@ -102,11 +92,9 @@ func (r *NotificationControllers) DeleteUserNotification(c *gin.Context) {
 }
 
 func (r *NotificationControllers) ViewAllNotifications(c *gin.Context) {
-	var authInfo AuthInfo
-	authValue := c.Request.Header.Get("X-Authentication-Info")
-	json.Unmarshal([]byte(authValue), &authInfo)
+	identity := authz.FromRequest(c.Request)
 
-	err := r.ApplicationService.ViewAllNotifications(c.Request.Context(), authInfo.UserID)
+	err := r.ApplicationService.ViewAllNotifications(c.Request.Context(), identity.UserID)
 	if err != nil {
 		httpErrors.RespondWithError(c, err)
 		return
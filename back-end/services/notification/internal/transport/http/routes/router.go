@@ -1,9 +1,10 @@
 package routes
 
 import (
-	"net/http"
 	"notification/config"
 	applicationServices "notification/internal/services"
+	"shared/authz"
+	"shared/health"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
@@ -18,17 +19,20 @@ func NewRouter(
 	logger zerolog.Logger,
 	config *config.Config,
 	socketServer *socketServer.SocketIOServer,
+	checks *health.Health,
+	verifier authz.Verifier,
 ) {
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
-	handler.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	checks.Register(handler)
+	verify := authz.Middleware(verifier)
 
-	handler.GET("/socket.io/*any", gin.WrapH(socketServer.Server))
-	handler.POST("/socket.io/*any", gin.WrapH(socketServer.Server))
+	handler.GET("/socket.io/*any", verify, gin.WrapH(socketServer.Server))
+	handler.POST("/socket.io/*any", verify, gin.WrapH(socketServer.Server))
 
 	r := controllers.NewNotificationController(n, logger, config)
 
-	v1 := handler.Group("/v1")
+	v1 := handler.Group("/v1", verify)
 	v1.GET("/users/me/notifications", r.GetNotificationsByNotificationID)
 	v1.PATCH("/users/me/notifications/view", r.ViewAllNotifications)
 	v1.DELETE("/users/me/notifications/:notificationId", r.DeleteUserNotification)
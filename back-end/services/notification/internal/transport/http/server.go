@@ -13,6 +13,8 @@ import (
 	routes "notification/internal/transport/http/routes"
 	socketService "notification/internal/transport/http/socketio"
 	"notification/pkg/httpserver"
+	"shared/authz"
+	"shared/health"
 )
 
 func NewHTTPServer(
@@ -22,9 +24,10 @@ func NewHTTPServer(
 	config *config.Config,
 	db *bun.DB,
 	socketServer *socketService.SocketIOServer,
-
+	checks *health.Health,
+	verifier authz.Verifier,
 ) *httpserver.Server {
-	routes.NewRouter(handler, notificationApplicationService, logger, config, socketServer)
+	routes.NewRouter(handler, notificationApplicationService, logger, config, socketServer, checks, verifier)
 	logger.Info().Msg(fmt.Sprintf("Listening on %s port", config.HTTP.Port))
 	return httpserver.New(http.Handler(handler), httpserver.Port(config.HTTP.Port))
 }
@@ -1,8 +1,9 @@
 package socketserver
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
+	"shared/authz"
 	"sync"
 	"time"
 
@@ -27,10 +28,7 @@ type SocketIOServer struct {
 	Server      *socketio.Server
 	Connections Connections
 	logger      zerolog.Logger
-}
-
-type AuthInfo struct {
-	UserID string `json:"user_id"`
+	verifier    authz.Verifier
 }
 
 func (s *SocketIOServer) SendEvent(userId string) {
@@ -52,14 +50,16 @@ func (s *SocketIOServer) SendEvent(userId string) {
 
 func (s *SocketIOServer) initialize() {
 	s.Server.OnConnect("/", func(so socketio.Conn) error {
-		var authInfo AuthInfo
-		authValue := so.RemoteHeader().Get("X-Authentication-Info")
-		json.Unmarshal([]byte(authValue), &authInfo)
+		identity, err := s.verifier.Verify(context.Background(), so.RemoteHeader().Get(authz.Header))
+		if err != nil {
+			s.logger.Info().Err(err).Msgf("rejected client: %s", so.ID())
+			return err
+		}
 		s.logger.Info().Msgf("New client connected: %s", so.ID())
 		s.Connections.Lock()
 		defer s.Connections.Unlock()
-		so.SetContext(authInfo)
-		s.Connections.connections[authInfo.UserID] = append(s.Connections.connections[authInfo.UserID], so)
+		so.SetContext(identity)
+		s.Connections.connections[identity.UserID] = append(s.Connections.connections[identity.UserID], so)
 
 		return nil
 	})
@@ -82,7 +82,7 @@ func (s *SocketIOServer) initialize() {
 
 }
 
-func NewSocketIOServer(logger zerolog.Logger) *SocketIOServer {
+func NewSocketIOServer(logger zerolog.Logger, verifier authz.Verifier) *SocketIOServer {
 	server := socketio.NewServer(&engineio.Options{
 		Transports: []transport.Transport{
 			&polling.Transport{
@@ -94,7 +94,12 @@ func NewSocketIOServer(logger zerolog.Logger) *SocketIOServer {
 		},
 	})
 
-	socketServer := &SocketIOServer{Server: server, logger: logger, Connections: Connections{connections: make(map[string][]socketio.Conn)}}
+	socketServer := &SocketIOServer{
+		Server:      server,
+		logger:      logger,
+		verifier:    verifier,
+		Connections: Connections{connections: make(map[string][]socketio.Conn)},
+	}
 	socketServer.initialize()
 
 	go func() {
@@ -3,6 +3,8 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"shared/events"
+	"shared/inbox"
 	natsClient "shared/messaging/nats"
 
 	notification "notification/internal/domain/entities/notification"
@@ -15,7 +17,6 @@ import (
 )
 
 const (
-	userNotificationCreationSubject       = "notifications.created"
 	refetchUserNotificationSubject        = "notifications.refetch"
 	notificationCreateDurableConsumerName = "notification-notification-create"
 	notificationStream                    = "notifications"
@@ -32,6 +33,7 @@ type UserNotificationRefetchEvent struct {
 
 type notificationMessagingHandlers struct {
 	natsClient   natsClient.NatsClient
+	inbox        inbox.Inbox
 	logger       zerolog.Logger
 	appService   applicationServices.NotificationApplicationService
 	socketServer *socketServer.SocketIOServer
@@ -39,11 +41,12 @@ type notificationMessagingHandlers struct {
 
 func NewNotificationMessagingHandlers(
 	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
 	appService applicationServices.NotificationApplicationService,
 	logger zerolog.Logger,
 	socketServer *socketServer.SocketIOServer,
 ) *notificationMessagingHandlers {
-	d := notificationMessagingHandlers{natsClient: natsClient, appService: appService, logger: logger, socketServer: socketServer}
+	d := notificationMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger, socketServer: socketServer}
 	return &d
 }
 
@@ -59,25 +62,12 @@ func (d *notificationMessagingHandlers) Init() {
 	d.RefetchNotificationListener()
 }
 
-type UserNotificationCreatedEvent struct {
-	NotificationTypeID string      `json:"notificationTypeID"`
-	UserID             string      `json:"userID"`
-	Data               interface{} `json:"data,omitempty"`
-}
-
 func (d *notificationMessagingHandlers) NotificationListener() {
 	d.logger.Info().Msg("NotificationListener initialized")
-	handler := func(n *nats.Msg) error {
-		messageData := n.Data
-		log.Info().Msg("NotificationListener -> Received a message: " + string(messageData))
+	handler := func(ctx context.Context, notificationEvent events.NotificationCreated, envelope events.Envelope) error {
+		log.Info().Msg("NotificationListener -> Received a message: " + string(envelope.Payload))
 
-		var notificationEvent UserNotificationCreatedEvent
-		err := json.Unmarshal(messageData, &notificationEvent)
-		if err != nil {
-			log.Error().Msg("NotificationListener -> Error in unmarshalling the message")
-			return err
-		}
-		err = d.appService.CreateUserNotification(context.Background(), notification.CreateUserNotificationParams{
+		err := d.appService.CreateUserNotification(ctx, notification.CreateUserNotificationParams{
 			UserID:             notificationEvent.UserID,
 			NotificationTypeID: notificationEvent.NotificationTypeID,
 			Data:               notificationEvent.Data,
@@ -88,7 +78,7 @@ func (d *notificationMessagingHandlers) NotificationListener() {
 		}
 		return nil
 	}
-	d.natsClient.SubscribeDurable(userNotificationCreationSubject, notificationStream, notificationCreateDurableConsumerName, handler)
+	events.Subscribe(d.natsClient, notificationStream, notificationCreateDurableConsumerName, inbox.Idempotent(d.inbox, notificationCreateDurableConsumerName, handler))
 }
 
 func (d *notificationMessagingHandlers) RefetchNotificationListener() {
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS processed_messages (
+    consumer varchar NOT NULL,
+    message_id varchar NOT NULL,
+    processed_at timestamptz NOT NULL,
+    CONSTRAINT processed_messages_pk PRIMARY KEY (consumer, message_id)
+);
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"shared/lifecycle"
+)
+
+func run() {
+
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	logger := zerolog.New(os.Stdout)
+	runner := lifecycle.NewRunner(logger)
+
+	productMessageHandlers, cartMessageHandlers, inventoryMessageHandlers, err := buildDependencies(runner, logger)
+	if err != nil {
+		log.Panic().Err(err).Msg("c.Invoke")
+	}
+
+	productMessageHandlers.Init()
+	cartMessageHandlers.Init()
+	inventoryMessageHandlers.Init()
+
+	err = runner.Run()
+	if err != nil {
+		log.Error().Err(err).Msg("app - Run - runner.Run")
+	}
+
+}
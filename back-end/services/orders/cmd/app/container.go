@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"orders/config"
+	"shared/authz"
+	"shared/health"
+	inboxStore "shared/inbox/pg"
+	"shared/lifecycle"
+	"shared/outbox"
+	outboxStore "shared/outbox/pg"
+	pgStorage "shared/storage/pg"
+
+	httpServ "orders/internal/transport/http"
+
+	cartInfraRepository "orders/internal/repositories/cart/pg"
+	orderInfraRepository "orders/internal/repositories/order/pg"
+	productInfraRepository "orders/internal/repositories/product/pg"
+	applicationServices "orders/internal/services"
+	nats "shared/messaging/nats"
+
+	messaging "orders/internal/transport/messaging"
+
+	controllers "orders/internal/transport/http/controllers"
+)
+
+const (
+	orderStream         = "orders"
+	orderStreamSubjects = "orders.*"
+)
+
+const maxConsumerLag = 1000
+
+func buildDependencies(runner *lifecycle.Runner, logger zerolog.Logger) (
+	messaging.ProductMessagingHandlers,
+	messaging.CartMessagingHandlers,
+	messaging.InventoryMessagingHandlers,
+	error,
+) {
+	config, err := config.NewConfig()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	checks := health.NewHealth(runner)
+	pg := pgStorage.NewClient(logger, pgStorage.Config{DSN: config.PgSDN})
+	runner.OnShutdown("postgres", lifecycle.Closer(pg))
+	checks.AddReadinessCheck("postgres", health.Ping(pg))
+	nats := nats.NewNatsClient()
+	runner.OnShutdown("nats", nats.Drain)
+	checks.AddReadinessCheck("nats", health.Connection(nats))
+	checks.AddDegradedCheck("consumer lag", health.ConsumerLag(nats, maxConsumerLag))
+	err = nats.CreateStream(orderStream, orderStreamSubjects)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	transactor := pgStorage.NewTransactor(pg)
+	inbox := inboxStore.NewInbox(pg, transactor)
+	inboxCleaner := inboxStore.NewCleaner(pg, logger)
+	runner.Go("inbox cleaner", func(ctx context.Context) error {
+		inboxCleaner.Run(ctx)
+		return nil
+	})
+	outboxRepo := outboxStore.NewStore(pg)
+	relay := outbox.NewRelay(outboxRepo, nats, logger)
+	runner.Go("outbox relay", func(ctx context.Context) error {
+		relay.Run(ctx)
+		return nil
+	})
+	orderRepo := orderInfraRepository.NewOrderRepository(pg, logger)
+	cartRepo := cartInfraRepository.NewCartRepository(pg, logger)
+	productRepo := productInfraRepository.NewProductRepository(pg, logger)
+
+	orderAppService := applicationServices.NewOrderApplicationService(orderRepo, cartRepo, logger, transactor, outboxRepo)
+	catalogAppService := applicationServices.NewCatalogApplicationService(productRepo, cartRepo, logger)
+
+	orderController := controllers.NewOrderController(orderAppService, logger, config)
+
+	productMessageHandlers := messaging.NewProductMessagingHandlers(nats, inbox, catalogAppService, logger)
+	cartMessageHandlers := messaging.NewCartMessagingHandlers(nats, inbox, catalogAppService, logger)
+	inventoryMessageHandlers := messaging.NewInventoryMessagingHandlers(nats, inbox, orderAppService, logger)
+	verifier := authz.NewJWKSVerifier(config.Identity.JWKSURL, config.Identity.Issuer, &http.Client{Timeout: 5 * time.Second})
+	httpServer := httpServ.NewHTTPServer(orderController, gin.New(), logger, config, checks, verifier)
+	runner.AddServer("http server", httpServer)
+	return productMessageHandlers, cartMessageHandlers, inventoryMessageHandlers, nil
+}
@@ -0,0 +1,198 @@
+package order
+
+import (
+	"time"
+
+	customErrors "shared/errors"
+	"shared/money"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCustomerID = customErrors.NewIncorrectInputError("orders.checkout.invalid_customer_id", "invalid customer ID")
+	ErrEmptyCart         = customErrors.NewIncorrectInputError("orders.checkout.empty_cart", "Cart is empty")
+	ErrPriceChanged      = customErrors.NewIncorrectInputError("orders.checkout.price_changed", "Prices in the cart changed, accept the current prices of the cart before checking out")
+	ErrMixedCurrencies   = customErrors.NewIncorrectInputError("orders.checkout.mixed_currencies", "Products in the cart are priced in different currencies")
+	ErrOrderNotFound     = customErrors.NewNotFoundError("orders.get.not_found", "Order not found")
+	ErrOrderNotPending   = customErrors.NewIncorrectInputError("orders.confirm.not_pending", "Order is not waiting for confirmation")
+	ErrOrderCancelled    = customErrors.NewIncorrectInputError("orders.cancel.already_cancelled", "Order is already cancelled")
+)
+
+// An order waits in StatusCreated until the catalog reserved its stock.
+const (
+	StatusCreated   = "created"
+	StatusConfirmed = "confirmed"
+	StatusCancelled = "cancelled"
+)
+
+// Item is a snapshot of a cart line at checkout time. Later changes to the
+// product do not affect orders that were already placed.
+type Item struct {
+	ProductID string
+	VariantID string
+	SKU       string
+	Name      string
+	Price     money.Money
+	Quantity  int
+}
+
+// CartItem is a cart line priced with the current product. AddedPrice is the
+// price the customer saw when adding it, zero for lines added before the cart
+// tracked it.
+type CartItem struct {
+	Item
+	AddedPrice money.Money
+}
+
+type Order struct {
+	id                 string
+	customerID         string
+	status             string
+	cancellationReason string
+	items              []Item
+	totalPrice         money.Money
+	createdAt          time.Time
+	updatedAt          time.Time
+}
+
+type CheckoutParams struct {
+	CustomerID string
+	Items      []CartItem
+}
+
+// Checkout places an order at the prices the customer saw in the cart. A
+// price that changed since the line was added must be reviewed in the cart
+// first, the order is never priced differently from what was agreed to.
+func Checkout(checkoutParams CheckoutParams) (Order, error) {
+	if checkoutParams.CustomerID == "" {
+		return Order{}, ErrInvalidCustomerID
+	}
+	items := make([]Item, 0, len(checkoutParams.Items))
+	for _, cartItem := range checkoutParams.Items {
+		if cartItem.Quantity <= 0 {
+			continue
+		}
+		if !cartItem.AddedPrice.IsZero() && !cartItem.AddedPrice.Equal(cartItem.Price) {
+			return Order{}, ErrPriceChanged
+		}
+		items = append(items, cartItem.Item)
+	}
+	if len(items) == 0 {
+		return Order{}, ErrEmptyCart
+	}
+	totalPrice, err := calculateTotalPrice(items)
+	if err != nil {
+		return Order{}, err
+	}
+	now := time.Now().UTC()
+	return Order{
+		id:         uuid.New().String(),
+		customerID: checkoutParams.CustomerID,
+		status:     StatusCreated,
+		items:      items,
+		totalPrice: totalPrice,
+		createdAt:  now,
+		updatedAt:  now,
+	}, nil
+}
+
+func NewOrderFromDatabase(
+	id string,
+	customerID string,
+	status string,
+	cancellationReason string,
+	items []Item,
+	totalPrice money.Money,
+	createdAt time.Time,
+	updatedAt time.Time,
+) Order {
+	return Order{
+		id:                 id,
+		customerID:         customerID,
+		status:             status,
+		cancellationReason: cancellationReason,
+		items:              items,
+		totalPrice:         totalPrice,
+		createdAt:          createdAt,
+		updatedAt:          updatedAt,
+	}
+}
+
+// Confirm is called once the stock of every item is reserved.
+func (o Order) Confirm() (Order, error) {
+	if o.status != StatusCreated {
+		return Order{}, ErrOrderNotPending
+	}
+	o.status = StatusConfirmed
+	o.updatedAt = time.Now().UTC()
+	return o, nil
+}
+
+// Cancel is allowed for confirmed orders as well, it compensates a
+// reservation that expired before the confirmation reached the catalog.
+func (o Order) Cancel(reason string) (Order, error) {
+	if o.status == StatusCancelled {
+		return Order{}, ErrOrderCancelled
+	}
+	o.status = StatusCancelled
+	o.cancellationReason = reason
+	o.updatedAt = time.Now().UTC()
+	return o, nil
+}
+
+// calculateTotalPrice does not convert currencies, an order is paid in a
+// single currency.
+func calculateTotalPrice(items []Item) (money.Money, error) {
+	totalPrice := money.Zero(items[0].Price.Currency())
+	for _, item := range items {
+		if item.Price.Currency() != totalPrice.Currency() {
+			return money.Money{}, ErrMixedCurrencies
+		}
+		linePrice, err := item.Price.Multiply(int64(item.Quantity))
+		if err != nil {
+			return money.Money{}, err
+		}
+		totalPrice, err = totalPrice.Add(linePrice)
+		if err != nil {
+			return money.Money{}, err
+		}
+	}
+	return totalPrice, nil
+}
+
+func (o Order) ID() string {
+	return o.id
+}
+
+func (o Order) CustomerID() string {
+	return o.customerID
+}
+
+func (o Order) Status() string {
+	return o.status
+}
+
+func (o Order) CancellationReason() string {
+	return o.cancellationReason
+}
+
+func (o Order) Items() []Item {
+	return o.items
+}
+
+func (o Order) TotalPrice() money.Money {
+	return o.totalPrice
+}
+
+func (o Order) CreatedAt() time.Time {
+	return o.createdAt
+}
+
+func (o Order) UpdatedAt() time.Time {
+	return o.updatedAt
+}
+
+func (o Order) IsZero() bool {
+	return o.id == ""
+}
@@ -0,0 +1,174 @@
+package order_test
+
+import (
+	orderEntity "orders/internal/domain/entities/order"
+	"shared/money"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gotest.tools/v3/assert"
+)
+
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.USD)
+}
+
+// cartItem is a line whose price did not change since it was added.
+func cartItem(item orderEntity.Item) orderEntity.CartItem {
+	return orderEntity.CartItem{Item: item, AddedPrice: item.Price}
+}
+
+func TestOrderEntity_Checkout(t *testing.T) {
+	t.Parallel()
+	type want struct {
+		CustomerID string
+		Items      []orderEntity.Item
+		TotalPrice money.Money
+	}
+	testCases := []struct {
+		name   string
+		args   orderEntity.CheckoutParams
+		want   want
+		expErr error
+	}{
+		{
+			name: "error_invalid_customer_id",
+			args: orderEntity.CheckoutParams{
+				CustomerID: "",
+				Items: []orderEntity.CartItem{
+					cartItem(orderEntity.Item{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 1}),
+				},
+			},
+			expErr: orderEntity.ErrInvalidCustomerID,
+		},
+		{
+			name: "error_empty_cart",
+			args: orderEntity.CheckoutParams{
+				CustomerID: "customer_id",
+			},
+			expErr: orderEntity.ErrEmptyCart,
+		},
+		{
+			name: "error_only_zero_quantities",
+			args: orderEntity.CheckoutParams{
+				CustomerID: "customer_id",
+				Items: []orderEntity.CartItem{
+					cartItem(orderEntity.Item{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 0}),
+				},
+			},
+			expErr: orderEntity.ErrEmptyCart,
+		},
+		{
+			name: "error_price_changed",
+			args: orderEntity.CheckoutParams{
+				CustomerID: "customer_id",
+				Items: []orderEntity.CartItem{
+					cartItem(orderEntity.Item{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 1}),
+					{
+						Item:       orderEntity.Item{ProductID: "product_id_two", Name: "Apple", Price: usd("0.2"), Quantity: 1},
+						AddedPrice: usd("0.1"),
+					},
+				},
+			},
+			expErr: orderEntity.ErrPriceChanged,
+		},
+		{
+			name: "order_with_items",
+			args: orderEntity.CheckoutParams{
+				CustomerID: "customer_id",
+				Items: []orderEntity.CartItem{
+					cartItem(orderEntity.Item{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 2}),
+					cartItem(orderEntity.Item{ProductID: "product_id_two", Name: "Apple", Price: usd("0.1"), Quantity: 3}),
+					cartItem(orderEntity.Item{ProductID: "product_id_three", Name: "Pear", Price: usd("1"), Quantity: 0}),
+				},
+			},
+			want: want{
+				CustomerID: "customer_id",
+				Items: []orderEntity.Item{
+					{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 2},
+					{ProductID: "product_id_two", Name: "Apple", Price: usd("0.1"), Quantity: 3},
+				},
+				TotalPrice: usd("5.4"),
+			},
+		},
+		{
+			// removed lines and lines added before the price was tracked
+			name: "order_without_added_prices",
+			args: orderEntity.CheckoutParams{
+				CustomerID: "customer_id",
+				Items: []orderEntity.CartItem{
+					{Item: orderEntity.Item{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 1}},
+					{
+						Item:       orderEntity.Item{ProductID: "product_id_two", Name: "Apple", Price: usd("0.2"), Quantity: 0},
+						AddedPrice: usd("0.1"),
+					},
+				},
+			},
+			want: want{
+				CustomerID: "customer_id",
+				Items: []orderEntity.Item{
+					{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 1},
+				},
+				TotalPrice: usd("2.55"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			order, err := orderEntity.Checkout(tc.args)
+
+			require.Equal(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+			assert.Assert(t, order.ID() != "")
+			assert.Equal(t, orderEntity.StatusCreated, order.Status())
+			assert.Equal(t, tc.want.CustomerID, order.CustomerID())
+			assert.DeepEqual(t, tc.want.Items, order.Items())
+			assert.Equal(t, tc.want.TotalPrice, order.TotalPrice())
+		})
+	}
+}
+
+func TestOrderEntity_Checkout_MixedCurrencies(t *testing.T) {
+	t.Parallel()
+	_, err := orderEntity.Checkout(orderEntity.CheckoutParams{
+		CustomerID: "customer_id",
+		Items: []orderEntity.CartItem{
+			cartItem(orderEntity.Item{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 1}),
+			cartItem(orderEntity.Item{ProductID: "product_id_two", Name: "Apple", Price: money.MustParse("1", money.EUR), Quantity: 1}),
+		},
+	})
+	require.Equal(t, orderEntity.ErrMixedCurrencies, err)
+}
+
+func TestOrderEntity_Transitions(t *testing.T) {
+	t.Parallel()
+	order, err := orderEntity.Checkout(orderEntity.CheckoutParams{
+		CustomerID: "customer_id",
+		Items: []orderEntity.CartItem{
+			cartItem(orderEntity.Item{ProductID: "product_id_one", Name: "Banana", Price: usd("2.55"), Quantity: 1}),
+		},
+	})
+	require.NoError(t, err)
+
+	confirmed, err := order.Confirm()
+	require.NoError(t, err)
+	assert.Equal(t, orderEntity.StatusConfirmed, confirmed.Status())
+
+	_, err = confirmed.Confirm()
+	require.Equal(t, orderEntity.ErrOrderNotPending, err)
+
+	cancelled, err := confirmed.Cancel("reservation_expired")
+	require.NoError(t, err)
+	assert.Equal(t, orderEntity.StatusCancelled, cancelled.Status())
+	assert.Equal(t, "reservation_expired", cancelled.CancellationReason())
+
+	_, err = cancelled.Cancel("order_cancelled")
+	require.Equal(t, orderEntity.ErrOrderCancelled, err)
+	_, err = cancelled.Confirm()
+	require.Equal(t, orderEntity.ErrOrderNotPending, err)
+}
@@ -0,0 +1,50 @@
+package order
+
+import (
+	"shared/money"
+	"time"
+)
+
+type OrderReadModelItem struct {
+	ProductID string      `json:"productId"`
+	VariantID string      `json:"variantId,omitempty"`
+	SKU       string      `json:"sku,omitempty"`
+	Name      string      `json:"name"`
+	Price     money.Money `json:"price"`
+	Quantity  int         `json:"quantity"`
+}
+
+type OrderReadModel struct {
+	Type               string               `json:"type"`
+	ID                 string               `json:"id"`
+	CustomerID         string               `json:"customerId"`
+	Status             string               `json:"status"`
+	CancellationReason string               `json:"cancellationReason,omitempty"`
+	Items              []OrderReadModelItem `json:"items"`
+	TotalPrice         money.Money          `json:"totalPrice"`
+	CreatedAt          time.Time            `json:"createdAt"`
+}
+
+func NewOrderReadModel(order Order) OrderReadModel {
+	items := make([]OrderReadModelItem, 0, len(order.Items()))
+	for _, item := range order.Items() {
+		items = append(items, OrderReadModelItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			SKU:       item.SKU,
+			Name:      item.Name,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+		})
+	}
+	return OrderReadModel{
+		Type:               "order",
+		ID:                 order.ID(),
+		CustomerID:         order.CustomerID(),
+		Status:             order.Status(),
+		CancellationReason: order.CancellationReason(),
+		Items:              items,
+		TotalPrice:         order.TotalPrice(),
+		CreatedAt:          order.CreatedAt(),
+	}
+}
@@ -0,0 +1,95 @@
+package product
+
+import (
+	"shared/money"
+	"time"
+)
+
+// Product is the part of the catalog product that orders need to price a
+// checkout. It is kept up to date from the products stream.
+type Product struct {
+	id        string
+	name      string
+	price     money.Money
+	updatedAt time.Time
+}
+
+type CreateProductParams struct {
+	ID        string
+	Name      string
+	Price     money.Money
+	UpdatedAt time.Time
+}
+
+func NewProduct(createProductParams CreateProductParams) Product {
+	return Product{
+		id:        createProductParams.ID,
+		name:      createProductParams.Name,
+		price:     createProductParams.Price,
+		updatedAt: createProductParams.UpdatedAt,
+	}
+}
+
+func (p Product) ID() string {
+	return p.id
+}
+
+func (p Product) Name() string {
+	return p.name
+}
+
+func (p Product) Price() money.Money {
+	return p.price
+}
+
+func (p Product) UpdatedAt() time.Time {
+	return p.updatedAt
+}
+
+// Variant is the part of a catalog product variant that orders need to price
+// a checkout. Its name is the name of its product.
+type Variant struct {
+	id        string
+	productID string
+	sku       string
+	price     money.Money
+	updatedAt time.Time
+}
+
+type CreateVariantParams struct {
+	ID        string
+	ProductID string
+	SKU       string
+	Price     money.Money
+	UpdatedAt time.Time
+}
+
+func NewVariant(createVariantParams CreateVariantParams) Variant {
+	return Variant{
+		id:        createVariantParams.ID,
+		productID: createVariantParams.ProductID,
+		sku:       createVariantParams.SKU,
+		price:     createVariantParams.Price,
+		updatedAt: createVariantParams.UpdatedAt,
+	}
+}
+
+func (v Variant) ID() string {
+	return v.id
+}
+
+func (v Variant) ProductID() string {
+	return v.productID
+}
+
+func (v Variant) SKU() string {
+	return v.sku
+}
+
+func (v Variant) Price() money.Money {
+	return v.price
+}
+
+func (v Variant) UpdatedAt() time.Time {
+	return v.updatedAt
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	orderEntity "orders/internal/domain/entities/order"
+	"shared/money"
+)
+
+// CartRepository stores the local copy of the customers' carts, built from
+// the carts stream.
+type CartRepository interface {
+	// SetProductQuantity keeps addedPrice, the price of the line when it was
+	// added to the cart, it is zero when the cart did not report it.
+	SetProductQuantity(ctx context.Context, customerID string, productID string, variantID string, quantity int, addedPrice money.Money) error
+	RemoveProduct(ctx context.Context, customerID string, productID string, variantID string) error
+	// GetCheckoutItems returns the cart lines priced with the current products
+	// and variants. Lines whose product or variant no longer exists are left out.
+	GetCheckoutItems(ctx context.Context, customerID string) ([]orderEntity.CartItem, error)
+	// RemoveItems removes the lines of items from the cart, lines that were
+	// not ordered stay.
+	RemoveItems(ctx context.Context, customerID string, items []orderEntity.Item) error
+}
@@ -0,0 +1,142 @@
+package pgrepositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	orderEntity "orders/internal/domain/entities/order"
+	repository "orders/internal/repositories/cart"
+	"shared/money"
+	pgStorage "shared/storage/pg"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+)
+
+type CartProductModel struct {
+	bun.BaseModel `bun:"table:cart_products,alias:cart_products"`
+
+	CustomerID    string    `bun:"customer_id,pk"`
+	ProductID     string    `bun:"product_id,pk"`
+	VariantID     string    `bun:"variant_id,pk"`
+	Quantity      int       `bun:"quantity"`
+	AddedAmount   int64     `bun:"added_price_amount,nullzero"`
+	AddedCurrency string    `bun:"added_price_currency,nullzero"`
+	UpdatedAt     time.Time `bun:"updated_at"`
+}
+
+type checkoutItemModel struct {
+	ProductID     string `bun:"product_id"`
+	VariantID     string `bun:"variant_id"`
+	SKU           string `bun:"sku"`
+	Name          string `bun:"name"`
+	PriceAmount   int64  `bun:"price_amount"`
+	PriceCurrency string `bun:"price_currency"`
+	Quantity      int    `bun:"quantity"`
+	AddedAmount   int64  `bun:"added_price_amount"`
+	AddedCurrency string `bun:"added_price_currency"`
+}
+
+var _ repository.CartRepository = (*cartRepository)(nil)
+
+type cartRepository struct {
+	db     *bun.DB
+	logger zerolog.Logger
+}
+
+func NewCartRepository(sql *bun.DB, logger zerolog.Logger) *cartRepository {
+	return &cartRepository{db: sql, logger: logger}
+}
+
+func (r *cartRepository) SetProductQuantity(ctx context.Context, customerID string, productID string, variantID string, quantity int, addedPrice money.Money) error {
+	cartProduct := CartProductModel{
+		CustomerID:    customerID,
+		ProductID:     productID,
+		VariantID:     variantID,
+		Quantity:      quantity,
+		AddedAmount:   addedPrice.MinorUnits(),
+		AddedCurrency: string(addedPrice.Currency()),
+		UpdatedAt:     time.Now().UTC(),
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().
+		Model(&cartProduct).
+		On("CONFLICT (customer_id, product_id, variant_id) DO UPDATE").
+		Set("quantity = EXCLUDED.quantity").
+		Set("added_price_amount = EXCLUDED.added_price_amount").
+		Set("added_price_currency = EXCLUDED.added_price_currency").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("cartRepository SetProductQuantity -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepository) RemoveProduct(ctx context.Context, customerID string, productID string, variantID string) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().
+		Model((*CartProductModel)(nil)).
+		Where("customer_id = ? AND product_id = ? AND variant_id = ?", customerID, productID, variantID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("cartRepository RemoveProduct -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepository) GetCheckoutItems(ctx context.Context, customerID string) ([]orderEntity.CartItem, error) {
+	var itemModels []checkoutItemModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model((*CartProductModel)(nil)).
+		ColumnExpr("cart_products.product_id, cart_products.variant_id, cart_products.quantity, p.name").
+		ColumnExpr("COALESCE(cart_products.added_price_amount, 0) AS added_price_amount").
+		ColumnExpr("COALESCE(cart_products.added_price_currency, '') AS added_price_currency").
+		ColumnExpr("COALESCE(v.sku, '') AS sku").
+		ColumnExpr("COALESCE(v.price_amount, p.price_amount) AS price_amount").
+		ColumnExpr("COALESCE(v.price_currency, p.price_currency) AS price_currency").
+		Join("JOIN products AS p ON p.id = cart_products.product_id").
+		Join("LEFT JOIN product_variants AS v ON v.id::text = cart_products.variant_id").
+		Where("cart_products.customer_id = ?", customerID).
+		Where("cart_products.variant_id = '' OR v.id IS NOT NULL").
+		OrderExpr("cart_products.product_id, cart_products.variant_id").
+		For("UPDATE OF cart_products").
+		Scan(ctx, &itemModels)
+	if err != nil {
+		return nil, fmt.Errorf("cartRepository GetCheckoutItems -> r.db.NewSelect(): %w", err)
+	}
+
+	items := make([]orderEntity.CartItem, 0, len(itemModels))
+	for _, itemModel := range itemModels {
+		items = append(items, orderEntity.CartItem{
+			Item: orderEntity.Item{
+				ProductID: itemModel.ProductID,
+				VariantID: itemModel.VariantID,
+				SKU:       itemModel.SKU,
+				Name:      itemModel.Name,
+				Price:     money.New(itemModel.PriceAmount, money.Currency(itemModel.PriceCurrency)),
+				Quantity:  itemModel.Quantity,
+			},
+			AddedPrice: money.New(itemModel.AddedAmount, money.Currency(itemModel.AddedCurrency)),
+		})
+	}
+	return items, nil
+}
+
+func (r *cartRepository) RemoveItems(ctx context.Context, customerID string, items []orderEntity.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	lines := make([][]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, []string{item.ProductID, item.VariantID})
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().
+		Model((*CartProductModel)(nil)).
+		Where("customer_id = ?", customerID).
+		Where("(product_id, variant_id) IN (?)", bun.In(lines)).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("cartRepository RemoveItems -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
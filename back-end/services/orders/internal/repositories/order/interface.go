@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	orderEntity "orders/internal/domain/entities/order"
+)
+
+type OrderRepository interface {
+	Create(ctx context.Context, order orderEntity.Order) error
+	GetByID(ctx context.Context, id string) (orderEntity.Order, error)
+	Update(ctx context.Context, id string, updateFunc func(order orderEntity.Order) (orderEntity.Order, error)) error
+	ListByCustomerID(ctx context.Context, customerID string) ([]orderEntity.Order, error)
+}
@@ -0,0 +1,199 @@
+package pgrepositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	orderEntity "orders/internal/domain/entities/order"
+	repository "orders/internal/repositories/order"
+	"shared/money"
+	pgStorage "shared/storage/pg"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+)
+
+type OrderModel struct {
+	bun.BaseModel `bun:"table:orders,alias:o"`
+
+	ID                 string           `bun:"id,pk"`
+	CustomerID         string           `bun:"customer_id"`
+	Status             string           `bun:"status"`
+	CancellationReason string           `bun:"cancellation_reason,nullzero"`
+	TotalAmount        int64            `bun:"total_price_amount"`
+	TotalCurrency      string           `bun:"total_price_currency"`
+	CreatedAt          time.Time        `bun:"created_at"`
+	UpdatedAt          time.Time        `bun:"updated_at"`
+	Items              []OrderItemModel `bun:"rel:has-many,join:id=order_id"`
+}
+
+type OrderItemModel struct {
+	bun.BaseModel `bun:"table:order_items,alias:oi"`
+
+	OrderID       string `bun:"order_id,pk"`
+	ProductID     string `bun:"product_id,pk"`
+	VariantID     string `bun:"variant_id,pk"`
+	SKU           string `bun:"sku"`
+	Name          string `bun:"name"`
+	PriceAmount   int64  `bun:"price_amount"`
+	PriceCurrency string `bun:"price_currency"`
+	Quantity      int    `bun:"quantity"`
+}
+
+func toDB(order orderEntity.Order) OrderModel {
+	items := make([]OrderItemModel, 0, len(order.Items()))
+	for _, item := range order.Items() {
+		items = append(items, OrderItemModel{
+			OrderID:       order.ID(),
+			ProductID:     item.ProductID,
+			VariantID:     item.VariantID,
+			SKU:           item.SKU,
+			Name:          item.Name,
+			PriceAmount:   item.Price.MinorUnits(),
+			PriceCurrency: string(item.Price.Currency()),
+			Quantity:      item.Quantity,
+		})
+	}
+	return OrderModel{
+		ID:                 order.ID(),
+		CustomerID:         order.CustomerID(),
+		Status:             order.Status(),
+		CancellationReason: order.CancellationReason(),
+		TotalAmount:        order.TotalPrice().MinorUnits(),
+		TotalCurrency:      string(order.TotalPrice().Currency()),
+		CreatedAt:          order.CreatedAt(),
+		UpdatedAt:          order.UpdatedAt(),
+		Items:              items,
+	}
+}
+
+func (m OrderModel) toEntity() orderEntity.Order {
+	items := make([]orderEntity.Item, 0, len(m.Items))
+	for _, item := range m.Items {
+		items = append(items, orderEntity.Item{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			SKU:       item.SKU,
+			Name:      item.Name,
+			Price:     money.New(item.PriceAmount, money.Currency(item.PriceCurrency)),
+			Quantity:  item.Quantity,
+		})
+	}
+	return orderEntity.NewOrderFromDatabase(
+		m.ID,
+		m.CustomerID,
+		m.Status,
+		m.CancellationReason,
+		items,
+		money.New(m.TotalAmount, money.Currency(m.TotalCurrency)),
+		m.CreatedAt,
+		m.UpdatedAt,
+	)
+}
+
+var _ repository.OrderRepository = (*orderRepository)(nil)
+
+type orderRepository struct {
+	db         *bun.DB
+	logger     zerolog.Logger
+	transactor pgStorage.Transactor
+}
+
+func NewOrderRepository(sql *bun.DB, logger zerolog.Logger) *orderRepository {
+	return &orderRepository{db: sql, logger: logger, transactor: pgStorage.NewTransactor(sql)}
+}
+
+func (r *orderRepository) Create(ctx context.Context, order orderEntity.Order) error {
+	orderModel := toDB(order)
+	return r.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		db := pgStorage.Conn(ctx, r.db)
+		_, err := db.NewInsert().Model(&orderModel).Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("orderRepository -> Create -> r.db.NewInsert(order): %w", err)
+		}
+		_, err = db.NewInsert().Model(&orderModel.Items).Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("orderRepository -> Create -> r.db.NewInsert(items): %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *orderRepository) GetByID(ctx context.Context, id string) (orderEntity.Order, error) {
+	var orderModel OrderModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&orderModel).
+		Relation("Items").
+		Where("o.id = ?", id).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return orderEntity.Order{}, nil
+	}
+	if err != nil {
+		return orderEntity.Order{}, fmt.Errorf("orderRepository -> GetByID -> r.db.NewSelect(): %w", err)
+	}
+	return orderModel.toEntity(), nil
+}
+
+// Update locks the order, applies updateFunc and stores the new status. The
+// order passed to updateFunc is zero when it does not exist.
+func (r *orderRepository) Update(
+	ctx context.Context,
+	id string,
+	updateFunc func(order orderEntity.Order) (orderEntity.Order, error),
+) error {
+	return r.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		db := pgStorage.Conn(ctx, r.db)
+		var orderModel OrderModel
+		err := db.NewSelect().
+			Model(&orderModel).
+			Relation("Items").
+			Where("o.id = ?", id).
+			For("UPDATE OF o").
+			Scan(ctx)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("orderRepository -> Update -> r.db.NewSelect(): %w", err)
+		}
+
+		var order orderEntity.Order
+		if err == nil {
+			order = orderModel.toEntity()
+		}
+		order, err = updateFunc(order)
+		if err != nil {
+			return fmt.Errorf("orderRepository -> Update -> updateFunc(): %w", err)
+		}
+
+		orderModel = toDB(order)
+		_, err = db.NewUpdate().
+			Model(&orderModel).
+			Column("status", "cancellation_reason", "updated_at").
+			WherePK().
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("orderRepository -> Update -> r.db.NewUpdate(): %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *orderRepository) ListByCustomerID(ctx context.Context, customerID string) ([]orderEntity.Order, error) {
+	var orderModels []OrderModel
+	err := pgStorage.Conn(ctx, r.db).NewSelect().
+		Model(&orderModels).
+		Relation("Items").
+		Where("o.customer_id = ?", customerID).
+		Order("o.created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository -> ListByCustomerID -> r.db.NewSelect(): %w", err)
+	}
+	orders := make([]orderEntity.Order, 0, len(orderModels))
+	for _, orderModel := range orderModels {
+		orders = append(orders, orderModel.toEntity())
+	}
+	return orders, nil
+}
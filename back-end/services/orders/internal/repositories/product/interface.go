@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	productEntity "orders/internal/domain/entities/product"
+)
+
+type ProductRepository interface {
+	SaveProduct(ctx context.Context, product productEntity.Product) error
+	DeleteProductByID(ctx context.Context, id string) error
+	SaveVariant(ctx context.Context, variant productEntity.Variant) error
+	DeleteVariantByID(ctx context.Context, id string) error
+}
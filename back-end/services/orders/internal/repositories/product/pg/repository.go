@@ -0,0 +1,113 @@
+package pgrepositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	productEntity "orders/internal/domain/entities/product"
+	repository "orders/internal/repositories/product"
+	pgStorage "shared/storage/pg"
+
+	"github.com/rs/zerolog"
+	"github.com/uptrace/bun"
+)
+
+type ProductModel struct {
+	bun.BaseModel `bun:"table:products"`
+
+	ID            string    `bun:"id,pk"`
+	Name          string    `bun:"name"`
+	PriceAmount   int64     `bun:"price_amount"`
+	PriceCurrency string    `bun:"price_currency"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero"`
+}
+
+type VariantModel struct {
+	bun.BaseModel `bun:"table:product_variants"`
+
+	ID            string    `bun:"id,pk"`
+	ProductID     string    `bun:"product_id"`
+	SKU           string    `bun:"sku"`
+	PriceAmount   int64     `bun:"price_amount"`
+	PriceCurrency string    `bun:"price_currency"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero"`
+}
+
+var _ repository.ProductRepository = (*productPGRepository)(nil)
+
+type productPGRepository struct {
+	db     *bun.DB
+	logger zerolog.Logger
+}
+
+func NewProductRepository(sql *bun.DB, logger zerolog.Logger) *productPGRepository {
+	return &productPGRepository{sql, logger}
+}
+
+// SaveProduct inserts the product or overwrites the stored copy.
+func (r *productPGRepository) SaveProduct(ctx context.Context, product productEntity.Product) error {
+	productModel := ProductModel{
+		ID:            product.ID(),
+		Name:          product.Name(),
+		PriceAmount:   product.Price().MinorUnits(),
+		PriceCurrency: string(product.Price().Currency()),
+		UpdatedAt:     product.UpdatedAt(),
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().
+		Model(&productModel).
+		On("CONFLICT (id) DO UPDATE").
+		Set("name = EXCLUDED.name").
+		Set("price_amount = EXCLUDED.price_amount").
+		Set("price_currency = EXCLUDED.price_currency").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository SaveProduct -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *productPGRepository) DeleteProductByID(ctx context.Context, productID string) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().Model((*ProductModel)(nil)).Where("id = ?", productID).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository DeleteProductByID -> r.db.NewDelete(): %w", err)
+	}
+	_, err = pgStorage.Conn(ctx, r.db).NewDelete().Model((*VariantModel)(nil)).Where("product_id = ?", productID).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository DeleteProductByID -> variants r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
+
+// SaveVariant inserts the variant or overwrites the stored copy.
+func (r *productPGRepository) SaveVariant(ctx context.Context, variant productEntity.Variant) error {
+	variantModel := VariantModel{
+		ID:            variant.ID(),
+		ProductID:     variant.ProductID(),
+		SKU:           variant.SKU(),
+		PriceAmount:   variant.Price().MinorUnits(),
+		PriceCurrency: string(variant.Price().Currency()),
+		UpdatedAt:     variant.UpdatedAt(),
+	}
+	_, err := pgStorage.Conn(ctx, r.db).NewInsert().
+		Model(&variantModel).
+		On("CONFLICT (id) DO UPDATE").
+		Set("sku = EXCLUDED.sku").
+		Set("price_amount = EXCLUDED.price_amount").
+		Set("price_currency = EXCLUDED.price_currency").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository SaveVariant -> r.db.NewInsert(): %w", err)
+	}
+	return nil
+}
+
+func (r *productPGRepository) DeleteVariantByID(ctx context.Context, variantID string) error {
+	_, err := pgStorage.Conn(ctx, r.db).NewDelete().Model((*VariantModel)(nil)).Where("id = ?", variantID).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("productPGRepository DeleteVariantByID -> r.db.NewDelete(): %w", err)
+	}
+	return nil
+}
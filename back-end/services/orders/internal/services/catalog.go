@@ -0,0 +1,95 @@
+package applicationservices
+
+import (
+	"context"
+	"fmt"
+
+	productEntity "orders/internal/domain/entities/product"
+	cartRepo "orders/internal/repositories/cart"
+	productRepo "orders/internal/repositories/product"
+	"shared/money"
+
+	"github.com/rs/zerolog"
+)
+
+var _ CatalogApplicationService = (*catalogApplicationService)(nil)
+
+// CatalogApplicationService keeps the local copies of products and carts
+// that a checkout reads from.
+type CatalogApplicationService interface {
+	SaveProduct(ctx context.Context, createProductParams productEntity.CreateProductParams) error
+	DeleteProduct(ctx context.Context, productID string) error
+	SaveVariant(ctx context.Context, createVariantParams productEntity.CreateVariantParams) error
+	DeleteVariant(ctx context.Context, variantID string) error
+	SetCartProductQuantity(ctx context.Context, customerID string, productID string, variantID string, quantity int, addedPrice money.Money) error
+	RemoveCartProduct(ctx context.Context, customerID string, productID string, variantID string) error
+}
+
+type catalogApplicationService struct {
+	productRepository productRepo.ProductRepository
+	cartRepository    cartRepo.CartRepository
+	logger            zerolog.Logger
+}
+
+func NewCatalogApplicationService(
+	productRepository productRepo.ProductRepository,
+	cartRepository cartRepo.CartRepository,
+	logger zerolog.Logger,
+) catalogApplicationService {
+	return catalogApplicationService{
+		productRepository: productRepository,
+		cartRepository:    cartRepository,
+		logger:            logger,
+	}
+}
+
+func (c catalogApplicationService) SaveProduct(ctx context.Context, createProductParams productEntity.CreateProductParams) error {
+	err := c.productRepository.SaveProduct(ctx, productEntity.NewProduct(createProductParams))
+	if err != nil {
+		return fmt.Errorf("catalogApplicationService SaveProduct -> c.productRepository.SaveProduct: %w", err)
+	}
+	return nil
+}
+
+func (c catalogApplicationService) DeleteProduct(ctx context.Context, productID string) error {
+	err := c.productRepository.DeleteProductByID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("catalogApplicationService DeleteProduct -> c.productRepository.DeleteProductByID: %w", err)
+	}
+	return nil
+}
+
+func (c catalogApplicationService) SaveVariant(ctx context.Context, createVariantParams productEntity.CreateVariantParams) error {
+	err := c.productRepository.SaveVariant(ctx, productEntity.NewVariant(createVariantParams))
+	if err != nil {
+		return fmt.Errorf("catalogApplicationService SaveVariant -> c.productRepository.SaveVariant: %w", err)
+	}
+	return nil
+}
+
+func (c catalogApplicationService) DeleteVariant(ctx context.Context, variantID string) error {
+	err := c.productRepository.DeleteVariantByID(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("catalogApplicationService DeleteVariant -> c.productRepository.DeleteVariantByID: %w", err)
+	}
+	return nil
+}
+
+func (c catalogApplicationService) SetCartProductQuantity(ctx context.Context, customerID string, productID string, variantID string, quantity int, addedPrice money.Money) error {
+	if quantity <= 0 {
+		return c.RemoveCartProduct(ctx, customerID, productID, variantID)
+	}
+	err := c.cartRepository.SetProductQuantity(ctx, customerID, productID, variantID, quantity, addedPrice)
+	if err != nil {
+		return fmt.Errorf("catalogApplicationService SetCartProductQuantity -> c.cartRepository.SetProductQuantity: %w", err)
+	}
+	return nil
+}
+
+func (c catalogApplicationService) RemoveCartProduct(ctx context.Context, customerID string, productID string, variantID string) error {
+	err := c.cartRepository.RemoveProduct(ctx, customerID, productID, variantID)
+	if err != nil {
+		return fmt.Errorf("catalogApplicationService RemoveCartProduct -> c.cartRepository.RemoveProduct: %w", err)
+	}
+	return nil
+}
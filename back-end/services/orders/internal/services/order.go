@@ -0,0 +1,216 @@
+package applicationservices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	orderEntity "orders/internal/domain/entities/order"
+	cartRepo "orders/internal/repositories/cart"
+	orderRepo "orders/internal/repositories/order"
+	"shared/authz"
+	"shared/events"
+	"shared/outbox"
+	pgStorage "shared/storage/pg"
+
+	"github.com/rs/zerolog"
+)
+
+const producerName = "orders"
+
+var _ OrderApplicationService = (*orderApplicationService)(nil)
+
+type OrderApplicationService interface {
+	Checkout(ctx context.Context, customerID string) (orderEntity.OrderReadModel, error)
+	GetOrder(ctx context.Context, identity authz.Identity, orderID string) (orderEntity.OrderReadModel, error)
+	ListOrders(ctx context.Context, customerID string) ([]orderEntity.OrderReadModel, error)
+	ConfirmOrder(ctx context.Context, orderID string) error
+	CancelOrder(ctx context.Context, orderID string, reason string) error
+}
+
+type orderApplicationService struct {
+	orderRepository orderRepo.OrderRepository
+	cartRepository  cartRepo.CartRepository
+	logger          zerolog.Logger
+	transactor      pgStorage.Transactor
+	outbox          outbox.Writer
+}
+
+func NewOrderApplicationService(
+	orderRepository orderRepo.OrderRepository,
+	cartRepository cartRepo.CartRepository,
+	logger zerolog.Logger,
+	transactor pgStorage.Transactor,
+	outboxWriter outbox.Writer,
+) orderApplicationService {
+	return orderApplicationService{
+		orderRepository: orderRepository,
+		cartRepository:  cartRepository,
+		logger:          logger,
+		transactor:      transactor,
+		outbox:          outboxWriter,
+	}
+}
+
+// Checkout turns the customer's cart into an order. The ordered lines are
+// removed from the local copy of the cart in the same transaction, lines of
+// deleted products or variants are not ordered and stay, as they do in the
+// cart service, which removes the ordered lines when it receives orders.created.
+func (o orderApplicationService) Checkout(ctx context.Context, customerID string) (orderEntity.OrderReadModel, error) {
+	var order orderEntity.Order
+	err := o.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		items, err := o.cartRepository.GetCheckoutItems(ctx, customerID)
+		if err != nil {
+			return fmt.Errorf("orderApplicationService Checkout -> o.cartRepository.GetCheckoutItems: %w", err)
+		}
+		order, err = orderEntity.Checkout(orderEntity.CheckoutParams{
+			CustomerID: customerID,
+			Items:      items,
+		})
+		if err != nil {
+			return fmt.Errorf("orderApplicationService Checkout -> orderEntity.Checkout: %w", err)
+		}
+		err = o.orderRepository.Create(ctx, order)
+		if err != nil {
+			return fmt.Errorf("orderApplicationService Checkout -> o.orderRepository.Create: %w", err)
+		}
+		err = o.cartRepository.RemoveItems(ctx, customerID, order.Items())
+		if err != nil {
+			return fmt.Errorf("orderApplicationService Checkout -> o.cartRepository.RemoveItems: %w", err)
+		}
+
+		message, err := events.NewOutboxMessage(ctx, producerName, orderCreatedEvent(order))
+		if err != nil {
+			return fmt.Errorf("orderApplicationService Checkout -> events.NewOutboxMessage: %w", err)
+		}
+		err = o.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("orderApplicationService Checkout -> o.outbox.Save: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return orderEntity.OrderReadModel{}, err
+	}
+	return orderEntity.NewOrderReadModel(order), nil
+}
+
+// GetOrder returns an order of the customer, support staff can read any order.
+func (o orderApplicationService) GetOrder(ctx context.Context, identity authz.Identity, orderID string) (orderEntity.OrderReadModel, error) {
+	order, err := o.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		return orderEntity.OrderReadModel{}, fmt.Errorf("orderApplicationService GetOrder -> o.orderRepository.GetByID: %w", err)
+	}
+	// other customers' orders are reported as missing rather than forbidden
+	if order.IsZero() || order.CustomerID() != identity.UserID && !identity.Can(authz.PermissionReadAnyOrder) {
+		return orderEntity.OrderReadModel{}, orderEntity.ErrOrderNotFound
+	}
+	return orderEntity.NewOrderReadModel(order), nil
+}
+
+func (o orderApplicationService) ListOrders(ctx context.Context, customerID string) ([]orderEntity.OrderReadModel, error) {
+	orders, err := o.orderRepository.ListByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("orderApplicationService ListOrders -> o.orderRepository.ListByCustomerID: %w", err)
+	}
+	readModels := make([]orderEntity.OrderReadModel, 0, len(orders))
+	for _, order := range orders {
+		readModels = append(readModels, orderEntity.NewOrderReadModel(order))
+	}
+	return readModels, nil
+}
+
+// ConfirmOrder is the outcome of a successful stock reservation.
+func (o orderApplicationService) ConfirmOrder(ctx context.Context, orderID string) error {
+	var order orderEntity.Order
+	err := o.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := o.orderRepository.Update(ctx, orderID, func(current orderEntity.Order) (orderEntity.Order, error) {
+			if current.IsZero() {
+				return orderEntity.Order{}, orderEntity.ErrOrderNotFound
+			}
+			var err error
+			order, err = current.Confirm()
+			return order, err
+		})
+		if err != nil {
+			return fmt.Errorf("orderApplicationService ConfirmOrder -> o.orderRepository.Update: %w", err)
+		}
+
+		message, err := events.NewOutboxMessage(ctx, producerName, events.OrderConfirmed{
+			ID:         order.ID(),
+			CustomerID: order.CustomerID(),
+		})
+		if err != nil {
+			return fmt.Errorf("orderApplicationService ConfirmOrder -> events.NewOutboxMessage: %w", err)
+		}
+		err = o.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("orderApplicationService ConfirmOrder -> o.outbox.Save: %w", err)
+		}
+		return nil
+	})
+	// a reservation that arrives after the order was cancelled is released by
+	// the catalog once it sees orders.cancelled or the reservation expires
+	if errors.Is(err, orderEntity.ErrOrderNotPending) {
+		o.logger.Warn().Str("orderID", orderID).Msg("orderApplicationService ConfirmOrder -> order is not pending")
+		return nil
+	}
+	return err
+}
+
+// CancelOrder is the outcome of a failed or released stock reservation.
+func (o orderApplicationService) CancelOrder(ctx context.Context, orderID string, reason string) error {
+	var order orderEntity.Order
+	err := o.transactor.RunInTransaction(ctx, func(ctx context.Context) error {
+		err := o.orderRepository.Update(ctx, orderID, func(current orderEntity.Order) (orderEntity.Order, error) {
+			if current.IsZero() {
+				return orderEntity.Order{}, orderEntity.ErrOrderNotFound
+			}
+			var err error
+			order, err = current.Cancel(reason)
+			return order, err
+		})
+		if err != nil {
+			return fmt.Errorf("orderApplicationService CancelOrder -> o.orderRepository.Update: %w", err)
+		}
+
+		message, err := events.NewOutboxMessage(ctx, producerName, events.OrderCancelled{
+			ID:         order.ID(),
+			CustomerID: order.CustomerID(),
+			Reason:     reason,
+		})
+		if err != nil {
+			return fmt.Errorf("orderApplicationService CancelOrder -> events.NewOutboxMessage: %w", err)
+		}
+		err = o.outbox.Save(ctx, message)
+		if err != nil {
+			return fmt.Errorf("orderApplicationService CancelOrder -> o.outbox.Save: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, orderEntity.ErrOrderCancelled) {
+		return nil
+	}
+	return err
+}
+
+func orderCreatedEvent(order orderEntity.Order) events.OrderCreated {
+	items := make([]events.OrderItem, 0, len(order.Items()))
+	for _, item := range order.Items() {
+		items = append(items, events.OrderItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			SKU:       item.SKU,
+			Name:      item.Name,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+		})
+	}
+	return events.OrderCreated{
+		ID:         order.ID(),
+		CustomerID: order.CustomerID(),
+		Items:      items,
+		TotalPrice: order.TotalPrice(),
+		CreatedAt:  order.CreatedAt(),
+	}
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"orders/config"
+	applicationServices "orders/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"shared/authz"
+	httpErrors "shared/errors/http"
+)
+
+type OrderController struct {
+	ApplicationService applicationServices.OrderApplicationService
+	Logger             zerolog.Logger
+	Config             *config.Config
+}
+
+func NewOrderController(
+	appService applicationServices.OrderApplicationService,
+	logger zerolog.Logger,
+	config *config.Config,
+) *OrderController {
+	return &OrderController{
+		ApplicationService: appService,
+		Logger:             logger,
+		Config:             config,
+	}
+}
+
+func (o *OrderController) Checkout(c *gin.Context) {
+	identity := authz.FromRequest(c.Request)
+
+	order, err := o.ApplicationService.Checkout(c.Request.Context(), identity.UserID)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusCreated, order)
+}
+
+func (o *OrderController) GetOrder(c *gin.Context) {
+	identity := authz.FromRequest(c.Request)
+
+	order, err := o.ApplicationService.GetOrder(c.Request.Context(), identity, c.Param("orderID"))
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	handleResponseWithBody(c, order)
+}
+
+func (o *OrderController) ListOrders(c *gin.Context) {
+	identity := authz.FromRequest(c.Request)
+
+	orders, err := o.ApplicationService.ListOrders(c.Request.Context(), identity.UserID)
+	if err != nil {
+		httpErrors.RespondWithError(c, err)
+		return
+	}
+	handleResponseWithBody(c, orders)
+}
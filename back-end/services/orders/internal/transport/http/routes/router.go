@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"orders/config"
+	controllers "orders/internal/transport/http/controllers"
+	"shared/authz"
+	"shared/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func NewRouter(
+	handler *gin.Engine,
+	o *controllers.OrderController,
+	logger zerolog.Logger,
+	config *config.Config,
+	checks *health.Health,
+	verifier authz.Verifier,
+) {
+	handler.Use(gin.Logger())
+	handler.Use(gin.Recovery())
+	checks.Register(handler)
+
+	v1 := handler.Group("/v1", authz.Middleware(verifier))
+
+	v1.POST("/orders", o.Checkout)
+	v1.GET("/orders", o.ListOrders)
+	v1.GET("/orders/:orderID", o.GetOrder)
+}
@@ -0,0 +1,30 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"orders/config"
+	controllers "orders/internal/transport/http/controllers"
+	routes "orders/internal/transport/http/routes"
+	"shared/authz"
+	"shared/health"
+
+	"orders/pkg/httpserver"
+)
+
+func NewHTTPServer(
+	orderController *controllers.OrderController,
+	handler *gin.Engine,
+	logger zerolog.Logger,
+	config *config.Config,
+	checks *health.Health,
+	verifier authz.Verifier,
+) *httpserver.Server {
+	routes.NewRouter(handler, orderController, logger, config, checks, verifier)
+	logger.Info().Msg(fmt.Sprintf("Listening on %s port", config.HTTP.Port))
+	return httpserver.New(http.Handler(handler), httpserver.Port(config.HTTP.Port))
+}
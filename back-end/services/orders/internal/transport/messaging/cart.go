@@ -0,0 +1,116 @@
+package messaging
+
+import (
+	"context"
+	"shared/events"
+	"shared/inbox"
+	natsClient "shared/messaging/nats"
+	"shared/money"
+
+	applicationServices "orders/internal/services"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	cartDurableConsumerName = "orders-carts"
+	cartStream              = "carts"
+)
+
+// consumers of the cart events before they were handled in order
+var legacyCartDurableConsumerNames = []string{
+	"orders-cart-product-added",
+	"orders-cart-product-quantity-changed",
+	"orders-cart-product-removed",
+}
+
+type CartMessagingHandlers interface {
+	CartEventsListener()
+	Init()
+}
+
+var _ CartMessagingHandlers = (*cartMessagingHandlers)(nil)
+
+type cartMessagingHandlers struct {
+	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
+	logger     zerolog.Logger
+	appService applicationServices.CatalogApplicationService
+}
+
+func NewCartMessagingHandlers(
+	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
+	appService applicationServices.CatalogApplicationService,
+	logger zerolog.Logger,
+) *cartMessagingHandlers {
+	c := cartMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
+	return &c
+}
+
+func (c *cartMessagingHandlers) Init() {
+	c.logger.Info().Msg("initializing CartMessagingHandlers")
+
+	err := c.natsClient.CreateStream(cartStream, "carts.*")
+	if err != nil {
+		log.Error().Err(err).Msg("cartMessagingHandlers Init -> c.natsClient.CreateStream")
+	}
+
+	c.CartEventsListener()
+	// only once the new consumer exists, the stream drops the messages nobody
+	// is interested in
+	for _, consumerName := range legacyCartDurableConsumerNames {
+		err = c.natsClient.DeleteConsumer(cartStream, consumerName)
+		if err != nil {
+			log.Error().Err(err).Str("consumerName", consumerName).Msg("cartMessagingHandlers Init -> c.natsClient.DeleteConsumer")
+		}
+	}
+}
+
+// CartEventsListener applies the events of the carts one at a time in the
+// order they were published, so that a line removed after it was changed is
+// not added back by the late change.
+func (c *cartMessagingHandlers) CartEventsListener() {
+	c.logger.Info().Msg("CartEventsListener initialized")
+	events.SubscribeAll(c.natsClient, cartStream, cartDurableConsumerName, "carts.*", []events.Handler{
+		events.Handle(inbox.Idempotent(c.inbox, cartDurableConsumerName, c.cartProductAdded)),
+		events.Handle(inbox.Idempotent(c.inbox, cartDurableConsumerName, c.cartProductQuantityChanged)),
+		events.Handle(inbox.Idempotent(c.inbox, cartDurableConsumerName, c.cartProductRemoved)),
+	}, natsClient.MaxAckPending(1))
+}
+
+func (c *cartMessagingHandlers) cartProductAdded(ctx context.Context, cartEvent events.CartProductAdded, envelope events.Envelope) error {
+	err := c.appService.SetCartProductQuantity(ctx, cartEvent.CustomerID, cartEvent.ProductID, cartEvent.VariantID, cartEvent.Quantity, addedPrice(cartEvent.AddedPrice))
+	if err != nil {
+		log.Error().Err(err).Msg("CartEventsListener -> c.appService.SetCartProductQuantity")
+		return err
+	}
+	return nil
+}
+
+func (c *cartMessagingHandlers) cartProductQuantityChanged(ctx context.Context, cartEvent events.CartProductQuantityChanged, envelope events.Envelope) error {
+	err := c.appService.SetCartProductQuantity(ctx, cartEvent.CustomerID, cartEvent.ProductID, cartEvent.VariantID, cartEvent.Quantity, addedPrice(cartEvent.AddedPrice))
+	if err != nil {
+		log.Error().Err(err).Msg("CartEventsListener -> c.appService.SetCartProductQuantity")
+		return err
+	}
+	return nil
+}
+
+func (c *cartMessagingHandlers) cartProductRemoved(ctx context.Context, cartEvent events.CartProductRemoved, envelope events.Envelope) error {
+	err := c.appService.RemoveCartProduct(ctx, cartEvent.CustomerID, cartEvent.ProductID, cartEvent.VariantID)
+	if err != nil {
+		log.Error().Err(err).Msg("CartEventsListener -> c.appService.RemoveCartProduct")
+		return err
+	}
+	return nil
+}
+
+// addedPrice is zero for events published before the cart reported it.
+func addedPrice(price *money.Money) money.Money {
+	if price == nil {
+		return money.Money{}
+	}
+	return *price
+}
@@ -0,0 +1,104 @@
+package messaging
+
+import (
+	"context"
+	"shared/events"
+	"shared/inbox"
+	natsClient "shared/messaging/nats"
+
+	applicationServices "orders/internal/services"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	stockReservedDurableConsumerName          = "orders-stock-reserved"
+	stockReservationFailedDurableConsumerName = "orders-stock-reservation-failed"
+	stockReleasedDurableConsumerName          = "orders-stock-released"
+	inventoryStream                           = "inventory"
+)
+
+type InventoryMessagingHandlers interface {
+	StockReservedListener()
+	StockReservationFailedListener()
+	StockReleasedListener()
+	Init()
+}
+
+var _ InventoryMessagingHandlers = (*inventoryMessagingHandlers)(nil)
+
+type inventoryMessagingHandlers struct {
+	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
+	logger     zerolog.Logger
+	appService applicationServices.OrderApplicationService
+}
+
+func NewInventoryMessagingHandlers(
+	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
+	appService applicationServices.OrderApplicationService,
+	logger zerolog.Logger,
+) *inventoryMessagingHandlers {
+	i := inventoryMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
+	return &i
+}
+
+func (i *inventoryMessagingHandlers) Init() {
+	i.logger.Info().Msg("initializing InventoryMessagingHandlers")
+
+	err := i.natsClient.CreateStream(inventoryStream, "inventory.*")
+	if err != nil {
+		log.Error().Err(err).Msg("inventoryMessagingHandlers Init -> i.natsClient.CreateStream")
+	}
+
+	i.StockReservedListener()
+	i.StockReservationFailedListener()
+	i.StockReleasedListener()
+}
+
+func (i *inventoryMessagingHandlers) StockReservedListener() {
+	i.logger.Info().Msg("StockReservedListener initialized")
+	handler := func(ctx context.Context, stockEvent events.StockReserved, envelope events.Envelope) error {
+		log.Info().Msg("StockReservedListener -> Received a message: " + string(envelope.Payload))
+
+		err := i.appService.ConfirmOrder(ctx, stockEvent.OrderID)
+		if err != nil {
+			log.Error().Err(err).Msg("StockReservedListener -> i.appService.ConfirmOrder")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(i.natsClient, inventoryStream, stockReservedDurableConsumerName, inbox.Idempotent(i.inbox, stockReservedDurableConsumerName, handler))
+}
+
+func (i *inventoryMessagingHandlers) StockReservationFailedListener() {
+	i.logger.Info().Msg("StockReservationFailedListener initialized")
+	handler := func(ctx context.Context, stockEvent events.StockReservationFailed, envelope events.Envelope) error {
+		log.Info().Msg("StockReservationFailedListener -> Received a message: " + string(envelope.Payload))
+
+		err := i.appService.CancelOrder(ctx, stockEvent.OrderID, stockEvent.Reason)
+		if err != nil {
+			log.Error().Err(err).Msg("StockReservationFailedListener -> i.appService.CancelOrder")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(i.natsClient, inventoryStream, stockReservationFailedDurableConsumerName, inbox.Idempotent(i.inbox, stockReservationFailedDurableConsumerName, handler))
+}
+
+func (i *inventoryMessagingHandlers) StockReleasedListener() {
+	i.logger.Info().Msg("StockReleasedListener initialized")
+	handler := func(ctx context.Context, stockEvent events.StockReleased, envelope events.Envelope) error {
+		log.Info().Msg("StockReleasedListener -> Received a message: " + string(envelope.Payload))
+
+		err := i.appService.CancelOrder(ctx, stockEvent.OrderID, stockEvent.Reason)
+		if err != nil {
+			log.Error().Err(err).Msg("StockReleasedListener -> i.appService.CancelOrder")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(i.natsClient, inventoryStream, stockReleasedDurableConsumerName, inbox.Idempotent(i.inbox, stockReleasedDurableConsumerName, handler))
+}
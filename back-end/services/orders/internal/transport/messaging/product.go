@@ -0,0 +1,169 @@
+package messaging
+
+import (
+	"context"
+	"shared/events"
+	"shared/inbox"
+	natsClient "shared/messaging/nats"
+
+	productEntity "orders/internal/domain/entities/product"
+	applicationServices "orders/internal/services"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	productCreatedDurableConsumerName = "orders-product-created"
+	productDeletedDurableConsumerName = "orders-product-deleted"
+	productUpdatedDurableConsumerName = "orders-product-updated"
+	variantCreatedDurableConsumerName = "orders-product-variant-created"
+	variantUpdatedDurableConsumerName = "orders-product-variant-updated"
+	variantDeletedDurableConsumerName = "orders-product-variant-deleted"
+	productStream                     = "products"
+)
+
+type ProductMessagingHandlers interface {
+	ProductCreatedListener()
+	ProductUpdatedListener()
+	ProductDeletedListener()
+	VariantCreatedListener()
+	VariantUpdatedListener()
+	VariantDeletedListener()
+	Init()
+}
+
+var _ ProductMessagingHandlers = (*productMessagingHandlers)(nil)
+
+type productMessagingHandlers struct {
+	natsClient natsClient.NatsClient
+	inbox      inbox.Inbox
+	logger     zerolog.Logger
+	appService applicationServices.CatalogApplicationService
+}
+
+func NewProductMessagingHandlers(
+	natsClient natsClient.NatsClient,
+	inbox inbox.Inbox,
+	appService applicationServices.CatalogApplicationService,
+	logger zerolog.Logger,
+) *productMessagingHandlers {
+	d := productMessagingHandlers{natsClient: natsClient, inbox: inbox, appService: appService, logger: logger}
+	return &d
+}
+
+func (d *productMessagingHandlers) Init() {
+	d.logger.Info().Msg("initializing ProductMessagingHandlers")
+
+	err := d.natsClient.CreateStream(productStream, "products.*")
+	if err != nil {
+		log.Error().Err(err).Msg("productMessagingHandlers Init -> d.natsClient.CreateStream")
+	}
+
+	d.ProductCreatedListener()
+	d.ProductUpdatedListener()
+	d.ProductDeletedListener()
+	d.VariantCreatedListener()
+	d.VariantUpdatedListener()
+	d.VariantDeletedListener()
+}
+
+func (d *productMessagingHandlers) ProductCreatedListener() {
+	d.logger.Info().Msg("ProductCreatedListener initialized")
+	handler := func(ctx context.Context, productEvent events.ProductCreated, envelope events.Envelope) error {
+		err := d.appService.SaveProduct(ctx, productEntity.CreateProductParams{
+			ID:        productEvent.ID,
+			Name:      productEvent.Name,
+			Price:     productEvent.Price,
+			UpdatedAt: productEvent.UpdatedAt,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("ProductCreatedListener -> d.appService.SaveProduct")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, productCreatedDurableConsumerName, inbox.Idempotent(d.inbox, productCreatedDurableConsumerName, handler))
+}
+
+func (d *productMessagingHandlers) ProductUpdatedListener() {
+	d.logger.Info().Msg("ProductUpdatedListener initialized")
+	handler := func(ctx context.Context, productEvent events.ProductUpdated, envelope events.Envelope) error {
+		err := d.appService.SaveProduct(ctx, productEntity.CreateProductParams{
+			ID:        productEvent.ID,
+			Name:      productEvent.Name,
+			Price:     productEvent.Price,
+			UpdatedAt: productEvent.UpdatedAt,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("ProductUpdatedListener -> d.appService.SaveProduct")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, productUpdatedDurableConsumerName, inbox.Idempotent(d.inbox, productUpdatedDurableConsumerName, handler))
+}
+
+func (d *productMessagingHandlers) ProductDeletedListener() {
+	d.logger.Info().Msg("ProductDeletedListener initialized")
+	handler := func(ctx context.Context, productEvent events.ProductDeleted, envelope events.Envelope) error {
+		err := d.appService.DeleteProduct(ctx, productEvent.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("ProductDeletedListener -> d.appService.DeleteProduct")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, productDeletedDurableConsumerName, inbox.Idempotent(d.inbox, productDeletedDurableConsumerName, handler))
+}
+
+func (d *productMessagingHandlers) VariantCreatedListener() {
+	d.logger.Info().Msg("VariantCreatedListener initialized")
+	handler := func(ctx context.Context, variantEvent events.ProductVariantCreated, envelope events.Envelope) error {
+		err := d.appService.SaveVariant(ctx, productEntity.CreateVariantParams{
+			ID:        variantEvent.ID,
+			ProductID: variantEvent.ProductID,
+			SKU:       variantEvent.SKU,
+			Price:     variantEvent.Price,
+			UpdatedAt: variantEvent.UpdatedAt,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("VariantCreatedListener -> d.appService.SaveVariant")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, variantCreatedDurableConsumerName, inbox.Idempotent(d.inbox, variantCreatedDurableConsumerName, handler))
+}
+
+func (d *productMessagingHandlers) VariantUpdatedListener() {
+	d.logger.Info().Msg("VariantUpdatedListener initialized")
+	handler := func(ctx context.Context, variantEvent events.ProductVariantUpdated, envelope events.Envelope) error {
+		err := d.appService.SaveVariant(ctx, productEntity.CreateVariantParams{
+			ID:        variantEvent.ID,
+			ProductID: variantEvent.ProductID,
+			SKU:       variantEvent.SKU,
+			Price:     variantEvent.Price,
+			UpdatedAt: variantEvent.UpdatedAt,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("VariantUpdatedListener -> d.appService.SaveVariant")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, variantUpdatedDurableConsumerName, inbox.Idempotent(d.inbox, variantUpdatedDurableConsumerName, handler))
+}
+
+func (d *productMessagingHandlers) VariantDeletedListener() {
+	d.logger.Info().Msg("VariantDeletedListener initialized")
+	handler := func(ctx context.Context, variantEvent events.ProductVariantDeleted, envelope events.Envelope) error {
+		err := d.appService.DeleteVariant(ctx, variantEvent.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("VariantDeletedListener -> d.appService.DeleteVariant")
+			return err
+		}
+		return nil
+	}
+	events.Subscribe(d.natsClient, productStream, variantDeletedDurableConsumerName, inbox.Idempotent(d.inbox, variantDeletedDurableConsumerName, handler))
+}
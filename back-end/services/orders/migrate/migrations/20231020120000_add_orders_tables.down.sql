@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS order_items;
+DROP INDEX IF EXISTS orders_customer_id_idx;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS cart_products;
+DROP TABLE IF EXISTS products;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS products (
+    id uuid NOT NULL,
+    name varchar NOT NULL,
+    price numeric NOT NULL,
+    updated_at timestamptz NULL,
+    CONSTRAINT products_pk PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS cart_products (
+    customer_id uuid NOT NULL,
+    product_id uuid NOT NULL,
+    quantity int NOT NULL,
+    updated_at timestamptz NOT NULL,
+    CONSTRAINT cart_products_pk PRIMARY KEY (customer_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id uuid NOT NULL,
+    customer_id uuid NOT NULL,
+    status varchar NOT NULL,
+    total_price numeric NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    CONSTRAINT orders_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS orders_customer_id_idx ON orders (customer_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id uuid NOT NULL,
+    product_id uuid NOT NULL,
+    name varchar NOT NULL,
+    price numeric NOT NULL,
+    quantity int NOT NULL,
+    CONSTRAINT order_items_pk PRIMARY KEY (order_id, product_id),
+    CONSTRAINT order_items_order_fk FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
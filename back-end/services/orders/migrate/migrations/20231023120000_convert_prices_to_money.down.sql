@@ -0,0 +1,18 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price numeric NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_price numeric NULL;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS price numeric NULL;
+
+UPDATE products SET price = price_amount / 100.0 WHERE price IS NULL;
+UPDATE orders SET total_price = total_price_amount / 100.0 WHERE total_price IS NULL;
+UPDATE order_items SET price = price_amount / 100.0 WHERE price IS NULL;
+
+ALTER TABLE products ALTER COLUMN price SET NOT NULL;
+ALTER TABLE orders ALTER COLUMN total_price SET NOT NULL;
+ALTER TABLE order_items ALTER COLUMN price SET NOT NULL;
+
+ALTER TABLE products DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE products DROP COLUMN IF EXISTS price_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_price_currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_price_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_currency;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_amount;
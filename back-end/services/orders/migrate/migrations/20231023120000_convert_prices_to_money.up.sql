@@ -0,0 +1,21 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_amount bigint NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_currency char(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_price_amount bigint NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_price_currency char(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS price_amount bigint NULL;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS price_currency char(3) NOT NULL DEFAULT 'USD';
+
+-- prices were stored as decimal USD amounts
+UPDATE products SET price_amount = ROUND(price * 100) WHERE price_amount IS NULL;
+UPDATE orders SET total_price_amount = ROUND(total_price * 100) WHERE total_price_amount IS NULL;
+UPDATE order_items SET price_amount = ROUND(price * 100) WHERE price_amount IS NULL;
+
+ALTER TABLE products ALTER COLUMN price_amount SET NOT NULL;
+ALTER TABLE orders ALTER COLUMN total_price_amount SET NOT NULL;
+ALTER TABLE order_items ALTER COLUMN price_amount SET NOT NULL;
+
+ALTER TABLE products DROP COLUMN IF EXISTS price;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_price;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price;
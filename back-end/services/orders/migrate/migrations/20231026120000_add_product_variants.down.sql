@@ -0,0 +1,12 @@
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_pk;
+DELETE FROM order_items WHERE variant_id <> '';
+ALTER TABLE order_items DROP COLUMN IF EXISTS sku;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE order_items ADD CONSTRAINT order_items_pk PRIMARY KEY (order_id, product_id);
+
+ALTER TABLE cart_products DROP CONSTRAINT IF EXISTS cart_products_pk;
+DELETE FROM cart_products WHERE variant_id <> '';
+ALTER TABLE cart_products DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE cart_products ADD CONSTRAINT cart_products_pk PRIMARY KEY (customer_id, product_id);
+
+DROP TABLE IF EXISTS product_variants;
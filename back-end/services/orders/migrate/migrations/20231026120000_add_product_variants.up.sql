@@ -0,0 +1,22 @@
+-- no foreign key to products: variant events may be consumed before the product's
+CREATE TABLE IF NOT EXISTS product_variants (
+    id uuid NOT NULL,
+    product_id uuid NOT NULL,
+    sku varchar NOT NULL,
+    price_amount bigint NOT NULL,
+    price_currency char(3) NOT NULL,
+    updated_at timestamptz NULL,
+    CONSTRAINT product_variants_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS product_variants_product_id_idx ON product_variants (product_id);
+
+-- an empty variant_id is a line for a product without variants
+ALTER TABLE cart_products ADD COLUMN IF NOT EXISTS variant_id varchar NOT NULL DEFAULT '';
+ALTER TABLE cart_products DROP CONSTRAINT IF EXISTS cart_products_pk;
+ALTER TABLE cart_products ADD CONSTRAINT cart_products_pk PRIMARY KEY (customer_id, product_id, variant_id);
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id varchar NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS sku varchar NOT NULL DEFAULT '';
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_pk;
+ALTER TABLE order_items ADD CONSTRAINT order_items_pk PRIMARY KEY (order_id, product_id, variant_id);
@@ -0,0 +1,2 @@
+ALTER TABLE cart_products DROP COLUMN IF EXISTS added_price_currency;
+ALTER TABLE cart_products DROP COLUMN IF EXISTS added_price_amount;
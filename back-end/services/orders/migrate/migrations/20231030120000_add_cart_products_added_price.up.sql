@@ -0,0 +1,3 @@
+-- price of the line when it was added to the cart, NULL for lines added before it was tracked
+ALTER TABLE cart_products ADD COLUMN IF NOT EXISTS added_price_amount bigint NULL;
+ALTER TABLE cart_products ADD COLUMN IF NOT EXISTS added_price_currency char(3) NULL;
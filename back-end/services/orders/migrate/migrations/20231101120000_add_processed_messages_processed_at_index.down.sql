@@ -0,0 +1 @@
+DROP INDEX IF EXISTS processed_messages_processed_at_idx;
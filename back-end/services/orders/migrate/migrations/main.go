@@ -0,0 +1,11 @@
+package migrations
+
+import "github.com/uptrace/bun/migrate"
+
+var Migrations = migrate.NewMigrations()
+
+func init() {
+	if err := Migrations.DiscoverCaller(); err != nil {
+		panic(err)
+	}
+}